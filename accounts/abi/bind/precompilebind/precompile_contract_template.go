@@ -250,7 +250,7 @@ func {{decapitalise .Normalized.Name}}(accessibleState contract.AccessibleState,
 	// You can modify/delete this code if you don't want this function to be restricted by the allow list.
 	stateDB := accessibleState.GetStateDB()
 	// Verify that the caller is in the allow list and therefore has the right to call this function.
-	callerStatus := allowlist.GetAllowListStatus(stateDB, ContractAddress, caller)
+	callerStatus := allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp())
 	if !callerStatus.IsEnabled() {
 		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannot{{.Normalized.Name}}, caller)
 	}
@@ -301,7 +301,7 @@ func {{decapitalise $contract.Type}}Fallback (accessibleState contract.Accessibl
 	// You can modify/delete this code if you don't want this function to be restricted by the allow list.
 	stateDB := accessibleState.GetStateDB()
 	// Verify that the caller is in the allow list and therefore has the right to call this function.
-	callerStatus := allowlist.GetAllowListStatus(stateDB, ContractAddress, caller)
+	callerStatus := allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp())
 	if !callerStatus.IsEnabled() {
 		return nil, remainingGas, fmt.Errorf("%w: %s", Err{{$contract.Type}}CannotFallback, caller)
 	}
@@ -29,6 +29,7 @@ package t8ntool
 import (
 	"fmt"
 	"math/big"
+	"github.com/luxfi/evm/commontype"
 	"github.com/luxfi/evm/consensus/misc/eip4844"
 	"github.com/luxfi/evm/core"
 	"github.com/luxfi/geth/core/rawdb"
@@ -37,14 +38,19 @@ import (
 	"github.com/luxfi/evm/core/vm"
 	"github.com/luxfi/geth/ethdb"
 	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/plugin/evm/customtypes"
+	customheader "github.com/luxfi/evm/plugin/evm/header"
+	"github.com/luxfi/evm/plugin/evm/upgrade/subnetevm"
 	"github.com/luxfi/geth/trie"
 	"github.com/luxfi/geth/triedb"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
 	"github.com/luxfi/geth/common/math"
 	"github.com/luxfi/geth/crypto"
 	"github.com/luxfi/geth/log"
 	"github.com/luxfi/geth/rlp"
 	"github.com/luxfi/geth/core/tracing"
+	ethstate "github.com/luxfi/geth/core/state"
 	"github.com/holiman/uint256"
 	"golang.org/x/crypto/sha3"
 )
@@ -69,6 +75,8 @@ type ExecutionResult struct {
 	BaseFee              *math.HexOrDecimal256 `json:"currentBaseFee,omitempty"`
 	CurrentExcessBlobGas *math.HexOrDecimal64  `json:"currentExcessBlobGas,omitempty"`
 	CurrentBlobGasUsed   *math.HexOrDecimal64  `json:"blobGasUsed,omitempty"`
+	BlockGasCost         *math.HexOrDecimal256 `json:"currentBlockGasCost,omitempty"`
+	Extra                hexutil.Bytes         `json:"currentExtra,omitempty"`
 }
 
 type ommer struct {
@@ -98,6 +106,11 @@ type stEnv struct {
 	ParentExcessBlobGas   *uint64                             `json:"parentExcessBlobGas,omitempty"`
 	ParentBlobGasUsed     *uint64                             `json:"parentBlobGasUsed,omitempty"`
 	ParentBeaconBlockRoot *common.Hash                        `json:"parentBeaconBlockRoot"`
+	FeeConfig             *commontype.FeeConfig               `json:"feeConfig,omitempty"`
+	ParentExtra           []byte                              `json:"parentExtra,omitempty"`
+	ParentBlockGasCost    *big.Int                            `json:"parentBlockGasCost,omitempty"`
+	BlockGasCost          *big.Int                            `json:"currentBlockGasCost,omitempty"`
+	PredicateResults      []byte                              `json:"predicateResults,omitempty"`
 }
 
 type stEnvMarshaling struct {
@@ -117,6 +130,43 @@ type stEnvMarshaling struct {
 	ExcessBlobGas       *math.HexOrDecimal64
 	ParentExcessBlobGas *math.HexOrDecimal64
 	ParentBlobGasUsed   *math.HexOrDecimal64
+	ParentExtra         hexutil.Bytes
+	ParentBlockGasCost  *math.HexOrDecimal256
+	BlockGasCost        *math.HexOrDecimal256
+	PredicateResults    hexutil.Bytes
+}
+
+// parent returns the parent header as far as it's described by the env, for
+// the fee computations of the current block.
+func (env *stEnv) parent() *types.Header {
+	extra := env.ParentExtra
+	if extra == nil {
+		extra = make([]byte, subnetevm.WindowSize)
+	}
+	parent := &types.Header{
+		Number:   new(big.Int).SetUint64(env.Number - 1),
+		Time:     env.ParentTimestamp,
+		BaseFee:  env.ParentBaseFee,
+		GasUsed:  env.ParentGasUsed,
+		GasLimit: env.ParentGasLimit,
+		Extra:    extra,
+	}
+	customtypes.GetHeaderExtra(parent).BlockGasCost = env.ParentBlockGasCost
+	return parent
+}
+
+// feeConfig returns the fee config in effect for the current block. It
+// defaults to [params.DefaultFeeConfig], and the min base fee can be overridden
+// on its own.
+func (env *stEnv) feeConfig() commontype.FeeConfig {
+	feeConfig := params.DefaultFeeConfig
+	if env.FeeConfig != nil {
+		feeConfig = *env.FeeConfig
+	}
+	if env.MinBaseFee != nil {
+		feeConfig.MinBaseFee = env.MinBaseFee
+	}
+	return feeConfig
 }
 
 type rejectedTx struct {
@@ -124,10 +174,10 @@ type rejectedTx struct {
 	Err   string `json:"error"`
 }
 
-// Apply applies a set of transactions to a pre-state
+// Apply applies a set of transactions to a pre-state. If vmConfig carries a
+// tracer, it's invoked for every applied transaction.
 func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
-	txIt txIterator, miningReward int64,
-	getTracerFn func(txIndex int, txHash common.Hash) (vm.EVMLogger, error)) (*state.StateDB, *ExecutionResult, []byte, error) {
+	txIt txIterator, miningReward int64) (*state.StateDB, *ExecutionResult, []byte, error) {
 	// Capture errors for BLOCKHASH operation, if we haven't been supplied the
 	// required blockhashes
 	var hashError error
@@ -155,20 +205,32 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		txIndex     = 0
 	)
 	gaspool.AddGas(pre.Env.GasLimit)
-	vmContext := vm.BlockContext{
-		CanTransfer: core.CanTransfer,
-		Transfer:    core.Transfer,
-		Coinbase:    pre.Env.Coinbase,
-		BlockNumber: new(big.Int).SetUint64(pre.Env.Number),
-		Time:        pre.Env.Timestamp,
-		Difficulty:  pre.Env.Difficulty,
-		GasLimit:    pre.Env.GasLimit,
-		GetHash:     getHash,
+	header := &types.Header{
+		Coinbase:   pre.Env.Coinbase,
+		Number:     new(big.Int).SetUint64(pre.Env.Number),
+		Time:       pre.Env.Timestamp,
+		Difficulty: new(big.Int),
+		GasLimit:   pre.Env.GasLimit,
+		BaseFee:    pre.Env.BaseFee,
+	}
+	// Past SubnetEVM, the extra carries the fee window followed by the results
+	// of the predicates, which are made available to the precompiles.
+	configExtra := params.GetExtra(chainConfig)
+	if configExtra.IsSubnetEVM(pre.Env.Timestamp) && pre.Env.Number > 0 {
+		extra, err := customheader.ExtraPrefix(configExtra, pre.Env.parent(), header)
+		if err != nil {
+			return nil, nil, nil, NewError(ErrorConfig, fmt.Errorf("failed calculating extra: %v", err))
+		}
+		header.Extra = extra
 	}
-	// If currentBaseFee is defined, add it to the vmContext.
-	if pre.Env.BaseFee != nil {
-		vmContext.BaseFee = new(big.Int).Set(pre.Env.BaseFee)
+	if len(pre.Env.PredicateResults) > 0 {
+		header.Extra = customheader.SetPredicateBytesInExtra(header.Extra, pre.Env.PredicateResults)
 	}
+	// There's no chain to resolve the author or the block hashes from, those
+	// are taken from the env instead.
+	vmContext := core.NewEVMBlockContext(header, nil, &pre.Env.Coinbase)
+	vmContext.Difficulty = pre.Env.Difficulty
+	vmContext.GetHash = getHash
 	// If random is defined, add it to the vmContext.
 	if pre.Env.Random != nil {
 		rnd := common.BigToHash(pre.Env.Random)
@@ -230,27 +292,34 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 				continue
 			}
 		}
-		_, err = getTracerFn(txIndex, tx.Hash())
-		if err != nil {
-			return nil, nil, nil, err
-		}
-		// vmConfig.Tracer = tracer // TODO: Fix tracer interface
 		statedb.SetTxContext(tx.Hash(), txIndex)
 
 		var (
-			txContext = core.NewEVMTxContext(msg)
-			snapshot  = statedb.Snapshot()
-			prevGas   = gaspool.Gas()
+			tracer         = vmConfig.Tracer
+			tracingStateDB = vm.StateDB(statedb)
+			txContext      = core.NewEVMTxContext(msg)
+			snapshot       = statedb.Snapshot()
+			prevGas        = gaspool.Gas()
 		)
-		evm := vm.NewEVMWithStateDB(vmContext, txContext, statedb, chainConfig, vmConfig)
+		if tracer != nil {
+			tracingStateDB = ethstate.NewHookedState(statedb.StateDB, tracer)
+		}
+		evm := vm.NewEVM(vmContext, tracingStateDB, vm.ConvertChainConfig(chainConfig), vmConfig)
+		evm.SetTxContext(txContext)
 
+		if tracer != nil && tracer.OnTxStart != nil {
+			tracer.OnTxStart(evm.GetVMContext(), tx, msg.From)
+		}
 		// (ret []byte, usedGas uint64, failed bool, err error)
-		msgResult, err := core.ApplyMessage(evm.EVM, msg, gaspool)
+		msgResult, err := core.ApplyMessage(chainConfig, evm.EVM, msg, gaspool)
 		if err != nil {
 			statedb.RevertToSnapshot(snapshot)
 			log.Info("rejected tx", "index", i, "hash", tx.Hash(), "from", msg.From, "error", err)
 			rejectedTxs = append(rejectedTxs, &rejectedTx{i, err.Error()})
 			gaspool.SetGas(prevGas)
+			if tracer != nil && tracer.OnTxEnd != nil {
+				tracer.OnTxEnd(nil, err)
+			}
 			continue
 		}
 		includedTxs = append(includedTxs, tx)
@@ -282,7 +351,7 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 
 			// If the transaction created a contract, store the creation address in the receipt.
 			if msg.To == nil {
-				receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
+				receipt.ContractAddress = crypto.CreateAddress(msg.From, tx.Nonce())
 			}
 
 			// Set the receipt logs and create the bloom filter.
@@ -293,6 +362,9 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 			//receipt.BlockNumber
 			receipt.TransactionIndex = uint(txIndex)
 			receipts = append(receipts, receipt)
+			if tracer != nil && tracer.OnTxEnd != nil {
+				tracer.OnTxEnd(receipt, nil)
+			}
 		}
 
 		txIndex++
@@ -331,13 +403,17 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		StateRoot:   root,
 		TxRoot:      types.DeriveSha(includedTxs, trie.NewStackTrie(nil)),
 		ReceiptRoot: types.DeriveSha(receipts, trie.NewStackTrie(nil)),
-		Bloom:       types.Bloom{}, // TODO: Fix bloom creation
+		Bloom:       types.MergeBloom(receipts),
 		LogsHash:    rlpHash(statedb.Logs()),
 		Receipts:    receipts,
 		Rejected:    rejectedTxs,
 		Difficulty:  (*math.HexOrDecimal256)(vmContext.Difficulty),
 		GasUsed:     (math.HexOrDecimal64)(gasUsed),
 		BaseFee:     (*math.HexOrDecimal256)(vmContext.BaseFee),
+		Extra:       header.Extra,
+	}
+	if pre.Env.BlockGasCost != nil {
+		execRs.BlockGasCost = (*math.HexOrDecimal256)(pre.Env.BlockGasCost)
 	}
 	if vmContext.BlobBaseFee != nil {
 		execRs.CurrentExcessBlobGas = (*math.HexOrDecimal64)(&excessBlobGas)
@@ -7,7 +7,9 @@ import (
 	"errors"
 	"math/big"
 
+	"github.com/luxfi/evm/commontype"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
 	"github.com/luxfi/geth/common/math"
 )
 
@@ -36,6 +38,11 @@ func (s stEnv) MarshalJSON() ([]byte, error) {
 		ParentExcessBlobGas   *math.HexOrDecimal64                `json:"parentExcessBlobGas,omitempty"`
 		ParentBlobGasUsed     *math.HexOrDecimal64                `json:"parentBlobGasUsed,omitempty"`
 		ParentBeaconBlockRoot *common.Hash                        `json:"parentBeaconBlockRoot"`
+		FeeConfig             *commontype.FeeConfig               `json:"feeConfig,omitempty"`
+		ParentExtra           hexutil.Bytes                       `json:"parentExtra,omitempty"`
+		ParentBlockGasCost    *math.HexOrDecimal256               `json:"parentBlockGasCost,omitempty"`
+		BlockGasCost          *math.HexOrDecimal256               `json:"currentBlockGasCost,omitempty"`
+		PredicateResults      hexutil.Bytes                       `json:"predicateResults,omitempty"`
 	}
 	var enc stEnv
 	enc.Coinbase = common.UnprefixedAddress(s.Coinbase)
@@ -58,6 +65,11 @@ func (s stEnv) MarshalJSON() ([]byte, error) {
 	enc.ParentExcessBlobGas = (*math.HexOrDecimal64)(s.ParentExcessBlobGas)
 	enc.ParentBlobGasUsed = (*math.HexOrDecimal64)(s.ParentBlobGasUsed)
 	enc.ParentBeaconBlockRoot = s.ParentBeaconBlockRoot
+	enc.FeeConfig = s.FeeConfig
+	enc.ParentExtra = s.ParentExtra
+	enc.ParentBlockGasCost = (*math.HexOrDecimal256)(s.ParentBlockGasCost)
+	enc.BlockGasCost = (*math.HexOrDecimal256)(s.BlockGasCost)
+	enc.PredicateResults = s.PredicateResults
 	return json.Marshal(&enc)
 }
 
@@ -84,6 +96,11 @@ func (s *stEnv) UnmarshalJSON(input []byte) error {
 		ParentExcessBlobGas   *math.HexOrDecimal64                `json:"parentExcessBlobGas,omitempty"`
 		ParentBlobGasUsed     *math.HexOrDecimal64                `json:"parentBlobGasUsed,omitempty"`
 		ParentBeaconBlockRoot *common.Hash                        `json:"parentBeaconBlockRoot"`
+		FeeConfig             *commontype.FeeConfig               `json:"feeConfig,omitempty"`
+		ParentExtra           hexutil.Bytes                       `json:"parentExtra,omitempty"`
+		ParentBlockGasCost    *math.HexOrDecimal256               `json:"parentBlockGasCost,omitempty"`
+		BlockGasCost          *math.HexOrDecimal256               `json:"currentBlockGasCost,omitempty"`
+		PredicateResults      hexutil.Bytes                       `json:"predicateResults,omitempty"`
 	}
 	var dec stEnv
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -153,5 +170,20 @@ func (s *stEnv) UnmarshalJSON(input []byte) error {
 	if dec.ParentBeaconBlockRoot != nil {
 		s.ParentBeaconBlockRoot = dec.ParentBeaconBlockRoot
 	}
+	if dec.FeeConfig != nil {
+		s.FeeConfig = dec.FeeConfig
+	}
+	if dec.ParentExtra != nil {
+		s.ParentExtra = dec.ParentExtra
+	}
+	if dec.ParentBlockGasCost != nil {
+		s.ParentBlockGasCost = (*big.Int)(dec.ParentBlockGasCost)
+	}
+	if dec.BlockGasCost != nil {
+		s.BlockGasCost = (*big.Int)(dec.BlockGasCost)
+	}
+	if dec.PredicateResults != nil {
+		s.PredicateResults = dec.PredicateResults
+	}
 	return nil
 }
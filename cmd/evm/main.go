@@ -35,8 +35,8 @@ import (
 	"github.com/luxfi/evm/internal/flags"
 	"github.com/urfave/cli/v2"
 	// Force-load the tracer engines to trigger registration
-	_ "github.com/luxfi/evm/interfaces/eth/tracers/js"
-	_ "github.com/luxfi/evm/interfaces/eth/tracers/native"
+	_ "github.com/luxfi/evm/eth/tracers/js"
+	_ "github.com/luxfi/evm/eth/tracers/native"
 )
 
 var (
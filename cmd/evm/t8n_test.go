@@ -29,13 +29,21 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 	"github.com/luxfi/evm/cmd/evm/internal/t8ntool"
 	"github.com/luxfi/evm/internal/cmdtest"
+	"github.com/luxfi/evm/plugin/evm/upgrade/subnetevm"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/common/math"
+	"github.com/luxfi/geth/core/types"
 	"github.com/docker/docker/pkg/reexec"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMain(m *testing.M) {
@@ -337,6 +345,69 @@ func TestT8n(t *testing.T) {
 	}
 }
 
+// TestT8nSubnetEVM checks the trace files, the logs bloom and the fields of the
+// env specific to subnet-evm. The expected values are explained in the readme
+// of the test data.
+func TestT8nSubnetEVM(t *testing.T) {
+	t.Parallel()
+	tt := new(testT8n)
+	tt.TestCmd = cmdtest.NewTestCmd(t, tt)
+
+	baseDir := t.TempDir()
+	args := []string{"t8n", "--trace", "--output.basedir", baseDir}
+	args = append(args, (&t8nOutput{result: true}).get()...)
+	args = append(args, (&t8nInput{"alloc.json", "txs.json", "env.json", "Durango", ""}).get("./testdata/31")...)
+	tt.Run("evm-test", args...)
+	output := tt.Output()
+	tt.WaitExit()
+	require.Zero(t, tt.ExitStatus())
+
+	var out struct {
+		Result struct {
+			LogsBloom    types.Bloom           `json:"logsBloom"`
+			Receipts     []*types.Receipt      `json:"receipts"`
+			BaseFee      *math.HexOrDecimal256 `json:"currentBaseFee"`
+			BlockGasCost *math.HexOrDecimal256 `json:"currentBlockGasCost"`
+			Extra        hexutil.Bytes         `json:"currentExtra"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(output, &out))
+	result := out.Result
+
+	// The log emitted by the transaction is in the bloom of the block.
+	require.Len(t, result.Receipts, 1)
+	receipt := result.Receipts[0]
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	var bloom types.Bloom
+	bloom.Add(common.HexToAddress("0x000000000000000000000000000000000000aaaa").Bytes())
+	bloom.Add(common.BigToHash(common.Big1).Bytes())
+	require.Equal(t, bloom, receipt.Bloom)
+	require.Equal(t, bloom, result.LogsBloom)
+
+	require.Equal(t, int64(950), (*big.Int)(result.BaseFee).Int64())
+	require.Equal(t, int64(500_000), (*big.Int)(result.BlockGasCost).Int64())
+	window := subnetevm.Window{7: 500_000}
+	predicateResults := common.FromHex("000000000001010000000000000000000000000000000000000000000000000000000000000000000001020000000000000000000000000000000000000000000003010203")
+	require.Equal(t, append(window.Bytes(), predicateResults...), []byte(result.Extra))
+
+	// The opcodes executed by the transaction are traced to its own file,
+	// ending with the result of the execution.
+	traceFile := filepath.Join(baseDir, fmt.Sprintf("trace-0-%v.jsonl", receipt.TxHash.String()))
+	trace, err := os.ReadFile(traceFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(trace)), "\n")
+	var ops []string
+	for _, line := range lines[:len(lines)-1] {
+		var op struct {
+			OpName string `json:"opName"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &op))
+		ops = append(ops, op.OpName)
+	}
+	require.Equal(t, []string{"PUSH1", "PUSH1", "PUSH1", "LOG1", "STOP"}, ops)
+	require.Contains(t, lines[len(lines)-1], "gasUsed")
+}
+
 type t9nInput struct {
 	inTxs  string
 	stFork string
@@ -58,6 +58,14 @@ type FeeConfig struct {
 // represents an empty fee config without any field
 var EmptyFeeConfig = FeeConfig{}
 
+// ScheduledFeeConfig is a fee config scheduled through the Fee Manager
+// precompile to take effect from the first block with a timestamp of at least
+// [EffectiveTimestamp].
+type ScheduledFeeConfig struct {
+	FeeConfig          FeeConfig `json:"feeConfig"`
+	EffectiveTimestamp uint64    `json:"effectiveTimestamp"`
+}
+
 // Verify checks fields of this config to ensure a valid fee configuration is provided.
 func (f *FeeConfig) Verify() error {
 	switch {
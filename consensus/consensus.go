@@ -57,6 +57,9 @@ type ChainHeaderReader interface {
 	// GetFeeConfigAt retrieves the fee config and last changed block number at block header.
 	GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error)
 
+	// GetScheduledFeeConfigAt retrieves the fee config scheduled at block header, or nil if none is scheduled.
+	GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error)
+
 	// GetCoinbaseAt retrieves the configured coinbase address at [parent].
 	// If fee recipients are allowed, returns true in the second return value and a predefined address in the first value.
 	GetCoinbaseAt(parent *types.Header) (common.Address, bool, error)
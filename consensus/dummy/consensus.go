@@ -9,6 +9,7 @@ import (
 	"math/big"
 	"time"
 	"github.com/luxfi/node/utils/timer/mockable"
+	"github.com/luxfi/evm/commontype"
 	"github.com/luxfi/evm/consensus"
 	"github.com/luxfi/evm/core/state"
 	"github.com/luxfi/geth/core/types"
@@ -123,13 +124,21 @@ func (eng *DummyEngine) verifyCoinbase(header *types.Header, parent *types.Heade
 	return nil
 }
 
+// feeConfigAt returns the fee config in effect for a block at [timestamp] built
+// on [parent], which is the fee config scheduled at [parent] if it takes effect
+// by [timestamp].
+func feeConfigAt(chain consensus.ChainHeaderReader, parent *types.Header, timestamp uint64) (commontype.FeeConfig, error) {
+	feeConfig, _, err := customheader.FeeConfigAt(chain, parent, timestamp)
+	return feeConfig, err
+}
+
 func verifyHeaderGasFields(config *extras.ChainConfig, header *types.Header, parent *types.Header, chain consensus.ChainHeaderReader) error {
 	// We verify the current block by checking the parent fee config
 	// this is because the current block cannot set the fee config for itself
 	// Fee config might depend on the state when precompile is activated
 	// but we don't know the final state while forming the block.
 	// See worker package for more details.
-	feeConfig, _, err := chain.GetFeeConfigAt(parent)
+	feeConfig, err := feeConfigAt(chain, parent, header.Time)
 	if err != nil {
 		return err
 	}
@@ -330,7 +339,7 @@ func (eng *DummyEngine) Finalize(chain consensus.ChainHeaderReader, block *types
 	timestamp := block.Time()
 	// we use the parent to determine the fee config
 	// since the current block has not been finalized yet.
-	feeConfig, _, err := chain.GetFeeConfigAt(parent)
+	feeConfig, err := feeConfigAt(chain, parent, timestamp)
 	if err != nil {
 		return err
 	}
@@ -365,7 +374,7 @@ func (eng *DummyEngine) FinalizeAndAssemble(chain consensus.ChainHeaderReader, h
 ) (*types.Block, error) {
 	// we use the parent to determine the fee config
 	// since the current block has not been finalized yet.
-	feeConfig, _, err := chain.GetFeeConfigAt(parent)
+	feeConfig, err := feeConfigAt(chain, parent, header.Time)
 	if err != nil {
 		return nil, err
 	}
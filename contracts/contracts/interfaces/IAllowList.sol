@@ -3,6 +3,13 @@ pragma solidity ^0.8.24;
 
 interface IAllowList {
   event RoleSet(uint256 indexed role, address indexed account, address indexed sender, uint256 oldRole);
+  event RoleSetWithExpiry(
+    uint256 indexed role,
+    address indexed account,
+    address indexed sender,
+    uint256 oldRole,
+    uint256 expiry
+  );
 
   // Set [addr] to have the admin role over the precompile contract.
   function setAdmin(address addr) external;
@@ -16,6 +23,21 @@ interface IAllowList {
   // Set [addr] to have no role for the precompile contract.
   function setNone(address addr) external;
 
+  // Set [addr] to have [role] until the block timestamp reaches [expiry], after which it
+  // has no role. Setting a role through any other setter removes the expiry.
+  function setRoleWithExpiry(address addr, uint256 role, uint256 expiry) external;
+
   // Read the status of [addr].
   function readAllowList(address addr) external view returns (uint256 role);
+
+  // Read the status of [addr] and the timestamp at which it expires, or 0 if it does not expire.
+  function readAllowListWithExpiry(address addr) external view returns (uint256 role, uint256 expiry);
+
+  // Get the number of addresses holding [role]. Available once the role holder index is built.
+  // Addresses whose role has expired are counted until their role is set again.
+  function getRoleHolderCount(uint256 role) external view returns (uint256 count);
+
+  // Get at most [limit] of the addresses holding [role], starting from [offset].
+  // Available once the role holder index is built.
+  function getRoleHolders(uint256 role, uint256 offset, uint256 limit) external view returns (address[] memory holders);
 }
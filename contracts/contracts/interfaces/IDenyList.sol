@@ -0,0 +1,21 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.24;
+import "./IAllowList.sol";
+
+interface IDenyList is IAllowList {
+  // AccountFrozen is the event logged whenever an account is frozen
+  event AccountFrozen(address indexed account, address indexed sender);
+
+  // AccountUnfrozen is the event logged whenever an account is unfrozen
+  event AccountUnfrozen(address indexed account, address indexed sender);
+
+  // freeze prevents [account] from sending transactions and from being the recipient of one.
+  // The deny list, precompiles, system addresses, admins and managers cannot be frozen.
+  function freeze(address account) external;
+
+  // unfreeze lifts the freeze of [account]
+  function unfreeze(address account) external;
+
+  // isFrozen returns true if [account] is frozen
+  function isFrozen(address account) external view returns (bool frozen);
+}
@@ -14,6 +14,8 @@ interface IFeeManager is IAllowList {
     uint256 blockGasCostStep;
   }
   event FeeConfigChanged(address indexed sender, FeeConfig oldFeeConfig, FeeConfig newFeeConfig);
+  event FeeConfigScheduled(address indexed sender, FeeConfig feeConfig, uint256 effectiveTimestamp);
+  event PendingFeeConfigCancelled(address indexed sender, FeeConfig feeConfig, uint256 effectiveTimestamp);
 
   // Set fee config fields to contract storage
   function setFeeConfig(
@@ -44,4 +46,37 @@ interface IFeeManager is IAllowList {
 
   // Get the last block number changed the fee config from the contract storage
   function getFeeConfigLastChangedAt() external view returns (uint256 blockNumber);
+
+  // Schedule fee config fields to take effect from the first block with a timestamp
+  // of at least effectiveTimestamp, replacing any pending fee config
+  function scheduleFeeConfig(
+    uint256 gasLimit,
+    uint256 targetBlockRate,
+    uint256 minBaseFee,
+    uint256 targetGas,
+    uint256 baseFeeChangeDenominator,
+    uint256 minBlockGasCost,
+    uint256 maxBlockGasCost,
+    uint256 blockGasCostStep,
+    uint256 effectiveTimestamp
+  ) external;
+
+  // Get the pending fee config from the contract storage, effectiveTimestamp is zero if none is pending
+  function getPendingFeeConfig()
+    external
+    view
+    returns (
+      uint256 gasLimit,
+      uint256 targetBlockRate,
+      uint256 minBaseFee,
+      uint256 targetGas,
+      uint256 baseFeeChangeDenominator,
+      uint256 minBlockGasCost,
+      uint256 maxBlockGasCost,
+      uint256 blockGasCostStep,
+      uint256 effectiveTimestamp
+    );
+
+  // Cancel the pending fee config
+  function cancelPendingFeeConfig() external;
 }
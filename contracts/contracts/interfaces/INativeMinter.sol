@@ -4,6 +4,27 @@ import "./IAllowList.sol";
 
 interface INativeMinter is IAllowList {
   event NativeCoinMinted(address indexed sender, address indexed recipient, uint256 amount);
+  event MintCapChanged(address indexed sender, uint256 oldCap, uint256 newCap);
+  event MinterAllowanceChanged(address indexed sender, address indexed minter, bool limited, uint256 allowance);
+  event PeriodMintLimitChanged(address indexed sender, uint256 limit, uint256 periodSeconds);
   // Mint [amount] number of native coins and send to [addr]
   function mintNativeCoin(address addr, uint256 amount) external;
+
+  // Cap the total amount minted to [cap], where 0 removes the cap
+  function setMintCap(uint256 cap) external;
+  // Get the mint cap and the total amount minted, which is kept when the cap changes
+  function getMintCap() external view returns (uint256 cap, uint256 totalMinted);
+  // Limit the amount [minter] can mint to [allowance]
+  function setMinterAllowance(address minter, uint256 allowance) external;
+  // Remove the limit on the amount [minter] can mint
+  function removeMinterAllowance(address minter) external;
+  // Get whether the amount [minter] can mint is limited, and its remaining allowance
+  function getMinterAllowance(address minter) external view returns (bool limited, uint256 allowance);
+  // Limit the amount minted within every [periodSeconds] seconds to [limit], where 0 removes the limit
+  function setPeriodMintLimit(uint256 limit, uint256 periodSeconds) external;
+  // Get the period mint limit and the amount minted in the current period
+  function getPeriodMintLimit()
+    external
+    view
+    returns (uint256 limit, uint256 periodSeconds, uint256 periodStart, uint256 periodMinted);
 }
@@ -56,7 +56,7 @@ import (
 	"github.com/luxfi/geth/trie"
 	"github.com/luxfi/geth/triedb"
 	"github.com/luxfi/geth/triedb/hashdb"
-	"github.com/luxfi/geth/triedb/pathdb"
+	"github.com/luxfi/evm/triedb/pathdb"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/common/lru"
 	"github.com/luxfi/geth/event"
@@ -168,8 +168,9 @@ const (
 // cacheableFeeConfig encapsulates fee configuration itself and the block number that it has changed at,
 // in order to cache them together.
 type cacheableFeeConfig struct {
-	feeConfig     commontype.FeeConfig
-	lastChangedAt *big.Int
+	feeConfig          commontype.FeeConfig
+	lastChangedAt      *big.Int
+	scheduledFeeConfig *commontype.ScheduledFeeConfig
 }
 
 // cacheableCoinbaseConfig encapsulates coinbase address itself and allowFeeRecipient flag,
@@ -215,15 +216,20 @@ func (c *CacheConfig) triedbConfig() *triedb.Config {
 		}
 	}
 	if c.StateScheme == rawdb.PathScheme {
-		config.PathDB = &pathdb.Config{
-			StateHistory:   c.StateHistory,
-			CleanCacheSize: c.TrieCleanLimit * 1024 * 1024,
-			DirtyCacheSize: c.TrieDirtyLimit * 1024 * 1024,
-		}
+		config.DBOverride = c.pathdbConfig().BackendConstructor
 	}
 	return config
 }
 
+// pathdbConfig derives the configures for the path-based trie database.
+func (c *CacheConfig) pathdbConfig() pathdb.Config {
+	return pathdb.Config{
+		StateHistory:   c.StateHistory,
+		CleanCacheSize: c.TrieCleanLimit * 1024 * 1024,
+		DirtyCacheSize: c.TrieDirtyLimit * 1024 * 1024,
+	}
+}
+
 // DefaultCacheConfig are the default caching values if none are specified by the
 // user (also used during testing).
 var DefaultCacheConfig = &CacheConfig{
@@ -275,6 +281,7 @@ type BlockChain struct {
 	db           ethdb.Database   // Low level persistent database to store final content in
 	snaps        *snapshot.Tree   // Snapshot tree for fast trie leaf access
 	triedb       *triedb.Database // The database handler for maintaining trie nodes.
+	pathdb       *pathdb.Database // The path-based backend of triedb, nil in hash scheme
 	stateCache   state.Database   // State database to reuse between imports (contains state cache)
 	txIndexer    *txIndexer       // Transaction indexer, might be nil if not enabled
 	stateManager TrieWriter
@@ -371,8 +378,19 @@ func NewBlockChain(
 	if cacheConfig == nil {
 		return nil, errCacheConfigNotSpecified
 	}
-	// Open trie database with provided config
-	triedb := triedb.NewDatabase(db, cacheConfig.triedbConfig())
+	// Open trie database with provided config. The path-based backend is kept
+	// at hand to revert the persistent state with the state histories.
+	var (
+		pathBackend *pathdb.Database
+		tdbConfig   = cacheConfig.triedbConfig()
+	)
+	if cacheConfig.StateScheme == rawdb.PathScheme {
+		tdbConfig.DBOverride = func(diskdb ethdb.Database) *pathdb.Database {
+			pathBackend = cacheConfig.pathdbConfig().BackendConstructor(diskdb)
+			return pathBackend
+		}
+	}
+	triedb := triedb.NewDatabase(db, tdbConfig)
 
 	// Setup the genesis block, commit the provided genesis specification
 	// to database if the genesis block is not present yet, or load the
@@ -397,6 +415,7 @@ func NewBlockChain(
 		cacheConfig:         cacheConfig,
 		db:                  db,
 		triedb:              triedb,
+		pathdb:              pathBackend,
 		bodyCache:           lru.NewCache[common.Hash, *types.Body](bodyCacheLimit),
 		receiptsCache:       lru.NewCache[common.Hash, []*types.Receipt](receiptsCacheLimit),
 		blockCache:          lru.NewCache[common.Hash, *types.Block](blockCacheLimit),
@@ -642,6 +661,12 @@ func (bc *BlockChain) addAcceptorQueue(b *types.Block) {
 	bc.acceptorQueue <- b
 }
 
+// AcceptorQueueSize returns the number of accepted blocks waiting in the
+// [acceptorQueue] to be processed.
+func (bc *BlockChain) AcceptorQueueSize() int {
+	return len(bc.acceptorQueue)
+}
+
 // DrainAcceptorQueue blocks until all items in [acceptorQueue] have been
 // processed.
 func (bc *BlockChain) DrainAcceptorQueue() {
@@ -772,6 +797,15 @@ func (bc *BlockChain) ExportN(w io.Writer, first uint64, last uint64) error {
 	}, first, last)
 }
 
+// DatabaseSnapshotter is implemented by the chain databases able to take
+// point-in-time snapshots of themselves.
+type DatabaseSnapshotter interface {
+	// NewSnapshotDatabase returns a read-only view of the database at the time
+	// of the call, unaffected by the writes made afterwards. Closing it
+	// releases the snapshot.
+	NewSnapshotDatabase() (ethdb.Database, error)
+}
+
 // ExportCallback invokes [callback] for every block from [first] to [last] in order.
 func (bc *BlockChain) ExportCallback(callback func(block *types.Block) error, first uint64, last uint64) error {
 	if first > last {
@@ -779,13 +813,32 @@ func (bc *BlockChain) ExportCallback(callback func(block *types.Block) error, fi
 	}
 	log.Info("Exporting batch of blocks", "count", last-first+1)
 
+	// Read the blocks from a snapshot of the database if it supports them, so
+	// the blocks accepted while exporting don't race with the export.
+	getBlock := bc.GetBlockByNumber
+	if snapshotter, ok := bc.db.(DatabaseSnapshotter); ok {
+		db, err := snapshotter.NewSnapshotDatabase()
+		if err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
+		defer db.Close()
+
+		getBlock = func(number uint64) *types.Block {
+			hash := rawdb.ReadCanonicalHash(db, number)
+			if hash == (common.Hash{}) {
+				return nil
+			}
+			return rawdb.ReadBlock(db, hash, number)
+		}
+	}
+
 	var (
 		parentHash common.Hash
 		start      = time.Now()
 		reported   = time.Now()
 	)
 	for nr := first; nr <= last; nr++ {
-		block := bc.GetBlockByNumber(nr)
+		block := getBlock(nr)
 		if block == nil {
 			return fmt.Errorf("export failed on #%d: not found", nr)
 		}
@@ -1837,6 +1890,13 @@ func (bc *BlockChain) reprocessState(current *types.Block, reexec uint64) error
 			return fmt.Errorf("failed to get block for acceptor tip %s", acceptorTip)
 		}
 	}
+	// In path scheme, the persistent state may have been flushed beyond the blocks
+	// to re-process before an unclean shutdown, leaving none of their states
+	// available. Revert it with the state histories to the block re-processing
+	// starts from.
+	if err := bc.revertPathState(current); err != nil {
+		return err
+	}
 
 	for i := 0; i < int(reexec); i++ {
 		// TODO: handle canceled context
@@ -1937,6 +1997,38 @@ func (bc *BlockChain) reprocessState(current *types.Block, reexec uint64) error
 	return nil
 }
 
+// revertPathState reverts the persistent state of the path scheme to the
+// parent of [current], if its state isn't available but can be recovered from
+// the state histories.
+func (bc *BlockChain) revertPathState(current *types.Block) error {
+	if bc.pathdb == nil || current.NumberU64() == 0 {
+		return nil
+	}
+	parent := bc.GetBlock(current.ParentHash(), current.NumberU64()-1)
+	if parent == nil || bc.HasState(parent.Root()) || !bc.pathdb.Recoverable(parent.Root()) {
+		return nil
+	}
+	log.Info("Reverting persistent state", "number", parent.NumberU64(), "hash", parent.Hash(), "root", parent.Root())
+	if err := bc.pathdb.Recover(parent.Root(), &trieLoader{db: bc.triedb}); err != nil {
+		return fmt.Errorf("failed to revert persistent state to block %d: %w", parent.NumberU64(), err)
+	}
+	return nil
+}
+
+// trieLoader opens the tries of the trie database, for the path-based backend
+// to apply the state histories with.
+type trieLoader struct {
+	db *triedb.Database
+}
+
+func (l *trieLoader) OpenTrie(root common.Hash) (pathdb.Trie, error) {
+	return trie.New(trie.TrieID(root), l.db)
+}
+
+func (l *trieLoader) OpenStorageTrie(stateRoot common.Hash, addrHash, root common.Hash) (pathdb.Trie, error) {
+	return trie.New(trie.StorageTrieID(stateRoot, addrHash, root), l.db)
+}
+
 func (bc *BlockChain) protectTrieIndex() error {
 	if !bc.cacheConfig.Pruning {
 		return customrawdb.WritePruningDisabled(bc.db)
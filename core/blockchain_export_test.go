@@ -0,0 +1,73 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/luxfi/evm/consensus/dummy"
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/stretchr/testify/require"
+)
+
+var _ DatabaseSnapshotter = copyingDatabase{}
+
+// copyingDatabase takes its snapshots by copying the whole database.
+type copyingDatabase struct {
+	ethdb.Database
+}
+
+func (db copyingDatabase) NewSnapshotDatabase() (ethdb.Database, error) {
+	snap := rawdb.NewMemoryDatabase()
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if err := snap.Put(it.Key(), it.Value()); err != nil {
+			return nil, err
+		}
+	}
+	return snap, it.Error()
+}
+
+// TestExportFromSnapshot checks the export reads the blocks from a snapshot of
+// the chain database, unaffected by the writes made while it runs.
+func TestExportFromSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		chainDB = copyingDatabase{Database: rawdb.NewMemoryDatabase()}
+		engine  = dummy.NewCoinbaseFaker()
+		gspec   = &Genesis{Config: params.TestChainConfig}
+	)
+	_, chain, _, err := GenerateChainWithGenesis(gspec, engine, 3, 10, func(int, *BlockGen) {})
+	require.NoError(err)
+
+	blockchain, err := createBlockChain(chainDB, DefaultCacheConfig, gspec, common.Hash{})
+	require.NoError(err)
+	defer blockchain.Stop()
+
+	_, err = blockchain.InsertChain(chain)
+	require.NoError(err)
+	for _, block := range chain {
+		require.NoError(blockchain.Accept(block))
+	}
+	blockchain.DrainAcceptorQueue()
+
+	var exported []common.Hash
+	err = blockchain.ExportCallback(func(block *types.Block) error {
+		if block.NumberU64() == 1 {
+			// Overwrite the canonical hash of a block not exported yet.
+			rawdb.WriteCanonicalHash(chainDB, common.Hash{0xff}, 2)
+		}
+		exported = append(exported, block.Hash())
+		return nil
+	}, 1, 3)
+	require.NoError(err)
+	require.Equal([]common.Hash{chain[0].Hash(), chain[1].Hash(), chain[2].Hash()}, exported)
+	require.Equal(common.Hash{0xff}, rawdb.ReadCanonicalHash(chainDB, 2))
+}
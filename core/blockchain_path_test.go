@@ -0,0 +1,137 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/params/extras"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/crypto"
+	"github.com/luxfi/geth/ethdb"
+	ethparams "github.com/luxfi/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+var pathConfig = &CacheConfig{
+	TrieCleanLimit:            256,
+	TrieDirtyLimit:            256,
+	TrieDirtyCommitTarget:     20,
+	TriePrefetcherParallelism: 4,
+	Pruning:                   true,
+	CommitInterval:            4096,
+	SnapshotLimit:             256,
+	AcceptorQueueLimit:        64,
+	StateScheme:               rawdb.PathScheme,
+	StateHistory:              32,
+}
+
+func TestPathBlockChain(t *testing.T) {
+	createPathBlockChain := func(db ethdb.Database, gspec *Genesis, lastAcceptedHash common.Hash) (*BlockChain, error) {
+		return createBlockChain(db, pathConfig, gspec, lastAcceptedHash)
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			tt.testFunc(t, createPathBlockChain)
+		})
+	}
+}
+
+// TestPathSchemeReorgRestart checks the repository's path-based trie database
+// backs the chain: a reorg between two branches, a clean restart from the
+// journal, and an unclean restart that has to revert the persistent state.
+func TestPathSchemeReorgRestart(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = common.Address{0x02}
+		addr3   = common.Address{0x03}
+		chainDB = rawdb.NewMemoryDatabase()
+		signer  = types.HomesteadSigner{}
+		gspec   = &Genesis{
+			Config: params.WithExtra(
+				&params.ChainConfig{HomesteadBlock: new(big.Int)},
+				&extras.ChainConfig{FeeConfig: params.DefaultFeeConfig},
+			),
+			Alloc: types.GenesisAlloc{addr1: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	blockchain, err := createBlockChain(chainDB, pathConfig, gspec, common.Hash{})
+	require.NoError(err)
+	require.NotNil(blockchain.pathdb, "path scheme must use the repository pathdb")
+
+	transfer := func(to common.Address) func(int, *BlockGen) {
+		return func(i int, gen *BlockGen) {
+			tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(addr1), to, big.NewInt(10000), ethparams.TxGas, nil, nil), signer, key1)
+			require.NoError(err)
+			gen.AddTx(tx)
+		}
+	}
+	// Two competing branches from genesis, the first one paying addr2 and the
+	// second one addr3.
+	genDB, chainA, _, err := GenerateChainWithGenesis(gspec, blockchain.engine, 4, 10, transfer(addr2))
+	require.NoError(err)
+	_, chainB, _, err := GenerateChainWithGenesis(gspec, blockchain.engine, 3, 10, transfer(addr3))
+	require.NoError(err)
+
+	_, err = blockchain.InsertChain(chainA[:2])
+	require.NoError(err)
+	_, err = blockchain.InsertChain(chainB)
+	require.NoError(err)
+	require.NoError(blockchain.SetPreference(chainB[2]))
+	require.Equal(chainB[2].Hash(), blockchain.CurrentBlock().Hash())
+
+	// Reorg back to the first branch by accepting it.
+	for _, block := range chainA[:2] {
+		require.NoError(blockchain.Accept(block))
+	}
+	for _, block := range chainB {
+		require.NoError(blockchain.Reject(block))
+	}
+	_, err = blockchain.InsertChain(chainA[2:])
+	require.NoError(err)
+	for _, block := range chainA[2:] {
+		require.NoError(blockchain.Accept(block))
+	}
+	blockchain.DrainAcceptorQueue()
+
+	checkState := func(bc *BlockChain) {
+		head := bc.LastConsensusAcceptedBlock()
+		require.Equal(chainA[3].Hash(), head.Hash())
+		statedb, err := bc.StateAt(head.Root())
+		require.NoError(err)
+		require.Equal(uint64(4), statedb.GetNonce(addr1))
+		require.Equal(uint64(40000), statedb.GetBalance(addr2).Uint64())
+		require.True(statedb.GetBalance(addr3).IsZero())
+	}
+	checkState(blockchain)
+
+	// A clean restart loads the layers from the journal.
+	blockchain.Stop()
+	blockchain, err = createBlockChain(chainDB, pathConfig, gspec, chainA[3].Hash())
+	require.NoError(err)
+	checkState(blockchain)
+
+	// Flush the state of a block that's not accepted into the persistent state,
+	// and restart without journaling it. The persistent state has to be reverted
+	// with the state histories for the last accepted block to be re-processed.
+	chainC, _, err := GenerateChain(gspec.Config, chainA[3], blockchain.engine, genDB, 1, 10, transfer(addr3))
+	require.NoError(err)
+	_, err = blockchain.InsertChain(chainC)
+	require.NoError(err)
+	require.NoError(blockchain.triedb.Commit(chainC[0].Root(), false))
+	require.NoError(blockchain.triedb.Close())
+	blockchain.stopWithoutSaving()
+
+	blockchain, err = createBlockChain(chainDB, pathConfig, gspec, chainA[3].Hash())
+	require.NoError(err)
+	defer blockchain.Stop()
+	checkState(blockchain)
+}
@@ -27,6 +27,8 @@
 package core
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 	"github.com/luxfi/evm/commontype"
 	"github.com/luxfi/evm/consensus"
@@ -204,6 +206,7 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 		LondonBlock: bc.chainConfig.LondonBlock,
 		ShanghaiTime: bc.chainConfig.ShanghaiTime,
 		CancunTime: bc.chainConfig.CancunTime,
+		PragueTime: bc.chainConfig.PragueTime,
 	}
 	receipts := rawdb.ReadReceipts(bc.db, hash, *number, header.Time, ethConfig)
 	if receipts == nil {
@@ -280,6 +283,21 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 	return state.New(root, bc.stateCache, nil)
 }
 
+// HistoricState returns a state specified by the given root, rebuilt from the
+// state histories retained by the path scheme (see [CacheConfig.StateHistory]).
+// The rebuilt layers are kept by the trie database until its persistent state
+// moves on, so the returned state must only be read from. Live states are
+// served as they are.
+func (bc *BlockChain) HistoricState(root common.Hash) (*state.StateDB, error) {
+	if bc.pathdb == nil {
+		return nil, errors.New("historic state is only available in path scheme")
+	}
+	if _, err := bc.pathdb.HistoricReader(root, &trieLoader{db: bc.triedb}); err != nil {
+		return nil, err
+	}
+	return state.New(root, bc.stateCache, nil)
+}
+
 // Config retrieves the chain's fork configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
@@ -391,14 +409,41 @@ func (bc *BlockChain) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig
 		return config.FeeConfig, common.Big0, nil
 	}
 
+	stored, err := bc.getStoredFeeConfigAt(parent)
+	if err != nil {
+		return commontype.EmptyFeeConfig, nil, err
+	}
+	return stored.feeConfig, stored.lastChangedAt, nil
+}
+
+// GetScheduledFeeConfigAt returns the fee config scheduled in the FeeManager
+// precompile contract state at [parent], or nil if none is scheduled or
+// FeeManager is not activated at [parent].
+// The scheduled fee config takes effect from the first block with a timestamp
+// of at least its effective timestamp.
+func (bc *BlockChain) GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error) {
+	config := params.GetExtra(bc.Config())
+	if !config.IsSubnetEVM(parent.Time) || !config.IsPrecompileEnabled(feemanager.ContractAddress, parent.Time) {
+		return nil, nil
+	}
+	stored, err := bc.getStoredFeeConfigAt(parent)
+	if err != nil {
+		return nil, err
+	}
+	return stored.scheduledFeeConfig, nil
+}
+
+// getStoredFeeConfigAt returns the fee configs stored in the FeeManager
+// precompile contract state at [parent].
+func (bc *BlockChain) getStoredFeeConfigAt(parent *types.Header) (*cacheableFeeConfig, error) {
 	// try to return it from the cache
 	if cached, hit := bc.feeConfigCache.Get(parent.Root); hit {
-		return cached.feeConfig, cached.lastChangedAt, nil
+		return cached, nil
 	}
 
 	stateDB, err := bc.StateAt(parent.Root)
 	if err != nil {
-		return commontype.EmptyFeeConfig, nil, err
+		return nil, err
 	}
 
 	storedFeeConfig := feemanager.GetStoredFeeConfig(stateDB)
@@ -407,13 +452,22 @@ func (bc *BlockChain) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig
 	// However an external stateDB call can modify the contract state.
 	// This check is added to add a defense in-depth.
 	if err := storedFeeConfig.Verify(); err != nil {
-		return commontype.EmptyFeeConfig, nil, err
+		return nil, err
+	}
+	scheduledFeeConfig := feemanager.GetScheduledFeeConfig(stateDB)
+	if scheduledFeeConfig != nil {
+		if err := scheduledFeeConfig.FeeConfig.Verify(); err != nil {
+			return nil, fmt.Errorf("invalid scheduled fee config: %w", err)
+		}
+	}
+	cacheable := &cacheableFeeConfig{
+		feeConfig:          storedFeeConfig,
+		lastChangedAt:      feemanager.GetFeeConfigLastChangedAt(stateDB),
+		scheduledFeeConfig: scheduledFeeConfig,
 	}
-	lastChangedAt := feemanager.GetFeeConfigLastChangedAt(stateDB)
-	cacheable := &cacheableFeeConfig{feeConfig: storedFeeConfig, lastChangedAt: lastChangedAt}
 	// add it to the cache
 	bc.feeConfigCache.Add(parent.Root, cacheable)
-	return storedFeeConfig, lastChangedAt, nil
+	return cacheable, nil
 }
 
 // GetCoinbaseAt returns the configured coinbase address at [parent].
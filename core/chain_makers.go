@@ -292,6 +292,13 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to configure precompiles %w", err)
 		}
+		if config.IsPrague(b.header.Time) {
+			var (
+				blockContext = NewEVMBlockContext(b.header, cm, &b.header.Coinbase)
+				vmenv        = vm.NewEVM(blockContext, vm.TxContext{}, statedb, convertToEthChainConfig(config), vm.Config{})
+			)
+			ProcessParentBlockHash(b.header.ParentHash, vmenv, statedb)
+		}
 
 		// Execute any user modifications to the block
 		if gen != nil {
@@ -382,6 +389,11 @@ func (cm *chainMaker) makeHeader(parent *types.Block, gap uint64, state *state.S
 	if err != nil {
 		panic(err)
 	}
+	scheduledFeeConfig, err := cm.GetScheduledFeeConfigAt(parent.Header())
+	if err != nil {
+		panic(err)
+	}
+	feeConfig = header.FeeConfig(feeConfig, scheduledFeeConfig, time)
 	config := params.GetExtra(cm.config)
 	gasLimit, err := header.GasLimit(config, feeConfig, parent.Header(), time)
 	if err != nil {
@@ -502,6 +514,10 @@ func (cm *chainMaker) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig
 	return params.GetExtra(cm.config).FeeConfig, nil, nil
 }
 
+func (cm *chainMaker) GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error) {
+	return nil, nil
+}
+
 func (cm *chainMaker) GetCoinbaseAt(parent *types.Header) (common.Address, bool, error) {
 	return constants.BlackholeAddr, params.GetExtra(cm.config).AllowFeeRecipients, nil
 }
@@ -523,5 +539,6 @@ func convertToEthChainConfig(config *params.ChainConfig) *ethparams.ChainConfig
 		LondonBlock:         config.LondonBlock,
 		ShanghaiTime:        config.ShanghaiTime,
 		CancunTime:          config.CancunTime,
+		PragueTime:          config.PragueTime,
 	}
 }
@@ -21,7 +21,7 @@ var ErrMissingPredicateContext = errors.New("missing predicate context")
 func CheckPredicates(rules params.Rules, predicateContext *precompileconfig.PredicateContext, tx *types.Transaction) (map[common.Address][]byte, error) {
 	// Check that the transaction can cover its IntrinsicGas (including the gas required by the predicate) before
 	// verifying the predicate.
-	intrinsicGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, rules)
+	intrinsicGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.SetCodeAuthorizations(), tx.To() == nil, rules)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func CheckPredicates(rules params.Rules, predicateContext *precompileconfig.Pred
 		predicaterContract := rules.Predicaters[address]
 		bitset := set.NewBits()
 		for i, predicate := range predicates {
-			if err := predicaterContract.VerifyPredicate(predicateContext, predicate); err != nil {
+			if err := predicaterContract.VerifyPredicate(predicateContext, predicate, &rules); err != nil {
 				bitset.Add(i)
 			}
 		}
@@ -95,7 +95,7 @@ func TestCheckPredicate(t *testing.T) {
 			createPredicates: func(t testing.TB) map[common.Address]precompileconfig.Predicater {
 				predicater := precompileconfig.NewMockPredicater(gomock.NewController(t))
 				arg := common.Hash{1}
-				predicater.EXPECT().PredicateGas(arg[:]).Return(uint64(0), nil).Times(1)
+				predicater.EXPECT().PredicateGas(arg[:], gomock.Any()).Return(uint64(0), nil).Times(1)
 				return map[common.Address]precompileconfig.Predicater{
 					addr1: predicater,
 				}
@@ -118,7 +118,7 @@ func TestCheckPredicate(t *testing.T) {
 			createPredicates: func(t testing.TB) map[common.Address]precompileconfig.Predicater {
 				predicater := precompileconfig.NewMockPredicater(gomock.NewController(t))
 				arg := common.Hash{1}
-				predicater.EXPECT().PredicateGas(arg[:]).Return(uint64(0), nil).Times(1)
+				predicater.EXPECT().PredicateGas(arg[:], gomock.Any()).Return(uint64(0), nil).Times(1)
 				return map[common.Address]precompileconfig.Predicater{
 					addr1: predicater,
 				}
@@ -139,8 +139,8 @@ func TestCheckPredicate(t *testing.T) {
 			createPredicates: func(t testing.TB) map[common.Address]precompileconfig.Predicater {
 				predicater := precompileconfig.NewMockPredicater(gomock.NewController(t))
 				arg := common.Hash{1}
-				predicater.EXPECT().PredicateGas(arg[:]).Return(uint64(0), nil).Times(2)
-				predicater.EXPECT().VerifyPredicate(gomock.Any(), arg[:]).Return(nil)
+				predicater.EXPECT().PredicateGas(arg[:], gomock.Any()).Return(uint64(0), nil).Times(2)
+				predicater.EXPECT().VerifyPredicate(gomock.Any(), arg[:], gomock.Any()).Return(nil)
 				return map[common.Address]precompileconfig.Predicater{
 					addr1: predicater,
 				}
@@ -164,7 +164,7 @@ func TestCheckPredicate(t *testing.T) {
 			createPredicates: func(t testing.TB) map[common.Address]precompileconfig.Predicater {
 				predicater := precompileconfig.NewMockPredicater(gomock.NewController(t))
 				arg := common.Hash{1}
-				predicater.EXPECT().PredicateGas(arg[:]).Return(uint64(0), testErr)
+				predicater.EXPECT().PredicateGas(arg[:], gomock.Any()).Return(uint64(0), testErr)
 				return map[common.Address]precompileconfig.Predicater{
 					addr1: predicater,
 				}
@@ -185,8 +185,8 @@ func TestCheckPredicate(t *testing.T) {
 			createPredicates: func(t testing.TB) map[common.Address]precompileconfig.Predicater {
 				predicater := precompileconfig.NewMockPredicater(gomock.NewController(t))
 				arg := common.Hash{1}
-				predicater.EXPECT().PredicateGas(arg[:]).Return(uint64(0), nil).Times(2)
-				predicater.EXPECT().VerifyPredicate(gomock.Any(), arg[:]).Return(nil)
+				predicater.EXPECT().PredicateGas(arg[:], gomock.Any()).Return(uint64(0), nil).Times(2)
+				predicater.EXPECT().VerifyPredicate(gomock.Any(), arg[:], gomock.Any()).Return(nil)
 				return map[common.Address]precompileconfig.Predicater{
 					addr1: predicater,
 					addr2: predicater,
@@ -212,12 +212,12 @@ func TestCheckPredicate(t *testing.T) {
 				ctrl := gomock.NewController(t)
 				predicate1 := precompileconfig.NewMockPredicater(ctrl)
 				arg1 := common.Hash{1}
-				predicate1.EXPECT().PredicateGas(arg1[:]).Return(uint64(0), nil).Times(2)
-				predicate1.EXPECT().VerifyPredicate(gomock.Any(), arg1[:]).Return(nil)
+				predicate1.EXPECT().PredicateGas(arg1[:], gomock.Any()).Return(uint64(0), nil).Times(2)
+				predicate1.EXPECT().VerifyPredicate(gomock.Any(), arg1[:], gomock.Any()).Return(nil)
 				predicate2 := precompileconfig.NewMockPredicater(ctrl)
 				arg2 := common.Hash{2}
-				predicate2.EXPECT().PredicateGas(arg2[:]).Return(uint64(0), nil).Times(2)
-				predicate2.EXPECT().VerifyPredicate(gomock.Any(), arg2[:]).Return(testErr)
+				predicate2.EXPECT().PredicateGas(arg2[:], gomock.Any()).Return(uint64(0), nil).Times(2)
+				predicate2.EXPECT().VerifyPredicate(gomock.Any(), arg2[:], gomock.Any()).Return(testErr)
 				return map[common.Address]precompileconfig.Predicater{
 					addr1: predicate1,
 					addr2: predicate2,
@@ -276,7 +276,7 @@ func TestCheckPredicate(t *testing.T) {
 			createPredicates: func(t testing.TB) map[common.Address]precompileconfig.Predicater {
 				predicater := precompileconfig.NewMockPredicater(gomock.NewController(t))
 				arg := common.Hash{1}
-				predicater.EXPECT().PredicateGas(arg[:]).Return(uint64(1), nil)
+				predicater.EXPECT().PredicateGas(arg[:], gomock.Any()).Return(uint64(1), nil)
 				return map[common.Address]precompileconfig.Predicater{
 					addr1: predicater,
 				}
@@ -314,7 +314,7 @@ func TestCheckPredicate(t *testing.T) {
 				return
 			}
 			require.Equal(test.expectedRes, predicateRes)
-			intrinsicGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), true, rules)
+			intrinsicGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), nil, true, rules)
 			require.NoError(err)
 			require.Equal(tx.Gas(), intrinsicGas) // Require test specifies exact amount of gas consumed
 		})
@@ -424,17 +424,17 @@ func TestCheckPredicatesOutput(t *testing.T) {
 			// Create the rules from TestChainConfig and update the predicates based on the test params
 			rules := params.TestChainConfig.LuxRules(common.Big0, 0)
 			predicater := precompileconfig.NewMockPredicater(gomock.NewController(t))
-			predicater.EXPECT().PredicateGas(gomock.Any()).Return(uint64(0), nil).Times(len(test.testTuple))
+			predicater.EXPECT().PredicateGas(gomock.Any(), gomock.Any()).Return(uint64(0), nil).Times(len(test.testTuple))
 
 			var txAccessList types.AccessList
 			for _, tuple := range test.testTuple {
 				var predicateHash common.Hash
 				if tuple.isValidPredicate {
 					predicateHash = validHash
-					predicater.EXPECT().VerifyPredicate(gomock.Any(), validHash[:]).Return(nil)
+					predicater.EXPECT().VerifyPredicate(gomock.Any(), validHash[:], gomock.Any()).Return(nil)
 				} else {
 					predicateHash = invalidHash
-					predicater.EXPECT().VerifyPredicate(gomock.Any(), invalidHash[:]).Return(testErr)
+					predicater.EXPECT().VerifyPredicate(gomock.Any(), invalidHash[:], gomock.Any()).Return(testErr)
 				}
 				txAccessList = append(txAccessList, types.AccessTuple{
 					Address: tuple.address,
@@ -6,17 +6,30 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"math/big"
+	"slices"
 
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
 	"github.com/luxfi/geth/log"
 	"github.com/luxfi/evm/core/extstate"
 	"github.com/luxfi/evm/core/state"
 	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/precompile/allowlist"
 	"github.com/luxfi/evm/precompile/contract"
+	"github.com/luxfi/evm/precompile/contracts/feemanager"
 	"github.com/luxfi/evm/precompile/modules"
 	"github.com/luxfi/evm/stateupgrade"
 )
 
+var (
+	// HistoryStorageAddress is the address of the EIP-2935 history storage contract.
+	HistoryStorageAddress = common.HexToAddress("0x0000F90827F1C53a10cb7A02335B175320002935")
+	// HistoryStorageCode is the code of the EIP-2935 history storage contract.
+	HistoryStorageCode = hexutil.MustDecode("0x3373fffffffffffffffffffffffffffffffffffffffe14604657602036036042575f35600143038111604257611fff81430311604257611fff9006545f5260205ff35b5f5ffd5b5f35611fff60014303065500")
+)
+
 // ApplyPrecompileActivations checks if any of the precompiles specified by the chain config are enabled or disabled by the block
 // transition from `parentTimestamp` to the timestamp set in `blockContext`. If this is the case, it calls [modules.Module]'s Configure
 // to apply the necessary state transitions for the upgrade.
@@ -82,6 +95,36 @@ func applyStateUpgrades(c *params.ChainConfig, parentTimestamp *uint64, blockCon
 		if err := stateupgrade.Configure(&upgrade, c, extstatedb, blockContext); err != nil {
 			return fmt.Errorf("could not configure state upgrade: %w", err)
 		}
+		// Build the indexes in a deterministic order.
+		precompileAddrs := slices.SortedFunc(maps.Keys(upgrade.AllowListRoleIndexes), common.Address.Cmp)
+		for _, precompileAddr := range precompileAddrs {
+			// Index the addresses of the allow list configs activated so far,
+			// then the candidates, reporting the ones holding no role as they
+			// may point at a mistake in the upgrade.
+			for _, config := range configExtra.GetActivatingPrecompileConfigs(precompileAddr, nil, blockContext.Timestamp(), configExtra.PrecompileUpgrades) {
+				if lister, ok := config.(allowlist.AddressLister); ok {
+					allowlist.BuildRoleIndex(extstatedb, precompileAddr, lister.Addresses())
+				}
+			}
+			candidates := upgrade.AllowListRoleIndexes[precompileAddr]
+			if noRole := allowlist.BuildRoleIndex(extstatedb, precompileAddr, candidates); len(noRole) > 0 {
+				log.Warn("Allow list role index candidates hold no role",
+					"blockNumber", blockContext.Number(),
+					"precompile", precompileAddr,
+					"addresses", noRole,
+				)
+			}
+			// The index can't be checked against the stored roles, so log the
+			// counts for the operators to compare with the expected holders.
+			log.Info("Built allow list role index",
+				"blockNumber", blockContext.Number(),
+				"precompile", precompileAddr,
+				"candidates", len(candidates),
+				"admins", allowlist.GetRoleHolderCount(extstatedb, precompileAddr, allowlist.AdminRole, blockContext.Timestamp()),
+				"managers", allowlist.GetRoleHolderCount(extstatedb, precompileAddr, allowlist.ManagerRole, blockContext.Timestamp()),
+				"enabled", allowlist.GetRoleHolderCount(extstatedb, precompileAddr, allowlist.EnabledRole, blockContext.Timestamp()),
+			)
+		}
 	}
 	return nil
 }
@@ -89,6 +132,8 @@ func applyStateUpgrades(c *params.ChainConfig, parentTimestamp *uint64, blockCon
 // ApplyUpgrades checks if any of the precompile or state upgrades specified by the chain config are activated by the block
 // transition from [parentTimestamp] to the timestamp set in [header]. If this is the case, it calls [Configure]
 // to apply the necessary state transitions for the upgrade.
+// It also stores the fee config scheduled through the FeeManager precompile once it takes effect.
+// It also deploys the EIP-2935 history storage contract once Prague is active.
 // This function is called:
 // - in block processing to update the state when processing a block.
 // - in the miner to apply the state upgrades when producing a block.
@@ -96,7 +141,37 @@ func ApplyUpgrades(c *params.ChainConfig, parentTimestamp *uint64, blockContext
 	if err := ApplyPrecompileActivations(c, parentTimestamp, blockContext, statedb); err != nil {
 		return err
 	}
-	return applyStateUpgrades(c, parentTimestamp, blockContext, statedb)
+	if err := applyStateUpgrades(c, parentTimestamp, blockContext, statedb); err != nil {
+		return err
+	}
+	applyHistoryStorageDeployment(c, blockContext, statedb)
+	return applyScheduledFeeConfig(c, blockContext, statedb)
+}
+
+// applyHistoryStorageDeployment deploys the EIP-2935 history storage contract
+// once Prague is active if it is not deployed yet, so that the parent block hash
+// can be stored from the first Prague block onwards. This also covers chains
+// activating Prague at genesis.
+func applyHistoryStorageDeployment(c *params.ChainConfig, blockContext contract.ConfigurationBlockContext, statedb *state.StateDB) {
+	if !c.IsPrague(blockContext.Timestamp()) || statedb.GetCodeSize(HistoryStorageAddress) != 0 {
+		return
+	}
+	log.Info("Deploying history storage contract", "blockNumber", blockContext.Number())
+	statedb.SetNonce(HistoryStorageAddress, 1)
+	statedb.SetCode(HistoryStorageAddress, HistoryStorageCode)
+}
+
+// applyScheduledFeeConfig stores the fee config scheduled through the FeeManager precompile
+// if it takes effect by the timestamp set in [blockContext], so that it is in effect for the
+// transactions of the block.
+func applyScheduledFeeConfig(c *params.ChainConfig, blockContext contract.ConfigurationBlockContext, statedb *state.StateDB) error {
+	if !params.GetExtra(c).IsPrecompileEnabled(feemanager.ContractAddress, blockContext.Timestamp()) {
+		return nil
+	}
+	if err := feemanager.ApplyScheduledFeeConfig(extstate.New(statedb), blockContext); err != nil {
+		return fmt.Errorf("could not apply scheduled fee config: %w", err)
+	}
+	return nil
 }
 
 // BlockContext implements [contract.ConfigurationBlockContext].
@@ -4,6 +4,7 @@
 package core
 
 import (
+	"crypto/ecdsa"
 	"math/big"
 	"testing"
 
@@ -14,10 +15,18 @@ import (
 	"github.com/luxfi/geth/crypto"
 	ethparams "github.com/luxfi/geth/params"
 	"github.com/luxfi/evm/consensus/dummy"
+	"github.com/luxfi/evm/core/extstate"
+	"github.com/luxfi/evm/core/state"
 	"github.com/luxfi/evm/params"
 	"github.com/luxfi/evm/params/extras"
+	"github.com/luxfi/evm/plugin/evm/vmerrors"
+	"github.com/luxfi/evm/precompile/allowlist"
+	"github.com/luxfi/evm/precompile/contracts/denylist"
+	"github.com/luxfi/evm/precompile/contracts/deployerallowlist"
 	"github.com/luxfi/evm/precompile/contracts/txallowlist"
 	"github.com/luxfi/evm/utils"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
 )
 
 // TestBadTxAllowListBlock tests the output generated when the
@@ -104,3 +113,466 @@ func TestBadTxAllowListBlock(t *testing.T) {
 		}
 	}
 }
+
+// TestBadDenyListBlock tests that the blockchain rejects a block with a
+// transaction from or to an address frozen by the deny list.
+func TestBadDenyListBlock(t *testing.T) {
+	var (
+		testAddr   = common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+		frozenAddr = common.HexToAddress("0x0000000000000000000000000000000000000055")
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	)
+
+	for name, tt := range map[string]struct {
+		frozen  common.Address
+		to      common.Address
+		wantErr error
+	}{
+		"frozen sender": {
+			frozen:  testAddr,
+			to:      common.Address{},
+			wantErr: vmerrors.ErrSenderAddressFrozen,
+		},
+		"frozen recipient": {
+			frozen:  frozenAddr,
+			to:      frozenAddr,
+			wantErr: vmerrors.ErrRecipientAddressFrozen,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := params.WithExtra(
+				&params.ChainConfig{
+					ChainID:             big.NewInt(1),
+					HomesteadBlock:      big.NewInt(0),
+					EIP150Block:         big.NewInt(0),
+					EIP155Block:         big.NewInt(0),
+					EIP158Block:         big.NewInt(0),
+					ByzantiumBlock:      big.NewInt(0),
+					ConstantinopleBlock: big.NewInt(0),
+					PetersburgBlock:     big.NewInt(0),
+					IstanbulBlock:       big.NewInt(0),
+					MuirGlacierBlock:    big.NewInt(0),
+					BerlinBlock:         big.NewInt(0),
+					LondonBlock:         big.NewInt(0),
+				},
+				&extras.ChainConfig{
+					FeeConfig: params.DefaultFeeConfig,
+					NetworkUpgrades: extras.NetworkUpgrades{
+						SubnetEVMTimestamp: utils.NewUint64(0),
+					},
+					GenesisPrecompiles: extras.Precompiles{
+						denylist.ConfigKey: denylist.NewConfig(utils.NewUint64(0), nil, nil, nil, []common.Address{tt.frozen}),
+					},
+				},
+			)
+			gspec := &Genesis{
+				Config: config,
+				Alloc: GenesisAlloc{
+					testAddr: GenesisAccount{
+						Balance: big.NewInt(1000000000000000000), // 1 ether
+						Nonce:   0,
+					},
+				},
+				GasLimit: params.GetExtra(config).FeeConfig.GasLimit.Uint64(),
+			}
+			blockchain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfig, gspec, dummy.NewCoinbaseFaker(), vm.Config{}, common.Hash{}, false)
+			require.NoError(t, err)
+			defer blockchain.Stop()
+
+			tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+				Nonce:     0,
+				GasTipCap: big.NewInt(0),
+				GasFeeCap: big.NewInt(225000000000),
+				Gas:       ethparams.TxGas,
+				To:        &tt.to,
+				Value:     big.NewInt(0),
+			}), types.LatestSigner(config), testKey)
+			require.NoError(t, err)
+
+			block := GenerateBadBlock(gspec.ToBlock(), dummy.NewCoinbaseFaker(), types.Transactions{tx}, gspec.Config)
+			_, err = blockchain.InsertChain(types.Blocks{block})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// TestSetCodeTxGraniteTransition tests that set code transactions are rejected
+// before Granite and applied once Granite activates Prague, and that the history
+// storage contract records parent block hashes from the first Granite block.
+func TestSetCodeTxGraniteTransition(t *testing.T) {
+	const graniteTime = 20
+	var (
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+		delegate   = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+		config = params.WithExtra(
+			&params.ChainConfig{
+				ChainID:             big.NewInt(1),
+				HomesteadBlock:      big.NewInt(0),
+				EIP150Block:         big.NewInt(0),
+				EIP155Block:         big.NewInt(0),
+				EIP158Block:         big.NewInt(0),
+				ByzantiumBlock:      big.NewInt(0),
+				ConstantinopleBlock: big.NewInt(0),
+				PetersburgBlock:     big.NewInt(0),
+				IstanbulBlock:       big.NewInt(0),
+				MuirGlacierBlock:    big.NewInt(0),
+				BerlinBlock:         big.NewInt(0),
+				LondonBlock:         big.NewInt(0),
+			},
+			&extras.ChainConfig{
+				FeeConfig: params.DefaultFeeConfig,
+				NetworkUpgrades: extras.NetworkUpgrades{
+					SubnetEVMTimestamp: utils.NewUint64(0),
+					DurangoTimestamp:   utils.NewUint64(0),
+					GraniteTimestamp:   utils.NewUint64(graniteTime),
+				},
+			},
+		)
+	)
+	params.SetEthUpgrades(config, params.GetExtra(config).NetworkUpgrades)
+	require.Equal(t, utils.NewUint64(graniteTime), config.PragueTime)
+
+	var (
+		signer = types.LatestSigner(config.ToEthChainConfig())
+		gspec  = &Genesis{
+			Config: config,
+			Alloc: GenesisAlloc{
+				testAddr: GenesisAccount{
+					Balance: big.NewInt(1000000000000000000), // 1 ether
+					Nonce:   0,
+				},
+			},
+			GasLimit: params.GetExtra(config).FeeConfig.GasLimit.Uint64(),
+		}
+	)
+	mkSetCodeTx := func(nonce uint64) *types.Transaction {
+		// The sender nonce is incremented before the authorizations are applied,
+		// so a self-sponsored authorization uses the next nonce.
+		auth, err := types.SignSetCode(testKey, types.SetCodeAuthorization{
+			ChainID: *uint256.MustFromBig(config.ChainID),
+			Address: delegate,
+			Nonce:   nonce + 1,
+		})
+		require.NoError(t, err)
+		tx, err := types.SignNewTx(testKey, signer, &types.SetCodeTx{
+			ChainID:   uint256.MustFromBig(config.ChainID),
+			Nonce:     nonce,
+			GasTipCap: uint256.NewInt(0),
+			GasFeeCap: uint256.NewInt(225000000000),
+			Gas:       100_000,
+			To:        testAddr,
+			Value:     uint256.NewInt(0),
+			AuthList:  []types.SetCodeAuthorization{auth},
+		})
+		require.NoError(t, err)
+		return tx
+	}
+
+	t.Run("before granite", func(t *testing.T) {
+		blockchain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfig, gspec, dummy.NewCoinbaseFaker(), vm.Config{}, common.Hash{}, false)
+		require.NoError(t, err)
+		defer blockchain.Stop()
+
+		// The bad block is built 10 seconds after genesis, before Granite.
+		block := GenerateBadBlock(gspec.ToBlock(), dummy.NewCoinbaseFaker(), types.Transactions{mkSetCodeTx(0)}, gspec.Config)
+		_, err = blockchain.InsertChain(types.Blocks{block})
+		require.ErrorIs(t, err, types.ErrTxTypeNotSupported)
+	})
+
+	t.Run("after granite", func(t *testing.T) {
+		// Block 1 is built before Granite and block 2 activates it.
+		_, blocks, _, err := GenerateChainWithGenesis(gspec, dummy.NewCoinbaseFaker(), 2, 10, func(i int, b *BlockGen) {
+			if b.Timestamp() >= graniteTime {
+				b.AddTx(mkSetCodeTx(0))
+			}
+		})
+		require.NoError(t, err)
+
+		blockchain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfig, gspec, dummy.NewCoinbaseFaker(), vm.Config{}, common.Hash{}, false)
+		require.NoError(t, err)
+		defer blockchain.Stop()
+		_, err = blockchain.InsertChain(blocks)
+		require.NoError(t, err)
+
+		statedb, err := blockchain.StateAt(blocks[1].Root())
+		require.NoError(t, err)
+		require.Equal(t, types.AddressToDelegation(delegate), statedb.GetCode(testAddr))
+		require.Equal(t, uint64(2), statedb.GetNonce(testAddr))
+
+		require.Equal(t, HistoryStorageCode, statedb.GetCode(HistoryStorageAddress))
+		// The history storage contract stores the hash of block n-1 in slot (n-1) % 8191,
+		// so block 2 stores the hash of block 1 and nothing is stored for the genesis block.
+		require.Equal(t, blocks[0].Hash(), statedb.GetState(HistoryStorageAddress, common.BigToHash(big.NewInt(1))))
+		require.Equal(t, common.Hash{}, statedb.GetState(HistoryStorageAddress, common.Hash{}))
+	})
+}
+
+// TestDenyListInternalTransfers tests that frozen addresses can neither
+// receive value nor be called from inside a contract. The transaction is
+// included in the block, but its execution fails.
+func TestDenyListInternalTransfers(t *testing.T) {
+	var (
+		testAddr   = common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		forwarder  = common.HexToAddress("0x00000000000000000000000000000000000000f0")
+		frozenAddr = common.HexToAddress("0x0000000000000000000000000000000000000055")
+		frozenCode = common.HexToAddress("0x0000000000000000000000000000000000000056")
+		otherAddr  = common.HexToAddress("0x0000000000000000000000000000000000000057")
+	)
+
+	for name, tt := range map[string]struct {
+		target     common.Address
+		value      int64
+		wantStatus uint64
+	}{
+		"value forwarded to frozen address": {
+			target:     frozenAddr,
+			value:      1000,
+			wantStatus: types.ReceiptStatusFailed,
+		},
+		"frozen contract called": {
+			target:     frozenCode,
+			value:      0,
+			wantStatus: types.ReceiptStatusFailed,
+		},
+		"value forwarded to other address": {
+			target:     otherAddr,
+			value:      1000,
+			wantStatus: types.ReceiptStatusSuccessful,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := params.WithExtra(
+				&params.ChainConfig{
+					ChainID:             big.NewInt(1),
+					HomesteadBlock:      big.NewInt(0),
+					EIP150Block:         big.NewInt(0),
+					EIP155Block:         big.NewInt(0),
+					EIP158Block:         big.NewInt(0),
+					ByzantiumBlock:      big.NewInt(0),
+					ConstantinopleBlock: big.NewInt(0),
+					PetersburgBlock:     big.NewInt(0),
+					IstanbulBlock:       big.NewInt(0),
+					MuirGlacierBlock:    big.NewInt(0),
+					BerlinBlock:         big.NewInt(0),
+					LondonBlock:         big.NewInt(0),
+				},
+				&extras.ChainConfig{
+					FeeConfig: params.DefaultFeeConfig,
+					NetworkUpgrades: extras.NetworkUpgrades{
+						SubnetEVMTimestamp: utils.NewUint64(0),
+					},
+					GenesisPrecompiles: extras.Precompiles{
+						denylist.ConfigKey: denylist.NewConfig(utils.NewUint64(0), nil, nil, nil, []common.Address{frozenAddr, frozenCode}),
+					},
+				},
+			)
+			gspec := &Genesis{
+				Config: config,
+				Alloc: GenesisAlloc{
+					testAddr: GenesisAccount{
+						Balance: big.NewInt(1000000000000000000), // 1 ether
+					},
+					// Calls the address in the calldata forwarding the call value
+					forwarder: GenesisAccount{
+						Code:    common.FromHex("6000600060006000346000355af100"),
+						Balance: common.Big0,
+					},
+					frozenCode: GenesisAccount{
+						Code:    []byte{byte(vm.STOP)},
+						Balance: common.Big0,
+					},
+				},
+				GasLimit: params.GetExtra(config).FeeConfig.GasLimit.Uint64(),
+			}
+			engine := dummy.NewCoinbaseFaker()
+			_, blocks, receipts, err := GenerateChainWithGenesis(gspec, engine, 1, 10, func(i int, gen *BlockGen) {
+				tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+					ChainID:   config.ChainID,
+					Nonce:     gen.TxNonce(testAddr),
+					GasTipCap: big.NewInt(0),
+					GasFeeCap: big.NewInt(225000000000),
+					Gas:       100_000,
+					To:        &forwarder,
+					Value:     big.NewInt(tt.value),
+					Data:      common.LeftPadBytes(tt.target.Bytes(), 32),
+				}), types.LatestSigner(config), testKey)
+				require.NoError(t, err)
+				gen.AddTx(tx)
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, receipts[0][0].Status)
+
+			blockchain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+			require.NoError(t, err)
+			defer blockchain.Stop()
+			_, err = blockchain.InsertChain(blocks)
+			require.NoError(t, err)
+
+			statedb, err := blockchain.StateAt(blocks[0].Root())
+			require.NoError(t, err)
+			require.Equal(t, uint64(1), statedb.GetNonce(testAddr))
+			require.True(t, statedb.GetBalance(forwarder).IsZero())
+			if tt.wantStatus == types.ReceiptStatusFailed {
+				require.True(t, statedb.GetBalance(tt.target).IsZero())
+			} else {
+				require.Equal(t, uint64(tt.value), statedb.GetBalance(tt.target).Uint64())
+			}
+		})
+	}
+}
+
+// TestDeployerAllowListExpiredRole tests that a deployer can create contracts
+// until its role on the contract deployer allow list expires.
+func TestDeployerAllowListExpiredRole(t *testing.T) {
+	const expiry = 20
+	var (
+		adminKey, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		adminAddr      = crypto.PubkeyToAddress(adminKey.PublicKey)
+		deployerKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		deployerAddr   = crypto.PubkeyToAddress(deployerKey.PublicKey)
+
+		config = params.WithExtra(
+			&params.ChainConfig{
+				ChainID:             big.NewInt(1),
+				HomesteadBlock:      big.NewInt(0),
+				EIP150Block:         big.NewInt(0),
+				EIP155Block:         big.NewInt(0),
+				EIP158Block:         big.NewInt(0),
+				ByzantiumBlock:      big.NewInt(0),
+				ConstantinopleBlock: big.NewInt(0),
+				PetersburgBlock:     big.NewInt(0),
+				IstanbulBlock:       big.NewInt(0),
+				MuirGlacierBlock:    big.NewInt(0),
+				BerlinBlock:         big.NewInt(0),
+				LondonBlock:         big.NewInt(0),
+			},
+			&extras.ChainConfig{
+				FeeConfig: params.DefaultFeeConfig,
+				NetworkUpgrades: extras.NetworkUpgrades{
+					SubnetEVMTimestamp: utils.NewUint64(0),
+					DurangoTimestamp:   utils.NewUint64(0),
+					GraniteTimestamp:   utils.NewUint64(0),
+				},
+				GenesisPrecompiles: extras.Precompiles{
+					deployerallowlist.ConfigKey: deployerallowlist.NewConfig(utils.NewUint64(0), []common.Address{adminAddr}, nil, nil),
+				},
+			},
+		)
+	)
+	params.SetEthUpgrades(config, params.GetExtra(config).NetworkUpgrades)
+
+	var (
+		signer = types.LatestSigner(config.ToEthChainConfig())
+		gspec  = &Genesis{
+			Config: config,
+			Alloc: GenesisAlloc{
+				adminAddr:    GenesisAccount{Balance: big.NewInt(1000000000000000000)}, // 1 ether
+				deployerAddr: GenesisAccount{Balance: big.NewInt(1000000000000000000)}, // 1 ether
+			},
+			GasLimit: params.GetExtra(config).FeeConfig.GasLimit.Uint64(),
+		}
+		engine = dummy.NewCoinbaseFaker()
+	)
+	signTx := func(key *ecdsa.PrivateKey, nonce uint64, to *common.Address, data []byte) *types.Transaction {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(0),
+			GasFeeCap: big.NewInt(225000000000),
+			Gas:       100_000,
+			To:        to,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		return tx
+	}
+	var (
+		// Deploys a contract whose code is a single STOP
+		initCode = common.FromHex("60016000f3")
+		// Deploys a factory creating an empty contract whenever it is called
+		factoryCode = common.FromHex("67600060006000f00060005260086018f3")
+		factory     = crypto.CreateAddress(deployerAddr, 1)
+	)
+
+	// Block 1 is built before the role expires and block 2 when it does.
+	_, blocks, receipts, err := GenerateChainWithGenesis(gspec, engine, 2, 10, func(i int, b *BlockGen) {
+		if i == 0 {
+			input, err := allowlist.PackSetRoleWithExpiry(deployerAddr, allowlist.EnabledRole, expiry)
+			require.NoError(t, err)
+			b.AddTx(signTx(adminKey, b.TxNonce(adminAddr), &deployerallowlist.ContractAddress, input))
+			b.AddTx(signTx(deployerKey, b.TxNonce(deployerAddr), nil, initCode))
+			b.AddTx(signTx(deployerKey, b.TxNonce(deployerAddr), nil, factoryCode))
+			b.AddTx(signTx(deployerKey, b.TxNonce(deployerAddr), &factory, nil))
+			return
+		}
+		b.AddTx(signTx(deployerKey, b.TxNonce(deployerAddr), nil, initCode))
+		b.AddTx(signTx(deployerKey, b.TxNonce(deployerAddr), &factory, nil))
+	})
+	require.NoError(t, err)
+	for _, receipt := range receipts[0] {
+		require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	}
+	// Once the role expired, the deployer can deploy neither directly nor
+	// through the factory.
+	for _, receipt := range receipts[1] {
+		require.Equal(t, types.ReceiptStatusFailed, receipt.Status)
+		require.Equal(t, uint64(100_000), receipt.GasUsed)
+	}
+
+	blockchain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+	require.NoError(t, err)
+	defer blockchain.Stop()
+	_, err = blockchain.InsertChain(blocks)
+	require.NoError(t, err)
+
+	statedb, err := blockchain.StateAt(blocks[1].Root())
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), statedb.GetNonce(deployerAddr))
+	require.Len(t, statedb.GetCode(crypto.CreateAddress(deployerAddr, 0)), 1)
+	require.NotEmpty(t, statedb.GetCode(factory))
+	require.Empty(t, statedb.GetCode(crypto.CreateAddress(deployerAddr, 3)))
+	// Only the creation made by the factory before the expiry happened.
+	require.Equal(t, uint64(2), statedb.GetNonce(factory))
+	require.True(t, statedb.Exist(crypto.CreateAddress(factory, 1)))
+	require.False(t, statedb.Exist(crypto.CreateAddress(factory, 2)))
+}
+
+// TestApplyStateUpgradesRoleIndex checks the role index built by a state
+// upgrade includes the addresses of the allow list configs, besides the
+// candidates of the upgrade.
+func TestApplyStateUpgradesRoleIndex(t *testing.T) {
+	var (
+		admin   = common.Address{0xad}
+		enabled = common.Address{0xe1}
+		granted = common.Address{0xe2}
+		noRole  = common.Address{0xee}
+		config  = params.Copy(params.TestChainConfig)
+		extra   = params.GetExtra(&config)
+	)
+	extra.GenesisPrecompiles = extras.Precompiles{
+		txallowlist.ConfigKey: txallowlist.NewConfig(utils.NewUint64(0), []common.Address{admin}, []common.Address{enabled}, nil),
+	}
+	extra.StateUpgrades = []extras.StateUpgrade{{
+		BlockTimestamp: utils.NewUint64(10),
+		AllowListRoleIndexes: map[common.Address][]common.Address{
+			txallowlist.ContractAddress: {granted, noRole},
+		},
+	}}
+
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	require.NoError(t, ApplyUpgrades(&config, nil, NewBlockContext(big.NewInt(0), 0), statedb))
+	// The role granted after genesis is only known from the candidates.
+	txallowlist.SetTxAllowListStatus(extstate.New(statedb), granted, allowlist.EnabledRole)
+
+	parent := uint64(0)
+	require.NoError(t, ApplyUpgrades(&config, &parent, NewBlockContext(big.NewInt(1), 10), statedb))
+
+	extstatedb := extstate.New(statedb)
+	require.True(t, allowlist.IsRoleIndexBuilt(extstatedb, txallowlist.ContractAddress))
+	require.Equal(t, []common.Address{admin}, allowlist.GetRoleHolders(extstatedb, txallowlist.ContractAddress, allowlist.AdminRole, 0, 10, 10))
+	require.Equal(t, []common.Address{enabled, granted}, allowlist.GetRoleHolders(extstatedb, txallowlist.ContractAddress, allowlist.EnabledRole, 0, 10, 10))
+}
@@ -27,12 +27,18 @@
 package core
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"math/big"
 	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/core/vm"
 	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/plugin/evm/vmerrors"
+	"github.com/luxfi/evm/precompile/allowlist"
+	"github.com/luxfi/evm/precompile/contracts/denylist"
+	"github.com/luxfi/evm/precompile/contracts/deployerallowlist"
+	"github.com/luxfi/evm/precompile/contracts/txallowlist"
 	"github.com/luxfi/evm/precompile/precompileconfig"
 	"github.com/luxfi/evm/utils"
 	"github.com/luxfi/geth/common"
@@ -87,7 +93,7 @@ func (result *ExecutionResult) Revert() []byte {
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation bool, rules params.Rules) (uint64, error) {
+func IntrinsicGas(data []byte, accessList types.AccessList, authList []types.SetCodeAuthorization, isContractCreation bool, rules params.Rules) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if isContractCreation && rules.IsHomestead {
@@ -140,10 +146,33 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 		}
 		gas = totalGas
 	}
+	if authList != nil {
+		authGas := uint64(len(authList)) * ethparams.CallNewAccountGas
+		totalGas, overflow := cmath.SafeAdd(gas, authGas)
+		if overflow {
+			return 0, ErrGasUintOverflow
+		}
+		gas = totalGas
+	}
 
 	return gas, nil
 }
 
+// FloorDataGas computes the minimum gas required for a transaction based on its
+// data tokens (EIP-7623).
+func FloorDataGas(data []byte) (uint64, error) {
+	var (
+		z      = uint64(bytes.Count(data, []byte{0}))
+		nz     = uint64(len(data)) - z
+		tokens = nz*ethparams.TxTokenPerNonZeroByte + z
+	)
+	// Check for overflow
+	if (math.MaxUint64-ethparams.TxGas)/ethparams.TxCostFloorPerToken < tokens {
+		return 0, ErrGasUintOverflow
+	}
+	return ethparams.TxGas + tokens*ethparams.TxCostFloorPerToken, nil
+}
+
 func accessListGas(rules params.Rules, accessList types.AccessList) (uint64, error) {
 	var gas uint64
 	rulesExtra := params.GetRulesExtra(rules)
@@ -168,7 +197,7 @@ func accessListGas(rules params.Rules, accessList types.AccessList) (uint64, err
 			}
 			gas = totalGas
 		} else {
-			predicateGas, err := predicaterContract.PredicateGas(utils.HashSliceToBytes(accessTuple.StorageKeys))
+			predicateGas, err := predicaterContract.PredicateGas(utils.HashSliceToBytes(accessTuple.StorageKeys), &rules)
 			if err != nil {
 				return 0, err
 			}
@@ -208,6 +237,8 @@ type Message struct {
 	BlobGasFeeCap *big.Int
 	BlobHashes    []common.Hash
 
+	SetCodeAuthorizations []types.SetCodeAuthorization
+
 	// When SkipAccountChecks is true, the message nonce is not checked against the
 	// account nonce in state. It also disables checking that the sender is an EOA.
 	// This field will be set to true for operations like RPC eth_call.
@@ -229,6 +260,8 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 		SkipAccountChecks: false,
 		BlobHashes:        tx.BlobHashes(),
 		BlobGasFeeCap:     tx.BlobGasFeeCap(),
+
+		SetCodeAuthorizations: tx.SetCodeAuthorizations(),
 	}
 	// If baseFee provided, set gasPrice to effectiveGasPrice.
 	if baseFee != nil {
@@ -245,14 +278,15 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 }
 
 // ApplyMessage computes the new state by applying the given message
-// against the old state within the environment.
+// against the old state within the environment. The precompile-enforced rules
+// of [config], such as the tx allow list, are checked during the transition.
 //
 // ApplyMessage returns the bytes returned by any EVM execution (if it took place),
 // the gas used (which includes gas refunds) and an error if it failed. An error always
 // indicates a core error meaning that the message would always fail for that particular
 // state and would never be accepted within a block.
-func ApplyMessage(evm *vm.EVM, msg *Message, gp *GasPool) (*ExecutionResult, error) {
-	return NewStateTransition(evm, msg, gp).TransitionDb()
+func ApplyMessage(config *params.ChainConfig, evm *vm.EVM, msg *Message, gp *GasPool) (*ExecutionResult, error) {
+	return NewStateTransition(config, evm, msg, gp).TransitionDb()
 }
 
 // StateTransition represents a state transition.
@@ -288,15 +322,13 @@ type StateTransition struct {
 }
 
 // NewStateTransition initialises and returns a new state transition object.
-func NewStateTransition(evm *vm.EVM, msg *Message, gp *GasPool) *StateTransition {
+func NewStateTransition(config *params.ChainConfig, evm *vm.EVM, msg *Message, gp *GasPool) *StateTransition {
 	return &StateTransition{
-		gp:    gp,
-		evm:   evm,
-		msg:   msg,
-		state: evm.StateDB,
-		// config is set to nil here as we don't have access to luxfi ChainConfig
-		// from go-ethereum's EVM. This will need to be refactored.
-		config: nil,
+		gp:     gp,
+		evm:    evm,
+		msg:    msg,
+		state:  evm.StateDB,
+		config: config,
 	}
 }
 
@@ -363,22 +395,36 @@ func (st *StateTransition) preCheck() error {
 			return fmt.Errorf("%w: address %v, nonce: %d", ErrNonceMax,
 				msg.From.Hex(), stNonce)
 		}
-		// Make sure the sender is an EOA
-		codeHash := st.state.GetCodeHash(msg.From)
-		if codeHash != (common.Hash{}) && codeHash != types.EmptyCodeHash {
+		// Make sure the sender is an EOA, or an EOA delegated through EIP-7702
+		code := st.state.GetCode(msg.From)
+		_, delegated := types.ParseDelegation(code)
+		if len(code) > 0 && !delegated {
 			return fmt.Errorf("%w: address %v, codehash: %s", ErrSenderNoEOA,
-				msg.From.Hex(), codeHash)
+				msg.From.Hex(), st.state.GetCodeHash(msg.From))
+		}
+	}
+	// The precompile-enforced rules are checked for calls through the RPC as
+	// well, so that they fail like the transaction would in a block. Calls
+	// through the RPC without a sender are made from the zero address, whose
+	// roles are not checked.
+	checkSender := !msg.SkipAccountChecks || msg.From != (common.Address{})
+
+	// Check that the sender is on the tx allow list if enabled
+	if checkSender && st.config != nil && st.config.IsPrecompileEnabled(txallowlist.ContractAddress, st.evm.Context.Time) {
+		txAllowListRole := txallowlist.GetActiveTxAllowListStatus(st.state, msg.From, st.evm.Context.Time)
+		if !txAllowListRole.IsEnabled() {
+			return fmt.Errorf("%w: %s", vmerrors.ErrSenderAddressNotAllowListed, msg.From)
 		}
+	}
 
-		// Check that the sender is on the tx allow list if enabled
-		// TODO: This check is disabled because we don't have access to luxfi ChainConfig
-		// from go-ethereum's EVM. This needs to be refactored to pass the config properly.
-		// if st.config != nil && params.GetExtra(st.config).IsPrecompileEnabled(txallowlist.ContractAddress, st.evm.Context.Time) {
-		// 	txAllowListRole := txallowlist.GetTxAllowListStatus(st.state, msg.From)
-		// 	if !txAllowListRole.IsEnabled() {
-		// 		return fmt.Errorf("%w: %s", vmerrs.ErrSenderAddressNotAllowListed, msg.From)
-		// 	}
-		// }
+	// Check that neither the sender nor the recipient is frozen if the deny list is enabled
+	if st.config != nil && st.config.IsPrecompileEnabled(denylist.ContractAddress, st.evm.Context.Time) {
+		if checkSender && denylist.IsFrozen(st.state, msg.From) {
+			return fmt.Errorf("%w: %s", vmerrors.ErrSenderAddressFrozen, msg.From)
+		}
+		if msg.To != nil && denylist.IsFrozen(st.state, *msg.To) {
+			return fmt.Errorf("%w: %s", vmerrors.ErrRecipientAddressFrozen, msg.To)
+		}
 	}
 	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
 	if st.evm.ChainConfig().IsLondon(st.evm.Context.BlockNumber) {
@@ -422,6 +468,15 @@ func (st *StateTransition) preCheck() error {
 			}
 		}
 	}
+	// Check that EIP-7702 authorization list signatures are well formed.
+	if msg.SetCodeAuthorizations != nil {
+		if msg.To == nil {
+			return fmt.Errorf("%w (sender %v)", ErrSetCodeTxCreate, msg.From)
+		}
+		if len(msg.SetCodeAuthorizations) == 0 {
+			return fmt.Errorf("%w (sender %v)", ErrEmptyAuthList, msg.From)
+		}
+	}
 	// Check that the user is paying at least the current blob fee
 	if st.evm.ChainConfig().IsCancun(st.evm.Context.BlockNumber, st.evm.Context.Time) {
 		if st.blobGasUsed() > 0 {
@@ -461,6 +516,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	// 5. the purchased gas is enough to cover intrinsic usage
 	// 6. there is no overflow when calculating intrinsic gas
 	// 7. caller has enough balance to cover asset transfer for **topmost** call
+	// 8. the purchased gas is enough to cover the floor data gas (post prague)
 
 	// Check clauses 1-4, buy gas if everything is correct
 	if err := st.preCheck(); err != nil {
@@ -486,6 +542,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 			IsPetersburg:     ethConfig.IsPetersburg(st.evm.Context.BlockNumber),
 			IsIstanbul:       ethConfig.IsIstanbul(st.evm.Context.BlockNumber),
 			IsCancun:         ethConfig.IsCancun(st.evm.Context.BlockNumber, st.evm.Context.Time),
+			IsPrague:         ethConfig.IsPrague(st.evm.Context.BlockNumber, st.evm.Context.Time),
 			// Lux specific flags - default to false since we don't have luxfi config
 			IsSubnetEVM:      false,
 			IsDUpgrade:       false,
@@ -499,13 +556,24 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	)
 
 	// Check clauses 4-5, subtract intrinsic gas if everything is correct
-	gas, err := IntrinsicGas(msg.Data, msg.AccessList, contractCreation, rules)
+	gas, err := IntrinsicGas(msg.Data, msg.AccessList, msg.SetCodeAuthorizations, contractCreation, rules)
 	if err != nil {
 		return nil, err
 	}
 	if st.gasRemaining < gas {
 		return nil, fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, st.gasRemaining, gas)
 	}
+	// Gas limit suffices for the floor data cost (EIP-7623)
+	var floorDataGas uint64
+	if rules.IsPrague {
+		floorDataGas, err = FloorDataGas(msg.Data)
+		if err != nil {
+			return nil, err
+		}
+		if msg.GasLimit < floorDataGas {
+			return nil, fmt.Errorf("%w: have %d, want %d", ErrFloorDataGas, msg.GasLimit, floorDataGas)
+		}
+	}
 	st.gasRemaining -= gas
 
 	// Check clause 6
@@ -538,6 +606,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 			IsPetersburg:     rules.IsPetersburg,
 			IsIstanbul:       rules.IsIstanbul,
 			IsCancun:         rules.IsCancun,
+			IsPrague:         rules.IsPrague,
 		},
 	}
 	// Get precompiles for ethereum rules
@@ -550,19 +619,72 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	var (
 		ret   []byte
 		vmerr error // vm errors do not effect consensus and are therefore not assigned to err
+
+		// first transfer from or to a frozen address if the deny list is
+		// enabled, or contract creation by an origin without an active role on
+		// the deployer allow list
+		haltErr  error
+		snapshot int
+		nonce    = st.state.GetNonce(msg.From)
 	)
+	if st.config != nil && st.config.IsPrecompileEnabled(denylist.ContractAddress, st.evm.Context.Time) {
+		transfer := st.evm.Context.Transfer
+		st.evm.Context.Transfer = denyListTransfer(transfer, &haltErr)
+		defer func() { st.evm.Context.Transfer = transfer }()
+	}
+	if st.config != nil {
+		luxRules := st.config.LuxRules(st.evm.Context.BlockNumber, st.evm.Context.Time)
+		if luxRules.IsGraniteActivated() && luxRules.IsPrecompileEnabled(deployerallowlist.ContractAddress) {
+			stateDB := st.evm.StateDB
+			st.evm.StateDB = &deployerCheckStateDB{
+				StateDB: stateDB,
+				origin:  st.evm.TxContext.Origin,
+				time:    st.evm.Context.Time,
+				haltErr: &haltErr,
+			}
+			defer func() { st.evm.StateDB = stateDB }()
+		}
+	}
 	if contractCreation {
+		snapshot = st.state.Snapshot()
 		ret, _, st.gasRemaining, vmerr = st.evm.Create(vm.AccountRef(sender), msg.Data, st.gasRemaining, value)
 	} else {
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From, st.state.GetNonce(msg.From)+1)
+
+		// Apply EIP-7702 authorizations.
+		if msg.SetCodeAuthorizations != nil {
+			for _, auth := range msg.SetCodeAuthorizations {
+				// Note errors are ignored, we simply skip invalid authorizations here.
+				st.applyAuthorization(&auth)
+			}
+		}
+
+		// Perform convenience warming of sender's delegation target. Although the
+		// sender is already warmed in Prepare(..), it's possible a delegation to
+		// the account was deployed during this transaction. To handle correctly,
+		// simply wait until the final state of delegations is determined before
+		// performing the resolution and warming.
+		if addr, ok := types.ParseDelegation(st.state.GetCode(*msg.To)); ok {
+			st.state.AddAddressToAccessList(addr)
+		}
+
+		snapshot = st.state.Snapshot()
 		ret, st.gasRemaining, vmerr = st.evm.Call(vm.AccountRef(sender), st.to(), msg.Data, st.gasRemaining, value)
 	}
+	// A transfer involving a frozen address or a contract creation not allowed
+	// by the deployer allow list, at any depth, fails the whole execution,
+	// consuming all gas as an exceptional halt would.
+	if haltErr != nil {
+		st.state.RevertToSnapshot(snapshot)
+		st.state.SetNonce(msg.From, nonce+1)
+		ret, st.gasRemaining, vmerr = nil, 0, haltErr
+	}
 	price, overflow := uint256.FromBig(msg.GasPrice)
 	if overflow {
 		return nil, ErrGasUintOverflow
 	}
-	gasRefund := st.refundGas(rulesExtra.IsSubnetEVM)
+	gasRefund := st.refundGas(rulesExtra.IsSubnetEVM, floorDataGas)
 	fee := new(uint256.Int).SetUint64(st.gasUsed())
 	fee.Mul(fee, price)
 	st.state.AddBalance(st.evm.Context.Coinbase, fee)
@@ -575,7 +697,107 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	}, nil
 }
 
-func (st *StateTransition) refundGas(subnetEVM bool) uint64 {
+// deployerCheckStateDB wraps the StateDB of the EVM to enforce the contract
+// deployer allow list from Granite. Every contract creation of the transaction,
+// including the ones made by contracts, requires the transaction origin to hold
+// an active role at the time of the creation, so that a role stops allowing
+// deployments through factories as well once it expires. The first creation
+// not allowed is recorded in [haltErr].
+type deployerCheckStateDB struct {
+	vm.StateDB
+	origin  common.Address
+	time    uint64
+	haltErr *error
+}
+
+// CreateContract implements vm.StateDB
+func (db *deployerCheckStateDB) CreateContract(addr common.Address) {
+	if *db.haltErr == nil && !allowlist.GetActiveAllowListStatus(db.StateDB, deployerallowlist.ContractAddress, db.origin, db.time).IsEnabled() {
+		*db.haltErr = fmt.Errorf("%w: %s", vmerrors.ErrDeployerNotAllowListed, db.origin)
+	}
+	db.StateDB.CreateContract(addr)
+}
+
+// denyListTransfer wraps [transfer] so that no value is moved from or to an
+// address frozen by the deny list, recording the first such transfer in
+// [frozenErr] instead. The EVM transfers on every call and contract creation,
+// including the ones without value, so frozen addresses cannot be called from
+// inside a contract either. Static and delegate calls move no value and are
+// not affected.
+func denyListTransfer(transfer vm.TransferFunc, frozenErr *error) vm.TransferFunc {
+	return func(db vm.StateDB, sender, recipient common.Address, amount *uint256.Int) {
+		switch {
+		case *frozenErr != nil:
+			// The execution is reverted anyway
+		case denylist.IsFrozen(db, sender):
+			*frozenErr = fmt.Errorf("%w: %s", vmerrors.ErrSenderAddressFrozen, sender)
+		case denylist.IsFrozen(db, recipient):
+			*frozenErr = fmt.Errorf("%w: %s", vmerrors.ErrRecipientAddressFrozen, recipient)
+		default:
+			transfer(db, sender, recipient, amount)
+		}
+	}
+}
+
+// validateAuthorization validates an EIP-7702 authorization against the state.
+func (st *StateTransition) validateAuthorization(auth *types.SetCodeAuthorization) (authority common.Address, err error) {
+	// Verify chain ID is null or equal to current chain ID.
+	if !auth.ChainID.IsZero() && auth.ChainID.CmpBig(st.evm.ChainConfig().ChainID) != 0 {
+		return authority, ErrAuthorizationWrongChainID
+	}
+	// Limit nonce to 2^64-1 per EIP-2681.
+	if auth.Nonce+1 < auth.Nonce {
+		return authority, ErrAuthorizationNonceOverflow
+	}
+	// Validate signature values and recover authority.
+	authority, err = auth.Authority()
+	if err != nil {
+		return authority, fmt.Errorf("%w: %v", ErrAuthorizationInvalidSignature, err)
+	}
+	// Check the authority account
+	//  1) doesn't have code or has existing delegation
+	//  2) matches the auth's nonce
+	//
+	// Note it is added to the access list even if the authorization is invalid.
+	st.state.AddAddressToAccessList(authority)
+	code := st.state.GetCode(authority)
+	if _, ok := types.ParseDelegation(code); len(code) != 0 && !ok {
+		return authority, ErrAuthorizationDestinationHasCode
+	}
+	if have := st.state.GetNonce(authority); have != auth.Nonce {
+		return authority, ErrAuthorizationNonceMismatch
+	}
+	return authority, nil
+}
+
+// applyAuthorization applies an EIP-7702 code delegation to the state.
+func (st *StateTransition) applyAuthorization(auth *types.SetCodeAuthorization) error {
+	authority, err := st.validateAuthorization(auth)
+	if err != nil {
+		return err
+	}
+
+	// If the account already exists in state, refund the new account cost
+	// charged in the intrinsic calculation.
+	if st.state.Exist(authority) {
+		st.state.AddRefund(ethparams.CallNewAccountGas - ethparams.TxAuthTupleGas)
+	}
+
+	// Update nonce and account code.
+	st.state.SetNonce(authority, auth.Nonce+1)
+	if auth.Address == (common.Address{}) {
+		// Delegation to zero address means clear.
+		st.state.SetCode(authority, nil)
+		return nil
+	}
+
+	// Otherwise install delegation to auth.Address.
+	st.state.SetCode(authority, types.AddressToDelegation(auth.Address))
+
+	return nil
+}
+
+func (st *StateTransition) refundGas(subnetEVM bool, floorDataGas uint64) uint64 {
 	var refund uint64
 	// Inspired by: https://gist.github.com/holiman/460f952716a74eeb9ab358bb1836d821#gistcomment-3642048
 	if !subnetEVM {
@@ -586,6 +808,10 @@ func (st *StateTransition) refundGas(subnetEVM bool) uint64 {
 		}
 		st.gasRemaining += refund
 	}
+	// Make sure the gas used is at least the floor data gas (EIP-7623).
+	if st.gasUsed() < floorDataGas {
+		st.gasRemaining = st.initialGas - floorDataGas
+	}
 
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := uint256.NewInt(st.gasRemaining)
@@ -411,7 +411,8 @@ func (p *BlobPool) Init(gasTip uint64, head *types.Header, reserve txpool.Addres
 	for addr := range p.index {
 		p.recheck(addr, nil)
 	}
-	feeConfig, _, err := p.chain.GetFeeConfigAt(p.head)
+	timestamp := uint64(time.Now().Unix())
+	feeConfig, _, err := header.FeeConfigAt(p.chain, p.head, timestamp)
 	if err != nil {
 		p.Close()
 		return err
@@ -420,7 +421,7 @@ func (p *BlobPool) Init(gasTip uint64, head *types.Header, reserve txpool.Addres
 		params.GetExtra(p.chain.Config()),
 		feeConfig,
 		p.head,
-		uint64(time.Now().Unix()),
+		timestamp,
 	)
 	if err != nil {
 		p.Close()
@@ -847,7 +848,8 @@ func (p *BlobPool) Reset(oldHead, newHead *types.Header) {
 	if p.chain.Config().IsCancun(p.head.Time) {
 		p.limbo.finalize(p.chain.CurrentFinalBlock())
 	}
-	feeConfig, _, err := p.chain.GetFeeConfigAt(p.head)
+	timestamp := uint64(time.Now().Unix())
+	feeConfig, _, err := header.FeeConfigAt(p.chain, p.head, timestamp)
 	if err != nil {
 		log.Error("Failed to get fee config to reset blobpool fees", "err", err)
 		return
@@ -856,7 +858,7 @@ func (p *BlobPool) Reset(oldHead, newHead *types.Header) {
 		params.GetExtra(p.chain.Config()),
 		feeConfig,
 		p.head,
-		uint64(time.Now().Unix()),
+		timestamp,
 	)
 	if err != nil {
 		log.Error("Failed to estimate next base fee to reset blobpool fees", "err", err)
@@ -179,6 +179,10 @@ func (bc *testBlockChain) GetFeeConfigAt(header *types.Header) (commontype.FeeCo
 	return params.GetExtra(bc.config).FeeConfig, nil, nil
 }
 
+func (bc *testBlockChain) GetScheduledFeeConfigAt(header *types.Header) (*commontype.ScheduledFeeConfig, error) {
+	return nil, nil
+}
+
 // makeAddressReserver is a utility method to sanity check that accounts are
 // properly reserved by the blobpool (no duplicate reserves or unreserves).
 func makeAddressReserver() txpool.AddressReserver {
@@ -56,4 +56,5 @@ type BlockChain interface {
 	StateAt(root common.Hash) (*state.StateDB, error)
 
 	GetFeeConfigAt(header *types.Header) (commontype.FeeConfig, *big.Int, error)
+	GetScheduledFeeConfigAt(header *types.Header) (*commontype.ScheduledFeeConfig, error)
 }
@@ -42,7 +42,6 @@ import (
 	"github.com/luxfi/geth/event"
 	"github.com/luxfi/geth/log"
 	"github.com/luxfi/geth/metrics"
-	ethparams "github.com/luxfi/geth/params"
 	"github.com/luxfi/evm/commontype"
 	"github.com/luxfi/evm/core"
 	"github.com/luxfi/evm/core/state"
@@ -146,6 +145,7 @@ type BlockChain interface {
 
 	SenderCacher() *core.TxSenderCacher
 	GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error)
+	GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error)
 }
 
 // Config are the configuration parameters of the transaction pool.
@@ -168,7 +168,7 @@ type Config struct {
 
 // DefaultConfig contains the default configurations for the transaction pool.
 var DefaultConfig = Config{
-	// If we re-enable txpool journaling, we should also add the saved local
+	// Journaling is enabled by the VM, which also adds the saved local
 	// transactions to the p2p gossip on startup.
 	Journal:   "",
 	Rejournal: time.Hour,
@@ -284,7 +284,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		config:              config,
 		chain:               chain,
 		chainconfig:         chain.Config(),
-		signer:              types.LatestSigner(&ethparams.ChainConfig{ChainID: chain.Config().ChainID}),
+		signer:              types.LatestSigner(chain.Config().ToEthChainConfig()),
 		pending:             make(map[common.Address]*list),
 		queue:               make(map[common.Address]*list),
 		beats:               make(map[common.Address]time.Time),
@@ -304,17 +304,19 @@ func New(config Config, chain BlockChain) *LegacyPool {
 	}
 	pool.priced = newPricedList(pool.all)
 
-	if !config.NoLocals && config.Journal != "" {
+	// Transactions from the configured local accounts are journaled even if
+	// local transaction handling is otherwise disabled.
+	if (!config.NoLocals || len(config.Locals) > 0) && config.Journal != "" {
 		pool.journal = newTxJournal(config.Journal)
 	}
 	return pool
 }
 
 // Filter returns whether the given transaction can be consumed by the legacy
-// pool, specifically, whether it is a Legacy, AccessList or Dynamic transaction.
+// pool, specifically, whether it is a Legacy, AccessList, Dynamic or SetCode transaction.
 func (pool *LegacyPool) Filter(tx *types.Transaction) bool {
 	switch tx.Type() {
-	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType:
+	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.SetCodeTxType:
 		return true
 	default:
 		return false
@@ -679,7 +681,8 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 		Accept: 0 |
 			1<<types.LegacyTxType |
 			1<<types.AccessListTxType |
-			1<<types.DynamicFeeTxType,
+			1<<types.DynamicFeeTxType |
+			1<<types.SetCodeTxType,
 		MaxSize: txMaxSize,
 		MinTip:  pool.gasTip.Load().ToBig(),
 	}
@@ -702,6 +705,7 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 			pool.currentHead.Load().Time,
 		),
 		MinimumFee: pool.minimumFee,
+		Time:       pool.currentHead.Load().Time,
 
 		FirstNonceGap: nil, // Pool allows arbitrary arrival order, don't invalidate nonce gaps
 		UsedAndLeftSlots: func(addr common.Address) (int, int) {
@@ -1514,7 +1518,7 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	// so that we can correctly drop txs with < minBaseFee from tx pool.
 	chainConfig := params.GetExtra(pool.chainconfig)
 	if chainConfig.IsPrecompileEnabled(feemanager.ContractAddress, newHead.Time) {
-		feeConfig, _, err := pool.chain.GetFeeConfigAt(newHead)
+		feeConfig, _, err := header.FeeConfigAt(pool.chain, newHead, uint64(time.Now().Unix()))
 		if err != nil {
 			log.Error("Failed to get fee config state", "err", err, "root", newHead.Root)
 			return
@@ -1846,12 +1850,13 @@ func (pool *LegacyPool) updateBaseFee() {
 // assumes lock is already held
 // should only be called when the chain is in Lux EVM.
 func (pool *LegacyPool) updateBaseFeeAt(head *types.Header) error {
-	feeConfig, _, err := pool.chain.GetFeeConfigAt(head)
+	timestamp := uint64(time.Now().Unix())
+	feeConfig, _, err := header.FeeConfigAt(pool.chain, head, timestamp)
 	if err != nil {
 		return err
 	}
 	chainConfig := params.GetExtra(pool.chainconfig)
-	baseFeeEstimate, err := header.EstimateNextBaseFee(chainConfig, feeConfig, head, uint64(time.Now().Unix()))
+	baseFeeEstimate, err := header.EstimateNextBaseFee(chainConfig, feeConfig, head, timestamp)
 	if err != nil {
 		return err
 	}
@@ -34,6 +34,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -134,6 +135,10 @@ func (bc *testBlockChain) GetFeeConfigAt(parent *types.Header) (commontype.FeeCo
 	return testFeeConfig, common.Big0, nil
 }
 
+func (bc *testBlockChain) GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error) {
+	return nil, nil
+}
+
 func (bc *testBlockChain) SenderCacher() *core.TxSenderCacher {
 	// Zero threads avoids starting goroutines.
 	return core.NewTxSenderCacher(0)
@@ -2501,6 +2506,57 @@ func testJournaling(t *testing.T, nolocals bool) {
 	pool.Close()
 }
 
+// TestJournalingConfiguredLocals tests that the transactions of the configured
+// local accounts are journaled even if local transaction handling is disabled.
+func TestJournalingConfiguredLocals(t *testing.T) {
+	t.Parallel()
+
+	journal := filepath.Join(t.TempDir(), "transactions.rlp")
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	local, _ := crypto.GenerateKey()
+	remote, _ := crypto.GenerateKey()
+
+	config := testTxPoolConfig
+	config.NoLocals = true
+	config.Locals = []common.Address{crypto.PubkeyToAddress(local.PublicKey)}
+	config.Journal = journal
+	config.Rejournal = time.Second
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+
+	testAddBalance(pool, crypto.PubkeyToAddress(local.PublicKey), big.NewInt(1000000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(1), local)); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(1), remote)); err != nil {
+		t.Fatalf("failed to add remote transaction: %v", err)
+	}
+	pool.Close()
+
+	// Only the transaction of the configured local account should survive
+	blockchain = newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+	pool = New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	pending, queued := pool.Stats()
+	if pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	if queued != 0 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 0)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // TestStatusCheck tests that the pool can correctly retrieve the
 // pending status of individual transactions.
 func TestStatusCheck(t *testing.T) {
@@ -0,0 +1,99 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txpool
+
+import (
+	"sync"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+)
+
+// privateTxs tracks the transactions submitted privately to this node. Private
+// transactions are kept out of the transaction gossip and the pool content feeds,
+// so they only make it into the blocks built by this node, until they expire.
+type privateTxs struct {
+	lock   sync.RWMutex
+	added  map[common.Hash]uint64 // Block height each private transaction was added at
+	expiry uint64                 // Number of blocks after which private transactions are made public, 0 to never expire
+}
+
+func newPrivateTxs() *privateTxs {
+	return &privateTxs{
+		added: make(map[common.Hash]uint64),
+	}
+}
+
+// setExpiry sets the number of blocks after which private transactions are made
+// public.
+func (p *privateTxs) setExpiry(blocks uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.expiry = blocks
+}
+
+// add marks the given transaction hash as private from the given block height.
+func (p *privateTxs) add(hash common.Hash, number uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.added[hash] = number
+}
+
+// remove unmarks the given transaction hash as private.
+func (p *privateTxs) remove(hash common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.added, hash)
+}
+
+// contains returns whether the given transaction hash is private.
+func (p *privateTxs) contains(hash common.Hash) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.added[hash]
+	return ok
+}
+
+// filter returns the transactions of txs that are not private. The given slice
+// is not modified.
+func (p *privateTxs) filter(txs []*types.Transaction) []*types.Transaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if len(p.added) == 0 {
+		return txs
+	}
+	public := make([]*types.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if _, ok := p.added[tx.Hash()]; !ok {
+			public = append(public, tx)
+		}
+	}
+	return public
+}
+
+// expire unmarks the private transactions that are no longer known by the pool
+// as reported by [known], and the ones that were added at least [expiry] blocks
+// before the given block height. The hashes of the expired transactions still
+// known by the pool are returned.
+func (p *privateTxs) expire(number uint64, known func(common.Hash) bool) []common.Hash {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var expired []common.Hash
+	for hash, added := range p.added {
+		switch {
+		case !known(hash):
+			delete(p.added, hash)
+		case p.expiry != 0 && number >= added+p.expiry:
+			delete(p.added, hash)
+			expired = append(expired, hash)
+		}
+	}
+	return expired
+}
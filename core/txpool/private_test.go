@@ -0,0 +1,62 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txpool
+
+import (
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateTxsFilter(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		public  = types.NewTx(&types.LegacyTx{Nonce: 0})
+		private = types.NewTx(&types.LegacyTx{Nonce: 1})
+		txs     = []*types.Transaction{public, private}
+		p       = newPrivateTxs()
+	)
+	require.Equal(txs, p.filter(txs))
+
+	p.add(private.Hash(), 1)
+	require.True(p.contains(private.Hash()))
+	require.False(p.contains(public.Hash()))
+	require.Equal([]*types.Transaction{public}, p.filter(txs))
+	require.Len(txs, 2) // The given slice must not be modified
+
+	p.remove(private.Hash())
+	require.Equal(txs, p.filter(txs))
+}
+
+func TestPrivateTxsExpire(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		p       = newPrivateTxs()
+		old     = common.Hash{1}
+		recent  = common.Hash{2}
+		dropped = common.Hash{3}
+		known   = func(hash common.Hash) bool { return hash != dropped }
+	)
+	p.add(old, 1)
+	p.add(recent, 5)
+	p.add(dropped, 5)
+
+	// Without an expiry only the transactions no longer in the pool are forgotten
+	require.Empty(p.expire(100, known))
+	require.True(p.contains(old))
+	require.True(p.contains(recent))
+	require.False(p.contains(dropped))
+
+	p.setExpiry(5)
+	require.Empty(p.expire(5, known))
+	require.Equal([]common.Hash{old}, p.expire(6, known))
+	require.False(p.contains(old))
+	require.True(p.contains(recent))
+	require.Equal([]common.Hash{recent}, p.expire(10, known))
+	require.False(p.contains(recent))
+}
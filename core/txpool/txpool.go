@@ -107,6 +107,10 @@ type TxPool struct {
 
 	gasTip    atomic.Pointer[big.Int] // Remember last value set so it can be retrieved
 	reorgFeed event.Feed
+
+	head        atomic.Pointer[types.Header] // Current head of the pool, used to age the private transactions
+	private     *privateTxs                  // Transactions kept out of the gossip and the pool content feeds
+	expiredFeed event.Feed                   // Private transactions made public after expiring
 }
 
 // New creates a new transaction pool to gather, sort and filter inbound
@@ -123,8 +127,10 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 		quit:         make(chan chan error),
 		term:         make(chan struct{}),
 		sync:         make(chan chan error),
+		private:      newPrivateTxs(),
 	}
 	pool.gasTip.Store(new(big.Int).SetUint64(gasTip))
+	pool.head.Store(head)
 
 	for i, subpool := range subpools {
 		if err := subpool.Init(pool.gasTip.Load().Uint64(), head, pool.reserver(i, subpool)); err != nil {
@@ -255,6 +261,8 @@ func (p *TxPool) loop(head *types.Header, chain BlockChain) {
 					for _, subpool := range p.subpools {
 						subpool.Reset(oldHead, newHead)
 					}
+					p.head.Store(newHead)
+					p.expirePrivate(newHead)
 					p.reorgFeed.Send(core.NewTxPoolReorgEvent{Head: newHead})
 					resetDone <- newHead
 				}(oldHead, newHead)
@@ -394,8 +402,70 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	return errs
 }
 
+// AddPrivate enqueues a batch of transactions into the pool like [TxPool.Add],
+// but keeps the newly added ones out of the transaction gossip and the pool
+// content feeds, so they are only included in the blocks built by this node.
+// Private transactions are made public once they have been in the pool for the
+// number of blocks set with [TxPool.SetPrivateTxExpiry].
+func (p *TxPool) AddPrivate(txs []*types.Transaction, local bool, sync bool) []error {
+	// Mark the transactions before adding them, so that the events announcing
+	// them are filtered out. Known transactions are left as they are.
+	var (
+		number = p.head.Load().Number.Uint64()
+		marked = make([]bool, len(txs))
+	)
+	for i, tx := range txs {
+		if !p.Has(tx.Hash()) {
+			p.private.add(tx.Hash(), number)
+			marked[i] = true
+		}
+	}
+	errs := p.Add(txs, local, sync)
+	for i, err := range errs {
+		if err != nil && marked[i] {
+			p.private.remove(txs[i].Hash())
+		}
+	}
+	return errs
+}
+
+// IsPrivate returns whether the transaction with the given hash is kept private
+// by the pool.
+func (p *TxPool) IsPrivate(hash common.Hash) bool {
+	return p.private.contains(hash)
+}
+
+// SetPrivateTxExpiry sets the number of blocks after which the private
+// transactions still in the pool are made public. Zero keeps them private until
+// they are included or dropped.
+func (p *TxPool) SetPrivateTxExpiry(blocks uint64) {
+	p.private.setExpiry(blocks)
+}
+
+// expirePrivate forgets the private transactions no longer in the pool and
+// announces the ones that expired at the given head as new transactions.
+func (p *TxPool) expirePrivate(head *types.Header) {
+	hashes := p.private.expire(head.Number.Uint64(), p.Has)
+	if len(hashes) == 0 {
+		return
+	}
+	txs := make([]*types.Transaction, 0, len(hashes))
+	for _, hash := range hashes {
+		if tx := p.Get(hash); tx != nil {
+			txs = append(txs, tx)
+		}
+	}
+	if len(txs) > 0 {
+		log.Debug("Private transactions expired", "count", len(txs))
+		p.expiredFeed.Send(core.NewTxsEvent{Txs: txs})
+	}
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce.
+//
+// Private transactions are included, as they are meant for the blocks built by
+// this node.
 func (p *TxPool) Pending(filter PendingFilter) map[common.Address][]*types.Transaction {
 	txs := make(map[common.Address][]*types.Transaction)
 	for _, subpool := range p.subpools {
@@ -410,14 +480,59 @@ func (p *TxPool) Pending(filter PendingFilter) map[common.Address][]*types.Trans
 	return txs
 }
 
+// IteratePending iterates over the processable transactions of the pool that are
+// not private. Returns false if the iteration was interrupted.
+func (p *TxPool) IteratePending(f func(tx *types.Transaction) bool) bool {
+	for _, subpool := range p.subpools {
+		if !subpool.IteratePending(func(tx *types.Transaction) bool {
+			return p.private.contains(tx.Hash()) || f(tx)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
 // SubscribeNewTxsEvent registers a subscription of NewTxsEvent and starts sending
-// events to the given channel.
+// events to the given channel. Private transactions are only announced once they
+// expire.
 func (p *TxPool) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
-	subs := make([]event.Subscription, len(p.subpools))
-	for i, subpool := range p.subpools {
-		subs[i] = subpool.SubscribeTransactions(ch, false)
+	txsCh := make(chan core.NewTxsEvent)
+	subs := make([]event.Subscription, 0, len(p.subpools)+1)
+	for _, subpool := range p.subpools {
+		subs = append(subs, subpool.SubscribeTransactions(txsCh, false))
 	}
-	return p.subs.Track(event.JoinSubscriptions(subs...))
+	subs = append(subs, p.expiredFeed.Subscribe(txsCh))
+	sub := event.JoinSubscriptions(subs...)
+
+	return p.subs.Track(event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-txsCh:
+				if ev.Txs = p.private.filter(ev.Txs); len(ev.Txs) == 0 {
+					continue
+				}
+				select {
+				case ch <- ev:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}))
+}
+
+// SubscribeExpiredPrivateTxsEvent registers a subscription of the private
+// transactions made public after expiring.
+func (p *TxPool) SubscribeExpiredPrivateTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return p.subs.Track(p.expiredFeed.Subscribe(ch))
 }
 
 
@@ -449,7 +564,8 @@ func (p *TxPool) Stats() (int, int) {
 }
 
 // Content retrieves the data content of the transaction pool, returning all the
-// pending as well as queued transactions, grouped by account and sorted by nonce.
+// pending as well as queued transactions that are not private, grouped by account
+// and sorted by nonce.
 func (p *TxPool) Content() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
 	pending := make(map[common.Address][]*types.Transaction)
 	queued := make(map[common.Address][]*types.Transaction)
@@ -457,22 +573,27 @@ func (p *TxPool) Content() (map[common.Address][]*types.Transaction, map[common.
 	for _, subpool := range p.subpools {
 		pend, queue := subpool.Content()
 		for addr, txs := range pend {
-			pending[addr] = txs
+			if txs = p.private.filter(txs); len(txs) != 0 {
+				pending[addr] = txs
+			}
 		}
 		for addr, txs := range queue {
-			queued[addr] = txs
+			if txs = p.private.filter(txs); len(txs) != 0 {
+				queued[addr] = txs
+			}
 		}
 	}
 	return pending, queued
 }
 
 // ContentFrom retrieves the data content of the transaction pool, returning the
-// pending as well as queued transactions of this address, grouped by nonce.
+// pending as well as queued transactions of this address that are not private,
+// grouped by nonce.
 func (p *TxPool) ContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
 	for _, subpool := range p.subpools {
 		run, block := subpool.ContentFrom(addr)
 		if len(run) != 0 || len(block) != 0 {
-			return run, block
+			return p.private.filter(run), p.private.filter(block)
 		}
 	}
 	return []*types.Transaction{}, []*types.Transaction{}
@@ -0,0 +1,138 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txpool_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/luxfi/evm/consensus/dummy"
+	"github.com/luxfi/evm/core"
+	"github.com/luxfi/evm/core/txpool"
+	"github.com/luxfi/evm/core/txpool/legacypool"
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/luxfi/geth/crypto"
+	ethparams "github.com/luxfi/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	privateKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	privateAddr   = crypto.PubkeyToAddress(privateKey.PublicKey)
+	publicKey, _  = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	publicAddr    = crypto.PubkeyToAddress(publicKey.PublicKey)
+)
+
+func signTransfer(t *testing.T, key *ecdsa.PrivateKey, nonce uint64) *types.Transaction {
+	t.Helper()
+
+	tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       &common.Address{0xaa},
+		Value:    big.NewInt(1),
+		Gas:      ethparams.TxGas,
+		GasPrice: big.NewInt(300 * params.GWei),
+	}), types.LatestSignerForChainID(params.TestChainConfig.ChainID), key)
+	require.NoError(t, err)
+	return tx
+}
+
+// TestPrivateTransactions checks the transactions added with AddPrivate are
+// pending for the blocks built by the node, but kept out of the content and the
+// gossip of the pool until they expire.
+func TestPrivateTransactions(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		engine = dummy.NewFakerWithMode(dummy.Mode{ModeSkipBlockFee: true, ModeSkipCoinbase: true})
+		gspec  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				privateAddr: {Balance: big.NewInt(params.Ether)},
+				publicAddr:  {Balance: big.NewInt(params.Ether)},
+			},
+		}
+	)
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), core.DefaultCacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+	require.NoError(err)
+	defer chain.Stop()
+
+	pool, err := txpool.New(legacypool.DefaultConfig.PriceLimit, chain, []txpool.SubPool{legacypool.New(legacypool.DefaultConfig, chain)})
+	require.NoError(err)
+	defer pool.Close()
+	pool.SetPrivateTxExpiry(2)
+
+	newTxsCh := make(chan core.NewTxsEvent, 10)
+	sub := pool.SubscribeNewTxsEvent(newTxsCh)
+	defer sub.Unsubscribe()
+
+	var (
+		private = signTransfer(t, privateKey, 0)
+		public  = signTransfer(t, publicKey, 0)
+	)
+	for _, err := range pool.AddPrivate([]*types.Transaction{private}, false, true) {
+		require.NoError(err)
+	}
+	for _, err := range pool.Add([]*types.Transaction{public}, false, true) {
+		require.NoError(err)
+	}
+	require.True(pool.IsPrivate(private.Hash()))
+	require.False(pool.IsPrivate(public.Hash()))
+
+	// Only the public transaction is announced.
+	select {
+	case ev := <-newTxsCh:
+		require.Equal([]*types.Transaction{public}, ev.Txs)
+	case <-time.After(5 * time.Second):
+		require.FailNow("timed out waiting for the public transaction")
+	}
+	select {
+	case ev := <-newTxsCh:
+		require.FailNow("unexpected transactions announced", "%v", ev.Txs)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The private transaction is pending for the blocks built by the node.
+	pending := pool.Pending(txpool.PendingFilter{})
+	require.Equal([]*types.Transaction{private}, pending[privateAddr])
+	require.Equal([]*types.Transaction{public}, pending[publicAddr])
+
+	// But it is left out of the content of the pool.
+	content, queued := pool.Content()
+	require.Equal(map[common.Address][]*types.Transaction{publicAddr: {public}}, content)
+	require.Empty(queued)
+	contentFrom, queuedFrom := pool.ContentFrom(privateAddr)
+	require.Empty(contentFrom)
+	require.Empty(queuedFrom)
+
+	var iterated []*types.Transaction
+	pool.IteratePending(func(tx *types.Transaction) bool {
+		iterated = append(iterated, tx)
+		return true
+	})
+	require.Equal([]*types.Transaction{public}, iterated)
+
+	// The private transaction expires two blocks after it was added, once it is
+	// announced for the gossip and made part of the content of the pool.
+	_, blocks, _, err := core.GenerateChainWithGenesis(gspec, engine, 2, 10, func(int, *core.BlockGen) {})
+	require.NoError(err)
+	_, err = chain.InsertChain(blocks)
+	require.NoError(err)
+	select {
+	case ev := <-newTxsCh:
+		require.Equal([]*types.Transaction{private}, ev.Txs)
+	case <-time.After(5 * time.Second):
+		require.FailNow("timed out waiting for the expired private transaction")
+	}
+	require.False(pool.IsPrivate(private.Hash()))
+
+	contentFrom, _ = pool.ContentFrom(privateAddr)
+	require.Equal([]*types.Transaction{private}, contentFrom)
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/luxfi/evm/core/state"
 	"github.com/luxfi/evm/params"
 	"github.com/luxfi/evm/plugin/evm/vmerrors"
+	"github.com/luxfi/evm/precompile/contracts/denylist"
 	"github.com/luxfi/evm/precompile/contracts/txallowlist"
 )
 
@@ -86,6 +87,9 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if !opts.Config.IsCancun(head.Time) && tx.Type() == types.BlobTxType {
 		return fmt.Errorf("%w: type %d rejected, pool not yet in Cancun", core.ErrTxTypeNotSupported, tx.Type())
 	}
+	if !opts.Config.IsPrague(head.Time) && tx.Type() == types.SetCodeTxType {
+		return fmt.Errorf("%w: type %d rejected, pool not yet in Prague", core.ErrTxTypeNotSupported, tx.Type())
+	}
 	// Check whether the init code size has been exceeded
 	if opts.Config.IsShanghai(head.Number, head.Time) && tx.To() == nil && len(tx.Data()) > ethparams.MaxInitCodeSize {
 		return fmt.Errorf("%w: code size %v, limit %v", vm.ErrMaxInitCodeSizeExceeded, len(tx.Data()), ethparams.MaxInitCodeSize)
@@ -122,17 +126,32 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	}
 	// Ensure the transaction has more gas than the bare minimum needed to cover
 	// the transaction metadata
-	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, opts.Config.Rules(head.Number, head.Time))
+	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.SetCodeAuthorizations(), tx.To() == nil, opts.Config.Rules(head.Number, head.Time))
 	if err != nil {
 		return err
 	}
 	if txGas := tx.Gas(); txGas < intrGas {
 		return fmt.Errorf("%w: address %v tx gas (%v), minimum needed %v", core.ErrIntrinsicGas, from.Hex(), txGas, intrGas)
 	}
+	// Ensure the transaction can cover floor data gas.
+	if opts.Config.IsPrague(head.Time) {
+		floorDataGas, err := core.FloorDataGas(tx.Data())
+		if err != nil {
+			return err
+		}
+		if tx.Gas() < floorDataGas {
+			return fmt.Errorf("%w: gas %v, minimum needed %v", core.ErrFloorDataGas, tx.Gas(), floorDataGas)
+		}
+	}
 	// Ensure the gasprice is high enough to cover the requirement of the calling pool
 	if tx.GasTipCapIntCmp(opts.MinTip) < 0 {
 		return fmt.Errorf("%w: gas tip cap %v, minimum needed %v", ErrUnderpriced, tx.GasTipCap(), opts.MinTip)
 	}
+	if tx.Type() == types.SetCodeTxType {
+		if len(tx.SetCodeAuthorizations()) == 0 {
+			return fmt.Errorf("set code tx must have at least one authorization tuple")
+		}
+	}
 	if tx.Type() == types.BlobTxType {
 		// Ensure the blob fee cap satisfies the minimum blob gas price
 		if tx.BlobGasFeeCapIntCmp(blobTxMinBlobGasPrice) < 0 {
@@ -214,6 +233,10 @@ type ValidationOptionsWithState struct {
 
 	Rules      params.Rules
 	MinimumFee *big.Int
+
+	// Time is the timestamp allow list roles are checked at, so that expired
+	// roles are rejected.
+	Time uint64
 }
 
 // ValidateTransactionWithState is a helper method to check whether a transaction
@@ -277,11 +300,21 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 
 	// If the tx allow list is enabled, return an error if the from address is not allow listed.
 	if params.GetRulesExtra(opts.Rules).IsPrecompileEnabled(txallowlist.ContractAddress) {
-		txAllowListRole := txallowlist.GetTxAllowListStatus(opts.State, from)
+		txAllowListRole := txallowlist.GetActiveTxAllowListStatus(opts.State, from, opts.Time)
 		if !txAllowListRole.IsEnabled() {
 			return fmt.Errorf("%w: %s", vmerrors.ErrSenderAddressNotAllowListed, from)
 		}
 	}
 
+	// If the deny list is enabled, return an error if the from or to address is frozen.
+	if params.GetRulesExtra(opts.Rules).IsPrecompileEnabled(denylist.ContractAddress) {
+		if denylist.IsFrozen(opts.State, from) {
+			return fmt.Errorf("%w: %s", vmerrors.ErrSenderAddressFrozen, from)
+		}
+		if to := tx.To(); to != nil && denylist.IsFrozen(opts.State, *to) {
+			return fmt.Errorf("%w: %s", vmerrors.ErrRecipientAddressFrozen, to)
+		}
+	}
+
 	return nil
 }
@@ -95,36 +95,14 @@ func NewEVM(blockCtx BlockContext, statedb StateDB, chainConfig *params.ChainCon
 	return &EVM{EVM: evm}
 }
 
-// ConvertChainConfig converts luxfi chainConfig to ethereum chainConfig
+// ConvertChainConfig converts luxfi chainConfig to ethereum chainConfig. The
+// time based forks are carried over as scheduled, so that the EVM enables them
+// at the same timestamps as block processing does.
 func ConvertChainConfig(cfg *luxparams.ChainConfig) *params.ChainConfig {
 	if cfg == nil {
 		return nil
 	}
-	
-	ethConfig := &params.ChainConfig{
-		ChainID:             cfg.ChainID,
-		HomesteadBlock:      cfg.HomesteadBlock,
-		EIP150Block:         cfg.EIP150Block,
-		EIP155Block:         cfg.EIP155Block,
-		EIP158Block:         cfg.EIP158Block,
-		ByzantiumBlock:      cfg.ByzantiumBlock,
-		ConstantinopleBlock: cfg.ConstantinopleBlock,
-		PetersburgBlock:     cfg.PetersburgBlock,
-		IstanbulBlock:       cfg.IstanbulBlock,
-		MuirGlacierBlock:    cfg.MuirGlacierBlock,
-		BerlinBlock:         cfg.BerlinBlock,
-		LondonBlock:         cfg.LondonBlock,
-	}
-	
-	// Convert time-based forks to block-based for compatibility
-	if cfg.ShanghaiTime != nil && *cfg.ShanghaiTime == 0 {
-		ethConfig.ShanghaiTime = cfg.ShanghaiTime
-	}
-	if cfg.CancunTime != nil && *cfg.CancunTime == 0 {
-		ethConfig.CancunTime = cfg.CancunTime
-	}
-	
-	return ethConfig
+	return cfg.ToEthChainConfig()
 }
 
 // NewEVMWithStateDB creates a new EVM with luxfi chainConfig and statedb
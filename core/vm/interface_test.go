@@ -0,0 +1,37 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/params/extras"
+	"github.com/luxfi/evm/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertChainConfigScheduledGranite(t *testing.T) {
+	c := params.WithExtra(
+		&params.ChainConfig{ChainID: big.NewInt(1)},
+		&extras.ChainConfig{
+			NetworkUpgrades: extras.NetworkUpgrades{
+				SubnetEVMTimestamp: utils.NewUint64(0),
+				GraniteTimestamp:   utils.NewUint64(500),
+			},
+		},
+	)
+	params.SetEthUpgrades(c, params.GetExtra(c).NetworkUpgrades)
+
+	big0 := new(big.Int)
+	ethConfig := ConvertChainConfig(c)
+	require.Equal(t, utils.NewUint64(500), ethConfig.PragueTime)
+	require.False(t, ethConfig.IsPrague(big0, 499))
+	require.True(t, ethConfig.IsPrague(big0, 500))
+
+	// The EVM of eth_call is configured the same way.
+	evm := NewEVMWithStateDB(BlockContext{BlockNumber: big0, Time: 500}, TxContext{}, nil, c, Config{})
+	require.True(t, evm.ChainConfig().IsPrague(big0, 500))
+}
@@ -123,10 +123,18 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 	return b.eth.blockchain.GetHeaderByNumber(uint64(number)), nil
 }
 
+// GetFeeConfigAt returns the fee config stored at [parent]. The fee config of
+// a block built on it may be the one scheduled at [parent] instead, which
+// [header.FeeConfigAt] resolves.
 func (b *EthAPIBackend) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error) {
 	return b.eth.blockchain.GetFeeConfigAt(parent)
 }
 
+// GetScheduledFeeConfigAt returns the fee config scheduled at [parent], if any.
+func (b *EthAPIBackend) GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error) {
+	return b.eth.blockchain.GetScheduledFeeConfigAt(parent)
+}
+
 func (b *EthAPIBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -259,7 +267,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	if header == nil {
 		return nil, nil, errors.New("header not found")
 	}
-	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+	stateDb, err := b.stateAt(header)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -281,7 +289,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 		if header == nil {
 			return nil, nil, errors.New("header for hash not found")
 		}
-		stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+		stateDb, err := b.stateAt(header)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -290,6 +298,19 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 }
 
+// stateAt returns the state of the given header, falling back to the state
+// rebuilt from the state histories if the node runs with the path scheme.
+func (b *EthAPIBackend) stateAt(header *types.Header) (*state.StateDB, error) {
+	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+	if err == nil {
+		return stateDb, nil
+	}
+	if historic, herr := b.eth.BlockChain().HistoricState(header.Root); herr == nil {
+		return historic, nil
+	}
+	return nil, err
+}
+
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -355,6 +376,9 @@ func (b *EthAPIBackend) SubscribeAcceptedTransactionEvent(ch chan<- core.NewTxsE
 }
 
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if b.eth.config.PrivateTxSubmission {
+		return b.SendPrivateTx(ctx, signedTx)
+	}
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -368,12 +392,24 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return nil
 }
 
+// SendPrivateTx adds the transaction to the pool without gossiping it, so it is
+// only included in the blocks built by this node until it expires.
+//
+// Private transactions are added as remote ones, so they are not journaled and
+// regossiped on restart.
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.eth.txPool.AddPrivate([]*types.Transaction{signedTx}, false, false)[0]
+}
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending := b.eth.txPool.Pending(txpool.PendingFilter{})
 	var txs types.Transactions
 	for _, batch := range pending {
 		for _, tx := range batch {
-			if tx != nil {
+			if tx != nil && !b.eth.txPool.IsPrivate(tx.Hash()) {
 				txs = append(txs, tx)
 			}
 		}
@@ -381,7 +417,12 @@ func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	return txs, nil
 }
 
+// GetPoolTransaction returns the transaction with the given hash from the pool,
+// or nil if it is not in the pool or is still private.
 func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
+	if b.eth.txPool.IsPrivate(hash) {
+		return nil
+	}
 	return b.eth.txPool.Get(hash)
 }
 
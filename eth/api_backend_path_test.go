@@ -0,0 +1,102 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/evm/consensus/dummy"
+	"github.com/luxfi/evm/core"
+	"github.com/luxfi/evm/eth/ethconfig"
+	"github.com/luxfi/evm/internal/ethapi"
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/rpc"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/luxfi/geth/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPathSchemeHistoricQueries checks eth_getBalance and eth_call are served
+// at heights below the persistent state of the path scheme, by rebuilding the
+// states from the retained state histories.
+func TestPathSchemeHistoricQueries(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		counter = common.Address{0xcc}
+		engine  = dummy.NewFakerWithMode(dummy.Mode{ModeSkipBlockFee: true, ModeSkipCoinbase: true})
+		signer  = types.HomesteadSigner{}
+		gspec   = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				addr: {Balance: big.NewInt(params.Ether)},
+				// Increments the counter in slot 0 and returns its new value
+				counter: {Code: common.FromHex("6000546001018060005560005260206000f3")},
+			},
+		}
+		blocks = 8
+	)
+	_, chain, _, err := core.GenerateChainWithGenesis(gspec, engine, blocks, 10, func(i int, b *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    b.TxNonce(addr),
+			To:       &counter,
+			Value:    big.NewInt(1000),
+			Gas:      100_000,
+			GasPrice: b.BaseFee(),
+		}), signer, key)
+		require.NoError(err)
+		b.AddTx(tx)
+	})
+	require.NoError(err)
+
+	cacheConfig := core.DefaultCacheConfigWithScheme(rawdb.PathScheme)
+	cacheConfig.StateHistory = 32
+	blockchain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), cacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+	require.NoError(err)
+	defer blockchain.Stop()
+
+	_, err = blockchain.InsertChain(chain)
+	require.NoError(err)
+	for _, block := range chain {
+		require.NoError(blockchain.Accept(block))
+	}
+	blockchain.DrainAcceptorQueue()
+
+	// Flush every layer into the persistent state, so all but the head state
+	// are only available from the state histories.
+	head := chain[blocks-1]
+	require.NoError(blockchain.TrieDB().Commit(head.Root(), false))
+	_, err = blockchain.StateAt(chain[0].Root())
+	require.Error(err)
+
+	config := ethconfig.NewDefaultConfig()
+	backend := &EthAPIBackend{eth: &Ethereum{config: &config, blockchain: blockchain}}
+	api := ethapi.NewBlockChainAPI(backend)
+
+	// Query from the head downwards and back, to read both freshly rebuilt and
+	// cached historic states.
+	for _, number := range []int{blocks, 5, 1, 3, blocks} {
+		blockNr := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number))
+
+		balance, err := api.GetBalance(context.Background(), counter, blockNr)
+		require.NoError(err, "block %d", number)
+		require.Equal(big.NewInt(int64(1000*number)), balance.ToInt(), "block %d", number)
+
+		ret, err := api.Call(context.Background(), ethapi.TransactionArgs{From: &addr, To: &counter}, &blockNr, nil, nil)
+		require.NoError(err, "block %d", number)
+		require.Equal(hexutil.Bytes(common.BigToHash(big.NewInt(int64(number+1))).Bytes()), ret, "block %d", number)
+	}
+
+	// Blocks above the accepted head are not served.
+	_, err = api.GetBalance(context.Background(), counter, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blocks+1)))
+	require.Error(err)
+}
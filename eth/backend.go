@@ -256,6 +256,7 @@ func New(
 	if err != nil {
 		return nil, err
 	}
+	eth.txPool.SetPrivateTxExpiry(config.PrivateTxExpiry)
 
 	eth.miner = miner.New(eth, &config.Miner, eth.blockchain.Config(), eth.EventMux(), eth.engine, clock)
 
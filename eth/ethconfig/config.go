@@ -149,6 +149,13 @@ type Config struct {
 	// to be issued without replay protection over the API even if AllowUnprotectedTxs is false.
 	AllowUnprotectedTxHashes []common.Hash
 
+	// PrivateTxSubmission keeps all the transactions submitted over the API out
+	// of the transaction gossip, as if submitted with eth_sendPrivateRawTransaction.
+	PrivateTxSubmission bool
+	// PrivateTxExpiry is the number of blocks after which the private transactions
+	// still in the pool are gossiped. Zero keeps them private until included.
+	PrivateTxExpiry uint64
+
 	// OfflinePruning enables offline pruning on startup of the node. If a node is started
 	// with this configuration option, it must finish pruning before resuming normal operation.
 	OfflinePruning                bool
@@ -113,7 +113,7 @@ func Estimate(ctx context.Context, call *core.Message, opts *Options, gasCap uin
 	// directly try 21000. Returning 21000 without any execution is dangerous as
 	// some tx field combos might bump the price up even for plain transfers (e.g.
 	// unused access list items). Ever so slightly wasteful, but safer overall.
-	if len(call.Data) == 0 {
+	if len(call.Data) == 0 && len(call.SetCodeAuthorizations) == 0 {
 		if call.To != nil && opts.State.GetCodeSize(*call.To) == 0 {
 			failed, _, err := execute(ctx, call, opts, ethparams.TxGas)
 			if !failed && err == nil {
@@ -205,7 +205,7 @@ func execute(ctx context.Context, call *core.Message, opts *Options, gasLimit ui
 	// other non-fixable conditions
 	result, err := run(ctx, call, opts)
 	if err != nil {
-		if errors.Is(err, core.ErrIntrinsicGas) {
+		if errors.Is(err, core.ErrIntrinsicGas) || errors.Is(err, core.ErrFloorDataGas) {
 			return true, nil, nil // Special case, raise gas limit
 		}
 		return true, nil, err // Bail out
@@ -222,7 +222,7 @@ func run(ctx context.Context, call *core.Message, opts *Options) (*core.Executio
 		evmContext = core.NewEVMBlockContext(opts.Header, opts.Chain, nil)
 
 		dirtyState = opts.State.Copy()
-		evm        = vm.NewEVM(evmContext, dirtyState, &ethparams.ChainConfig{ChainID: opts.Config.ChainID}, vm.Config{NoBaseFee: true})
+		evm        = vm.NewEVM(evmContext, dirtyState, opts.Config.ToEthChainConfig(), vm.Config{NoBaseFee: true})
 	)
 	evm.SetTxContext(msgContext)
 	// Monitor the outer context and interrupt the EVM upon cancellation. To avoid
@@ -236,7 +236,7 @@ func run(ctx context.Context, call *core.Message, opts *Options) (*core.Executio
 		evm.Cancel()
 	}()
 	// Execute the call, returning a wrapped error or the result
-	result, err := core.ApplyMessage(evm, call, new(core.GasPool).AddGas(math.MaxUint64))
+	result, err := core.ApplyMessage(opts.Config, evm, call, new(core.GasPool).AddGas(math.MaxUint64))
 	if vmerr := dirtyState.Error(); vmerr != nil {
 		return nil, vmerr
 	}
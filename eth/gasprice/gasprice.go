@@ -36,6 +36,7 @@ import (
 	"github.com/luxfi/evm/core"
 	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/evm/params"
+	customheader "github.com/luxfi/evm/plugin/evm/header"
 	"github.com/luxfi/evm/precompile/contracts/feemanager"
 	"github.com/luxfi/evm/rpc"
 	"github.com/luxfi/geth/common"
@@ -99,6 +100,7 @@ type OracleBackend interface {
 	MinRequiredTip(ctx context.Context, header *types.Header) (*big.Int, error)
 	LastAcceptedBlock() *types.Block
 	GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error)
+	GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error)
 }
 
 // Oracle recommends gas prices based on the content of recent
@@ -223,7 +225,7 @@ func (oracle *Oracle) estimateNextBaseFee(ctx context.Context) (*big.Int, error)
 	if err != nil {
 		return nil, err
 	}
-	_, _, err = oracle.backend.GetFeeConfigAt(header)
+	_, _, err = customheader.FeeConfigAt(oracle.backend, header, oracle.nextTimestamp(header))
 	if err != nil {
 		return nil, err
 	}
@@ -240,6 +242,15 @@ func (oracle *Oracle) estimateNextBaseFee(ctx context.Context) (*big.Int, error)
 	return header.BaseFee, nil
 }
 
+// nextTimestamp returns the timestamp of the next block if it were produced
+// immediately on top of [head].
+func (oracle *Oracle) nextTimestamp(head *types.Header) uint64 {
+	if now := oracle.clock.Unix(); now > head.Time {
+		return now
+	}
+	return head.Time
+}
+
 // SuggestPrice returns an estimated price for legacy transactions.
 func (oracle *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	// Estimate the effective tip based on recent blocks.
@@ -282,7 +293,7 @@ func (oracle *Oracle) suggestTip(ctx context.Context) (*big.Int, error) {
 	chainConfig := params.GetExtra(oracle.backend.ChainConfig())
 	var feeLastChangedAt *big.Int
 	if chainConfig.IsPrecompileEnabled(feemanager.ContractAddress, head.Time) {
-		_, feeLastChangedAt, err = oracle.backend.GetFeeConfigAt(head)
+		_, feeLastChangedAt, err = customheader.FeeConfigAt(oracle.backend, head, oracle.nextTimestamp(head))
 		if err != nil {
 			return nil, err
 		}
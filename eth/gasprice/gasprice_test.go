@@ -93,6 +93,10 @@ func (b *testBackend) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig
 	return b.chain.GetFeeConfigAt(parent)
 }
 
+func (b *testBackend) GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error) {
+	return b.chain.GetScheduledFeeConfigAt(parent)
+}
+
 func (b *testBackend) teardown() {
 	b.chain.Stop()
 }
@@ -190,10 +190,13 @@ func (eth *Ethereum) pathState(block *types.Block) (*state.StateDB, func(), erro
 	if err == nil {
 		return statedb, noopReleaser, nil
 	}
-	// TODO historic state is not supported in path-based scheme.
-	// Fully archive node in pbss will be implemented by relying
-	// on state history, but needs more work on top.
-	return nil, nil, errors.New("historical state not available in path scheme yet")
+	// Otherwise rebuild it from the state histories, which are retained
+	// for the configured number of blocks below the persistent state.
+	statedb, err = eth.blockchain.HistoricState(block.Root())
+	if err != nil {
+		return nil, nil, fmt.Errorf("historical state %#x of block %d is not available: %w", block.Root(), block.NumberU64(), err)
+	}
+	return statedb, noopReleaser, nil
 }
 
 // stateAtBlock retrieves the state database associated with a certain block.
@@ -258,7 +261,7 @@ func (eth *Ethereum) stateAtTransaction(ctx context.Context, block *types.Block,
 		// Not yet the searched for transaction, execute on top of the current state
 		vmenv := vm.NewEVM(context, txContext, statedb, eth.blockchain.Config(), vm.Config{})
 		statedb.SetTxContext(tx.Hash(), idx)
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
+		if _, err := core.ApplyMessage(eth.blockchain.Config(), vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
 			return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
 		}
 		// Ensure any modifications are committed to the state
@@ -272,7 +275,8 @@ func (eth *Ethereum) stateAtTransaction(ctx context.Context, block *types.Block,
 // It wraps StateAtBlock and handles the case where Upgrades are applied to the
 // next block.
 // This is different than using StateAtBlock with [nextBlock] because it will
-// apply the upgrades to the [parent] state before returning it.
+// apply the upgrades and the system calls of [nextBlock] to the [parent] state
+// before returning it.
 func (eth *Ethereum) StateAtNextBlock(ctx context.Context, parent *types.Block, nextBlock *types.Block, reexec uint64, base *state.StateDB, readOnly bool, preferDisk bool) (*state.StateDB, tracers.StateReleaseFunc, error) {
 	// Get state for [parent]
 	statedb, release, err := eth.stateAtBlock(ctx, parent, reexec, base, readOnly, preferDisk)
@@ -287,6 +291,16 @@ func (eth *Ethereum) StateAtNextBlock(ctx context.Context, parent *types.Block,
 		release()
 		return nil, nil, err
 	}
+	// Apply the system calls made at the start of [nextBlock] as well, so that
+	// its transactions are replayed on the state they were executed on.
+	context := core.NewEVMBlockContext(nextBlock.Header(), eth.blockchain, nil)
+	vmenv := vm.NewEVM(context, vm.TxContext{}, statedb, eth.blockchain.Config(), vm.Config{})
+	if beaconRoot := nextBlock.BeaconRoot(); beaconRoot != nil {
+		core.ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
+	}
+	if eth.blockchain.Config().IsPrague(nextBlock.Time()) {
+		core.ProcessParentBlockHash(nextBlock.ParentHash(), vmenv, statedb)
+	}
 
 	return statedb, release, nil
 }
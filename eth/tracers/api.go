@@ -39,10 +39,11 @@ import (
 	"github.com/luxfi/evm/consensus"
 	"github.com/luxfi/evm/core"
 	"github.com/luxfi/evm/core/state"
+	ethstate "github.com/luxfi/geth/core/state"
 	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/core/vm"
 	"github.com/luxfi/geth/ethdb"
-	"github.com/luxfi/geth/eth/tracers/logger"
+	"github.com/luxfi/evm/eth/tracers/logger"
 	"github.com/luxfi/evm/internal/ethapi"
 	"github.com/luxfi/evm/params"
 	"github.com/luxfi/evm/rpc"
@@ -92,6 +93,7 @@ type Backend interface {
 	BadBlocks() ([]*types.Block, []*core.BadBlockReason)
 	GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error)
 	RPCGasCap() uint64
+	GetMaxBlocksPerRequest() int64
 	ChainConfig() *params.ChainConfig
 	Engine() consensus.Engine
 	ChainDb() ethdb.Database
@@ -310,7 +312,7 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 						TxIndex:     i,
 						TxHash:      tx.Hash(),
 					}
-					res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config)
+					res, err := api.traceTx(ctx, tx, msg, txctx, blockCtx, task.statedb, config)
 					if err != nil {
 						task.results[i] = &txTraceResult{TxHash: tx.Hash(), Error: err.Error()}
 						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
@@ -576,7 +578,7 @@ func (api *API) IntermediateRoots(ctx context.Context, hash common.Hash, config
 			vmenv     = vm.NewEVM(vmctx, statedb, ethCfg, vm.Config{})
 		)
 		statedb.SetTxContext(tx.Hash(), i)
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
+		if _, err := core.ApplyMessage(chainConfig, vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
 			log.Warn("Tracing intermediate roots did not complete", "txindex", i, "txhash", tx.Hash(), "err", err)
 			// We intentionally don't return the error here: if we do, then the RPC server will not
 			// return the roots. Most likely, the caller already knows that a certain transaction fails to
@@ -663,7 +665,7 @@ func (api *baseAPI) traceBlock(ctx context.Context, block *types.Block, config *
 			TxIndex:     i,
 			TxHash:      tx.Hash(),
 		}
-		res, err := api.traceTx(ctx, msg, txctx, blockCtx, statedb, config)
+		res, err := api.traceTx(ctx, tx, msg, txctx, blockCtx, statedb, config)
 		if err != nil {
 			return nil, err
 		}
@@ -707,7 +709,7 @@ func (api *baseAPI) traceBlockParallel(ctx context.Context, block *types.Block,
 					TxIndex:     task.index,
 					TxHash:      txs[task.index].Hash(),
 				}
-				res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config)
+				res, err := api.traceTx(ctx, txs[task.index], msg, txctx, blockCtx, task.statedb, config)
 				if err != nil {
 					results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Error: err.Error()}
 					continue
@@ -736,7 +738,7 @@ txloop:
 		ethCfg := convertToEthChainConfig(api.backend.ChainConfig())
 		vmenv := vm.NewEVM(blockCtx, statedb, ethCfg, vm.Config{})
 		vmenv.SetTxContext(core.NewEVMTxContext(msg))
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
+		if _, err := core.ApplyMessage(api.backend.ChainConfig(), vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
 			failed = err
 			break txloop
 		}
@@ -847,7 +849,7 @@ func (api *FileTracerAPI) standardTraceBlockToFile(ctx context.Context, block *t
 		vmenv := vm.NewEVM(vmctx, statedb, ethCfg, vmConf)
 		vmenv.SetTxContext(txContext)
 		statedb.SetTxContext(tx.Hash(), i)
-		_, err = core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit))
+		_, err = core.ApplyMessage(chainConfig, vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit))
 		if writer != nil {
 			writer.Flush()
 		}
@@ -884,7 +886,7 @@ func containsTx(block *types.Block, hash common.Hash) bool {
 // TraceTransaction returns the structured logs created during the execution of EVM
 // and returns them as a JSON object.
 func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
-	found, _, blockHash, blockNumber, index, err := api.backend.GetTransaction(ctx, hash)
+	found, tx, blockHash, blockNumber, index, err := api.backend.GetTransaction(ctx, hash)
 	if err != nil {
 		return nil, ethapi.NewTxIndexingError()
 	}
@@ -916,7 +918,7 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 		TxIndex:     int(index),
 		TxHash:      hash,
 	}
-	return api.traceTx(ctx, msg, txctx, vmctx, statedb, config)
+	return api.traceTx(ctx, tx, msg, txctx, vmctx, statedb, config)
 }
 
 // TraceCall lets you trace a given eth_call. It collects the structured logs
@@ -981,6 +983,12 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 		if err != nil {
 			return nil, err
 		}
+		// A call moved to a later block finds the hash of the traced block, as
+		// its parent, in the history storage contract.
+		if vmctx.BlockNumber.Cmp(block.Number()) > 0 && api.backend.ChainConfig().IsPrague(vmctx.Time) {
+			vmenv := vm.NewEVM(vmctx, statedb, convertToEthChainConfig(api.backend.ChainConfig()), vm.Config{})
+			core.ProcessParentBlockHash(block.Hash(), vmenv, statedb)
+		}
 
 		if err := config.StateOverrides.Apply(statedb); err != nil {
 			return nil, err
@@ -992,37 +1000,53 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 		return nil, err
 	}
 
+	// There is no signed transaction behind a call, so hand the tracers an
+	// unsigned one carrying the message fields.
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    msg.Nonce,
+		To:       msg.To,
+		Gas:      msg.GasLimit,
+		GasPrice: msg.GasPrice,
+		Value:    msg.Value,
+		Data:     msg.Data,
+	})
 	var traceConfig *TraceConfig
 	if config != nil {
 		traceConfig = &config.TraceConfig
 	}
-	return api.traceTx(ctx, msg, new(Context), vmctx, statedb, traceConfig)
+	return api.traceTx(ctx, tx, msg, new(Context), vmctx, statedb, traceConfig)
 }
 
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
-func (api *baseAPI) traceTx(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+func (api *baseAPI) traceTx(ctx context.Context, tx *types.Transaction, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
 	var (
-		tracer    Tracer // Keep as Tracer for now
+		tracer    *Tracer
 		err       error
 		timeout   = defaultTraceTimeout
 		txContext = core.NewEVMTxContext(message)
+		ethConfig = convertToEthChainConfig(api.backend.ChainConfig())
 	)
 	if config == nil {
 		config = &TraceConfig{}
 	}
 	// Default tracer is the struct logger
-	// TODO: Fix tracer compatibility between local and go-ethereum types
-	// tracer = logger.NewStructLogger(config.Config)
-	// if config.Tracer != nil {
-	// 	tracer, err = DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	// }
-	ethConfig := convertToEthChainConfig(api.backend.ChainConfig())
-	vmenv := vm.NewEVM(vmctx, statedb, ethConfig, vm.Config{NoBaseFee: true})
+	if config.Tracer == nil {
+		logger := logger.NewStructLogger(config.Config)
+		tracer = &Tracer{
+			Hooks:     logger.Hooks(),
+			GetResult: logger.GetResult,
+			Stop:      logger.Stop,
+		}
+	} else {
+		tracer, err = DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig, ethConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tracingStateDB := ethstate.NewHookedState(statedb.StateDB, tracer.Hooks)
+	vmenv := vm.NewEVM(vmctx, tracingStateDB, ethConfig, vm.Config{Tracer: tracer.Hooks, NoBaseFee: true})
 	vmenv.SetTxContext(txContext)
 
 	// Define a meaningful timeout of a single transaction trace
@@ -1044,9 +1068,19 @@ func (api *baseAPI) traceTx(ctx context.Context, message *core.Message, txctx *C
 
 	// Call Prepare to clear out the statedb access list
 	statedb.SetTxContext(txctx.TxHash, txctx.TxIndex)
-	if _, err = core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit)); err != nil {
+	if tracer.OnTxStart != nil {
+		tracer.OnTxStart(vmenv.GetVMContext(), tx, message.From)
+	}
+	result, err := core.ApplyMessage(api.backend.ChainConfig(), vmenv, message, new(core.GasPool).AddGas(message.GasLimit))
+	if err != nil {
+		if tracer.OnTxEnd != nil {
+			tracer.OnTxEnd(nil, err)
+		}
 		return nil, fmt.Errorf("tracing failed: %w", err)
 	}
+	if tracer.OnTxEnd != nil {
+		tracer.OnTxEnd(&types.Receipt{GasUsed: result.UsedGas}, nil)
+	}
 	return tracer.GetResult()
 }
 
@@ -1064,6 +1098,11 @@ func APIs(backend Backend) []rpc.API {
 			Service:   NewFileTracerAPI(backend),
 			Name:      "debug-file-tracer",
 		},
+		{
+			Namespace: "trace",
+			Service:   NewTraceAPI(backend),
+			Name:      "trace",
+		},
 	}
 }
 
@@ -1093,6 +1132,7 @@ func overrideConfig(original *params.ChainConfig, override *params.ChainConfig)
 	}
 	if timestamp := overrideExtra.GraniteTimestamp; timestamp != nil {
 		params.GetExtra(copy).GraniteTimestamp = timestamp
+		copy.PragueTime = timestamp
 		canon = false
 	}
 	if timestamp := override.CancunTime; timestamp != nil {
@@ -1125,5 +1165,6 @@ func convertToEthChainConfig(config *params.ChainConfig) *ethparams.ChainConfig
 		LondonBlock:         config.LondonBlock,
 		ShanghaiTime:        config.ShanghaiTime,
 		CancunTime:          config.CancunTime,
+		PragueTime:          config.PragueTime,
 	}
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/luxfi/evm/params"
 	"github.com/luxfi/evm/precompile/contracts/txallowlist"
 	"github.com/luxfi/evm/rpc"
+	"github.com/luxfi/evm/utils"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/common/hexutil"
 	"github.com/luxfi/geth/common/math"
@@ -414,3 +415,69 @@ func TestTraceCallWithOverridesStateUpgrade(t *testing.T) {
 		}
 	}
 }
+
+func TestTraceHistoryStorage(t *testing.T) {
+	t.Parallel()
+
+	// Initialize test accounts
+	accounts := newAccounts(1)
+	copyConfig := params.Copy(params.TestChainConfig)
+	params.GetExtra(&copyConfig).GraniteTimestamp = utils.NewUint64(0)
+	params.SetEthUpgrades(&copyConfig, params.GetExtra(&copyConfig).NetworkUpgrades)
+	genesis := &core.Genesis{
+		Config: &copyConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	// Reading the hash of block [number] from the history storage contract
+	readHistory := func(number int64) []byte {
+		return common.BigToHash(big.NewInt(number)).Bytes()
+	}
+	genBlocks := 2
+	signer := types.HomesteadSigner{}
+	var target common.Hash
+	backend := newTestBackend(t, genBlocks, genesis, func(i int, b *core.BlockGen) {
+		if i != genBlocks-1 {
+			return
+		}
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    0,
+			To:       &core.HistoryStorageAddress,
+			Gas:      100_000,
+			GasPrice: b.BaseFee(),
+			Data:     readHistory(1)}),
+			signer, accounts[0].key)
+		b.AddTx(tx)
+		target = tx.Hash()
+	})
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	checkResult := func(result interface{}, want common.Hash) {
+		t.Helper()
+		var have *logger.ExecutionResult
+		require.NoError(t, json.Unmarshal(result.(json.RawMessage), &have))
+		require.False(t, have.Failed)
+		require.Equal(t, fmt.Sprintf("%x", want), have.ReturnValue)
+	}
+
+	// The replayed transaction of block 2 reads the hash of block 1, stored at
+	// the start of block 2.
+	result, err := api.TraceTransaction(context.Background(), target, nil)
+	require.NoError(t, err)
+	checkResult(result, backend.chain.GetBlockByNumber(1).Hash())
+
+	// A call moved to block 3 reads the hash of block 2.
+	head := rpc.BlockNumber(genBlocks)
+	input := hexutil.Bytes(readHistory(int64(genBlocks)))
+	result, err = api.TraceCall(context.Background(), ethapi.TransactionArgs{
+		From:  &accounts[0].addr,
+		To:    &core.HistoryStorageAddress,
+		Input: &input,
+	}, rpc.BlockNumberOrHash{BlockNumber: &head}, &TraceCallConfig{
+		BlockOverrides: &ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(int64(genBlocks) + 1))},
+	})
+	require.NoError(t, err)
+	checkResult(result, backend.chain.GetBlockByNumber(uint64(genBlocks)).Hash())
+}
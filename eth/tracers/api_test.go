@@ -64,6 +64,8 @@ type testBackend struct {
 	chaindb     ethdb.Database
 	chain       *core.BlockChain
 
+	maxBlocksPerRequest int64
+
 	refHook func() // Hook is invoked when the requested state is referenced
 	relHook func() // Hook is invoked when the requested state is released
 }
@@ -140,6 +142,10 @@ func (b *testBackend) RPCGasCap() uint64 {
 	return 25000000
 }
 
+func (b *testBackend) GetMaxBlocksPerRequest() int64 {
+	return b.maxBlocksPerRequest
+}
+
 func (b *testBackend) ChainConfig() *params.ChainConfig {
 	return b.chainConfig
 }
@@ -185,6 +191,15 @@ func (b *testBackend) StateAtNextBlock(ctx context.Context, parent, nextBlock *t
 		release()
 		return nil, nil, err
 	}
+	// Apply the system calls of the next block
+	context := core.NewEVMBlockContext(nextBlock.Header(), b.chain, nil)
+	vmenv := vm.NewEVM(context, vm.TxContext{}, statedb, b.chainConfig, vm.Config{})
+	if beaconRoot := nextBlock.BeaconRoot(); beaconRoot != nil {
+		core.ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
+	}
+	if b.chainConfig.IsPrague(nextBlock.Time()) {
+		core.ProcessParentBlockHash(nextBlock.ParentHash(), vmenv, statedb)
+	}
 
 	return statedb, release, nil
 }
@@ -211,7 +226,7 @@ func (b *testBackend) StateAtTransaction(ctx context.Context, block *types.Block
 			return msg, context, statedb, release, nil
 		}
 		vmenv := vm.NewEVM(context, txContext, statedb, b.chainConfig, vm.Config{})
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
+		if _, err := core.ApplyMessage(b.chainConfig, vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
 			return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
 		}
 		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
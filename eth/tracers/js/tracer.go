@@ -1,4 +0,0 @@
-package js
-
-// Placeholder JS tracer
-type Tracer interface{}
@@ -0,0 +1,72 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/evm/eth/tracers"
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNativeTracersRegistered(t *testing.T) {
+	chainConfig := params.TestChainConfig.ToEthChainConfig()
+	for _, name := range []string{"callTracer", "prestateTracer", "4byteTracer", "noopTracer", "muxTracer", "vmTracer"} {
+		t.Run(name, func(t *testing.T) {
+			require.False(t, tracers.DefaultDirectory.IsJS(name))
+			tracer, err := tracers.DefaultDirectory.New(name, new(tracers.Context), nil, chainConfig)
+			require.NoError(t, err)
+			require.NotNil(t, tracer.Hooks)
+			require.NotNil(t, tracer.GetResult)
+			require.NotNil(t, tracer.Stop)
+		})
+	}
+}
+
+func TestCallTracerInvalidConfig(t *testing.T) {
+	_, err := tracers.DefaultDirectory.New("callTracer", new(tracers.Context), json.RawMessage(`{"onlyTopCall":"yes"}`), params.TestChainConfig.ToEthChainConfig())
+	require.Error(t, err)
+}
+
+func TestCallTracerFrames(t *testing.T) {
+	tracer, err := tracers.DefaultDirectory.New("callTracer", new(tracers.Context), nil, params.TestChainConfig.ToEthChainConfig())
+	require.NoError(t, err)
+
+	var (
+		from   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		to     = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		callee = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	)
+	tracer.OnEnter(0, byte(vm.CALL), from, to, []byte{0x01}, 100_000, big.NewInt(1))
+	tracer.OnEnter(1, byte(vm.STATICCALL), to, callee, []byte{0x02}, 50_000, nil)
+	tracer.OnExit(1, []byte{0x03}, 1_000, nil, false)
+	tracer.OnExit(0, nil, 21_000, nil, false)
+
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+
+	var frame struct {
+		Type  string `json:"type"`
+		From  common.Address
+		To    common.Address
+		Calls []struct {
+			Type   string `json:"type"`
+			To     common.Address
+			Output string `json:"output"`
+		} `json:"calls"`
+	}
+	require.NoError(t, json.Unmarshal(res, &frame))
+	require.Equal(t, "CALL", frame.Type)
+	require.Equal(t, from, frame.From)
+	require.Equal(t, to, frame.To)
+	require.Len(t, frame.Calls, 1)
+	require.Equal(t, "STATICCALL", frame.Calls[0].Type)
+	require.Equal(t, callee, frame.Calls[0].To)
+	require.Equal(t, "0x03", frame.Calls[0].Output)
+}
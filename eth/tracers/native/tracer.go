@@ -1,4 +0,0 @@
-package native
-
-// Placeholder native tracer
-type Tracer interface{}
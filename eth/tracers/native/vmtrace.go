@@ -0,0 +1,234 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/evm/eth/tracers"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/core/tracing"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/luxfi/geth/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("vmTracer", newVMTracer, false)
+}
+
+// vmTrace is the trace of the code executed by a single call frame, in the
+// vmTrace format of the Parity trace namespace.
+type vmTrace struct {
+	Code hexutil.Bytes `json:"code"`
+	Ops  []*vmOp       `json:"ops"`
+}
+
+// vmOp is a single executed opcode.
+type vmOp struct {
+	Cost uint64      `json:"cost"`
+	Ex   *vmExecuted `json:"ex"`
+	Pc   uint64      `json:"pc"`
+	Sub  *vmTrace    `json:"sub"`
+}
+
+// vmExecuted holds the effects of an executed opcode.
+type vmExecuted struct {
+	Mem   *vmMem         `json:"mem"`
+	Push  []*hexutil.Big `json:"push"`
+	Store *vmStore       `json:"store"`
+	Used  uint64         `json:"used"`
+}
+
+type vmMem struct {
+	Data hexutil.Bytes `json:"data"`
+	Off  uint64        `json:"off"`
+}
+
+type vmStore struct {
+	Key *hexutil.Big `json:"key"`
+	Val *hexutil.Big `json:"val"`
+}
+
+// vmFrame is a call frame being traced.
+type vmFrame struct {
+	trace    *vmTrace
+	gas      uint64    // Gas available to the frame
+	op       vm.OpCode // Last executed opcode
+	last     *vmOp     // Last executed opcode, waiting for its effects
+	memOff   uint64    // Memory region written by the last opcode
+	memSize  uint64
+	storeKey *uint256.Int // Storage slot written by the last opcode
+	storeVal *uint256.Int
+}
+
+// vmTracer records the executed opcodes and their effects on the stack, the
+// memory and the storage, for the vmTrace mode of the trace namespace.
+type vmTracer struct {
+	env       *tracing.VMContext
+	root      *vmTrace
+	frames    []*vmFrame
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+func newVMTracer(ctx *tracers.Context, cfg json.RawMessage, chainConfig *params.ChainConfig) (*tracers.Tracer, error) {
+	t := &vmTracer{}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: t.OnTxStart,
+			OnEnter:   t.OnEnter,
+			OnExit:    t.OnExit,
+			OnOpcode:  t.OnOpcode,
+		},
+		GetResult: t.GetResult,
+		Stop:      t.Stop,
+	}, nil
+}
+
+func (t *vmTracer) OnTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	t.env = env
+}
+
+// OnEnter starts the trace of a new call frame, as a sub trace of the opcode
+// that created it.
+func (t *vmTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.interrupt.Load() {
+		return
+	}
+	trace := &vmTrace{Ops: []*vmOp{}}
+	switch vm.OpCode(typ) {
+	case vm.CREATE, vm.CREATE2:
+		trace.Code = common.CopyBytes(input)
+	case vm.SELFDESTRUCT:
+		// Nothing is executed by the beneficiary
+		return
+	default:
+		trace.Code = t.env.StateDB.GetCode(to)
+	}
+	if len(t.frames) == 0 {
+		t.root = trace
+	} else if parent := t.frames[len(t.frames)-1]; parent.last != nil {
+		parent.last.Sub = trace
+	}
+	t.frames = append(t.frames, &vmFrame{trace: trace, gas: gas})
+}
+
+// OnExit completes the last opcode of the current call frame and returns to
+// its parent.
+func (t *vmTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.interrupt.Load() || len(t.frames) == 0 || len(t.frames) != depth+1 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	if frame.last != nil && err == nil {
+		var used uint64
+		if gasUsed < frame.gas {
+			used = frame.gas - gasUsed
+		}
+		frame.last.Ex = &vmExecuted{Push: []*hexutil.Big{}, Used: used}
+	}
+	t.frames = t.frames[:len(t.frames)-1]
+}
+
+// OnOpcode completes the previous opcode of the call frame with the effects now
+// visible on the stack and memory, and records the new one.
+func (t *vmTracer) OnOpcode(pc uint64, opcode byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	if t.interrupt.Load() || len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	if frame.last != nil {
+		frame.complete(scope, gas)
+	}
+	op := &vmOp{Cost: cost, Pc: pc}
+	frame.trace.Ops = append(frame.trace.Ops, op)
+	frame.op, frame.last = vm.OpCode(opcode), op
+	frame.memOff, frame.memSize = 0, 0
+	frame.storeKey, frame.storeVal = nil, nil
+
+	stack := scope.StackData()
+	peek := func(n int) *uint256.Int {
+		if n >= len(stack) {
+			return new(uint256.Int)
+		}
+		return &stack[len(stack)-1-n]
+	}
+	switch frame.op {
+	case vm.MSTORE:
+		frame.memOff, frame.memSize = peek(0).Uint64(), 32
+	case vm.MSTORE8:
+		frame.memOff, frame.memSize = peek(0).Uint64(), 1
+	case vm.CALLDATACOPY, vm.CODECOPY, vm.RETURNDATACOPY, vm.MCOPY:
+		frame.memOff, frame.memSize = peek(0).Uint64(), peek(2).Uint64()
+	case vm.EXTCODECOPY:
+		frame.memOff, frame.memSize = peek(1).Uint64(), peek(3).Uint64()
+	case vm.SSTORE:
+		frame.storeKey, frame.storeVal = new(uint256.Int).Set(peek(0)), new(uint256.Int).Set(peek(1))
+	}
+}
+
+// complete fills the effects of the last opcode of the frame.
+func (f *vmFrame) complete(scope tracing.OpContext, gas uint64) {
+	ex := &vmExecuted{Push: []*hexutil.Big{}, Used: gas}
+	stack := scope.StackData()
+	if n := stackPushes(f.op); n <= len(stack) {
+		for _, item := range stack[len(stack)-n:] {
+			ex.Push = append(ex.Push, (*hexutil.Big)(item.ToBig()))
+		}
+	}
+	if memory := scope.MemoryData(); f.memSize > 0 && f.memOff+f.memSize <= uint64(len(memory)) {
+		ex.Mem = &vmMem{
+			Data: common.CopyBytes(memory[f.memOff : f.memOff+f.memSize]),
+			Off:  f.memOff,
+		}
+	}
+	if f.storeKey != nil {
+		ex.Store = &vmStore{
+			Key: (*hexutil.Big)(f.storeKey.ToBig()),
+			Val: (*hexutil.Big)(f.storeVal.ToBig()),
+		}
+	}
+	f.last.Ex, f.last = ex, nil
+}
+
+// stackPushes returns the number of stack items reported as pushed by the
+// given opcode.
+func stackPushes(op vm.OpCode) int {
+	switch {
+	case op >= vm.PUSH0 && op <= vm.PUSH32, op >= vm.DUP1 && op <= vm.DUP16:
+		return 1
+	case op >= vm.SWAP1 && op <= vm.SWAP16:
+		return int(op-vm.SWAP1) + 2
+	case op >= vm.LOG0 && op <= vm.LOG4:
+		return 0
+	}
+	switch op {
+	case vm.STOP, vm.POP, vm.MSTORE, vm.MSTORE8, vm.SSTORE, vm.TSTORE, vm.JUMP, vm.JUMPI, vm.JUMPDEST,
+		vm.CALLDATACOPY, vm.CODECOPY, vm.EXTCODECOPY, vm.RETURNDATACOPY, vm.MCOPY,
+		vm.RETURN, vm.REVERT, vm.SELFDESTRUCT, vm.INVALID:
+		return 0
+	}
+	return 1
+}
+
+// GetResult returns the json-encoded vmTrace of the transaction, and any error
+// arising from the encoding or forceful termination (via `Stop`).
+func (t *vmTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.root)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *vmTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
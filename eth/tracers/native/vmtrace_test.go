@@ -0,0 +1,105 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/evm/core/state"
+	"github.com/luxfi/evm/core/vm/runtime"
+	"github.com/luxfi/evm/eth/tracers"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/tracing"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVMTracerCall(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		origin = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		main   = common.HexToAddress("0xaa")
+		callee = common.HexToAddress("0xbb")
+		// Writes 42 to memory and calls the callee with it
+		mainCode = []byte{
+			byte(vm.PUSH1), 0x2a, byte(vm.PUSH1), 0x00, byte(vm.MSTORE),
+			byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00,
+			byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0xbb, byte(vm.GAS), byte(vm.CALL),
+			byte(vm.STOP),
+		}
+		// Stores 1 in slot 0
+		calleeCode = []byte{
+			byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x00, byte(vm.SSTORE),
+			byte(vm.STOP),
+		}
+	)
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(err)
+	statedb.SetCode(main, mainCode)
+	statedb.SetCode(callee, calleeCode)
+
+	tracer, err := tracers.DefaultDirectory.New("vmTracer", new(tracers.Context), nil, nil)
+	require.NoError(err)
+	tracer.OnTxStart(&tracing.VMContext{StateDB: statedb}, nil, origin)
+	_, _, err = runtime.Call(main, nil, &runtime.Config{
+		Origin:    origin,
+		GasLimit:  1_000_000,
+		State:     statedb,
+		EVMConfig: vm.Config{Tracer: tracer.Hooks},
+	})
+	require.NoError(err)
+
+	res, err := tracer.GetResult()
+	require.NoError(err)
+	var trace vmTrace
+	require.NoError(json.Unmarshal(res, &trace))
+
+	// Every executed opcode of the call is traced, at its program counter.
+	require.Equal(hexutil.Bytes(mainCode), trace.Code)
+	pcs := make([]uint64, len(trace.Ops))
+	for i, op := range trace.Ops {
+		pcs[i] = op.Pc
+		require.NotNil(op.Ex, "pc %d", op.Pc)
+	}
+	require.Equal([]uint64{0, 2, 4, 5, 7, 9, 11, 13, 15, 17, 18, 19}, pcs)
+
+	push := trace.Ops[0]
+	require.Equal(uint64(3), push.Cost)
+	require.Equal([]*hexutil.Big{(*hexutil.Big)(big.NewInt(0x2a))}, push.Ex.Push)
+	require.Nil(push.Ex.Mem)
+	require.Nil(push.Sub)
+
+	// The memory written by MSTORE is reported.
+	mstore := trace.Ops[2]
+	require.Empty(mstore.Ex.Push)
+	require.Equal(&vmMem{Data: common.LeftPadBytes([]byte{0x2a}, 32), Off: 0}, mstore.Ex.Mem)
+
+	// The CALL pushes its success, and holds the trace of the callee.
+	call := trace.Ops[10]
+	require.Equal([]*hexutil.Big{(*hexutil.Big)(big.NewInt(1))}, call.Ex.Push)
+	require.Less(call.Ex.Used, trace.Ops[9].Ex.Used)
+	require.NotNil(call.Sub)
+	require.Equal(hexutil.Bytes(calleeCode), call.Sub.Code)
+
+	subPcs := make([]uint64, len(call.Sub.Ops))
+	for i, op := range call.Sub.Ops {
+		subPcs[i] = op.Pc
+	}
+	require.Equal([]uint64{0, 2, 4, 5}, subPcs)
+
+	// The storage written by SSTORE is reported.
+	sstore := call.Sub.Ops[2]
+	require.Empty(sstore.Ex.Push)
+	require.Equal(&vmStore{
+		Key: (*hexutil.Big)(big.NewInt(0)),
+		Val: (*hexutil.Big)(big.NewInt(1)),
+	}, sstore.Ex.Store)
+	require.Equal(common.BigToHash(big.NewInt(1)), statedb.GetState(callee, common.Hash{}))
+}
@@ -0,0 +1,623 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/luxfi/evm/internal/ethapi"
+	"github.com/luxfi/evm/rpc"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/core/types"
+)
+
+// Trace types accepted by trace_replayBlockTransactions and trace_call.
+const (
+	traceTypeTrace     = "trace"
+	traceTypeStateDiff = "stateDiff"
+	traceTypeVMTrace   = "vmTrace"
+)
+
+// TraceAPI is the collection of Parity-style tracing APIs exposed over the
+// trace namespace. The traces are built from the native call, prestate and vm
+// tracers, which must be registered in [DefaultDirectory].
+type TraceAPI struct {
+	api *API
+}
+
+// NewTraceAPI creates a new API definition for the Parity-style tracing
+// methods of the Ethereum service.
+func NewTraceAPI(backend Backend) *TraceAPI {
+	return &TraceAPI{api: NewAPI(backend)}
+}
+
+// ParityTrace is a single call of a transaction, flattened out of the call tree
+// at the position given by its trace address.
+type ParityTrace struct {
+	Action              interface{}     `json:"action"`
+	BlockHash           *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber         *uint64         `json:"blockNumber,omitempty"`
+	Error               string          `json:"error,omitempty"`
+	Result              interface{}     `json:"result"`
+	Subtraces           int             `json:"subtraces"`
+	TraceAddress        []int           `json:"traceAddress"`
+	TransactionHash     *common.Hash    `json:"transactionHash,omitempty"`
+	TransactionPosition *uint64         `json:"transactionPosition,omitempty"`
+	Type                string          `json:"type"`
+	from, to            *common.Address // Addresses matched by trace_filter
+}
+
+type callAction struct {
+	CallType string          `json:"callType"`
+	From     common.Address  `json:"from"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	Input    hexutil.Bytes   `json:"input"`
+	To       *common.Address `json:"to"`
+	Value    *hexutil.Big    `json:"value"`
+}
+
+type callResult struct {
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Output  hexutil.Bytes  `json:"output"`
+}
+
+type createAction struct {
+	CreationMethod string         `json:"creationMethod"`
+	From           common.Address `json:"from"`
+	Gas            hexutil.Uint64 `json:"gas"`
+	Init           hexutil.Bytes  `json:"init"`
+	Value          *hexutil.Big   `json:"value"`
+}
+
+type createResult struct {
+	Address *common.Address `json:"address"`
+	Code    hexutil.Bytes   `json:"code"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+}
+
+type suicideAction struct {
+	Address       common.Address  `json:"address"`
+	Balance       *hexutil.Big    `json:"balance"`
+	RefundAddress *common.Address `json:"refundAddress"`
+}
+
+// TraceResults is the result of replaying a transaction with the requested
+// trace types. The results of the trace types that were not requested are nil.
+type TraceResults struct {
+	Output          hexutil.Bytes                   `json:"output"`
+	StateDiff       map[common.Address]*AccountDiff `json:"stateDiff"`
+	Trace           []*ParityTrace                  `json:"trace"`
+	VMTrace         json.RawMessage                 `json:"vmTrace"`
+	TransactionHash *common.Hash                    `json:"transactionHash,omitempty"`
+}
+
+// AccountDiff is the change of an account made by a transaction. Each field is
+// either "=" when unchanged, or an object keyed by "+" when the account was
+// created, "-" when it was deleted, or "*" with the from and to values when it
+// was modified.
+type AccountDiff struct {
+	Balance interface{}                 `json:"balance"`
+	Code    interface{}                 `json:"code"`
+	Nonce   interface{}                 `json:"nonce"`
+	Storage map[common.Hash]interface{} `json:"storage"`
+}
+
+// diffChange is the value of a modified field of an [AccountDiff].
+type diffChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+const diffSame = "="
+
+func diffBorn(value interface{}) interface{} {
+	return map[string]interface{}{"+": value}
+}
+
+func diffDied(value interface{}) interface{} {
+	return map[string]interface{}{"-": value}
+}
+
+func diffChanged(from, to interface{}) interface{} {
+	return map[string]interface{}{"*": &diffChange{From: from, To: to}}
+}
+
+// TraceFilterArgs are the arguments of trace_filter.
+type TraceFilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	After       *uint64          `json:"after"`
+	Count       *uint64          `json:"count"`
+}
+
+// matches returns whether the trace was sent from one of the from addresses and
+// to one of the to addresses of the filter. An empty address list matches all
+// the traces.
+func (args *TraceFilterArgs) matches(trace *ParityTrace) bool {
+	return matchesAddress(args.FromAddress, trace.from) && matchesAddress(args.ToAddress, trace.to)
+}
+
+func matchesAddress(addresses []common.Address, addr *common.Address) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+	if addr == nil {
+		return false
+	}
+	for _, a := range addresses {
+		if a == *addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Block returns the traces of all the calls made by the transactions of the
+// given block.
+func (api *TraceAPI) Block(ctx context.Context, number rpc.BlockNumber) ([]*ParityTrace, error) {
+	block, err := api.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return api.blockTraces(ctx, block)
+}
+
+// Transaction returns the traces of all the calls made by the given
+// transaction.
+func (api *TraceAPI) Transaction(ctx context.Context, hash common.Hash) ([]*ParityTrace, error) {
+	found, _, blockHash, blockNumber, index, err := api.api.backend.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, ethapi.NewTxIndexingError()
+	}
+	if !found {
+		return nil, errTxNotFound
+	}
+	config, err := parityTraceConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := api.api.TraceTransaction(ctx, hash, config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := decodeParityResult(res)
+	if err != nil {
+		return nil, err
+	}
+	pos := &txPosition{blockHash: blockHash, blockNumber: blockNumber, txHash: hash, index: index}
+	return flattenCallFrame(result.Call, []int{}, pos, nil), nil
+}
+
+// Filter returns the traces of the calls matching the given filter, in the
+// given range of blocks. The range is limited by the maximum number of blocks
+// per request of the backend.
+func (api *TraceAPI) Filter(ctx context.Context, args TraceFilterArgs) ([]*ParityTrace, error) {
+	from, err := api.blockNumber(ctx, args.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.blockNumber(ctx, args.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	if to < from {
+		return nil, fmt.Errorf("begin block %d is greater than end block %d", from, to)
+	}
+	if maxBlocks := api.api.backend.GetMaxBlocksPerRequest(); maxBlocks > 0 && to-from >= uint64(maxBlocks) {
+		return nil, fmt.Errorf("requested too many blocks from %d to %d, maximum is set to %d", from, to, maxBlocks)
+	}
+	var (
+		traces  = []*ParityTrace{}
+		skipped uint64
+	)
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := api.api.blockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		blockTraces, err := api.blockTraces(ctx, block)
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range blockTraces {
+			if !args.matches(trace) {
+				continue
+			}
+			if args.After != nil && skipped < *args.After {
+				skipped++
+				continue
+			}
+			if args.Count != nil && uint64(len(traces)) >= *args.Count {
+				return traces, nil
+			}
+			traces = append(traces, trace)
+		}
+	}
+	return traces, nil
+}
+
+// ReplayBlockTransactions replays all the transactions of the given block and
+// returns the requested trace types for each of them.
+func (api *TraceAPI) ReplayBlockTransactions(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, traceTypes []string) ([]*TraceResults, error) {
+	config, err := parityTraceConfig(traceTypes)
+	if err != nil {
+		return nil, err
+	}
+	block, err := api.blockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block.NumberU64() == 0 {
+		return []*TraceResults{}, nil
+	}
+	txResults, err := api.api.traceBlock(ctx, block, config)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*TraceResults, len(txResults))
+	for i, txResult := range txResults {
+		if txResult.Error != "" {
+			return nil, errors.New(txResult.Error)
+		}
+		result, err := decodeParityResult(txResult.Result)
+		if err != nil {
+			return nil, err
+		}
+		txHash := txResult.TxHash
+		results[i] = result.traceResults(traceTypes)
+		results[i].TransactionHash = &txHash
+	}
+	return results, nil
+}
+
+// Call executes the given call on top of the given block, latest by default, and
+// returns the requested trace types.
+func (api *TraceAPI) Call(ctx context.Context, args ethapi.TransactionArgs, traceTypes []string, blockNrOrHash *rpc.BlockNumberOrHash) (*TraceResults, error) {
+	config, err := parityTraceConfig(traceTypes)
+	if err != nil {
+		return nil, err
+	}
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	res, err := api.api.TraceCall(ctx, args, *blockNrOrHash, &TraceCallConfig{TraceConfig: *config})
+	if err != nil {
+		return nil, err
+	}
+	result, err := decodeParityResult(res)
+	if err != nil {
+		return nil, err
+	}
+	return result.traceResults(traceTypes), nil
+}
+
+// blockTraces returns the flattened call traces of all the transactions of the
+// given block.
+func (api *TraceAPI) blockTraces(ctx context.Context, block *types.Block) ([]*ParityTrace, error) {
+	traces := []*ParityTrace{}
+	// The genesis block has no transactions to trace
+	if block.NumberU64() == 0 {
+		return traces, nil
+	}
+	config, err := parityTraceConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	txResults, err := api.api.traceBlock(ctx, block, config)
+	if err != nil {
+		return nil, err
+	}
+	for i, txResult := range txResults {
+		if txResult.Error != "" {
+			return nil, errors.New(txResult.Error)
+		}
+		result, err := decodeParityResult(txResult.Result)
+		if err != nil {
+			return nil, err
+		}
+		pos := &txPosition{
+			blockHash:   block.Hash(),
+			blockNumber: block.NumberU64(),
+			txHash:      txResult.TxHash,
+			index:       uint64(i),
+		}
+		traces = flattenCallFrame(result.Call, []int{}, pos, traces)
+	}
+	return traces, nil
+}
+
+// blockNumber resolves the given block number, latest by default, to the number
+// of a known block.
+func (api *TraceAPI) blockNumber(ctx context.Context, number *rpc.BlockNumber) (uint64, error) {
+	n := rpc.LatestBlockNumber
+	if number != nil {
+		n = *number
+	}
+	header, err := api.api.backend.HeaderByNumber(ctx, n)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block #%d not found", n)
+	}
+	return header.Number.Uint64(), nil
+}
+
+func (api *TraceAPI) blockByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Block, error) {
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		return api.api.blockByHash(ctx, hash)
+	}
+	if number, ok := blockNrOrHash.Number(); ok {
+		return api.api.blockByNumber(ctx, number)
+	}
+	return nil, errors.New("invalid arguments; neither block nor hash specified")
+}
+
+// parityTraceConfig returns the configuration of the mux tracer producing the
+// given trace types. The call tracer is always run, as it provides the output
+// of the transaction.
+func parityTraceConfig(traceTypes []string) (*TraceConfig, error) {
+	tracers := map[string]json.RawMessage{
+		"callTracer": json.RawMessage(`{}`),
+	}
+	for _, typ := range traceTypes {
+		switch typ {
+		case traceTypeTrace:
+		case traceTypeStateDiff:
+			tracers["prestateTracer"] = json.RawMessage(`{"diffMode":true}`)
+		case traceTypeVMTrace:
+			tracers["vmTracer"] = json.RawMessage(`{}`)
+		default:
+			return nil, fmt.Errorf("unknown trace type %q", typ)
+		}
+	}
+	tracerConfig, err := json.Marshal(tracers)
+	if err != nil {
+		return nil, err
+	}
+	tracer := "muxTracer"
+	return &TraceConfig{Tracer: &tracer, TracerConfig: tracerConfig}, nil
+}
+
+// parityResult is the result of the mux tracer configured by
+// [parityTraceConfig].
+type parityResult struct {
+	Call     *callFrame      `json:"callTracer"`
+	Prestate *prestateDiff   `json:"prestateTracer"`
+	VMTrace  json.RawMessage `json:"vmTracer"`
+}
+
+// callFrame is the result of the call tracer.
+type callFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to"`
+	Gas     hexutil.Uint64  `json:"gas"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Input   hexutil.Bytes   `json:"input"`
+	Output  hexutil.Bytes   `json:"output"`
+	Error   string          `json:"error"`
+	Value   *hexutil.Big    `json:"value"`
+	Calls   []*callFrame    `json:"calls"`
+}
+
+// prestateDiff is the result of the prestate tracer in diff mode.
+type prestateDiff struct {
+	Pre  map[common.Address]*prestateAccount `json:"pre"`
+	Post map[common.Address]*prestateAccount `json:"post"`
+}
+
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Code    hexutil.Bytes               `json:"code"`
+	Nonce   uint64                      `json:"nonce"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+func decodeParityResult(res interface{}) (*parityResult, error) {
+	raw, ok := res.(json.RawMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected trace result type %T", res)
+	}
+	result := new(parityResult)
+	if err := json.Unmarshal(raw, result); err != nil {
+		return nil, err
+	}
+	if result.Call == nil {
+		return nil, errors.New("missing call trace")
+	}
+	return result, nil
+}
+
+// traceResults converts the result to the given trace types.
+func (r *parityResult) traceResults(traceTypes []string) *TraceResults {
+	results := &TraceResults{Output: r.Call.Output}
+	for _, typ := range traceTypes {
+		switch typ {
+		case traceTypeTrace:
+			results.Trace = flattenCallFrame(r.Call, []int{}, nil, nil)
+		case traceTypeStateDiff:
+			results.StateDiff = r.Prestate.stateDiff()
+		case traceTypeVMTrace:
+			results.VMTrace = r.VMTrace
+		}
+	}
+	return results
+}
+
+// txPosition locates a traced transaction in the chain.
+type txPosition struct {
+	blockHash   common.Hash
+	blockNumber uint64
+	txHash      common.Hash
+	index       uint64
+}
+
+// flattenCallFrame appends the trace of the given call frame and of all its sub
+// calls, depth first, to traces.
+func flattenCallFrame(frame *callFrame, traceAddress []int, pos *txPosition, traces []*ParityTrace) []*ParityTrace {
+	trace := &ParityTrace{
+		Error:        parityError(frame.Error),
+		Subtraces:    len(frame.Calls),
+		TraceAddress: traceAddress,
+	}
+	if pos != nil {
+		trace.BlockHash = &pos.blockHash
+		trace.BlockNumber = &pos.blockNumber
+		trace.TransactionHash = &pos.txHash
+		trace.TransactionPosition = &pos.index
+	}
+	value := frame.Value
+	if value == nil {
+		value = (*hexutil.Big)(new(big.Int))
+	}
+	from := frame.From
+	switch frame.Type {
+	case "CREATE", "CREATE2":
+		trace.Type = "create"
+		trace.Action = &createAction{
+			CreationMethod: strings.ToLower(frame.Type),
+			From:           frame.From,
+			Gas:            frame.Gas,
+			Init:           frame.Input,
+			Value:          value,
+		}
+		if frame.Error == "" {
+			trace.Result = &createResult{
+				Address: frame.To,
+				Code:    frame.Output,
+				GasUsed: frame.GasUsed,
+			}
+		}
+	case "SELFDESTRUCT":
+		trace.Type = "suicide"
+		trace.Action = &suicideAction{
+			Address:       frame.From,
+			Balance:       value,
+			RefundAddress: frame.To,
+		}
+	default:
+		trace.Type = "call"
+		trace.Action = &callAction{
+			CallType: strings.ToLower(frame.Type),
+			From:     frame.From,
+			Gas:      frame.Gas,
+			Input:    frame.Input,
+			To:       frame.To,
+			Value:    value,
+		}
+		if frame.Error == "" {
+			trace.Result = &callResult{
+				GasUsed: frame.GasUsed,
+				Output:  frame.Output,
+			}
+		}
+	}
+	trace.from, trace.to = &from, frame.To
+	traces = append(traces, trace)
+
+	for i, call := range frame.Calls {
+		subAddress := make([]int, len(traceAddress)+1)
+		copy(subAddress, traceAddress)
+		subAddress[len(traceAddress)] = i
+		traces = flattenCallFrame(call, subAddress, pos, traces)
+	}
+	return traces
+}
+
+// parityError converts the error of a call frame to its Parity equivalent.
+func parityError(err string) string {
+	switch err {
+	case "execution reverted":
+		return "Reverted"
+	case "out of gas":
+		return "Out of gas"
+	}
+	return err
+}
+
+// stateDiff converts the prestate diff to the Parity state diff.
+func (d *prestateDiff) stateDiff() map[common.Address]*AccountDiff {
+	diffs := make(map[common.Address]*AccountDiff)
+	if d == nil {
+		return diffs
+	}
+	for addr, post := range d.Post {
+		pre, ok := d.Pre[addr]
+		if !ok {
+			diff := &AccountDiff{
+				Balance: diffBorn(post.balance()),
+				Code:    diffBorn(post.Code),
+				Nonce:   diffBorn(hexutil.Uint64(post.Nonce)),
+				Storage: make(map[common.Hash]interface{}),
+			}
+			for key, val := range post.Storage {
+				diff.Storage[key] = diffBorn(val)
+			}
+			diffs[addr] = diff
+			continue
+		}
+		// Only the modified fields are reported in the post state
+		diff := &AccountDiff{
+			Balance: diffSame,
+			Code:    diffSame,
+			Nonce:   diffSame,
+			Storage: make(map[common.Hash]interface{}),
+		}
+		if post.Balance != nil {
+			diff.Balance = diffChanged(pre.balance(), post.balance())
+		}
+		if post.Code != nil {
+			diff.Code = diffChanged(pre.Code, post.Code)
+		}
+		if post.Nonce != 0 && post.Nonce != pre.Nonce {
+			diff.Nonce = diffChanged(hexutil.Uint64(pre.Nonce), hexutil.Uint64(post.Nonce))
+		}
+		for key, val := range post.Storage {
+			diff.Storage[key] = diffChanged(pre.Storage[key], val)
+		}
+		// Slots cleared to zero are left out of the post state
+		for key, val := range pre.Storage {
+			if _, ok := post.Storage[key]; !ok {
+				diff.Storage[key] = diffChanged(val, common.Hash{})
+			}
+		}
+		diffs[addr] = diff
+	}
+	for addr, pre := range d.Pre {
+		if _, ok := d.Post[addr]; ok {
+			continue
+		}
+		diff := &AccountDiff{
+			Balance: diffDied(pre.balance()),
+			Code:    diffDied(pre.Code),
+			Nonce:   diffDied(hexutil.Uint64(pre.Nonce)),
+			Storage: make(map[common.Hash]interface{}),
+		}
+		for key, val := range pre.Storage {
+			diff.Storage[key] = diffDied(val)
+		}
+		diffs[addr] = diff
+	}
+	return diffs
+}
+
+func (a *prestateAccount) balance() *hexutil.Big {
+	if a.Balance == nil {
+		return (*hexutil.Big)(new(big.Int))
+	}
+	return a.Balance
+}
@@ -0,0 +1,151 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/evm/core"
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/rpc"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenCallFrame(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		sender   = common.Address{1}
+		contract = common.Address{2}
+		created  = common.Address{3}
+		pos      = &txPosition{blockHash: common.Hash{4}, blockNumber: 5, txHash: common.Hash{6}, index: 7}
+	)
+	frame := &callFrame{
+		Type:    "CALL",
+		From:    sender,
+		To:      &contract,
+		Gas:     100000,
+		GasUsed: 50000,
+		Output:  hexutil.Bytes{0x01},
+		Calls: []*callFrame{
+			{Type: "CREATE2", From: contract, To: &created, Gas: 30000, GasUsed: 20000, Input: hexutil.Bytes{0x60}},
+			{Type: "STATICCALL", From: contract, To: &created, Gas: 1000, GasUsed: 1000, Error: "execution reverted"},
+			{Type: "SELFDESTRUCT", From: contract, To: &sender, Value: (*hexutil.Big)(big.NewInt(10))},
+		},
+	}
+	traces := flattenCallFrame(frame, []int{}, pos, nil)
+	require.Len(traces, 4)
+
+	require.Equal("call", traces[0].Type)
+	require.Equal([]int{}, traces[0].TraceAddress)
+	require.Equal(3, traces[0].Subtraces)
+	require.Equal(&callResult{GasUsed: 50000, Output: hexutil.Bytes{0x01}}, traces[0].Result)
+	require.Equal(common.Hash{6}, *traces[0].TransactionHash)
+	require.Equal(uint64(7), *traces[0].TransactionPosition)
+
+	require.Equal("create", traces[1].Type)
+	require.Equal([]int{0}, traces[1].TraceAddress)
+	require.Equal("create2", traces[1].Action.(*createAction).CreationMethod)
+	require.Equal(&created, traces[1].Result.(*createResult).Address)
+
+	require.Equal([]int{1}, traces[2].TraceAddress)
+	require.Equal("staticcall", traces[2].Action.(*callAction).CallType)
+	require.Equal("Reverted", traces[2].Error)
+	require.Nil(traces[2].Result)
+
+	require.Equal("suicide", traces[3].Type)
+	require.Equal(&suicideAction{
+		Address:       contract,
+		Balance:       (*hexutil.Big)(big.NewInt(10)),
+		RefundAddress: &sender,
+	}, traces[3].Action)
+
+	// Calls made without a position, as in trace_call, have no block fields
+	encoded, err := json.Marshal(flattenCallFrame(frame, []int{}, nil, nil)[0])
+	require.NoError(err)
+	require.NotContains(string(encoded), "blockHash")
+	require.NotContains(string(encoded), "transactionPosition")
+}
+
+func TestPrestateDiffStateDiff(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		modified = common.Address{1}
+		created  = common.Address{2}
+		deleted  = common.Address{3}
+		slot     = common.Hash{1}
+		cleared  = common.Hash{2}
+	)
+	diff := &prestateDiff{
+		Pre: map[common.Address]*prestateAccount{
+			modified: {
+				Balance: (*hexutil.Big)(big.NewInt(100)),
+				Nonce:   1,
+				Storage: map[common.Hash]common.Hash{slot: {1}, cleared: {2}},
+			},
+			deleted: {Balance: (*hexutil.Big)(big.NewInt(5))},
+		},
+		Post: map[common.Address]*prestateAccount{
+			modified: {
+				Balance: (*hexutil.Big)(big.NewInt(90)),
+				Storage: map[common.Hash]common.Hash{slot: {3}},
+			},
+			created: {Nonce: 1, Code: hexutil.Bytes{0x60}},
+		},
+	}
+	stateDiff := diff.stateDiff()
+	require.Len(stateDiff, 3)
+
+	require.Equal(diffChanged((*hexutil.Big)(big.NewInt(100)), (*hexutil.Big)(big.NewInt(90))), stateDiff[modified].Balance)
+	require.Equal(diffSame, stateDiff[modified].Nonce)
+	require.Equal(diffSame, stateDiff[modified].Code)
+	require.Equal(diffChanged(common.Hash{1}, common.Hash{3}), stateDiff[modified].Storage[slot])
+	require.Equal(diffChanged(common.Hash{2}, common.Hash{}), stateDiff[modified].Storage[cleared])
+
+	require.Equal(diffBorn(hexutil.Uint64(1)), stateDiff[created].Nonce)
+	require.Equal(diffBorn(hexutil.Bytes{0x60}), stateDiff[created].Code)
+	require.Equal(diffBorn((*hexutil.Big)(new(big.Int))), stateDiff[created].Balance)
+
+	require.Equal(diffDied((*hexutil.Big)(big.NewInt(5))), stateDiff[deleted].Balance)
+}
+
+func TestParityTraceConfig(t *testing.T) {
+	require := require.New(t)
+
+	config, err := parityTraceConfig([]string{traceTypeTrace, traceTypeStateDiff, traceTypeVMTrace})
+	require.NoError(err)
+	require.Equal("muxTracer", *config.Tracer)
+	require.JSONEq(`{"callTracer":{},"prestateTracer":{"diffMode":true},"vmTracer":{}}`, string(config.TracerConfig))
+
+	_, err = parityTraceConfig([]string{"unknown"})
+	require.ErrorContains(err, "unknown trace type")
+}
+
+func TestTraceFilterMaxBlocks(t *testing.T) {
+	t.Parallel()
+
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  types.GenesisAlloc{},
+	}
+	backend := newTestBackend(t, 5, genesis, func(i int, b *core.BlockGen) {})
+	defer backend.chain.Stop()
+	backend.maxBlocksPerRequest = 3
+	api := NewTraceAPI(backend)
+
+	from, to := rpc.BlockNumber(1), rpc.BlockNumber(4)
+	_, err := api.Filter(context.Background(), TraceFilterArgs{FromBlock: &from, ToBlock: &to})
+	require.ErrorContains(t, err, "requested too many blocks from 1 to 4, maximum is set to 3")
+
+	from, to = rpc.BlockNumber(4), rpc.BlockNumber(1)
+	_, err = api.Filter(context.Background(), TraceFilterArgs{FromBlock: &from, ToBlock: &to})
+	require.ErrorContains(t, err, "begin block 4 is greater than end block 1")
+}
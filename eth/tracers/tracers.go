@@ -18,7 +18,12 @@
 package tracers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
 	ethtracers "github.com/luxfi/geth/eth/tracers"
+	ethparams "github.com/luxfi/geth/params"
 )
 
 // Context contains some contextual infos for a transaction execution that is not
@@ -29,9 +34,65 @@ type Context = ethtracers.Context
 // allows collecting the tracing result.
 type Tracer = ethtracers.Tracer
 
+// errJSTracerNotAvailable is returned when a tracer name is not registered and
+// no JavaScript evaluator has been registered to interpret it as code.
+var errJSTracerNotAvailable = errors.New("js tracers are not available")
+
+type ctorFn func(*Context, json.RawMessage, *ethparams.ChainConfig) (*Tracer, error)
+type jsCtorFn func(string, *Context, json.RawMessage, *ethparams.ChainConfig) (*Tracer, error)
+
+type elem struct {
+	ctor ctorFn
+	isJS bool
+}
+
 // DefaultDirectory is the collection of tracers bundled by default.
-var DefaultDirectory = ethtracers.DefaultDirectory
+var DefaultDirectory = directory{elems: make(map[string]elem)}
+
+// directory provides functionality to lookup a tracer by name
+// and a function to instantiate it. It falls back to a JS code evaluator
+// if no tracer of the given name exists.
+type directory struct {
+	elems  map[string]elem
+	jsEval jsCtorFn
+}
+
+// Register registers a method as a lookup for tracers, meaning that
+// users can invoke a named tracer through that lookup.
+func (d *directory) Register(name string, f ctorFn, isJS bool) {
+	d.elems[name] = elem{ctor: f, isJS: isJS}
+}
+
+// RegisterJSEval registers a tracer that is able to parse
+// dynamic user-provided JS code.
+func (d *directory) RegisterJSEval(f jsCtorFn) {
+	d.jsEval = f
+}
+
+// New returns a new instance of a tracer, by iterating through the
+// registered lookups. Name is either name of an existing tracer
+// or an arbitrary JS code.
+func (d *directory) New(name string, ctx *Context, cfg json.RawMessage, chainConfig *ethparams.ChainConfig) (*Tracer, error) {
+	if len(cfg) == 0 {
+		cfg = json.RawMessage("{}")
+	}
+	if elem, ok := d.elems[name]; ok {
+		return elem.ctor(ctx, cfg, chainConfig)
+	}
+	if d.jsEval == nil {
+		return nil, fmt.Errorf("%w: tracer %q not found", errJSTracerNotAvailable, name)
+	}
+	// Assume JS code
+	return d.jsEval(name, ctx, cfg, chainConfig)
+}
 
-// GetMemoryCopyPadded returns offset + size as a new slice.
-// It zero-pads the slice if it extends beyond memory bounds.
-// var GetMemoryCopyPadded = ethtracers.GetMemoryCopyPadded // TODO: Fix when ethtracers is properly imported
+// IsJS will return true if the given tracer will evaluate
+// JS code. Because code evaluation has high overhead, this
+// info will be used in determining fast and slow code paths.
+func (d *directory) IsJS(name string) bool {
+	if elem, ok := d.elems[name]; ok {
+		return elem.isJS
+	}
+	// JS eval will execute JS code
+	return true
+}
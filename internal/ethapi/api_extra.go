@@ -100,6 +100,9 @@ func (s *BlockChainAPI) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs, erro
 type FeeConfigResult struct {
 	FeeConfig     commontype.FeeConfig `json:"feeConfig"`
 	LastChangedAt *big.Int             `json:"lastChangedAt,omitempty"`
+	// PendingFeeConfig is the fee config scheduled to replace [FeeConfig]
+	// from its effective timestamp, if any.
+	PendingFeeConfig *commontype.ScheduledFeeConfig `json:"pendingFeeConfig,omitempty"`
 }
 
 func (s *BlockChainAPI) FeeConfig(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash) (*FeeConfigResult, error) {
@@ -120,7 +123,11 @@ func (s *BlockChainAPI) FeeConfig(ctx context.Context, blockNrOrHash *rpc.BlockN
 	if err != nil {
 		return nil, err
 	}
-	return &FeeConfigResult{FeeConfig: feeConfig, LastChangedAt: lastChangedAt}, nil
+	pendingFeeConfig, err := s.b.GetScheduledFeeConfigAt(header)
+	if err != nil {
+		return nil, err
+	}
+	return &FeeConfigResult{FeeConfig: feeConfig, LastChangedAt: lastChangedAt, PendingFeeConfig: pendingFeeConfig}, nil
 }
 
 // GetActivePrecompilesAt returns the active precompile configs at the given block timestamp.
@@ -4,14 +4,18 @@
 package ethapi
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/crypto"
+	"github.com/luxfi/evm/params"
 	"github.com/luxfi/evm/rpc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -130,3 +134,37 @@ func TestBlockChainAPI_stateQueryBlockNumberAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionAPI_SendPrivateRawTransaction(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	config := params.TestChainConfig
+	tx, err := types.SignNewTx(key, types.LatestSigner(config.ToEthChainConfig()), &types.LegacyTx{
+		To:       &common.Address{1},
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+	})
+	require.NoError(t, err)
+	input, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	// The transaction must be sent privately, and never through SendTx.
+	ctrl := gomock.NewController(t)
+	backend := NewMockBackend(ctrl)
+	backend.EXPECT().RPCTxFeeCap().Return(float64(1))
+	backend.EXPECT().UnprotectedAllowed(gomock.Any()).Return(false)
+	backend.EXPECT().SendPrivateTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sent *types.Transaction) error {
+			assert.Equal(t, tx.Hash(), sent.Hash())
+			return nil
+		})
+	backend.EXPECT().CurrentBlock().Return(&types.Header{Number: big.NewInt(0)})
+	backend.EXPECT().ChainConfig().Return(config)
+
+	api := &TransactionAPI{b: backend}
+	hash, err := api.SendPrivateRawTransaction(context.Background(), input)
+	require.NoError(t, err)
+	assert.Equal(t, tx.Hash(), hash)
+}
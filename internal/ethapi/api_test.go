@@ -52,7 +52,11 @@ import (
 	"github.com/luxfi/geth/core/vm"
 	"github.com/luxfi/geth/ethdb"
 	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/params/extras"
+	"github.com/luxfi/evm/plugin/evm/vmerrors"
+	"github.com/luxfi/evm/precompile/contracts/txallowlist"
 	"github.com/luxfi/evm/rpc"
+	"github.com/luxfi/evm/utils"
 	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/common/hexutil"
@@ -575,9 +579,15 @@ func (b testBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) even
 func (b testBackend) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error) {
 	panic("implement me")
 }
+func (b testBackend) GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error) {
+	panic("implement me")
+}
 func (b testBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	panic("implement me")
 }
+func (b testBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error {
+	panic("implement me")
+}
 func (b testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(b.db, txHash)
 	return true, tx, blockHash, blockNumber, index, nil
@@ -987,6 +997,54 @@ func TestCall(t *testing.T) {
 	}
 }
 
+// TestCallTxAllowList checks eth_call and eth_estimateGas reject senders that
+// are not on the tx allow list, as block processing does, while calls without
+// a sender still succeed.
+func TestCallTxAllowList(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(2)
+		cfg      = params.Copy(params.TestChainConfig)
+	)
+	params.GetExtra(&cfg).GenesisPrecompiles = extras.Precompiles{
+		txallowlist.ConfigKey: txallowlist.NewConfig(utils.NewUint64(0), []common.Address{accounts[0].addr}, nil, nil),
+	}
+	genesis := &core.Genesis{
+		Config: &cfg,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	api := NewBlockChainAPI(newTestBackend(t, 1, genesis, dummy.NewCoinbaseFaker(), func(i int, b *core.BlockGen) {}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	allowed := TransactionArgs{From: &accounts[0].addr, To: &accounts[1].addr, Value: (*hexutil.Big)(big.NewInt(1000))}
+	if _, err := api.Call(context.Background(), allowed, &latest, nil, nil); err != nil {
+		t.Fatalf("call from allow listed sender failed: %v", err)
+	}
+	if gas, err := api.EstimateGas(context.Background(), allowed, &latest, nil); err != nil || uint64(gas) != ethparams.TxGas {
+		t.Fatalf("estimate for allow listed sender: have %d, %v, want %d", gas, err, ethparams.TxGas)
+	}
+
+	denied := TransactionArgs{From: &accounts[1].addr, To: &accounts[0].addr, Value: (*hexutil.Big)(big.NewInt(1000))}
+	if _, err := api.Call(context.Background(), denied, &latest, nil, nil); !errors.Is(err, vmerrors.ErrSenderAddressNotAllowListed) {
+		t.Fatalf("call from non-allow listed sender: want %v, have %v", vmerrors.ErrSenderAddressNotAllowListed, err)
+	}
+	if _, err := api.EstimateGas(context.Background(), denied, &latest, nil); !errors.Is(err, vmerrors.ErrSenderAddressNotAllowListed) {
+		t.Fatalf("estimate for non-allow listed sender: want %v, have %v", vmerrors.ErrSenderAddressNotAllowListed, err)
+	}
+
+	// Calls without a sender are made from the zero address and not checked.
+	noSender := TransactionArgs{To: &accounts[1].addr}
+	if _, err := api.Call(context.Background(), noSender, &latest, nil, nil); err != nil {
+		t.Fatalf("call without sender failed: %v", err)
+	}
+	if gas, err := api.EstimateGas(context.Background(), noSender, &latest, nil); err != nil || uint64(gas) != ethparams.TxGas {
+		t.Fatalf("estimate without sender: have %d, %v, want %d", gas, err, ethparams.TxGas)
+	}
+}
+
 func TestSignTransaction(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -306,6 +306,21 @@ func (mr *MockBackendMockRecorder) GetFeeConfigAt(parent any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeeConfigAt", reflect.TypeOf((*MockBackend)(nil).GetFeeConfigAt), parent)
 }
 
+// GetScheduledFeeConfigAt mocks base method.
+func (m *MockBackend) GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledFeeConfigAt", parent)
+	ret0, _ := ret[0].(*commontype.ScheduledFeeConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledFeeConfigAt indicates an expected call of GetScheduledFeeConfigAt.
+func (mr *MockBackendMockRecorder) GetScheduledFeeConfigAt(parent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledFeeConfigAt", reflect.TypeOf((*MockBackend)(nil).GetScheduledFeeConfigAt), parent)
+}
+
 // GetLogs mocks base method.
 func (m *MockBackend) GetLogs(ctx context.Context, blockHash common.Hash, number uint64) ([][]*types.Log, error) {
 	m.ctrl.T.Helper()
@@ -528,6 +543,20 @@ func (mr *MockBackendMockRecorder) RPCTxFeeCap() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPCTxFeeCap", reflect.TypeOf((*MockBackend)(nil).RPCTxFeeCap))
 }
 
+// SendPrivateTx mocks base method.
+func (m *MockBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPrivateTx", ctx, signedTx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPrivateTx indicates an expected call of SendPrivateTx.
+func (mr *MockBackendMockRecorder) SendPrivateTx(ctx, signedTx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPrivateTx", reflect.TypeOf((*MockBackend)(nil).SendPrivateTx), ctx, signedTx)
+}
+
 // SendTx mocks base method.
 func (m *MockBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	m.ctrl.T.Helper()
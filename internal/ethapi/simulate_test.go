@@ -0,0 +1,205 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/luxfi/evm/consensus/dummy"
+	"github.com/luxfi/evm/core"
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/rpc"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateSanitizeBlockOrder(t *testing.T) {
+	type result struct {
+		number    uint64
+		timestamp uint64
+	}
+	tests := []struct {
+		name   string
+		blocks []simBlock
+		want   []result
+		err    string
+	}{
+		{
+			name:   "defaults",
+			blocks: []simBlock{{}, {}, {}},
+			want:   []result{{11, 62}, {12, 74}, {13, 86}},
+		},
+		{
+			name:   "gap filled before overridden block",
+			blocks: []simBlock{{BlockOverrides: &BlockOverrides{Number: simBig(13), Time: simUint64(80)}}, {}},
+			want:   []result{{11, 62}, {12, 74}, {13, 80}, {14, 92}},
+		},
+		{
+			name:   "gap filled between overridden blocks",
+			blocks: []simBlock{{BlockOverrides: &BlockOverrides{Number: simBig(11)}}, {BlockOverrides: &BlockOverrides{Number: simBig(14)}}, {}},
+			want:   []result{{11, 62}, {12, 74}, {13, 86}, {14, 98}, {15, 110}},
+		},
+		{
+			name:   "numbers out of order",
+			blocks: []simBlock{{BlockOverrides: &BlockOverrides{Number: simBig(13)}}, {BlockOverrides: &BlockOverrides{Number: simBig(12)}}},
+			err:    "block numbers must be in order: 12 <= 13",
+		},
+		{
+			name:   "timestamp not after gap",
+			blocks: []simBlock{{BlockOverrides: &BlockOverrides{Number: simBig(13), Time: simUint64(74)}}},
+			err:    "block timestamps must be in order: 74 <= 74",
+		},
+		{
+			name:   "timestamps out of order",
+			blocks: []simBlock{{BlockOverrides: &BlockOverrides{Number: simBig(11), Time: simUint64(60)}}, {BlockOverrides: &BlockOverrides{Number: simBig(12), Time: simUint64(55)}}},
+			err:    "block timestamps must be in order: 55 <= 60",
+		},
+		{
+			name:   "too many blocks",
+			blocks: []simBlock{{BlockOverrides: &BlockOverrides{Number: simBig(10 + maxSimulateBlocks + 1)}}},
+			err:    "too many blocks",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := &simulator{base: &types.Header{Number: big.NewInt(10), Time: 50}}
+			res, err := sim.sanitizeChain(tt.blocks)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, res, len(tt.want))
+			for i, b := range res {
+				require.Equal(t, tt.want[i].number, b.BlockOverrides.Number.ToInt().Uint64())
+				require.Equal(t, tt.want[i].timestamp, uint64(*b.BlockOverrides.Time))
+			}
+		})
+	}
+}
+
+func TestSimulateV1(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		api     = NewBlockChainAPI(newTestBackend(t, 1, genesis, dummy.NewCoinbaseFaker(), func(i int, b *core.BlockGen) {}))
+		latest  = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		value   = big.NewInt(1000)
+		base, _ = api.b.HeaderByNumber(context.Background(), rpc.LatestBlockNumber)
+	)
+	transfer := TransactionArgs{
+		From:  &accounts[0].addr,
+		To:    &accounts[1].addr,
+		Value: (*hexutil.Big)(value),
+	}
+	results, err := api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{
+			{Calls: []TransactionArgs{transfer}},
+			{
+				BlockOverrides: &BlockOverrides{Time: simUint64(base.Time + 100)},
+				Calls:          []TransactionArgs{transfer},
+			},
+		},
+		TraceTransfers: true,
+	}, &latest)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	parent := base.Hash()
+	for i, res := range results {
+		require.Equal(t, parent, res.Block.ParentHash())
+		require.Equal(t, base.Number.Uint64()+uint64(i+1), res.Block.NumberU64())
+		require.Len(t, res.Block.Transactions(), 1)
+		require.Len(t, res.Calls, 1)
+
+		call := res.Calls[0]
+		require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), call.Status)
+		require.Nil(t, call.Error)
+
+		// The value transfer is reported as an ERC-7528 log.
+		require.Len(t, call.Logs, 1)
+		require.Equal(t, transferAddress, call.Logs[0].Address)
+		require.Equal(t, []common.Hash{transferTopic, common.BytesToHash(accounts[0].addr.Bytes()), common.BytesToHash(accounts[1].addr.Bytes())}, call.Logs[0].Topics)
+		require.Equal(t, common.BigToHash(value).Bytes(), call.Logs[0].Data)
+		require.Equal(t, res.Block.Hash(), call.Logs[0].BlockHash)
+
+		parent = res.Block.Hash()
+	}
+	require.Equal(t, base.Time+timestampIncrement, results[0].Block.Time())
+	require.Equal(t, base.Time+100, results[1].Block.Time())
+
+	// In validation mode the nonce is checked.
+	nonce := hexutil.Uint64(1)
+	transfer.Nonce = &nonce
+	_, err = api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{{Calls: []TransactionArgs{transfer}}},
+		Validation:      true,
+	}, &latest)
+	var txErr *invalidTxError
+	require.ErrorAs(t, err, &txErr)
+	require.Equal(t, errCodeNonceTooHigh, txErr.ErrorCode())
+
+	// The request must not exceed the block limit.
+	_, err = api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: make([]simBlock, maxSimulateBlocks+1),
+	}, &latest)
+	require.ErrorContains(t, err, "too many blocks")
+}
+
+// timeoutBackend overrides the eth_call timeout of the test backend.
+type timeoutBackend struct {
+	*testBackend
+	timeout time.Duration
+}
+
+func (b timeoutBackend) RPCEVMTimeout() time.Duration { return b.timeout }
+
+func TestSimulateV1Timeout(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		backend = newTestBackend(t, 1, genesis, dummy.NewCoinbaseFaker(), func(i int, b *core.BlockGen) {})
+		latest  = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		opts    = simOpts{
+			BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{
+				From:  &accounts[0].addr,
+				To:    &accounts[1].addr,
+				Value: (*hexutil.Big)(big.NewInt(1)),
+			}}}},
+		}
+	)
+	// The simulation runs within the timeout of eth_call.
+	_, err := NewBlockChainAPI(timeoutBackend{testBackend: backend, timeout: time.Minute}).SimulateV1(context.Background(), opts, &latest)
+	require.NoError(t, err)
+
+	_, err = NewBlockChainAPI(timeoutBackend{testBackend: backend, timeout: time.Nanosecond}).SimulateV1(context.Background(), opts, &latest)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func simBig(n int64) *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(n))
+}
+
+func simUint64(n uint64) *hexutil.Uint64 {
+	return (*hexutil.Uint64)(&n)
+}
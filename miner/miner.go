@@ -49,6 +49,12 @@ type Backend interface {
 type Config struct {
 	Etherbase                    common.Address `toml:",omitempty"` // Public address for block mining rewards
 	TestOnlyAllowDuplicateBlocks bool           // Allow mining of duplicate blocks (used in tests only)
+
+	// Block building policy
+	MaxBlockTxs            int              // Maximum number of transactions per block, 0 for no limit
+	PriorityAddresses      []common.Address // Accounts whose transactions are included first in each block
+	PriorityGasReservation uint64           // Gas of each block only the transactions of the priority accounts can use
+	TxOrdering             TxOrdering       `toml:"-"` // Order to include the pending transactions in, defaults to [PriceOrdering]
 }
 
 type Miner struct {
@@ -0,0 +1,56 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package miner
+
+import (
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/evm/core/txpool"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+)
+
+var _ TxOrdering = PriceOrdering{}
+
+// TxOrder is a set of pending transactions the worker pulls the transactions to
+// include in a block from. Implementations must honour the nonce order of the
+// transactions of each account.
+type TxOrder interface {
+	// Peek returns the next transaction to include and its effective miner tip,
+	// or nil if the set is empty.
+	Peek() (*txpool.LazyTransaction, *uint256.Int)
+
+	// Shift replaces the next transaction with the following one from the same
+	// account.
+	Shift()
+
+	// Pop removes the next transaction, *not* replacing it with the following one
+	// from the same account.
+	Pop()
+
+	// Empty returns whether the set is empty.
+	Empty() bool
+
+	// Clear removes the entire content of the set.
+	Clear()
+}
+
+// TxOrdering decides the order the worker tries to include the pending
+// transactions in a block in.
+type TxOrdering interface {
+	// Order returns the [TxOrder] of the given pending transactions, grouped by
+	// account and sorted by nonce. The input map is reowned, so the caller should
+	// not interact any more with it after providing it.
+	Order(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int) TxOrder
+}
+
+// PriceOrdering is the default [TxOrdering], which orders the transactions by
+// effective miner tip and then by arrival time.
+type PriceOrdering struct{}
+
+// Order implements [TxOrdering].
+func (PriceOrdering) Order(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int) TxOrder {
+	return newTransactionsByPriceAndNonce(signer, txs, baseFee)
+}
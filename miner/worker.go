@@ -83,6 +83,18 @@ func convertToLazyTransactions(txs map[common.Address][]*types.Transaction) map[
 	return result
 }
 
+// takeAccounts removes the transactions of [accounts] from [txs] and returns them.
+func takeAccounts(txs map[common.Address][]*txpool.LazyTransaction, accounts []common.Address) map[common.Address][]*txpool.LazyTransaction {
+	taken := make(map[common.Address][]*txpool.LazyTransaction)
+	for _, account := range accounts {
+		if list := txs[account]; len(list) > 0 {
+			delete(txs, account)
+			taken[account] = list
+		}
+	}
+	return taken
+}
+
 // environment is the worker's current environment and holds all of the current state information.
 type environment struct {
 	signer  types.Signer
@@ -169,8 +181,10 @@ func (w *worker) commitNewWork(predicateContext *precompileconfig.PredicateConte
 	}
 
 	// The fee manager relies on the state of the parent block to set the fee config
-	// because the fee config may be changed by the current block.
-	feeConfig, _, err := w.chain.GetFeeConfigAt(parent)
+	// because the fee config may be changed by the current block. A fee config
+	// scheduled to take effect by [timestamp] is stored by the current block
+	// before its transactions are applied.
+	feeConfig, _, err := customheader.FeeConfigAt(w.chain, parent, timestamp)
 	if err != nil {
 		return nil, err
 	}
@@ -252,6 +266,11 @@ func (w *worker) commitNewWork(predicateContext *precompileconfig.PredicateConte
 		log.Error("failed to configure precompiles mining new block", "parent", parent.Hash(), "number", header.Number, "timestamp", header.Time, "err", err)
 		return nil, err
 	}
+	if w.chainConfig.IsPrague(header.Time) {
+		context := core.NewEVMBlockContext(header, w.chain, nil)
+		vmenv := vm.NewEVM(context, env.state, w.chainConfig.ToEthChainConfig(), vm.Config{})
+		core.ProcessParentBlockHash(header.ParentHash, vmenv, env.state)
+	}
 
 	// Retrieve the pending transactions pre-filtered by the 1559/4844 dynamic fees
 	filter := txpool.PendingFilter{
@@ -273,32 +292,42 @@ func (w *worker) commitNewWork(predicateContext *precompileconfig.PredicateConte
 	lazyPlainTxs := convertToLazyTransactions(pendingPlainTxs)
 	lazyBlobTxs := convertToLazyTransactions(pendingBlobTxs)
 	
-	// Split the pending transactions into locals and remotes.
-	localPlainTxs, remotePlainTxs := make(map[common.Address][]*txpool.LazyTransaction), lazyPlainTxs
-	localBlobTxs, remoteBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), lazyBlobTxs
-	for _, account := range w.eth.TxPool().Locals() {
-		if txs := remotePlainTxs[account]; len(txs) > 0 {
-			delete(remotePlainTxs, account)
-			localPlainTxs[account] = txs
-		}
-		if txs := remoteBlobTxs[account]; len(txs) > 0 {
-			delete(remoteBlobTxs, account)
-			localBlobTxs[account] = txs
-		}
-	}
-	// Fill the block with all available pending transactions.
-	if len(localPlainTxs) > 0 || len(localBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, localPlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, localBlobTxs, env.header.BaseFee)
+	// Split the pending transactions into the ones of the priority accounts, of
+	// the other locals and of the remotes.
+	priorityPlainTxs := takeAccounts(lazyPlainTxs, w.config.PriorityAddresses)
+	priorityBlobTxs := takeAccounts(lazyBlobTxs, w.config.PriorityAddresses)
+	localPlainTxs := takeAccounts(lazyPlainTxs, w.eth.TxPool().Locals())
+	localBlobTxs := takeAccounts(lazyBlobTxs, w.eth.TxPool().Locals())
+	remotePlainTxs, remoteBlobTxs := lazyPlainTxs, lazyBlobTxs
 
-		w.commitTransactions(env, plainTxs, blobTxs, env.header.Coinbase)
+	// Fill the block with all available pending transactions.
+	ordering := w.config.TxOrdering
+	if ordering == nil {
+		ordering = PriceOrdering{}
+	}
+	commitTxs := func(plainTxs, blobTxs map[common.Address][]*txpool.LazyTransaction) {
+		if len(plainTxs) == 0 && len(blobTxs) == 0 {
+			return
+		}
+		w.commitTransactions(
+			env,
+			ordering.Order(env.signer, plainTxs, env.header.BaseFee),
+			ordering.Order(env.signer, blobTxs, env.header.BaseFee),
+			env.header.Coinbase,
+		)
 	}
-	if len(remotePlainTxs) > 0 || len(remoteBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, remotePlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, remoteBlobTxs, env.header.BaseFee)
+	commitTxs(priorityPlainTxs, priorityBlobTxs)
 
-		w.commitTransactions(env, plainTxs, blobTxs, env.header.Coinbase)
+	// Hold back the part of the gas reserved to the priority accounts that they
+	// did not use.
+	var reserved uint64
+	if w.config.PriorityGasReservation > env.header.GasUsed {
+		reserved = min(w.config.PriorityGasReservation-env.header.GasUsed, env.gasPool.Gas())
 	}
+	env.gasPool.SetGas(env.gasPool.Gas() - reserved)
+	commitTxs(localPlainTxs, localBlobTxs)
+	commitTxs(remotePlainTxs, remoteBlobTxs)
+	env.gasPool.AddGas(reserved)
 
 	return w.commit(env)
 }
@@ -400,8 +429,13 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction, coinb
 	return receipt, err
 }
 
-func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transactionsByPriceAndNonce, coinbase common.Address) {
+func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs TxOrder, coinbase common.Address) {
 	for {
+		// If the block holds as many transactions as allowed then we're done.
+		if w.config.MaxBlockTxs > 0 && env.tcount >= w.config.MaxBlockTxs {
+			log.Trace("Transaction count limit reached", "count", env.tcount)
+			break
+		}
 		// If we don't have enough gas for any further transactions then we're done.
 		if env.gasPool.Gas() < ethparams.TxGas {
 			log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", ethparams.TxGas)
@@ -424,7 +458,7 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 		// Retrieve the next transaction and abort if all done.
 		var (
 			ltx *txpool.LazyTransaction
-			txs *transactionsByPriceAndNonce
+			txs TxOrder
 		)
 		pltx, ptip := plainTxs.Peek()
 		bltx, btip := blobTxs.Peek()
@@ -0,0 +1,144 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/evm/consensus/dummy"
+	"github.com/luxfi/evm/core"
+	"github.com/luxfi/evm/core/txpool"
+	"github.com/luxfi/evm/core/txpool/legacypool"
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/luxfi/geth/crypto"
+	"github.com/luxfi/geth/event"
+	ethparams "github.com/luxfi/geth/params"
+	"github.com/luxfi/node/utils/timer/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKey, _     = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr       = crypto.PubkeyToAddress(testKey.PublicKey)
+	priorityKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	priorityAddr   = crypto.PubkeyToAddress(priorityKey.PublicKey)
+
+	testGasLimit = params.DefaultFeeConfig.GasLimit.Uint64()
+)
+
+type testBackend struct {
+	chain  *core.BlockChain
+	txPool *txpool.TxPool
+}
+
+func (b *testBackend) BlockChain() *core.BlockChain { return b.chain }
+func (b *testBackend) TxPool() *txpool.TxPool       { return b.txPool }
+
+func newTestWorker(t *testing.T, config *Config) (*worker, *txpool.TxPool) {
+	t.Helper()
+
+	var (
+		engine = dummy.NewFakerWithMode(dummy.Mode{ModeSkipBlockFee: true, ModeSkipCoinbase: true})
+		gspec  = &core.Genesis{
+			Config:   params.TestChainConfig,
+			GasLimit: testGasLimit,
+			Alloc: types.GenesisAlloc{
+				testAddr:     {Balance: big.NewInt(params.Ether)},
+				priorityAddr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+	)
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), core.DefaultCacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+	require.NoError(t, err)
+	t.Cleanup(chain.Stop)
+
+	pool, err := txpool.New(legacypool.DefaultConfig.PriceLimit, chain, []txpool.SubPool{legacypool.New(legacypool.DefaultConfig, chain)})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, pool.Close()) })
+
+	backend := &testBackend{chain: chain, txPool: pool}
+	return newWorker(config, params.TestChainConfig, engine, backend, new(event.TypeMux), &mockable.Clock{}), pool
+}
+
+// addTransfers adds [count] transfers from the account of [key] to the pool.
+func addTransfers(t *testing.T, pool *txpool.TxPool, key *ecdsa.PrivateKey, count int) {
+	t.Helper()
+
+	signer := types.LatestSignerForChainID(params.TestChainConfig.ChainID)
+	txs := make([]*types.Transaction, count)
+	for i := range txs {
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &common.Address{0xaa},
+			Value:    big.NewInt(1),
+			Gas:      ethparams.TxGas,
+			GasPrice: big.NewInt(300 * params.GWei),
+		}), signer, key)
+		require.NoError(t, err)
+		txs[i] = tx
+	}
+	for _, err := range pool.Add(txs, false, true) {
+		require.NoError(t, err)
+	}
+}
+
+// countSenders returns the number of transactions of each sender in [block].
+func countSenders(t *testing.T, block *types.Block) map[common.Address]int {
+	t.Helper()
+
+	signer := types.LatestSignerForChainID(params.TestChainConfig.ChainID)
+	counts := make(map[common.Address]int)
+	for _, tx := range block.Transactions() {
+		sender, err := types.Sender(signer, tx)
+		require.NoError(t, err)
+		counts[sender]++
+	}
+	return counts
+}
+
+func TestWorkerMaxBlockTxs(t *testing.T) {
+	require := require.New(t)
+
+	w, pool := newTestWorker(t, &Config{MaxBlockTxs: 3})
+	addTransfers(t, pool, testKey, 5)
+
+	block, err := w.commitNewWork(nil)
+	require.NoError(err)
+	require.Len(block.Transactions(), 3)
+}
+
+func TestWorkerPriorityGasReservation(t *testing.T) {
+	require := require.New(t)
+
+	// Leave room for three transfers outside of the reservation.
+	config := &Config{
+		PriorityAddresses:      []common.Address{priorityAddr},
+		PriorityGasReservation: testGasLimit - 3*ethparams.TxGas,
+	}
+	w, pool := newTestWorker(t, config)
+	addTransfers(t, pool, testKey, 5)
+
+	block, err := w.commitNewWork(nil)
+	require.NoError(err)
+	require.Equal(map[common.Address]int{testAddr: 3}, countSenders(t, block))
+
+	// The transactions of the priority accounts use the reservation, not the gas
+	// left to the other accounts.
+	addTransfers(t, pool, priorityKey, 2)
+	block, err = w.commitNewWork(nil)
+	require.NoError(err)
+	require.Equal(map[common.Address]int{testAddr: 3, priorityAddr: 2}, countSenders(t, block))
+
+	// Without priority accounts, no transaction can use the reservation.
+	config.PriorityAddresses = nil
+	block, err = w.commitNewWork(nil)
+	require.NoError(err)
+	require.Len(block.Transactions(), 3)
+}
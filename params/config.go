@@ -217,6 +217,7 @@ type ChainConfig struct {
 	// Time based forks
 	ShanghaiTime        *uint64  `json:"shanghaiTime,omitempty"`        // Shanghai switch time (nil = no fork, 0 = already on shanghai)
 	CancunTime          *uint64  `json:"cancunTime,omitempty"`          // Cancun switch time (nil = no fork, 0 = already on cancun)
+	PragueTime          *uint64  `json:"pragueTime,omitempty"`          // Prague switch time (nil = no fork, 0 = already on prague)
 
 	MandatoryNetworkUpgrades             // Config for timestamps that enable mandatory network upgrades. Skip encoding/decoding directly into ChainConfig.
 	OptionalNetworkUpgrades              // Config for optional timestamps that enable network upgrades
@@ -289,6 +290,7 @@ func (c *ChainConfig) ToEthChainConfig() *ethparams.ChainConfig {
 		LondonBlock:         c.LondonBlock,
 		ShanghaiTime:        c.ShanghaiTime,
 		CancunTime:          c.CancunTime,
+		PragueTime:          c.PragueTime,
 	}
 }
 
@@ -440,6 +442,13 @@ func (c *ChainConfig) IsCancun(time uint64) bool {
 	return c.MandatoryNetworkUpgrades.IsEtna(time)
 }
 
+// IsPrague returns whether [time] represents a block
+// with a timestamp after the Prague upgrade time.
+// Prague is activated by the Granite upgrade.
+func (c *ChainConfig) IsPrague(time uint64) bool {
+	return GetExtra(c).IsGranite(time)
+}
+
 func (r *Rules) PredicatersExist() bool {
 	return len(r.Predicaters) > 0
 }
@@ -859,11 +868,12 @@ type Rules struct {
 	ChainID                                                 *big.Int
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158               bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
-	IsCancun                                                bool
+	IsCancun, IsPrague                                      bool
 
 	// Rules for Lux releases
 	IsSubnetEVM bool
 	IsDUpgrade  bool
+	IsGranite   bool
 
 	// ActivePrecompiles maps addresses to stateful precompiled contracts that are enabled
 	// for this rule set.
@@ -884,6 +894,12 @@ func (r *Rules) IsPrecompileEnabled(addr common.Address) bool {
 	return ok
 }
 
+// IsGraniteActivated returns true if the Granite upgrade is active.
+// Implements precompileconfig.Rules interface.
+func (r *Rules) IsGraniteActivated() bool {
+	return r.IsGranite
+}
+
 // Rules ensures c's ChainID is not nil.
 func (c *ChainConfig) rules(num *big.Int, timestamp uint64) Rules {
 	chainID := c.ChainID
@@ -901,6 +917,7 @@ func (c *ChainConfig) rules(num *big.Int, timestamp uint64) Rules {
 		IsPetersburg:     c.IsPetersburg(num),
 		IsIstanbul:       c.IsIstanbul(num),
 		IsCancun:         c.IsCancun(timestamp),
+		IsPrague:         c.IsPrague(timestamp),
 	}
 }
 
@@ -911,6 +928,7 @@ func (c *ChainConfig) LuxRules(blockNum *big.Int, timestamp uint64) Rules {
 
 	rules.IsSubnetEVM = c.IsSubnetEVM(timestamp)
 	rules.IsDUpgrade = c.IsDUpgrade(timestamp)
+	rules.IsGranite = GetExtra(c).IsGranite(timestamp)
 
 	// Initialize the stateful precompiles that should be enabled at [blockTimestamp].
 	rules.ActivePrecompiles = make(map[common.Address]precompileconfig.Config)
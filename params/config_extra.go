@@ -62,6 +62,9 @@ func SetEthUpgrades(c *ChainConfig, luxUpgrades extras.NetworkUpgrades) {
 	if luxUpgrades.EtnaTimestamp != nil {
 		c.CancunTime = utils.NewUint64(*luxUpgrades.EtnaTimestamp)
 	}
+	if luxUpgrades.GraniteTimestamp != nil {
+		c.PragueTime = utils.NewUint64(*luxUpgrades.GraniteTimestamp)
+	}
 }
 
 func GetExtra(c *ChainConfig) *extras.ChainConfig {
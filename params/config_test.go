@@ -172,6 +172,31 @@ func TestConfigRules(t *testing.T) {
 	}
 }
 
+func TestPragueActivatedByGranite(t *testing.T) {
+	c := WithExtra(
+		&ChainConfig{},
+		&extras.ChainConfig{
+			NetworkUpgrades: extras.NetworkUpgrades{
+				GraniteTimestamp: utils.NewUint64(500),
+			},
+		},
+	)
+	SetEthUpgrades(c, GetExtra(c).NetworkUpgrades)
+	require.Equal(t, utils.NewUint64(500), c.PragueTime)
+	require.Equal(t, c.PragueTime, c.ToEthChainConfig().PragueTime)
+
+	for _, stamp := range []uint64{0, 499} {
+		require.False(t, c.IsPrague(stamp))
+		require.False(t, c.LuxRules(big.NewInt(0), stamp).IsPrague)
+		require.False(t, GetExtra(c).GetAvalancheRules(stamp).IsPrague)
+	}
+	for _, stamp := range []uint64{500, math.MaxInt64} {
+		require.True(t, c.IsPrague(stamp))
+		require.True(t, c.LuxRules(big.NewInt(0), stamp).IsPrague)
+		require.True(t, GetExtra(c).GetAvalancheRules(stamp).IsPrague)
+	}
+}
+
 func TestConfigUnmarshalJSON(t *testing.T) {
 	require := require.New(t)
 
@@ -51,7 +51,9 @@ type NetworkUpgrades struct {
 	EtnaTimestamp *uint64 `json:"etnaTimestamp,omitempty"`
 	// Fortuna has no effect on EVM by itself, but is included for completeness.
 	FortunaTimestamp *uint64 `json:"fortunaTimestamp,omitempty"`
-	// Granite is a placeholder for the next upgrade.
+	// Granite charges warp predicates for the lookup of the validator set of
+	// the source subnet, enables time-bounded allow list roles and activates the
+	// Prague execution features (EIP-7702, EIP-2537, EIP-2935 and EIP-7623).
 	GraniteTimestamp *uint64 `json:"graniteTimestamp,omitempty"`
 }
 
@@ -197,6 +199,7 @@ type AvalancheRules struct {
 	IsEtna      bool
 	IsFortuna   bool
 	IsGranite   bool
+	IsPrague    bool // Prague is activated by Granite
 }
 
 func (n *NetworkUpgrades) GetAvalancheRules(time uint64) AvalancheRules {
@@ -206,6 +209,7 @@ func (n *NetworkUpgrades) GetAvalancheRules(time uint64) AvalancheRules {
 		IsEtna:      n.IsEtna(time),
 		IsFortuna:   n.IsFortuna(time),
 		IsGranite:   n.IsGranite(time),
+		IsPrague:    n.IsGranite(time),
 	}
 }
 
@@ -217,7 +221,7 @@ func getDefaultNetworkUpgrades(agoUpgrade upgrade.Config) NetworkUpgrades {
 		DurangoTimestamp:   utils.TimeToNewUint64(agoUpgrade.DurangoTime),
 		EtnaTimestamp:      utils.TimeToNewUint64(agoUpgrade.EtnaTime),
 		FortunaTimestamp:   nil, // Fortuna is optional and has no effect on EVM
-		GraniteTimestamp:   nil, // Granite is optional and has no effect on EVM
+		GraniteTimestamp:   nil, // Granite is optional and activates warp predicate gas, allow list role expiry and Prague
 	}
 }
 
@@ -20,6 +20,20 @@ type StateUpgrade struct {
 
 	// map from account address to the modification to be made to the account.
 	StateUpgradeAccounts map[common.Address]StateUpgradeAccount `json:"accounts"`
+
+	// map from the address of a precompile with an allow list to the addresses
+	// that may hold a role, from which the enumerable role holder index of the
+	// precompile is built.
+	//
+	// The addresses of the allow list configs of the precompile activated up to
+	// the upgrade are indexed as well, so they need not be listed.
+	//
+	// WARNING: the allow list stores the roles by address only, so the index
+	// cannot be checked against the stored roles. The addresses must include
+	// every account of a RoleSet event of the precompile for the index to be
+	// complete. An address holding a role that is left out is never counted nor
+	// listed until its role is set again.
+	AllowListRoleIndexes map[common.Address][]common.Address `json:"allowListRoleIndexes,omitempty"`
 }
 
 // StateUpgradeAccount describes the modifications to be made to an account during
@@ -36,6 +50,7 @@ func (s *StateUpgrade) Equal(other *StateUpgrade) bool {
 
 // verifyStateUpgrades checks [c.StateUpgrades] is well formed:
 // - the specified blockTimestamps must monotonically increase
+// - the allow list role indexes must be built from at least one address
 func (c *ChainConfig) verifyStateUpgrades() error {
 	var previousUpgradeTimestamp *uint64
 	for i, upgrade := range c.StateUpgrades {
@@ -53,6 +68,12 @@ func (c *ChainConfig) verifyStateUpgrades() error {
 			return fmt.Errorf("StateUpgrade[%d]: config block timestamp (%v) <= previous timestamp (%v)", i, *upgradeTimestamp, *previousUpgradeTimestamp)
 		}
 		previousUpgradeTimestamp = upgradeTimestamp
+
+		for precompileAddr, candidates := range upgrade.AllowListRoleIndexes {
+			if len(candidates) == 0 {
+				return fmt.Errorf("StateUpgrade[%d]: allow list role index of %s must be built from at least one address", i, precompileAddr)
+			}
+		}
 	}
 	return nil
 }
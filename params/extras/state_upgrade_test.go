@@ -54,6 +54,16 @@ func TestVerifyStateUpgrades(t *testing.T) {
 			},
 			expectedError: "config block timestamp (0) must be greater than 0",
 		},
+		{
+			name: "allow list role index without addresses",
+			upgrades: []StateUpgrade{
+				{
+					BlockTimestamp:       utils.NewUint64(1),
+					AllowListRoleIndexes: map[common.Address][]common.Address{{2}: {}},
+				},
+			},
+			expectedError: "must be built from at least one address",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -7,4 +7,4 @@ package params
 // quorum for Warp messages. A quorum is achieved when the sum of
 // the validators' weights that signed the message is greater than
 // (total_weight * quorum_numerator) / WarpQuorumDenominator.
-const WarpQuorumDenominator = 3
\ No newline at end of file
+const WarpQuorumDenominator = 100
@@ -27,13 +27,25 @@ import (
 const minRequestHandlingDuration = 100 * time.Millisecond
 
 var (
-	errAcquiringSemaphore                      = errors.New("error acquiring semaphore")
-	errExpiredRequest                          = errors.New("expired request")
-	_                     Network              = &network{}
-	_                     validators.Connector = &network{}
-	_                     engine.AppHandler    = &network{}
+	errAcquiringSemaphore                          = errors.New("error acquiring semaphore")
+	errExpiredRequest                              = errors.New("expired request")
+	errCrossChainNotSupported                      = errors.New("app sender does not support cross chain requests")
+	_                         Network              = &network{}
+	_                         validators.Connector = &network{}
+	_                         engine.AppHandler    = &network{}
 )
 
+// CrossChainAppSender is implemented by node AppSenders that are able to send
+// messages to other chains in the same network.
+type CrossChainAppSender interface {
+	// SendCrossChainAppRequest sends an application-level request to a
+	// specific chain.
+	SendCrossChainAppRequest(ctx context.Context, chainID ids.ID, requestID uint32, appRequestBytes []byte) error
+	// SendCrossChainAppResponse sends an application-level response to a
+	// specific chain.
+	SendCrossChainAppResponse(ctx context.Context, chainID ids.ID, requestID uint32, appResponseBytes []byte) error
+}
+
 type Network interface {
 	validators.Connector
 	engine.AppHandler
@@ -47,6 +59,9 @@ type Network interface {
 	// SendAppRequest sends message to given nodeID, notifying handler when there's a response or timeout
 	SendAppRequest(ctx context.Context, nodeID ids.NodeID, message []byte, handler message.ResponseHandler) error
 
+	// SendCrossChainRequest sends a message to given chainID, notifying handler when there's a response or timeout
+	SendCrossChainRequest(ctx context.Context, chainID ids.ID, message []byte, handler message.ResponseHandler) error
+
 	// Shutdown stops all peer channel listeners and marks the node to have stopped
 	// n.Start() can be called again but the peers will have to be reconnected
 	// by calling OnPeerConnected for each peer
@@ -55,6 +70,9 @@ type Network interface {
 	// SetRequestHandler sets the provided request handler as the request handler
 	SetRequestHandler(handler message.RequestHandler)
 
+	// SetCrossChainRequestHandler sets the provided cross chain request handler as the cross chain request handler
+	SetCrossChainRequestHandler(handler message.CrossChainRequestHandler)
+
 	// Size returns the size of the network in number of connected peers
 	Size() uint32
 
@@ -99,17 +117,21 @@ type network struct {
 	closed utils.Atomic[bool]
 }
 
-func NewNetwork(p2pNetwork *p2p.Network, appSender engine.AppSender, codec codec.Manager, self ids.NodeID, maxActiveAppRequests int64) Network {
+func NewNetwork(p2pNetwork *p2p.Network, appSender engine.AppSender, codec codec.Manager, crossChainCodec codec.Manager, self ids.NodeID, maxActiveAppRequests int64, maxActiveCrossChainRequests int64) Network {
 	return &network{
 		appSender:                  appSender,
 		codec:                      codec,
+		crossChainCodec:            crossChainCodec,
 		self:                       self,
 		outstandingRequestHandlers: make(map[uint32]message.ResponseHandler),
 		activeAppRequests:          semaphore.NewWeighted(maxActiveAppRequests),
+		activeCrossChainRequests:   semaphore.NewWeighted(maxActiveCrossChainRequests),
 		p2pNetwork:                 p2pNetwork,
 		appRequestHandler:          message.NoopRequestHandler{},
+		crossChainRequestHandler:   message.NoopCrossChainRequestHandler{},
 		peers:                      NewPeerTracker(),
 		appStats:                   stats.NewRequestHandlerStats(),
+		crossChainStats:            stats.NewCrossChainRequestHandlerStats(),
 	}
 }
 
@@ -221,6 +243,16 @@ func (n *network) sendAppRequest(ctx context.Context, nodeID ids.NodeID, request
 // so that it can be invoked when the network receives either a response or failure message.
 // Returns an error if [appSender] is unable to make the request.
 func (n *network) SendCrossChainRequest(ctx context.Context, chainID ids.ID, request []byte, handler message.ResponseHandler) error {
+	sender, ok := n.appSender.(CrossChainAppSender)
+	if !ok {
+		return errCrossChainNotSupported
+	}
+
+	// If the context was cancelled, we can skip sending this request.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Take a slot from total [activeCrossChainRequests] and block until a slot becomes available.
 	if err := n.activeCrossChainRequests.Acquire(ctx, 1); err != nil {
 		return errAcquiringSemaphore
@@ -239,15 +271,22 @@ func (n *network) SendCrossChainRequest(ctx context.Context, chainID ids.ID, req
 
 	// Send cross chain request to [chainID].
 	// On failure, release the slot from [activeCrossChainRequests] and delete request from [outstandingRequestHandlers].
-	// FIXME: SendCrossChainAppRequest is not available in luxfi/node v1.13.2
-	// if err := n.appSender.SendCrossChainAppRequest(ctx, chainID, requestID, request); err != nil {
-	// 	n.activeCrossChainRequests.Release(1)
-	// 	delete(n.outstandingRequestHandlers, requestID)
-	// 	return err
-	// }
-	n.activeCrossChainRequests.Release(1)
-	delete(n.outstandingRequestHandlers, requestID)
-	return errors.New("cross-chain requests not supported in current version")
+	if err := sender.SendCrossChainAppRequest(context.WithoutCancel(ctx), chainID, requestID, request); err != nil {
+		log.Error(
+			"cross chain request failed",
+			"chainID", chainID,
+			"requestID", requestID,
+			"requestLen", len(request),
+			"error", err,
+		)
+
+		n.activeCrossChainRequests.Release(1)
+		delete(n.outstandingRequestHandlers, requestID)
+		return err
+	}
+
+	log.Trace("sent request message to chain", "chainID", chainID, "crossChainRequestID", requestID)
+	return nil
 }
 
 // CrossChainAppRequest notifies the VM when another chain in the network requests for data.
@@ -260,33 +299,43 @@ func (n *network) CrossChainAppRequest(ctx context.Context, requestingChainID id
 
 	log.Trace("received CrossChainAppRequest from chain", "requestingChainID", requestingChainID, "requestID", requestID, "requestLen", len(request))
 
-	// FIXME: CrossChainRequest type is not available
-	// var req message.CrossChainRequest
-	// if _, err := n.crossChainCodec.Unmarshal(request, &req); err != nil {
-	// 	log.Trace("failed to unmarshal CrossChainAppRequest", "requestingChainID", requestingChainID, "requestID", requestID, "requestLen", len(request), "err", err)
-	// 	return nil
-	// }
-
-	// bufferedDeadline, err := calculateTimeUntilDeadline(deadline, n.crossChainStats)
-	// if err != nil {
-	// 	log.Trace("deadline to process CrossChainAppRequest has expired, skipping", "requestingChainID", requestingChainID, "requestID", requestID, "err", err)
-	// 	return nil
-	// }
-
-	// log.Trace("processing incoming CrossChainAppRequest", "requestingChainID", requestingChainID, "requestID", requestID, "req", req)
-	// handleCtx, cancel := context.WithDeadline(context.Background(), bufferedDeadline)
-	// defer cancel()
-
-	// responseBytes, err := req.Handle(handleCtx, requestingChainID, requestID, n.crossChainRequestHandler)
-	// switch {
-	// case err != nil && err != context.DeadlineExceeded:
-	// 	return err // Return a fatal error
-	// case responseBytes != nil:
-	// 	return n.appSender.SendCrossChainAppResponse(ctx, requestingChainID, requestID, responseBytes) // Propagate fatal error
-	// default:
-	// 	return nil
-	// }
-	return errors.New("cross-chain requests not supported in current version")
+	var req message.CrossChainRequest
+	if _, err := n.crossChainCodec.Unmarshal(request, &req); err != nil {
+		log.Trace("failed to unmarshal CrossChainAppRequest", "requestingChainID", requestingChainID, "requestID", requestID, "requestLen", len(request), "err", err)
+		return nil
+	}
+
+	bufferedDeadline, err := calculateTimeUntilDeadline(deadline, n.crossChainStats)
+	if err != nil {
+		log.Trace("deadline to process CrossChainAppRequest has expired, skipping", "requestingChainID", requestingChainID, "requestID", requestID, "err", err)
+		return nil
+	}
+
+	sender, ok := n.appSender.(CrossChainAppSender)
+	if !ok {
+		log.Trace("dropping CrossChainAppRequest, app sender cannot respond", "requestingChainID", requestingChainID, "requestID", requestID)
+		return nil
+	}
+
+	n.lock.RLock()
+	handler := n.crossChainRequestHandler
+	n.lock.RUnlock()
+
+	log.Trace("processing incoming CrossChainAppRequest", "requestingChainID", requestingChainID, "requestID", requestID, "req", req)
+	// We make a new context here because we don't want to cancel the context
+	// passed into sender.SendCrossChainAppResponse below
+	handleCtx, cancel := context.WithDeadline(context.Background(), bufferedDeadline)
+	defer cancel()
+
+	responseBytes, err := req.Handle(handleCtx, requestingChainID, requestID, handler)
+	switch {
+	case err != nil && err != context.DeadlineExceeded:
+		return err // Return a fatal error
+	case responseBytes != nil:
+		return sender.SendCrossChainAppResponse(ctx, requestingChainID, requestID, responseBytes) // Propagate fatal error
+	default:
+		return nil
+	}
 }
 
 // CrossChainAppRequestFailed can be called by the node -> VM in following cases:
@@ -419,8 +468,8 @@ func (n *network) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, reque
 }
 
 // calculateTimeUntilDeadline calculates the time until deadline and drops it if we missed he deadline to response.
-// This function updates metrics for app requests.
-// This is called by [AppRequest].
+// This function updates metrics for app requests and cross chain requests.
+// This is called by [AppRequest] and [CrossChainAppRequest].
 func calculateTimeUntilDeadline(deadline time.Time, stats stats.RequestHandlerStats) (time.Time, error) {
 	// calculate how much time is left until the deadline
 	timeTillDeadline := time.Until(deadline)
@@ -546,6 +595,13 @@ func (n *network) SetRequestHandler(handler message.RequestHandler) {
 	n.appRequestHandler = handler
 }
 
+func (n *network) SetCrossChainRequestHandler(handler message.CrossChainRequestHandler) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.crossChainRequestHandler = handler
+}
+
 func (n *network) Size() uint32 {
 	n.lock.RLock()
 	defer n.lock.RUnlock()
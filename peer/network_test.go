@@ -42,7 +42,10 @@ var (
 	_ message.RequestHandler = &HelloGreetingRequestHandler{}
 	_ message.RequestHandler = &testRequestHandler{}
 
-	_ common.AppSender = testAppSender{}
+	_ common.AppSender    = testAppSender{}
+	_ CrossChainAppSender = testAppSender{}
+
+	_ message.CrossChainRequestHandler = &testCrossChainHandler{}
 
 	_ p2p.Handler = &testSDKHandler{}
 )
@@ -51,7 +54,7 @@ func TestNetworkDoesNotConnectToItself(t *testing.T) {
 	selfNodeID := ids.GenerateTestNodeID()
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	n := NewNetwork(p2pNetwork, nil, nil, selfNodeID, 1)
+	n := NewNetwork(p2pNetwork, nil, nil, nil, selfNodeID, 1, 1)
 	assert.NoError(t, n.Connected(context.Background(), selfNodeID, defaultPeerVersion))
 	assert.EqualValues(t, 0, n.Size())
 }
@@ -88,7 +91,7 @@ func TestRequestAnyRequestsRoutingAndResponse(t *testing.T) {
 	codecManager := buildCodec(t, HelloRequest{}, HelloResponse{})
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	net = NewNetwork(p2pNetwork, sender, codecManager, ids.EmptyNodeID, 16)
+	net = NewNetwork(p2pNetwork, sender, codecManager, nil, ids.EmptyNodeID, 16, 1)
 	net.SetRequestHandler(&HelloGreetingRequestHandler{codec: codecManager})
 	client := NewNetworkClient(net)
 	nodeID := ids.GenerateTestNodeID()
@@ -140,7 +143,7 @@ func TestAppRequestOnCtxCancellation(t *testing.T) {
 
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	net := NewNetwork(p2pNetwork, sender, codecManager, ids.EmptyNodeID, 1)
+	net := NewNetwork(p2pNetwork, sender, codecManager, nil, ids.EmptyNodeID, 1, 1)
 	net.SetRequestHandler(&HelloGreetingRequestHandler{codec: codecManager})
 
 	requestMessage := HelloRequest{Message: "this is a request"}
@@ -193,7 +196,7 @@ func TestRequestRequestsRoutingAndResponse(t *testing.T) {
 	codecManager := buildCodec(t, HelloRequest{}, HelloResponse{})
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	net = NewNetwork(p2pNetwork, sender, codecManager, ids.EmptyNodeID, 16)
+	net = NewNetwork(p2pNetwork, sender, codecManager, nil, ids.EmptyNodeID, 16, 1)
 	net.SetRequestHandler(&HelloGreetingRequestHandler{codec: codecManager})
 	client := NewNetworkClient(net)
 
@@ -274,7 +277,7 @@ func TestAppRequestOnShutdown(t *testing.T) {
 	codecManager := buildCodec(t, HelloRequest{}, HelloResponse{})
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	net = NewNetwork(p2pNetwork, sender, codecManager, ids.EmptyNodeID, 1)
+	net = NewNetwork(p2pNetwork, sender, codecManager, nil, ids.EmptyNodeID, 1, 1)
 	client := NewNetworkClient(net)
 	nodeID := ids.GenerateTestNodeID()
 	require.NoError(t, net.Connected(context.Background(), nodeID, defaultPeerVersion))
@@ -323,7 +326,7 @@ func TestAppRequestAnyOnCtxCancellation(t *testing.T) {
 
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	net := NewNetwork(p2pNetwork, sender, codecManager, ids.EmptyNodeID, 1)
+	net := NewNetwork(p2pNetwork, sender, codecManager, nil, ids.EmptyNodeID, 1, 1)
 	net.SetRequestHandler(&HelloGreetingRequestHandler{codec: codecManager})
 	assert.NoError(t,
 		net.Connected(
@@ -401,7 +404,7 @@ func TestRequestMinVersion(t *testing.T) {
 	// passing nil as codec works because the net.AppRequest is never called
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	net = NewNetwork(p2pNetwork, sender, codecManager, ids.EmptyNodeID, 1)
+	net = NewNetwork(p2pNetwork, sender, codecManager, nil, ids.EmptyNodeID, 1, 1)
 	client := NewNetworkClient(net)
 	requestMessage := TestMessage{Message: "this is a request"}
 	requestBytes, err := message.RequestToBytes(codecManager, requestMessage)
@@ -467,7 +470,7 @@ func TestOnRequestHonoursDeadline(t *testing.T) {
 
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	net = NewNetwork(p2pNetwork, sender, codecManager, ids.EmptyNodeID, 1)
+	net = NewNetwork(p2pNetwork, sender, codecManager, nil, ids.EmptyNodeID, 1, 1)
 	net.SetRequestHandler(requestHandler)
 	nodeID := ids.GenerateTestNodeID()
 
@@ -495,7 +498,7 @@ func TestHandleInvalidMessages(t *testing.T) {
 	}
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, sender, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	clientNetwork := NewNetwork(p2pNetwork, sender, codecManager, ids.EmptyNodeID, 1)
+	clientNetwork := NewNetwork(p2pNetwork, sender, codecManager, nil, ids.EmptyNodeID, 1, 1)
 	clientNetwork.SetRequestHandler(&testRequestHandler{})
 
 	assert.NoError(t, clientNetwork.Connected(context.Background(), nodeID, defaultPeerVersion))
@@ -544,7 +547,7 @@ func TestNetworkPropagatesRequestHandlerError(t *testing.T) {
 
 	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
 	require.NoError(t, err)
-	clientNetwork := NewNetwork(p2pNetwork, sender, codecManager, ids.EmptyNodeID, 1)
+	clientNetwork := NewNetwork(p2pNetwork, sender, codecManager, nil, ids.EmptyNodeID, 1, 1)
 	clientNetwork.SetRequestHandler(&testRequestHandler{err: errors.New("fail")}) // Return an error from the request handler
 
 	assert.NoError(t, clientNetwork.Connected(context.Background(), nodeID, defaultPeerVersion))
@@ -562,7 +565,7 @@ func TestNetworkPropagatesRequestHandlerError(t *testing.T) {
 func TestNetworkAppRequestAfterShutdown(t *testing.T) {
 	require := require.New(t)
 
-	net := NewNetwork(nil, nil, nil, ids.EmptyNodeID, 1)
+	net := NewNetwork(nil, nil, nil, nil, ids.EmptyNodeID, 1, 1)
 	net.Shutdown()
 
 	require.NoError(net.SendAppRequest(context.Background(), ids.GenerateTestNodeID(), nil, nil))
@@ -587,7 +590,7 @@ func TestNetworkRouting(t *testing.T) {
 	require.NoError(p2pNetwork.AddHandler(uint64(protocol), handler))
 
 	networkCodec := codec.NewManager(0)
-	network := NewNetwork(p2pNetwork, nil, networkCodec, ids.EmptyNodeID, 1)
+	network := NewNetwork(p2pNetwork, nil, networkCodec, nil, ids.EmptyNodeID, 1, 1)
 
 	nodeID := ids.GenerateTestNodeID()
 	foobar := append([]byte{byte(protocol)}, []byte("foobar")...)
@@ -602,6 +605,175 @@ func TestNetworkRouting(t *testing.T) {
 	require.ErrorIs(err, p2p.ErrUnrequestedResponse)
 }
 
+func TestCrossChainAppRequest(t *testing.T) {
+	var net Network
+	codecManager := buildCodec(t, message.EthCallRequest{}, message.EthCallResponse{})
+	sender := testAppSender{
+		sendCrossChainAppRequestFn: func(requestingChainID ids.ID, requestID uint32, requestBytes []byte) error {
+			go func() {
+				if err := net.CrossChainAppRequest(context.Background(), requestingChainID, requestID, time.Now().Add(5*time.Second), requestBytes); err != nil {
+					panic(err)
+				}
+			}()
+			return nil
+		},
+		sendCrossChainAppResponseFn: func(respondingChainID ids.ID, requestID uint32, responseBytes []byte) error {
+			go func() {
+				if err := net.CrossChainAppResponse(context.Background(), respondingChainID, requestID, responseBytes); err != nil {
+					panic(err)
+				}
+			}()
+			return nil
+		},
+	}
+
+	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
+	require.NoError(t, err)
+	net = NewNetwork(p2pNetwork, sender, nil, codecManager, ids.EmptyNodeID, 1, 1)
+	net.SetCrossChainRequestHandler(&testCrossChainHandler{codec: codecManager})
+	client := NewNetworkClient(net)
+	defer net.Shutdown()
+
+	requestArgs := []byte(`{"to":"0x0100000000000000000000000000000000000000"}`)
+	crossChainRequest, err := message.CrossChainRequestToBytes(codecManager, message.EthCallRequest{RequestArgs: requestArgs})
+	require.NoError(t, err)
+
+	chainID := ids.GenerateTestID()
+	responseBytes, err := client.SendCrossChainRequest(context.Background(), chainID, crossChainRequest)
+	require.NoError(t, err)
+
+	var response message.EthCallResponse
+	_, err = codecManager.Unmarshal(responseBytes, &response)
+	require.NoError(t, err)
+	require.Equal(t, requestArgs, response.ExecutionResult)
+}
+
+func TestCrossChainAppRequestOnCtxCancellation(t *testing.T) {
+	codecManager := buildCodec(t, message.EthCallRequest{}, message.EthCallResponse{})
+	sender := testAppSender{
+		sendCrossChainAppRequestFn: func(requestingChainID ids.ID, requestID uint32, requestBytes []byte) error {
+			return nil
+		},
+		sendCrossChainAppResponseFn: func(respondingChainID ids.ID, requestID uint32, responseBytes []byte) error {
+			return nil
+		},
+	}
+
+	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
+	require.NoError(t, err)
+	net := NewNetwork(p2pNetwork, sender, nil, codecManager, ids.EmptyNodeID, 1, 1)
+	net.SetCrossChainRequestHandler(&testCrossChainHandler{codec: codecManager})
+
+	crossChainRequest, err := message.CrossChainRequestToBytes(codecManager, message.EthCallRequest{RequestArgs: []byte("{}")})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// cancel context prior to sending
+	cancel()
+	client := NewNetworkClient(net)
+	_, err = client.SendCrossChainRequest(ctx, ids.GenerateTestID(), crossChainRequest)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCrossChainRequestRequiresCrossChainSender(t *testing.T) {
+	require := require.New(t)
+
+	codecManager := buildCodec(t, message.EthCallRequest{}, message.EthCallResponse{})
+	sender := noCrossChainAppSender{
+		AppSender: testAppSender{
+			sendAppResponseFn: func(ids.NodeID, uint32, []byte) error {
+				panic("unexpected app response")
+			},
+		},
+	}
+
+	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
+	require.NoError(err)
+	net := NewNetwork(p2pNetwork, sender, nil, codecManager, ids.EmptyNodeID, 1, 1)
+	handler := &testCrossChainHandler{codec: codecManager}
+	net.SetCrossChainRequestHandler(handler)
+
+	crossChainRequest, err := message.CrossChainRequestToBytes(codecManager, message.EthCallRequest{RequestArgs: []byte("{}")})
+	require.NoError(err)
+
+	// Sending fails without consuming the request slot.
+	err = net.SendCrossChainRequest(context.Background(), ids.GenerateTestID(), crossChainRequest, newWaitingResponseHandler())
+	require.ErrorIs(err, errCrossChainNotSupported)
+
+	// Incoming requests are dropped, since they cannot be responded to.
+	require.NoError(net.CrossChainAppRequest(context.Background(), ids.GenerateTestID(), 0, time.Now().Add(time.Second), crossChainRequest))
+	require.Zero(handler.calls)
+}
+
+func TestCrossChainRequestHonoursDeadline(t *testing.T) {
+	require := require.New(t)
+
+	responded := false
+	codecManager := buildCodec(t, message.EthCallRequest{}, message.EthCallResponse{})
+	sender := testAppSender{
+		sendCrossChainAppResponseFn: func(ids.ID, uint32, []byte) error {
+			responded = true
+			return nil
+		},
+	}
+
+	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
+	require.NoError(err)
+	net := NewNetwork(p2pNetwork, sender, nil, codecManager, ids.EmptyNodeID, 1, 1)
+	handler := &testCrossChainHandler{codec: codecManager}
+	net.SetCrossChainRequestHandler(handler)
+
+	crossChainRequest, err := message.CrossChainRequestToBytes(codecManager, message.EthCallRequest{RequestArgs: []byte("{}")})
+	require.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	requestID := peertest.TestPeerRequestID
+	require.NoError(net.CrossChainAppRequest(context.Background(), chainID, requestID, time.Now().Add(time.Millisecond), crossChainRequest))
+	// ensure the handler didn't get called (as peer.Network would've dropped the request)
+	require.Zero(handler.calls)
+	require.False(responded)
+
+	// garbage requests are dropped without a fatal error
+	require.NoError(net.CrossChainAppRequest(context.Background(), chainID, requestID, time.Now().Add(time.Second), []byte("garbage")))
+	require.Zero(handler.calls)
+
+	require.NoError(net.CrossChainAppRequest(context.Background(), chainID, requestID, time.Now().Add(time.Second), crossChainRequest))
+	require.EqualValues(1, handler.calls)
+	require.True(responded)
+}
+
+func TestCrossChainAppRequestFailed(t *testing.T) {
+	require := require.New(t)
+
+	var net Network
+	codecManager := buildCodec(t, message.EthCallRequest{}, message.EthCallResponse{})
+	sender := testAppSender{
+		sendCrossChainAppRequestFn: func(respondingChainID ids.ID, requestID uint32, _ []byte) error {
+			go func() {
+				if err := net.CrossChainAppRequestFailed(context.Background(), respondingChainID, requestID); err != nil {
+					panic(err)
+				}
+			}()
+			return nil
+		},
+	}
+
+	p2pNetwork, err := p2p.NewNetwork(logging.NoLog{}, nil, prometheus.NewRegistry(), "")
+	require.NoError(err)
+	net = NewNetwork(p2pNetwork, sender, nil, codecManager, ids.EmptyNodeID, 1, 1)
+	client := NewNetworkClient(net)
+	defer net.Shutdown()
+
+	crossChainRequest, err := message.CrossChainRequestToBytes(codecManager, message.EthCallRequest{RequestArgs: []byte("{}")})
+	require.NoError(err)
+
+	// Both requests must complete, so the failed request released its slot.
+	for i := 0; i < 2; i++ {
+		_, err = client.SendCrossChainRequest(context.Background(), ids.GenerateTestID(), crossChainRequest)
+		require.ErrorIs(err, ErrRequestFailed)
+	}
+}
+
 func buildCodec(t *testing.T, types ...interface{}) codec.Manager {
 	codecManager := codec.NewDefaultManager()
 	c := linearcodec.NewDefault()
@@ -619,9 +791,19 @@ func marshalStruct(codec codec.Manager, obj interface{}) ([]byte, error) {
 }
 
 type testAppSender struct {
-	sendAppRequestFn  func(context.Context, set.Set[ids.NodeID], uint32, []byte) error
-	sendAppResponseFn func(ids.NodeID, uint32, []byte) error
-	sendAppGossipFn   func(common.SendConfig, []byte) error
+	sendAppRequestFn            func(context.Context, set.Set[ids.NodeID], uint32, []byte) error
+	sendAppResponseFn           func(ids.NodeID, uint32, []byte) error
+	sendAppGossipFn             func(common.SendConfig, []byte) error
+	sendCrossChainAppRequestFn  func(ids.ID, uint32, []byte) error
+	sendCrossChainAppResponseFn func(ids.ID, uint32, []byte) error
+}
+
+func (t testAppSender) SendCrossChainAppRequest(_ context.Context, chainID ids.ID, requestID uint32, message []byte) error {
+	return t.sendCrossChainAppRequestFn(chainID, requestID, message)
+}
+
+func (t testAppSender) SendCrossChainAppResponse(_ context.Context, chainID ids.ID, requestID uint32, message []byte) error {
+	return t.sendCrossChainAppResponseFn(chainID, requestID, message)
 }
 
 func (t testAppSender) SendAppRequest(ctx context.Context, nodeIDs set.Set[ids.NodeID], requestID uint32, message []byte) error {
@@ -745,6 +927,22 @@ func (r *testRequestHandler) handleTestRequest(ctx context.Context, _ ids.NodeID
 	return r.response, r.err
 }
 
+type noCrossChainAppSender struct {
+	common.AppSender
+}
+
+type testCrossChainHandler struct {
+	message.CrossChainRequestHandler
+	codec codec.Manager
+	calls uint32
+}
+
+// HandleEthCallRequest echoes the request arguments back as the execution result.
+func (t *testCrossChainHandler) HandleEthCallRequest(_ context.Context, _ ids.ID, _ uint32, request message.EthCallRequest) ([]byte, error) {
+	t.calls++
+	return t.codec.Marshal(message.Version, message.EthCallResponse{ExecutionResult: request.RequestArgs})
+}
+
 type testSDKHandler struct {
 	appRequested bool
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/luxfi/node/api"
 	"github.com/luxfi/node/utils/profiler"
 	"github.com/luxfi/geth/log"
+	"github.com/luxfi/evm/plugin/evm/client"
 )
 
 // Admin is the API service for admin API calls
@@ -80,3 +81,21 @@ func (p *Admin) GetVMConfig(_ *http.Request, _ *struct{}, reply *client.ConfigRe
 	reply.Config = &p.vm.config
 	return nil
 }
+
+// InspectDatabase traverses the VM's databases and returns the size and number
+// of items of all different categories of data.
+// The databases are not locked during the traversal, which may take a long time
+// on large databases.
+func (p *Admin) InspectDatabase(_ *http.Request, _ *struct{}, reply *client.InspectDatabaseReply) error {
+	log.Info("Admin: InspectDatabase called")
+
+	stats, err := p.vm.inspectDatabases()
+	if err != nil {
+		return fmt.Errorf("failed to inspect database: %w", err)
+	}
+	reply.Stats = stats
+	for _, stat := range stats {
+		reply.Total += stat.Size
+	}
+	return nil
+}
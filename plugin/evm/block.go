@@ -17,6 +17,7 @@ import (
 	"github.com/luxfi/evm/params"
 	"github.com/luxfi/evm/precompile/precompileconfig"
 	"github.com/luxfi/evm/predicate"
+	warpValidators "github.com/luxfi/evm/warp/validators"
 	"github.com/luxfi/node/ids"
 	"github.com/luxfi/node/consensus/choices"
 	"github.com/luxfi/node/consensus/chain"
@@ -190,6 +191,7 @@ func (b *Block) VerifyWithContext(ctx context.Context, proposerVMBlockCtx *block
 	return b.verify(&precompileconfig.PredicateContext{
 		SnowCtx:            b.vm.ctx,
 		ProposerVMBlockCtx: proposerVMBlockCtx,
+		WarpValidatorSets:  warpValidators.NewCanonicalSetCache(),
 	}, true)
 }
 
@@ -6,12 +6,14 @@ package evm
 import (
 	"sync"
 	"time"
+	"github.com/holiman/uint256"
 	"github.com/luxfi/node/utils/timer"
 	"github.com/luxfi/evm/core"
 	"github.com/luxfi/evm/core/txpool"
 	"github.com/luxfi/evm/params"
 	"github.com/luxfi/node/consensus"
 	commonEng "github.com/luxfi/node/consensus/engine"
+	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/log"
 )
 
@@ -25,7 +27,14 @@ type blockBuilder struct {
 	ctx         *consensus.Context
 	chainConfig *params.ChainConfig
 
-	txPool *txpool.TxPool
+	txPool     *txpool.TxPool
+	blockChain *core.BlockChain
+
+	// minBuildDelay is the minimum amount of time to wait for more transactions after
+	// the first one is submitted before building a block, unless the pending transactions
+	// fill [targetFillRatio] of the gas limit.
+	minBuildDelay   time.Duration
+	targetFillRatio float64
 
 	shutdownChan <-chan struct{}
 	shutdownWg   *sync.WaitGroup
@@ -34,13 +43,21 @@ type blockBuilder struct {
 	// is ready to be build. This notifies the consensus common.
 	notifyBuildBlockChan chan<- commonEng.Message
 
-	// [buildBlockLock] must be held when accessing [buildSent]
+	// [buildBlockLock] must be held when accessing [buildSent], [batching] and [submittedGas]
 	buildBlockLock sync.Mutex
 
 	// buildSent is true iff we have sent a PendingTxs message to the consensus message and
 	// are still waiting for buildBlock to be called.
 	buildSent bool
 
+	// batching is true iff submitted transactions are waiting for [minBuildDelay] to
+	// elapse before a PendingTxs message is sent.
+	batching bool
+
+	// submittedGas is the gas of the transactions submitted since the engine last
+	// called BuildBlock, which is compared against [targetFillRatio] of the gas limit.
+	submittedGas uint64
+
 	// buildBlockTimer is a timer used to delay retrying block building a minimum amount of time
 	// with the same contents of the mempool.
 	// If the mempool receives a new transaction, the block builder will send a new notification to
@@ -53,6 +70,9 @@ func (vm *VM) NewBlockBuilder(notifyBuildBlockChan chan<- commonEng.Message) *bl
 		ctx:                  vm.ctx,
 		chainConfig:          vm.chainConfig,
 		txPool:               vm.txPool,
+		blockChain:           vm.blockChain,
+		minBuildDelay:        vm.config.BlockBuildingMinDelay.Duration,
+		targetFillRatio:      vm.config.BlockBuildingTargetFillRatio,
 		shutdownChan:         vm.shutdownChan,
 		shutdownWg:           &vm.shutdownWg,
 		notifyBuildBlockChan: notifyBuildBlockChan,
@@ -74,6 +94,9 @@ func (b *blockBuilder) buildBlockTimerCallback() {
 	b.buildBlockLock.Lock()
 	defer b.buildBlockLock.Unlock()
 
+	// Any batching delay has elapsed.
+	b.batching = false
+
 	// If there are still transactions in the mempool, send another notification to
 	// the engine to retry BuildBlock.
 	if b.needToBuild() {
@@ -86,8 +109,10 @@ func (b *blockBuilder) handleGenerateBlock() {
 	b.buildBlockLock.Lock()
 	defer b.buildBlockLock.Unlock()
 
-	// Reset buildSent now that the engine has called BuildBlock.
+	// Reset buildSent now that the engine has called BuildBlock. The submitted
+	// transactions are now either in the block or left for the retry below.
 	b.buildSent = false
+	b.submittedGas = 0
 
 	// Set a timer to check if calling build block a second time is needed.
 	b.buildBlockTimer.SetTimeoutIn(minBlockBuildingRetryDelay)
@@ -102,6 +127,18 @@ func (b *blockBuilder) needToBuild() bool {
 	return size > 0
 }
 
+// targetFillReached returns true if the transactions submitted since the last
+// block was built fill at least [targetFillRatio] of the gas limit of the current
+// block.
+// targetFillReached assumes the [buildBlockLock] is held.
+func (b *blockBuilder) targetFillReached() bool {
+	if b.targetFillRatio <= 0 {
+		return false
+	}
+	target := uint64(b.targetFillRatio * float64(b.blockChain.CurrentBlock().GasLimit))
+	return b.submittedGas >= target
+}
+
 // markBuilding adds a PendingTxs message to the toEngine channel.
 // markBuilding assumes the [buildBlockLock] is held.
 func (b *blockBuilder) markBuilding() {
@@ -110,6 +147,7 @@ func (b *blockBuilder) markBuilding() {
 		return
 	}
 	b.buildBlockTimer.Cancel() // Cancel any future attempt from the timer to send a PendingTxs message
+	b.batching = false
 
 	select {
 	case b.notifyBuildBlockChan <- commonEng.PendingTxs:
@@ -135,6 +173,29 @@ func (b *blockBuilder) signalTxsReady() {
 	b.markBuilding()
 }
 
+// signalTxsSubmitted sends a PendingTxs notification to the consensus common once
+// [minBuildDelay] has elapsed since the first of the submitted transactions, or
+// as soon as the submitted transactions reach the target fill ratio.
+func (b *blockBuilder) signalTxsSubmitted(txs []*types.Transaction) {
+	b.buildBlockLock.Lock()
+	defer b.buildBlockLock.Unlock()
+
+	for _, tx := range txs {
+		b.submittedGas += tx.Gas()
+	}
+	if b.minBuildDelay <= 0 || b.targetFillReached() {
+		b.markBuilding()
+		return
+	}
+	if b.buildSent || b.batching {
+		return
+	}
+	// Wait for more transactions to batch into the block. The timer callback sends
+	// the notification once the delay has elapsed.
+	b.batching = true
+	b.buildBlockTimer.SetTimeoutIn(b.minBuildDelay)
+}
+
 // awaitSubmittedTxs waits for new transactions to be submitted
 // and notifies the VM when the tx pool has transactions to be
 // put into a new block.
@@ -150,9 +211,9 @@ func (b *blockBuilder) awaitSubmittedTxs() {
 
 		for {
 			select {
-			case <-txSubmitChan:
+			case ev := <-txSubmitChan:
 				log.Trace("New tx detected, trying to generate a block")
-				b.signalTxsReady()
+				b.signalTxsSubmitted(ev.Txs)
 			case <-b.shutdownChan:
 				b.buildBlockTimer.Stop()
 				return
@@ -0,0 +1,72 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	commonEng "github.com/luxfi/node/consensus/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockBuildingTargetFill(t *testing.T) {
+	require := require.New(t)
+
+	// The target is 1% of the 8M gas limit, which four transfers fill but three
+	// do not.
+	issuer, vm, _, _ := GenesisVM(t, true, genesisJSONSubnetEVM, `{"block-building-min-delay":"1h","block-building-target-fill-ratio":0.01}`, "")
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+	}()
+
+	nonce := uint64(0)
+	addTransfers := func(count int) {
+		txs := make([]*types.Transaction, count)
+		for i := range txs {
+			tx := types.NewTransaction(nonce, common.Address{0xaa}, big.NewInt(1), 21000, big.NewInt(testMinGasPrice), nil)
+			signedTx, err := types.SignTx(tx, types.NewEIP155Signer(vm.chainConfig.ChainID), testKeys[0])
+			require.NoError(err)
+			txs[i] = signedTx
+			nonce++
+		}
+		for _, err := range vm.txPool.AddRemotesSync(txs) {
+			require.NoError(err)
+		}
+	}
+	requireNoBuild := func() {
+		select {
+		case msg := <-issuer:
+			require.FailNow("unexpected message", "%v", msg)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	// Below the target the transactions are batched until the delay elapses.
+	addTransfers(3)
+	requireNoBuild()
+
+	addTransfers(1)
+	select {
+	case msg := <-issuer:
+		require.Equal(commonEng.PendingTxs, msg)
+	case <-time.After(5 * time.Second):
+		require.FailNow("timed out waiting for the target fill to trigger block building")
+	}
+
+	blk, err := vm.BuildBlock(context.Background())
+	require.NoError(err)
+	require.NoError(blk.Verify(context.Background()))
+	require.NoError(vm.SetPreference(context.Background(), blk.ID()))
+	require.NoError(blk.Accept(context.Background()))
+
+	// The gas of the transactions included in the block no longer counts
+	// towards the target.
+	addTransfers(3)
+	requireNoBuild()
+}
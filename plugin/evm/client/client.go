@@ -13,6 +13,7 @@ import (
 	"github.com/luxfi/node/ids"
 	"github.com/luxfi/node/utils/rpc"
 	"github.com/luxfi/evm/plugin/evm/config"
+	"github.com/luxfi/evm/plugin/evm/customrawdb"
 )
 
 // Interface compliance
@@ -38,6 +39,7 @@ type Client interface {
 	LockProfile(ctx context.Context, options ...rpc.Option) error
 	SetLogLevel(ctx context.Context, level slog.Level, options ...rpc.Option) error
 	GetVMConfig(ctx context.Context, options ...rpc.Option) (*config.Config, error)
+	InspectDatabase(ctx context.Context, options ...rpc.Option) (*InspectDatabaseReply, error)
 	GetCurrentValidators(ctx context.Context, nodeIDs []ids.NodeID, options ...rpc.Option) ([]CurrentValidator, error)
 }
 
@@ -103,6 +105,19 @@ func (c *client) GetVMConfig(ctx context.Context, options ...rpc.Option) (*confi
 	return res.Config, err
 }
 
+type InspectDatabaseReply struct {
+	Stats []*customrawdb.DatabaseStat `json:"stats"`
+	Total uint64                      `json:"total"` // in bytes
+}
+
+// InspectDatabase returns the size and number of items of all categories of
+// data stored by the VM
+func (c *client) InspectDatabase(ctx context.Context, options ...rpc.Option) (*InspectDatabaseReply, error) {
+	res := &InspectDatabaseReply{}
+	err := c.adminRequester.SendRequest(ctx, "admin.inspectDatabase", struct{}{}, res, options...)
+	return res, err
+}
+
 type GetCurrentValidatorsRequest struct {
 	NodeIDs []ids.NodeID `json:"nodeIDs"`
 }
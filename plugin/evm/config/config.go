@@ -8,9 +8,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/luxfi/evm/params"
 	"github.com/luxfi/node/database/pebbledb"
+	"github.com/luxfi/node/ids"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/core/rawdb"
 	"github.com/spf13/cast"
 )
 
@@ -49,6 +52,16 @@ const (
 	defaultPopulateMissingTriesParallelism        = 1024
 	defaultStateSyncServerTrieCache               = 64 // MB
 	defaultAcceptedCacheSize                      = 32 // blocks
+	defaultTxPoolRejournal                        = time.Hour
+	defaultPrivateTxExpiryBlocks                  = 30 // About a minute (2s block target)
+
+	// Limits the number of outstanding requests to other chains in the network
+	defaultMaxOutboundActiveCrossChainRequests = 64
+
+	// Health check thresholds
+	defaultHealthMaxBlockAcceptanceDelay  = 0 // Disabled by default, as idle chains do not produce blocks
+	defaultHealthMaxTxPoolSize            = 0 // Disabled by default
+	defaultHealthMinConnectedStakePercent = .8
 
 	// defaultStateSyncMinBlocks is the minimum number of blocks the blockchain
 	// should be ahead of local last accepted to perform state sync.
@@ -64,6 +77,10 @@ const (
 
 	estimatedBlockAcceptPeriod        = 2 * time.Second
 	defaultHistoricalProofQueryWindow = uint64(24 * time.Hour / estimatedBlockAcceptPeriod)
+
+	// defaultStateHistory keeps the state histories of the path scheme for as
+	// many blocks as the historical proof query window covers.
+	defaultStateHistory = defaultHistoricalProofQueryWindow
 )
 
 type PBool bool
@@ -137,6 +154,13 @@ type Config struct {
 	// last accepted block to be accepted for proof state queries.
 	HistoricalProofQueryWindow uint64 `json:"historical-proof-query-window,omitempty"`
 
+	// State Scheme Settings
+	StateScheme string `json:"state-scheme,omitempty"` // Scheme used to store states and trie nodes ("hash" or "path"), defaults to the one of the persistent state
+	// StateHistory is, when running with the path scheme, the number of blocks before the last
+	// accepted block whose state can be rebuilt from state histories for tracing and RPC calls.
+	// Zero retains the state histories of all blocks.
+	StateHistory uint64 `json:"state-history"`
+
 	// Metric Settings
 	MetricsExpensiveEnabled bool `json:"metrics-expensive-enabled"` // Debug-level metrics that might impact runtime performance
 
@@ -151,6 +175,13 @@ type Config struct {
 	TxPoolGlobalQueue  uint64   `json:"tx-pool-global-queue"`
 	TxPoolLifetime     Duration `json:"tx-pool-lifetime"`
 
+	// TxPoolJournal is the file the local transactions are journaled to so they
+	// survive node restarts, relative to the chain data directory if not absolute.
+	// Journaling is disabled if empty. Reloaded transactions are push gossiped once
+	// normal operations start.
+	TxPoolJournal   string   `json:"tx-pool-journal"`
+	TxPoolRejournal Duration `json:"tx-pool-rejournal"` // Time interval to regenerate the local transaction journal
+
 	APIMaxDuration           Duration      `json:"api-max-duration"`
 	WSCPURefillRate          Duration      `json:"ws-cpu-refill-rate"`
 	WSCPUMaxStored           Duration      `json:"ws-cpu-max-stored"`
@@ -175,6 +206,23 @@ type Config struct {
 	RegossipFrequency         Duration         `json:"regossip-frequency"`
 	PriorityRegossipAddresses []common.Address `json:"priority-regossip-addresses"`
 
+	// Private Transaction Settings
+	// Private transactions are kept out of the transaction gossip and the txpool_content
+	// and newPendingTransactions feeds, so they are only included in the blocks built
+	// by this node until they expire. eth_sendPrivateRawTransaction always submits
+	// transactions privately.
+	PrivateTxSubmission   bool   `json:"private-tx-submission"`    // Submits all the transactions received over the API privately
+	PrivateTxExpiryBlocks uint64 `json:"private-tx-expiry-blocks"` // Number of blocks after which private transactions are gossiped, 0 to keep them private
+
+	// Block Building Settings
+	// Blocks are built as soon as transactions are submitted unless a minimum delay is
+	// set, in which case the submitted transactions are batched until the delay elapses
+	// or they fill the target ratio of the gas limit. Zero values disable the limits.
+	BlockBuildingMinDelay               Duration `json:"block-building-min-delay"`                // Minimum time to wait for more transactions before building a block
+	BlockBuildingTargetFillRatio        float64  `json:"block-building-target-fill-ratio"`        // Fraction of the gas limit the pending transactions must fill to build before the delay elapses
+	BlockBuildingMaxTxs                 int      `json:"block-building-max-txs"`                  // Maximum number of transactions per block
+	BlockBuildingPriorityGasReservation uint64   `json:"block-building-priority-gas-reservation"` // Gas of each block only the transactions of the priority regossip addresses can use
+
 	// Log
 	LogLevel      string `json:"log-level"`
 	LogJSONFormat bool   `json:"log-json-format"`
@@ -188,7 +236,11 @@ type Config struct {
 	OfflinePruningDataDirectory   string `json:"offline-pruning-data-directory"`
 
 	// VM2VM network
-	MaxOutboundActiveRequests int64 `json:"max-outbound-active-requests"`
+	MaxOutboundActiveRequests           int64 `json:"max-outbound-active-requests"`
+	MaxOutboundActiveCrossChainRequests int64 `json:"max-outbound-active-cross-chain-requests"`
+	// CrossChainRequestsEnabled serves and sends requests to the other chains
+	// of the network. It requires the node to support cross chain messages.
+	CrossChainRequestsEnabled bool `json:"cross-chain-requests-enabled"`
 
 	// Sync settings
 	StateSyncEnabled         bool   `json:"state-sync-enabled"`
@@ -202,6 +254,13 @@ type Config struct {
 	// Database Settings
 	InspectDatabase bool `json:"inspect-database"` // Inspects the database on startup if enabled.
 
+	// Health Settings
+	// A value of 0 disables the corresponding check.
+	HealthMaxBlockAcceptanceDelay  Duration `json:"health-max-block-acceptance-delay"`  // Maximum age of the last accepted block
+	HealthMaxAcceptorQueueSize     *int     `json:"health-max-acceptor-queue-size"`     // Maximum number of blocks waiting in the acceptor queue, half of accepted-queue-limit if unset
+	HealthMaxTxPoolSize            int      `json:"health-max-tx-pool-size"`            // Maximum number of pending and queued transactions
+	HealthMinConnectedStakePercent float64  `json:"health-min-connected-stake-percent"` // Minimum fraction of validator stake this node is connected to
+
 	// SkipUpgradeCheck disables checking that upgrades must take place before the last
 	// accepted block. Skipping this check is useful when a node operator does not update
 	// their node before the network upgrade and their node accepts blocks that have
@@ -234,6 +293,16 @@ type Config struct {
 	// https://github.com/luxfi/node/tree/7623ffd4be915a5185c9ed5e11fa9be15a6e1f00/vms/platformvm/warp/payload#addressedcall
 	WarpOffChainMessages []hexutil.Bytes `json:"warp-off-chain-messages"`
 
+	// Warp relayer settings
+	// The relayer delivers the warp messages sent on this chain to the configured
+	// destinations, signing the delivery transactions with the key stored in
+	// [WarpRelayerPrivateKeyFile]. Zero values use the relayer defaults.
+	WarpRelayerEnabled        bool                     `json:"warp-relayer-enabled"`
+	WarpRelayerPrivateKeyFile string                   `json:"warp-relayer-private-key-file"` // Hex encoded secp256k1 key paying for the deliveries
+	WarpRelayerQuorumNum      uint64                   `json:"warp-relayer-quorum-num"`       // Quorum numerator the relayed messages must be signed with
+	WarpRelayerRetryInterval  Duration                 `json:"warp-relayer-retry-interval"`   // Interval to retry failed deliveries at
+	WarpRelayerDestinations   []WarpRelayerDestination `json:"warp-relayer-destinations"`
+
 	// RPC settings
 	HttpBodyLimit uint64 `json:"http-body-limit"`
 
@@ -246,6 +315,17 @@ type Config struct {
 	DatabaseReadOnly      bool   `json:"database-read-only"`
 }
 
+// WarpRelayerDestination is a chain the warp relayer delivers messages to.
+type WarpRelayerDestination struct {
+	BlockchainID ids.ID         `json:"blockchain-id"`
+	Endpoint     string         `json:"endpoint"` // RPC endpoint of the chain
+	Receiver     common.Address `json:"receiver"` // Contract called with the payload of the messages
+	GasLimit     uint64         `json:"gas-limit"`
+	// AllowedSenders are the addresses whose messages are delivered. All
+	// messages are delivered if empty.
+	AllowedSenders []common.Address `json:"allowed-senders"`
+}
+
 // TxPoolConfig contains the transaction pool config to be passed
 // to [Config.SetDefaults].
 type TxPoolConfig struct {
@@ -277,6 +357,7 @@ func (c *Config) SetDefaults(txPoolConfig TxPoolConfig) {
 	c.TxPoolAccountQueue = txPoolConfig.AccountQueue
 	c.TxPoolGlobalQueue = txPoolConfig.GlobalQueue
 	c.TxPoolLifetime.Duration = txPoolConfig.Lifetime
+	c.TxPoolRejournal.Duration = defaultTxPoolRejournal
 
 	c.APIMaxDuration.Duration = defaultApiMaxDuration
 	c.WSCPURefillRate.Duration = defaultWsCpuRefillRate
@@ -301,10 +382,12 @@ func (c *Config) SetDefaults(txPoolConfig TxPoolConfig) {
 	c.PushGossipFrequency.Duration = defaultPushGossipFrequency
 	c.PullGossipFrequency.Duration = defaultPullGossipFrequency
 	c.RegossipFrequency.Duration = defaultRegossipFrequency
+	c.PrivateTxExpiryBlocks = defaultPrivateTxExpiryBlocks
 	c.OfflinePruningBloomFilterSize = defaultOfflinePruningBloomFilterSize
 	c.LogLevel = defaultLogLevel
 	c.LogJSONFormat = defaultLogJSONFormat
 	c.MaxOutboundActiveRequests = defaultMaxOutboundActiveRequests
+	c.MaxOutboundActiveCrossChainRequests = defaultMaxOutboundActiveCrossChainRequests
 	c.PopulateMissingTriesParallelism = defaultPopulateMissingTriesParallelism
 	c.StateSyncServerTrieCache = defaultStateSyncServerTrieCache
 	c.StateSyncCommitInterval = defaultSyncableCommitInterval
@@ -315,6 +398,10 @@ func (c *Config) SetDefaults(txPoolConfig TxPoolConfig) {
 	c.DatabaseType = defaultDBType
 	c.ValidatorsAPIEnabled = defaultValidatorAPIEnabled
 	c.HistoricalProofQueryWindow = defaultHistoricalProofQueryWindow
+	c.StateHistory = defaultStateHistory
+	c.HealthMaxBlockAcceptanceDelay.Duration = defaultHealthMaxBlockAcceptanceDelay
+	c.HealthMaxTxPoolSize = defaultHealthMaxTxPoolSize
+	c.HealthMinConnectedStakePercent = defaultHealthMinConnectedStakePercent
 }
 
 func (d *Duration) UnmarshalJSON(data []byte) (err error) {
@@ -353,12 +440,65 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("cannot use commit interval of 0 with pruning enabled")
 	}
 
+	switch c.StateScheme {
+	case "", rawdb.HashScheme, rawdb.PathScheme:
+	default:
+		return fmt.Errorf("state-scheme is %q but must be one of %q or %q", c.StateScheme, rawdb.HashScheme, rawdb.PathScheme)
+	}
+
+	if c.TxPoolJournal != "" && c.TxPoolRejournal.Duration < time.Second {
+		return fmt.Errorf("tx-pool-rejournal is %s but must be at least 1s when tx-pool-journal is set", c.TxPoolRejournal)
+	}
+
 	if c.PushGossipPercentStake < 0 || c.PushGossipPercentStake > 1 {
 		return fmt.Errorf("push-gossip-percent-stake is %f but must be in the range [0, 1]", c.PushGossipPercentStake)
 	}
+	if c.BlockBuildingTargetFillRatio < 0 || c.BlockBuildingTargetFillRatio > 1 {
+		return fmt.Errorf("block-building-target-fill-ratio is %f but must be in the range [0, 1]", c.BlockBuildingTargetFillRatio)
+	}
+	if c.BlockBuildingMinDelay.Duration < 0 || c.BlockBuildingMaxTxs < 0 {
+		return fmt.Errorf("block building limits must be non-negative")
+	}
+	if c.HealthMinConnectedStakePercent < 0 || c.HealthMinConnectedStakePercent > 1 {
+		return fmt.Errorf("health-min-connected-stake-percent is %f but must be in the range [0, 1]", c.HealthMinConnectedStakePercent)
+	}
+	if c.HealthMaxBlockAcceptanceDelay.Duration < 0 || (c.HealthMaxAcceptorQueueSize != nil && *c.HealthMaxAcceptorQueueSize < 0) || c.HealthMaxTxPoolSize < 0 {
+		return fmt.Errorf("health thresholds must be non-negative")
+	}
+
+	if c.WarpRelayerEnabled {
+		if c.WarpRelayerPrivateKeyFile == "" {
+			return fmt.Errorf("cannot enable the warp relayer without a warp-relayer-private-key-file")
+		}
+		if len(c.WarpRelayerDestinations) == 0 {
+			return fmt.Errorf("cannot enable the warp relayer without warp-relayer-destinations")
+		}
+		if c.WarpRelayerQuorumNum > params.WarpQuorumDenominator {
+			return fmt.Errorf("warp-relayer-quorum-num is %d but must be at most %d", c.WarpRelayerQuorumNum, params.WarpQuorumDenominator)
+		}
+		if c.WarpRelayerRetryInterval.Duration < 0 {
+			return fmt.Errorf("warp-relayer-retry-interval must be non-negative")
+		}
+		for _, dest := range c.WarpRelayerDestinations {
+			if dest.BlockchainID == ids.Empty || dest.Endpoint == "" {
+				return fmt.Errorf("warp-relayer-destinations must specify a blockchain-id and an endpoint")
+			}
+		}
+	}
 	return nil
 }
 
+// MaxAcceptorQueueSize returns the number of blocks waiting in the acceptor
+// queue above which the VM is unhealthy, or 0 if the check is disabled. Unless
+// configured, the VM is unhealthy once the acceptor queue is more than half
+// full.
+func (c *Config) MaxAcceptorQueueSize() int {
+	if c.HealthMaxAcceptorQueueSize != nil {
+		return *c.HealthMaxAcceptorQueueSize
+	}
+	return c.AcceptorQueueLimit / 2
+}
+
 func (c *Config) Deprecate() string {
 	msg := ""
 	// Deprecate the old config options and set the new ones.
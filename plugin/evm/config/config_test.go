@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/node/ids"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,7 +59,33 @@ func TestUnmarshalConfig(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"tx pool journal",
+			[]byte(`{"tx-pool-journal": "transactions.rlp", "tx-pool-rejournal": "10m"}`),
+			Config{
+				TxPoolJournal:   "transactions.rlp",
+				TxPoolRejournal: Duration{10 * time.Minute},
+			},
+			false,
+		},
 
+		{
+			"private transactions",
+			[]byte(`{"private-tx-submission": true, "private-tx-expiry-blocks": 10}`),
+			Config{PrivateTxSubmission: true, PrivateTxExpiryBlocks: 10},
+			false,
+		},
+		{
+			"block building policy",
+			[]byte(`{"block-building-min-delay": "250ms", "block-building-target-fill-ratio": 0.5, "block-building-max-txs": 100, "block-building-priority-gas-reservation": 1000000}`),
+			Config{
+				BlockBuildingMinDelay:               Duration{250 * time.Millisecond},
+				BlockBuildingTargetFillRatio:        0.5,
+				BlockBuildingMaxTxs:                 100,
+				BlockBuildingPriorityGasReservation: 1_000_000,
+			},
+			false,
+		},
 		{
 			"state sync enabled",
 			[]byte(`{"state-sync-enabled":true}`),
@@ -111,6 +138,39 @@ func TestUnmarshalConfig(t *testing.T) {
 			Config{AllowUnprotectedTxHashes: []common.Hash{common.HexToHash("0x803351deb6d745e91545a6a3e1c0ea3e9a6a02a1a4193b70edfcd2f40f71a01c")}},
 			false,
 		},
+		{
+			"health thresholds",
+			[]byte(`{"health-max-block-acceptance-delay": "30s", "health-max-acceptor-queue-size": 10, "health-max-tx-pool-size": 5000, "health-min-connected-stake-percent": 0.5}`),
+			Config{
+				HealthMaxBlockAcceptanceDelay:  Duration{30 * time.Second},
+				HealthMaxAcceptorQueueSize:     newInt(10),
+				HealthMaxTxPoolSize:            5000,
+				HealthMinConnectedStakePercent: 0.5,
+			},
+			false,
+		},
+		{
+			"warp relayer",
+			[]byte(`{"warp-relayer-enabled": true, "warp-relayer-private-key-file": "/keys/relayer", "warp-relayer-retry-interval": "10s", "warp-relayer-destinations": [{"blockchain-id": "2JVSBoinj9C2J33VntvzYtVJNZdN2NKiwwKjcumHUWEb5DbBrm", "endpoint": "http://127.0.0.1:9650/ext/bc/dest/rpc", "receiver": "0x0200000000000000000000000000000000000005", "allowed-senders": ["0x0100000000000000000000000000000000000001"]}]}`),
+			Config{
+				WarpRelayerEnabled:        true,
+				WarpRelayerPrivateKeyFile: "/keys/relayer",
+				WarpRelayerRetryInterval:  Duration{10 * time.Second},
+				WarpRelayerDestinations: []WarpRelayerDestination{{
+					BlockchainID:   ids.FromStringOrPanic("2JVSBoinj9C2J33VntvzYtVJNZdN2NKiwwKjcumHUWEb5DbBrm"),
+					Endpoint:       "http://127.0.0.1:9650/ext/bc/dest/rpc",
+					Receiver:       common.HexToAddress("0x0200000000000000000000000000000000000005"),
+					AllowedSenders: []common.Address{common.HexToAddress("0x0100000000000000000000000000000000000001")},
+				}},
+			},
+			false,
+		},
+		{
+			"path scheme state history",
+			[]byte(`{"state-scheme": "path", "state-history": 128}`),
+			Config{StateScheme: "path", StateHistory: 128},
+			false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -127,3 +187,26 @@ func TestUnmarshalConfig(t *testing.T) {
 		})
 	}
 }
+
+func newInt(val int) *int { return &val }
+
+func TestMaxAcceptorQueueSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenJSON []byte
+		expected  int
+	}{
+		{"default", []byte(`{}`), defaultAcceptorQueueLimit / 2},
+		{"derived from the queue limit", []byte(`{"accepted-queue-limit": 10}`), 5},
+		{"configured", []byte(`{"accepted-queue-limit": 10, "health-max-acceptor-queue-size": 8}`), 8},
+		{"disabled", []byte(`{"health-max-acceptor-queue-size": 0}`), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			config.SetDefaults(TxPoolConfig{})
+			assert.NoError(t, json.Unmarshal(tt.givenJSON, &config))
+			assert.Equal(t, tt.expected, config.MaxAcceptorQueueSize())
+		})
+	}
+}
@@ -0,0 +1,323 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package customrawdb
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/geth/log"
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	// Database labels used in the inspection report.
+	keyValueStoreLabel = "Key-Value store"
+	stateSyncLabel     = "State sync"
+)
+
+// Key prefixes of the go-ethereum schema that are not exported by its rawdb
+// package. These must be kept in sync with go-ethereum/core/rawdb/schema.go.
+var (
+	headerPrefix          = []byte("h")                 // headerPrefix + num (uint64 big endian) + hash -> header
+	headerTDSuffix        = []byte("t")                 // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
+	headerHashSuffix      = []byte("n")                 // headerPrefix + num (uint64 big endian) + headerHashSuffix -> hash
+	headerNumberPrefix    = []byte("H")                 // headerNumberPrefix + hash -> num (uint64 big endian)
+	blockBodyPrefix       = []byte("b")                 // blockBodyPrefix + num (uint64 big endian) + hash -> block body
+	blockReceiptsPrefix   = []byte("r")                 // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
+	txLookupPrefix        = []byte("l")                 // txLookupPrefix + hash -> transaction/receipt lookup metadata
+	bloomBitsPrefix       = []byte("B")                 // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	bloomBitsMetaPrefix   = []byte("iB")                // bloomBitsMetaPrefix + key -> bloom bits indexer metadata
+	codePrefix            = []byte("c")                 // codePrefix + code hash -> account code
+	preimagePrefix        = []byte("secure-key-")       // preimagePrefix + hash -> preimage
+	snapshotAccountPrefix = []byte("a")                 // snapshotAccountPrefix + account hash -> account trie value
+	snapshotStoragePrefix = []byte("o")                 // snapshotStoragePrefix + account hash + storage hash -> storage trie value
+	trieNodeAccountPrefix = []byte("A")                 // trieNodeAccountPrefix + hexPath -> trie node
+	trieNodeStoragePrefix = []byte("O")                 // trieNodeStoragePrefix + accountHash + hexPath -> trie node
+	stateIDPrefix         = []byte("L")                 // stateIDPrefix + state root -> state id
+	configPrefix          = []byte("ethereum-config-")  // config prefix for the db
+	genesisPrefix         = []byte("ethereum-genesis-") // genesis state prefix for the db
+)
+
+// gethMetadataKeys are the singleton keys written by go-ethereum.
+var gethMetadataKeys = [][]byte{
+	[]byte("DatabaseVersion"),
+	[]byte("LastHeader"),
+	[]byte("LastBlock"),
+	[]byte("LastFast"),
+	[]byte("LastFinalized"),
+	[]byte("LastStateID"),
+	[]byte("LastPivot"),
+	[]byte("SnapshotDisabled"),
+	[]byte("SnapshotRoot"),
+	[]byte("SnapshotJournal"),
+	[]byte("SnapshotGenerator"),
+	[]byte("SnapshotRecovery"),
+	[]byte("SnapshotSyncStatus"),
+	[]byte("SkeletonSyncStatus"),
+	[]byte("TrieJournal"),
+	[]byte("TransactionIndexTail"),
+	[]byte("FastTransactionLookupLimit"),
+	[]byte("unclean-shutdown"),
+	[]byte("eth2-transition"),
+	[]byte("TrieSync"),
+}
+
+// extraMetadataKeys are the singleton keys written by this VM.
+var extraMetadataKeys = [][]byte{
+	snapshotBlockHashKey,
+	offlinePruningKey,
+	populateMissingTriesKey,
+	pruningDisabledKey,
+	acceptorTipKey,
+	syncRootKey,
+}
+
+// DatabaseStat is the size and number of items of one category of data.
+type DatabaseStat struct {
+	Database string `json:"database"`
+	Category string `json:"category"`
+	Size     uint64 `json:"size"` // in bytes
+	Items    uint64 `json:"items"`
+}
+
+// Add records a key/value pair of [size] bytes in the stat.
+func (s *DatabaseStat) Add(size uint64) {
+	s.Size += size
+	s.Items++
+}
+
+// NewDatabaseStat returns an empty stat for [category] of [database].
+func NewDatabaseStat(database, category string) *DatabaseStat {
+	return &DatabaseStat{Database: database, Category: category}
+}
+
+// databaseStats holds the stats of every category known to [InspectDatabase],
+// in the order they are reported.
+type databaseStats struct {
+	headers          *DatabaseStat
+	bodies           *DatabaseStat
+	receipts         *DatabaseStat
+	numHashPairings  *DatabaseStat
+	hashNumPairings  *DatabaseStat
+	txLookups        *DatabaseStat
+	bloomBits        *DatabaseStat
+	codes            *DatabaseStat
+	legacyTries      *DatabaseStat
+	stateLookups     *DatabaseStat
+	accountTries     *DatabaseStat
+	storageTries     *DatabaseStat
+	preimages        *DatabaseStat
+	accountSnapshots *DatabaseStat
+	storageSnapshots *DatabaseStat
+	chainConfigs     *DatabaseStat
+	metadata         *DatabaseStat
+	unaccounted      *DatabaseStat
+
+	syncSegments  *DatabaseStat
+	syncStorage   *DatabaseStat
+	codeToFetch   *DatabaseStat
+	syncPerformed *DatabaseStat
+}
+
+func newDatabaseStats() *databaseStats {
+	return &databaseStats{
+		headers:          NewDatabaseStat(keyValueStoreLabel, "Headers"),
+		bodies:           NewDatabaseStat(keyValueStoreLabel, "Bodies"),
+		receipts:         NewDatabaseStat(keyValueStoreLabel, "Receipt lists"),
+		numHashPairings:  NewDatabaseStat(keyValueStoreLabel, "Block number->hash"),
+		hashNumPairings:  NewDatabaseStat(keyValueStoreLabel, "Block hash->number"),
+		txLookups:        NewDatabaseStat(keyValueStoreLabel, "Transaction index"),
+		bloomBits:        NewDatabaseStat(keyValueStoreLabel, "Bloombit index"),
+		codes:            NewDatabaseStat(keyValueStoreLabel, "Contract codes"),
+		legacyTries:      NewDatabaseStat(keyValueStoreLabel, "Hash trie nodes"),
+		stateLookups:     NewDatabaseStat(keyValueStoreLabel, "Path trie state lookups"),
+		accountTries:     NewDatabaseStat(keyValueStoreLabel, "Path trie account nodes"),
+		storageTries:     NewDatabaseStat(keyValueStoreLabel, "Path trie storage nodes"),
+		preimages:        NewDatabaseStat(keyValueStoreLabel, "Trie preimages"),
+		accountSnapshots: NewDatabaseStat(keyValueStoreLabel, "Account snapshot"),
+		storageSnapshots: NewDatabaseStat(keyValueStoreLabel, "Storage snapshot"),
+		chainConfigs:     NewDatabaseStat(keyValueStoreLabel, "Chain configs"),
+		metadata:         NewDatabaseStat(keyValueStoreLabel, "Singleton metadata"),
+		unaccounted:      NewDatabaseStat(keyValueStoreLabel, "Unaccounted"),
+		syncSegments:     NewDatabaseStat(stateSyncLabel, "Trie segments"),
+		syncStorage:      NewDatabaseStat(stateSyncLabel, "Storage tries to fetch"),
+		codeToFetch:      NewDatabaseStat(stateSyncLabel, "Code to fetch"),
+		syncPerformed:    NewDatabaseStat(stateSyncLabel, "Block numbers synced to"),
+	}
+}
+
+func (s *databaseStats) list() []*DatabaseStat {
+	return []*DatabaseStat{
+		s.headers,
+		s.bodies,
+		s.receipts,
+		s.numHashPairings,
+		s.hashNumPairings,
+		s.txLookups,
+		s.bloomBits,
+		s.codes,
+		s.legacyTries,
+		s.stateLookups,
+		s.accountTries,
+		s.storageTries,
+		s.preimages,
+		s.accountSnapshots,
+		s.storageSnapshots,
+		s.chainConfigs,
+		s.metadata,
+		s.unaccounted,
+		s.syncSegments,
+		s.syncStorage,
+		s.codeToFetch,
+		s.syncPerformed,
+	}
+}
+
+// categorize returns the stat [key] is accounted in.
+func (s *databaseStats) categorize(key []byte) *DatabaseStat {
+	// Singleton keys are checked first, as some of them share a prefix with
+	// the path based trie nodes.
+	if isMetadataKey(key) {
+		return s.metadata
+	}
+	length := len(key)
+	switch {
+	// State sync progress markers
+	case bytes.HasPrefix(key, syncSegmentsPrefix) && length == syncSegmentsKeyLength:
+		return s.syncSegments
+	case bytes.HasPrefix(key, syncStorageTriesPrefix) && length == syncStorageTriesKeyLength:
+		return s.syncStorage
+	case bytes.HasPrefix(key, CodeToFetchPrefix) && length == codeToFetchKeyLength:
+		return s.codeToFetch
+	case bytes.HasPrefix(key, syncPerformedPrefix) && length == syncPerformedKeyLength:
+		return s.syncPerformed
+
+	// Chain data
+	case bytes.HasPrefix(key, headerPrefix) && length == len(headerPrefix)+8+common.HashLength:
+		return s.headers
+	case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerTDSuffix) && length == len(headerPrefix)+8+common.HashLength+len(headerTDSuffix):
+		return s.headers
+	case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix) && length == len(headerPrefix)+8+len(headerHashSuffix):
+		return s.numHashPairings
+	case bytes.HasPrefix(key, headerNumberPrefix) && length == len(headerNumberPrefix)+common.HashLength:
+		return s.hashNumPairings
+	case bytes.HasPrefix(key, blockBodyPrefix) && length == len(blockBodyPrefix)+8+common.HashLength:
+		return s.bodies
+	case bytes.HasPrefix(key, blockReceiptsPrefix) && length == len(blockReceiptsPrefix)+8+common.HashLength:
+		return s.receipts
+	case bytes.HasPrefix(key, txLookupPrefix) && length == len(txLookupPrefix)+common.HashLength:
+		return s.txLookups
+	case bytes.HasPrefix(key, bloomBitsPrefix) && length == len(bloomBitsPrefix)+10+common.HashLength:
+		return s.bloomBits
+	case bytes.HasPrefix(key, bloomBitsMetaPrefix):
+		return s.bloomBits
+
+	// State data
+	case bytes.HasPrefix(key, codePrefix) && length == len(codePrefix)+common.HashLength:
+		return s.codes
+	case length == common.HashLength:
+		return s.legacyTries
+	case bytes.HasPrefix(key, stateIDPrefix) && length == len(stateIDPrefix)+common.HashLength:
+		return s.stateLookups
+	case bytes.HasPrefix(key, trieNodeAccountPrefix) && length <= len(trieNodeAccountPrefix)+2*common.HashLength:
+		return s.accountTries
+	case bytes.HasPrefix(key, trieNodeStoragePrefix) && length >= len(trieNodeStoragePrefix)+common.HashLength && length <= len(trieNodeStoragePrefix)+3*common.HashLength:
+		return s.storageTries
+	case bytes.HasPrefix(key, preimagePrefix) && length == len(preimagePrefix)+common.HashLength:
+		return s.preimages
+	case bytes.HasPrefix(key, snapshotAccountPrefix) && length == len(snapshotAccountPrefix)+common.HashLength:
+		return s.accountSnapshots
+	case bytes.HasPrefix(key, snapshotStoragePrefix) && length == len(snapshotStoragePrefix)+2*common.HashLength:
+		return s.storageSnapshots
+
+	// Chain configuration
+	case bytes.HasPrefix(key, configPrefix) && length == len(configPrefix)+common.HashLength:
+		return s.chainConfigs
+	case bytes.HasPrefix(key, genesisPrefix) && length == len(genesisPrefix)+common.HashLength:
+		return s.chainConfigs
+	case bytes.HasPrefix(key, upgradeConfigPrefix) && length == len(upgradeConfigPrefix)+common.HashLength:
+		return s.chainConfigs
+	default:
+		return s.unaccounted
+	}
+}
+
+func isMetadataKey(key []byte) bool {
+	for _, metaKey := range gethMetadataKeys {
+		if bytes.Equal(key, metaKey) {
+			return true
+		}
+	}
+	for _, metaKey := range extraMetadataKeys {
+		if bytes.Equal(key, metaKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// InspectDatabaseStats traverses the entire database and returns the size and
+// number of items of all different categories of data, including the ones
+// specific to this VM.
+func InspectDatabaseStats(db ethdb.Database, keyPrefix, keyStart []byte) ([]*DatabaseStat, error) {
+	it := db.NewIterator(keyPrefix, keyStart)
+	defer it.Release()
+
+	var (
+		stats  = newDatabaseStats()
+		count  int64
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for it.Next() {
+		key := it.Key()
+		size := uint64(len(key) + len(it.Value()))
+		stats.categorize(key).Add(size)
+
+		count++
+		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
+			log.Info("Inspecting database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return stats.list(), nil
+}
+
+// InspectDatabase traverses the entire database and checks the size
+// of all different categories of data.
+func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
+	stats, err := InspectDatabaseStats(db, keyPrefix, keyStart)
+	if err != nil {
+		return err
+	}
+	WriteDatabaseStats(os.Stdout, stats)
+	return nil
+}
+
+// WriteDatabaseStats renders [stats] as a table to [w].
+func WriteDatabaseStats(w io.Writer, stats []*DatabaseStat) {
+	var (
+		total common.StorageSize
+		rows  = make([][]string, 0, len(stats))
+	)
+	for _, stat := range stats {
+		size := common.StorageSize(stat.Size)
+		total += size
+		rows = append(rows, []string{stat.Database, stat.Category, size.String(), strconv.FormatUint(stat.Items, 10)})
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Database", "Category", "Size", "Items"})
+	table.SetFooter([]string{"", "Total", total.String(), " "})
+	table.AppendBulk(rows)
+	table.Render()
+}
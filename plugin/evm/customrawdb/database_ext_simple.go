@@ -1,16 +0,0 @@
-// (c) 2025, Hanzo Industries, Inc. All rights reserved.
-// See the file LICENSE for licensing terms.
-
-package customrawdb
-
-import (
-	"github.com/luxfi/geth/ethdb"
-)
-
-// InspectDatabase traverses the entire database and checks the size
-// of all different categories of data.
-func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
-	// TODO: Implement database inspection
-	// The InspectDatabase API has changed in ethereum v1.16.1
-	return nil
-}
\ No newline at end of file
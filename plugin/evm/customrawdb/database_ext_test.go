@@ -7,7 +7,7 @@ import (
 	"fmt"
 
 	"github.com/luxfi/geth/common"
-	ethrawdb "github.com/luxfi/evm/interfaces/core/rawdb"
+	ethrawdb "github.com/luxfi/geth/core/rawdb"
 	"github.com/luxfi/geth/ethdb"
 )
 
@@ -54,10 +54,9 @@ func ExampleInspectDatabase() {
 	// | Key-Value store | Trie preimages          | 0.00 B   |     0 |
 	// | Key-Value store | Account snapshot        | 0.00 B   |     0 |
 	// | Key-Value store | Storage snapshot        | 0.00 B   |     0 |
-	// | Key-Value store | Clique snapshots        | 0.00 B   |     0 |
+	// | Key-Value store | Chain configs           | 0.00 B   |     0 |
 	// | Key-Value store | Singleton metadata      | 93.00 B  |     2 |
-	// | Light client    | CHT trie nodes          | 0.00 B   |     0 |
-	// | Light client    | Bloom trie nodes        | 0.00 B   |     0 |
+	// | Key-Value store | Unaccounted             | 0.00 B   |     0 |
 	// | State sync      | Trie segments           | 78.00 B  |     1 |
 	// | State sync      | Storage tries to fetch  | 77.00 B  |     1 |
 	// | State sync      | Code to fetch           | 34.00 B  |     1 |
@@ -126,6 +125,10 @@ func (s *stubIterator) Next() bool {
 
 func (s *stubIterator) Release() {}
 
+func (s *stubIterator) Error() error {
+	return nil
+}
+
 func (s *stubIterator) Key() []byte {
 	return s.kvs[s.pos()].key
 }
@@ -0,0 +1,104 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"errors"
+
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/node/database"
+)
+
+var (
+	_ ethdb.KeyValueStore = snapshotStore{}
+	_ ethdb.Batch         = readOnlyBatch{}
+
+	errReadOnly = errors.New("snapshot database is read-only")
+)
+
+// chainDatabase is the ethdb.Database of the chain, which also provides
+// read-only snapshots of itself for the chain export. rawdb.NewDatabase hides
+// the methods of the store it wraps, so the snapshots are passed through here.
+type chainDatabase struct {
+	ethdb.Database
+	snapshotter Snapshotter
+}
+
+// NewChainDatabase returns the ethdb.Database of the chain backed by the given
+// luxd database. The returned database also implements
+// core.DatabaseSnapshotter.
+func NewChainDatabase(db database.Database) ethdb.Database {
+	kv := ethDbWrapper{Database: db, snaps: newSnapshots(db)}
+	return chainDatabase{Database: rawdb.NewDatabase(kv), snapshotter: kv}
+}
+
+// NewSnapshotDatabase implements core.DatabaseSnapshotter
+func (db chainDatabase) NewSnapshotDatabase() (ethdb.Database, error) {
+	snap, err := db.snapshotter.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return rawdb.NewDatabase(snapshotStore{Snapshot: snap}), nil
+}
+
+// snapshotStore implements ethdb.KeyValueStore on top of a snapshot, failing
+// all of the writes.
+type snapshotStore struct {
+	Snapshot
+}
+
+// Put implements ethdb.KeyValueWriter
+func (snapshotStore) Put([]byte, []byte) error { return errReadOnly }
+
+// Delete implements ethdb.KeyValueWriter
+func (snapshotStore) Delete([]byte) error { return errReadOnly }
+
+// DeleteRange implements ethdb.KeyValueStore
+func (snapshotStore) DeleteRange([]byte, []byte) error { return errReadOnly }
+
+// Stat implements ethdb.KeyValueStater
+func (snapshotStore) Stat() (string, error) { return "", database.ErrNotFound }
+
+// SyncKeyValue implements ethdb.KeyValueStore
+func (snapshotStore) SyncKeyValue() error { return nil }
+
+// Compact implements ethdb.Compacter
+func (snapshotStore) Compact([]byte, []byte) error { return nil }
+
+// NewBatch implements ethdb.Batcher
+func (snapshotStore) NewBatch() ethdb.Batch { return readOnlyBatch{} }
+
+// NewBatchWithSize implements ethdb.Batcher
+func (snapshotStore) NewBatchWithSize(int) ethdb.Batch { return readOnlyBatch{} }
+
+// Close implements io.Closer
+func (store snapshotStore) Close() error {
+	store.Snapshot.Release()
+	return nil
+}
+
+// readOnlyBatch is the batch of a snapshot database, which can't be written.
+type readOnlyBatch struct{}
+
+// Put implements ethdb.KeyValueWriter
+func (readOnlyBatch) Put([]byte, []byte) error { return errReadOnly }
+
+// Delete implements ethdb.KeyValueWriter
+func (readOnlyBatch) Delete([]byte) error { return errReadOnly }
+
+// DeleteRange implements ethdb.Batch
+func (readOnlyBatch) DeleteRange([]byte, []byte) error { return errReadOnly }
+
+// ValueSize implements ethdb.Batch
+func (readOnlyBatch) ValueSize() int { return 0 }
+
+// Write implements ethdb.Batch
+func (readOnlyBatch) Write() error { return errReadOnly }
+
+// Reset implements ethdb.Batch
+func (readOnlyBatch) Reset() {}
+
+// Replay implements ethdb.Batch
+func (readOnlyBatch) Replay(ethdb.KeyValueWriter) error { return nil }
@@ -0,0 +1,303 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/google/btree"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/node/database"
+)
+
+var errSnapshotReleased = errors.New("snapshot released")
+
+// Snapshot is a read-only view of the database at the time it was taken,
+// unaffected by the writes made afterwards. It must be released once it's no
+// longer needed.
+type Snapshot interface {
+	ethdb.KeyValueReader
+	ethdb.Iteratee
+
+	// Release releases the resources held by the snapshot. The snapshot and
+	// its iterators can't be used afterwards.
+	Release()
+}
+
+// Snapshotter wraps the NewSnapshot method of a database supporting
+// point-in-time snapshots.
+type Snapshotter interface {
+	// NewSnapshot creates a snapshot of the current state of the database.
+	NewSnapshot() (Snapshot, error)
+}
+
+// snapshots tracks the overlay snapshots taken of a database, which the writes
+// made to it must preserve the overwritten values in.
+type snapshots struct {
+	lock   sync.RWMutex // Held for reading by writers, and for writing to take or release snapshots
+	db     database.Database
+	active map[*overlaySnapshot]struct{}
+}
+
+func newSnapshots(db database.Database) *snapshots {
+	return &snapshots{
+		db:     db,
+		active: make(map[*overlaySnapshot]struct{}),
+	}
+}
+
+// newSnapshot takes an overlay snapshot of the database. Writes in flight are
+// waited for, so the snapshot never observes half of a batch.
+func (s *snapshots) newSnapshot() *overlaySnapshot {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snap := &overlaySnapshot{
+		snaps:     s,
+		preserved: btree.NewG(32, func(a, b preservedValue) bool { return bytes.Compare(a.key, b.key) < 0 }),
+	}
+	s.active[snap] = struct{}{}
+	return snap
+}
+
+// modify preserves the current values of the keys about to be modified in all
+// active snapshots, and then applies the modification. The keys are only
+// resolved if there are any snapshots to preserve them in.
+func (s *snapshots) modify(keys func() ([][]byte, error), apply func() error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if len(s.active) > 0 {
+		keys, err := keys()
+		if err != nil {
+			return err
+		}
+		for snap := range s.active {
+			if err := snap.preserve(keys); err != nil {
+				return err
+			}
+		}
+	}
+	return apply()
+}
+
+// singleKey returns a key resolver for modifying a single key.
+func singleKey(key []byte) func() ([][]byte, error) {
+	return func() ([][]byte, error) { return [][]byte{key}, nil }
+}
+
+// keyCollector collects the keys of the operations replayed into it.
+type keyCollector [][]byte
+
+func (c *keyCollector) Put(key []byte, _ []byte) error {
+	*c = append(*c, common.CopyBytes(key))
+	return nil
+}
+
+func (c *keyCollector) Delete(key []byte) error {
+	*c = append(*c, common.CopyBytes(key))
+	return nil
+}
+
+// preservedValue is the value a key had when a snapshot was taken.
+type preservedValue struct {
+	key    []byte
+	value  []byte
+	exists bool
+}
+
+// overlaySnapshot is a copy-on-write snapshot of a database without native
+// snapshot support. It reads through to the live database, except for the keys
+// modified since it was taken, whose original values it preserves in memory.
+type overlaySnapshot struct {
+	snaps *snapshots
+
+	lock      sync.RWMutex // Held for reading across every read through to the live database
+	preserved *btree.BTreeG[preservedValue]
+	released  bool
+}
+
+// preserve records the current values of the given keys, unless they were
+// already modified since the snapshot was taken.
+func (snap *overlaySnapshot) preserve(keys [][]byte) error {
+	snap.lock.Lock()
+	defer snap.lock.Unlock()
+
+	for _, key := range keys {
+		if _, ok := snap.preserved.Get(preservedValue{key: key}); ok {
+			continue
+		}
+		value, err := snap.snaps.db.Get(key)
+		switch {
+		case err == nil:
+			snap.preserved.ReplaceOrInsert(preservedValue{key: common.CopyBytes(key), value: common.CopyBytes(value), exists: true})
+		case errors.Is(err, database.ErrNotFound):
+			snap.preserved.ReplaceOrInsert(preservedValue{key: common.CopyBytes(key)})
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// Has implements ethdb.KeyValueReader
+func (snap *overlaySnapshot) Has(key []byte) (bool, error) {
+	snap.lock.RLock()
+	defer snap.lock.RUnlock()
+
+	if snap.released {
+		return false, errSnapshotReleased
+	}
+	if preserved, ok := snap.preserved.Get(preservedValue{key: key}); ok {
+		return preserved.exists, nil
+	}
+	return snap.snaps.db.Has(key)
+}
+
+// Get implements ethdb.KeyValueReader
+func (snap *overlaySnapshot) Get(key []byte) ([]byte, error) {
+	snap.lock.RLock()
+	defer snap.lock.RUnlock()
+
+	if snap.released {
+		return nil, errSnapshotReleased
+	}
+	if preserved, ok := snap.preserved.Get(preservedValue{key: key}); ok {
+		if !preserved.exists {
+			return nil, database.ErrNotFound
+		}
+		return common.CopyBytes(preserved.value), nil
+	}
+	return snap.snaps.db.Get(key)
+}
+
+// NewIterator implements ethdb.Iteratee
+//
+// Note: as for the wrapped database, the prefix is NOT part of the start.
+func (snap *overlaySnapshot) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	start = append(common.CopyBytes(prefix), start...)
+	return &snapshotIterator{
+		snap:   snap,
+		live:   snap.snaps.db.NewIteratorWithStartAndPrefix(start, prefix),
+		prefix: common.CopyBytes(prefix),
+		start:  start,
+	}
+}
+
+// Release implements Snapshot
+func (snap *overlaySnapshot) Release() {
+	snap.snaps.lock.Lock()
+	delete(snap.snaps.active, snap)
+	snap.snaps.lock.Unlock()
+
+	snap.lock.Lock()
+	defer snap.lock.Unlock()
+
+	snap.released = true
+	snap.preserved.Clear(false)
+}
+
+// snapshotIterator iterates over the snapshot by merging the live database with
+// the preserved values. Live entries modified since the snapshot was taken are
+// skipped in favor of the preserved ones, which is re-evaluated at every step
+// as the live database may change during the iteration.
+type snapshotIterator struct {
+	snap   *overlaySnapshot
+	live   database.Iterator
+	prefix []byte
+	start  []byte
+
+	livePending bool   // Whether the live iterator is positioned at an entry not yet returned
+	liveDone    bool   // Whether the live iterator is exhausted
+	pos         []byte // Key last returned, nil before the first step
+	key, value  []byte
+	err         error
+}
+
+// Next implements ethdb.Iterator
+func (it *snapshotIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.snap.lock.RLock()
+	defer it.snap.lock.RUnlock()
+
+	if it.snap.released {
+		it.err = errSnapshotReleased
+		it.key, it.value = nil, nil
+		return false
+	}
+	// Position the live iterator at the next entry not modified since the
+	// snapshot was taken.
+	for !it.liveDone {
+		if !it.livePending {
+			if !it.live.Next() {
+				it.liveDone = true
+				it.err = it.live.Error()
+				break
+			}
+			it.livePending = true
+		}
+		key := it.live.Key()
+		if it.pos != nil && bytes.Compare(key, it.pos) <= 0 {
+			it.livePending = false
+			continue
+		}
+		if _, ok := it.snap.preserved.Get(preservedValue{key: key}); ok {
+			it.livePending = false
+			continue
+		}
+		break
+	}
+	if it.err != nil {
+		it.key, it.value = nil, nil
+		return false
+	}
+	// Find the next preserved entry existing at the time of the snapshot.
+	var (
+		preserved preservedValue
+		found     bool
+		pivot     = it.start
+	)
+	if it.pos != nil {
+		pivot = it.pos
+	}
+	it.snap.preserved.AscendGreaterOrEqual(preservedValue{key: pivot}, func(item preservedValue) bool {
+		if !bytes.HasPrefix(item.key, it.prefix) {
+			return false
+		}
+		if !item.exists || (it.pos != nil && bytes.Equal(item.key, it.pos)) {
+			return true
+		}
+		preserved, found = item, true
+		return false
+	})
+	switch {
+	case !it.liveDone && (!found || bytes.Compare(it.live.Key(), preserved.key) < 0):
+		it.key, it.value = common.CopyBytes(it.live.Key()), common.CopyBytes(it.live.Value())
+		it.livePending = false
+	case found:
+		it.key, it.value = common.CopyBytes(preserved.key), common.CopyBytes(preserved.value)
+	default:
+		it.key, it.value = nil, nil
+		return false
+	}
+	it.pos = it.key
+	return true
+}
+
+// Error implements ethdb.Iterator
+func (it *snapshotIterator) Error() error { return it.err }
+
+// Key implements ethdb.Iterator
+func (it *snapshotIterator) Key() []byte { return it.key }
+
+// Value implements ethdb.Iterator
+func (it *snapshotIterator) Value() []byte { return it.value }
+
+// Release implements ethdb.Iterator
+func (it *snapshotIterator) Release() { it.live.Release() }
@@ -12,18 +12,36 @@ import (
 
 var (
 	_ ethdb.KeyValueStore = &ethDbWrapper{}
+	_ Snapshotter         = &ethDbWrapper{}
 
 	ErrSnapshotNotSupported = errors.New("snapshot is not supported")
 )
 
 // ethDbWrapper implements ethdb.Database
-type ethDbWrapper struct{ database.Database }
+type ethDbWrapper struct {
+	database.Database
+	snaps *snapshots
+}
 
-func WrapDatabase(db database.Database) ethdb.KeyValueStore { return ethDbWrapper{db} }
+// WrapDatabase returns an ethdb.KeyValueStore backed by the given luxd
+// database. The returned store also implements [Snapshotter].
+func WrapDatabase(db database.Database) ethdb.KeyValueStore {
+	return ethDbWrapper{Database: db, snaps: newSnapshots(db)}
+}
 
 // Stat implements ethdb.Database
 func (db ethDbWrapper) Stat() (string, error) { return "", database.ErrNotFound }
 
+// Put implements ethdb.KeyValueWriter
+func (db ethDbWrapper) Put(key []byte, value []byte) error {
+	return db.snaps.modify(singleKey(key), func() error { return db.Database.Put(key, value) })
+}
+
+// Delete implements ethdb.KeyValueWriter
+func (db ethDbWrapper) Delete(key []byte) error {
+	return db.snaps.modify(singleKey(key), func() error { return db.Database.Delete(key) })
+}
+
 // DeleteRange implements ethdb.KeyValueStore
 func (db ethDbWrapper) DeleteRange(start []byte, end []byte) error {
 	// Not supported in avalanche database
@@ -37,18 +55,35 @@ func (db ethDbWrapper) SyncKeyValue() error {
 }
 
 // NewBatch implements ethdb.Database
-func (db ethDbWrapper) NewBatch() ethdb.Batch { return wrappedBatch{db.Database.NewBatch()} }
+func (db ethDbWrapper) NewBatch() ethdb.Batch {
+	return wrappedBatch{Batch: db.Database.NewBatch(), snaps: db.snaps}
+}
+
+// sizedBatcher is implemented by luxd databases accepting a hint of the size
+// of the batches they create.
+type sizedBatcher interface {
+	NewBatchWithSize(size int) database.Batch
+}
 
 // NewBatchWithSize implements ethdb.Database
-// TODO: propagate size through luxd Database interface
+//
+// The size hint is passed on if the luxd database accepts it, and ignored
+// otherwise.
 func (db ethDbWrapper) NewBatchWithSize(size int) ethdb.Batch {
-	return wrappedBatch{db.Database.NewBatch()}
+	if sized, ok := db.Database.(sizedBatcher); ok && size > 0 {
+		return wrappedBatch{Batch: sized.NewBatchWithSize(size), snaps: db.snaps}
+	}
+	return db.NewBatch()
 }
 
-// NewSnapshot is not implemented
-// func (db ethDbWrapper) NewSnapshot() (ethdb.Snapshot, error) {
-// 	return nil, ErrSnapshotNotSupported
-// }
+// NewSnapshot implements Snapshotter
+//
+// The snapshot is an in-memory overlay preserving the values overwritten
+// through this wrapper after it is taken, which must hence be the only writer
+// of the database.
+func (db ethDbWrapper) NewSnapshot() (Snapshot, error) {
+	return db.snaps.newSnapshot(), nil
+}
 
 // NewIterator implements ethdb.Database
 //
@@ -72,7 +107,24 @@ func (db ethDbWrapper) NewIteratorWithStart(start []byte) ethdb.Iterator {
 }
 
 // wrappedBatch implements ethdb.wrappedBatch
-type wrappedBatch struct{ database.Batch }
+type wrappedBatch struct {
+	database.Batch
+	snaps *snapshots
+}
+
+// Write implements ethdb.Batch
+func (batch wrappedBatch) Write() error {
+	return batch.snaps.modify(batch.keys, batch.Batch.Write)
+}
+
+// keys returns the keys modified by the batch.
+func (batch wrappedBatch) keys() ([][]byte, error) {
+	var keys keyCollector
+	if err := batch.Batch.Replay(&keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
 
 // ValueSize implements ethdb.Batch
 func (batch wrappedBatch) ValueSize() int { return batch.Batch.Size() }
@@ -0,0 +1,235 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"testing"
+
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/node/api/metrics"
+	"github.com/luxfi/node/database"
+	"github.com/luxfi/node/database/leveldb"
+	"github.com/luxfi/node/database/memdb"
+	"github.com/luxfi/node/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackends runs the test against the wrapper of every luxd backend.
+func testBackends(t *testing.T, test func(t *testing.T, db ethdb.KeyValueStore)) {
+	backends := map[string]func(t *testing.T) database.Database{
+		"memdb": func(*testing.T) database.Database { return memdb.New() },
+		"leveldb": func(t *testing.T) database.Database {
+			db, err := leveldb.New(t.TempDir(), nil, logging.NoLog{}, metrics.NewPrefixGatherer())
+			require.NoError(t, err)
+			return db
+		},
+	}
+	for name, newDB := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := WrapDatabase(newDB(t))
+			defer db.Close()
+			test(t, db)
+		})
+	}
+}
+
+func newSnapshot(t *testing.T, db ethdb.KeyValueStore) Snapshot {
+	snapshotter, ok := db.(Snapshotter)
+	require.True(t, ok)
+	snap, err := snapshotter.NewSnapshot()
+	require.NoError(t, err)
+	return snap
+}
+
+// requireEntries checks the iterator yields exactly the expected entries.
+func requireEntries(t *testing.T, it ethdb.Iterator, expected ...string) {
+	t.Helper()
+	defer it.Release()
+
+	var entries []string
+	for it.Next() {
+		entries = append(entries, string(it.Key())+"="+string(it.Value()))
+	}
+	require.NoError(t, it.Error())
+	require.Equal(t, expected, entries)
+}
+
+func TestSnapshot(t *testing.T) {
+	testBackends(t, func(t *testing.T, db ethdb.KeyValueStore) {
+		require := require.New(t)
+
+		require.NoError(db.Put([]byte("a"), []byte("1")))
+		require.NoError(db.Put([]byte("b"), []byte("2")))
+		require.NoError(db.Put([]byte("c"), []byte("3")))
+
+		snap := newSnapshot(t, db)
+
+		// Modify the database both directly and through batches.
+		require.NoError(db.Put([]byte("b"), []byte("20")))
+		require.NoError(db.Delete([]byte("c")))
+		require.NoError(db.Put([]byte("d"), []byte("4")))
+		batch := db.NewBatch()
+		require.NoError(batch.Put([]byte("a"), []byte("10")))
+		require.NoError(batch.Delete([]byte("b")))
+		require.NoError(batch.Put([]byte("e"), []byte("5")))
+		require.NoError(batch.Write())
+
+		// The snapshot is unaffected.
+		for key, value := range map[string]string{"a": "1", "b": "2", "c": "3"} {
+			has, err := snap.Has([]byte(key))
+			require.NoError(err)
+			require.True(has, key)
+			got, err := snap.Get([]byte(key))
+			require.NoError(err)
+			require.Equal(value, string(got), key)
+		}
+		for _, key := range []string{"d", "e"} {
+			has, err := snap.Has([]byte(key))
+			require.NoError(err)
+			require.False(has, key)
+			_, err = snap.Get([]byte(key))
+			require.ErrorIs(err, database.ErrNotFound)
+		}
+		requireEntries(t, snap.NewIterator(nil, nil), "a=1", "b=2", "c=3")
+
+		// While the database is not.
+		requireEntries(t, db.NewIterator(nil, nil), "a=10", "d=4", "e=5")
+
+		snap.Release()
+		_, err := snap.Get([]byte("a"))
+		require.ErrorIs(err, errSnapshotReleased)
+
+		// Writes don't need to be preserved anymore.
+		require.NoError(db.Put([]byte("f"), []byte("6")))
+		requireEntries(t, db.NewIterator(nil, nil), "a=10", "d=4", "e=5", "f=6")
+	})
+}
+
+func TestSnapshotIteratorBounds(t *testing.T) {
+	testBackends(t, func(t *testing.T, db ethdb.KeyValueStore) {
+		require := require.New(t)
+
+		for _, key := range []string{"p1", "p2", "p3", "q1"} {
+			require.NoError(db.Put([]byte(key), []byte(key)))
+		}
+		snap := newSnapshot(t, db)
+		defer snap.Release()
+
+		require.NoError(db.Delete([]byte("p2")))
+		require.NoError(db.Put([]byte("p0"), []byte("new")))
+		require.NoError(db.Put([]byte("p3"), []byte("new")))
+		require.NoError(db.Delete([]byte("q1")))
+
+		requireEntries(t, snap.NewIterator([]byte("p"), nil), "p1=p1", "p2=p2", "p3=p3")
+		requireEntries(t, snap.NewIterator([]byte("p"), []byte("2")), "p2=p2", "p3=p3")
+		requireEntries(t, snap.NewIterator([]byte("q"), nil), "q1=q1")
+		requireEntries(t, snap.NewIterator([]byte("r"), nil))
+	})
+}
+
+func TestSnapshotIteratorConcurrentWrites(t *testing.T) {
+	testBackends(t, func(t *testing.T, db ethdb.KeyValueStore) {
+		require := require.New(t)
+
+		for _, key := range []string{"a", "b", "c", "d"} {
+			require.NoError(db.Put([]byte(key), []byte(key)))
+		}
+		snap := newSnapshot(t, db)
+		defer snap.Release()
+
+		it := snap.NewIterator(nil, nil)
+		require.True(it.Next())
+		require.Equal("a", string(it.Key()))
+
+		// Modify the entries ahead of the iterator.
+		require.NoError(db.Delete([]byte("b")))
+		require.NoError(db.Put([]byte("bb"), []byte("bb")))
+		require.NoError(db.Put([]byte("c"), []byte("new")))
+		require.NoError(db.Delete([]byte("d")))
+
+		var keys []string
+		for it.Next() {
+			require.Equal(string(it.Key()), string(it.Value()))
+			keys = append(keys, string(it.Key()))
+		}
+		require.NoError(it.Error())
+		it.Release()
+		require.Equal([]string{"b", "c", "d"}, keys)
+	})
+}
+
+func TestSnapshotsIndependent(t *testing.T) {
+	testBackends(t, func(t *testing.T, db ethdb.KeyValueStore) {
+		require := require.New(t)
+
+		require.NoError(db.Put([]byte("a"), []byte("1")))
+		first := newSnapshot(t, db)
+		defer first.Release()
+
+		require.NoError(db.Put([]byte("a"), []byte("2")))
+		second := newSnapshot(t, db)
+		defer second.Release()
+
+		require.NoError(db.Put([]byte("a"), []byte("3")))
+
+		got, err := first.Get([]byte("a"))
+		require.NoError(err)
+		require.Equal("1", string(got))
+		got, err = second.Get([]byte("a"))
+		require.NoError(err)
+		require.Equal("2", string(got))
+	})
+}
+
+func TestNewBatchWithSize(t *testing.T) {
+	testBackends(t, func(t *testing.T, db ethdb.KeyValueStore) {
+		require := require.New(t)
+
+		snap := newSnapshot(t, db)
+		defer snap.Release()
+
+		batch := db.NewBatchWithSize(1024)
+		require.NoError(batch.Put([]byte("a"), []byte("1")))
+		require.Positive(batch.ValueSize())
+		require.NoError(batch.Write())
+
+		got, err := db.Get([]byte("a"))
+		require.NoError(err)
+		require.Equal("1", string(got))
+		has, err := snap.Has([]byte("a"))
+		require.NoError(err)
+		require.False(has)
+	})
+}
+
+func TestSnapshotDatabase(t *testing.T) {
+	require := require.New(t)
+
+	db := NewChainDatabase(memdb.New())
+	defer db.Close()
+	require.NoError(db.Put([]byte("a"), []byte("1")))
+
+	snapshotter, ok := db.(interface {
+		NewSnapshotDatabase() (ethdb.Database, error)
+	})
+	require.True(ok)
+	snapDB, err := snapshotter.NewSnapshotDatabase()
+	require.NoError(err)
+	require.NoError(db.Put([]byte("a"), []byte("2")))
+
+	got, err := snapDB.Get([]byte("a"))
+	require.NoError(err)
+	require.Equal([]byte("1"), got)
+
+	// Writes to the snapshot fail.
+	require.ErrorIs(snapDB.Put([]byte("b"), []byte("3")), errReadOnly)
+	batch := snapDB.NewBatch()
+	require.ErrorIs(batch.Put([]byte("b"), []byte("3")), errReadOnly)
+	require.ErrorIs(batch.Write(), errReadOnly)
+
+	// Closing the database releases the snapshot.
+	require.NoError(snapDB.Close())
+	_, err = snapDB.Get([]byte("a"))
+	require.ErrorIs(err, errSnapshotReleased)
+}
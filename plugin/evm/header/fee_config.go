@@ -0,0 +1,55 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package header
+
+import (
+	"math/big"
+
+	"github.com/luxfi/evm/commontype"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+)
+
+// FeeConfigReader reads the fee config stored at a block, and the fee config
+// scheduled there to replace it.
+type FeeConfigReader interface {
+	GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error)
+	GetScheduledFeeConfigAt(parent *types.Header) (*commontype.ScheduledFeeConfig, error)
+}
+
+// FeeConfig takes the fee config in effect at the parent, the fee config
+// scheduled to take effect at the parent if any, and the timestamp of its child
+// block, and returns the fee config of the child block.
+//
+// A scheduled fee config applies from the first block with a timestamp of at
+// least its effective timestamp.
+func FeeConfig(
+	parentFeeConfig commontype.FeeConfig,
+	scheduled *commontype.ScheduledFeeConfig,
+	timestamp uint64,
+) commontype.FeeConfig {
+	if scheduled != nil && timestamp >= scheduled.EffectiveTimestamp {
+		return scheduled.FeeConfig
+	}
+	return parentFeeConfig
+}
+
+// FeeConfigAt returns the fee config of a block at [timestamp] built on
+// [parent], and the number of the block that last changed it. If the fee config
+// scheduled at [parent] takes effect by [timestamp], it is changed by the child
+// block itself.
+func FeeConfigAt(chain FeeConfigReader, parent *types.Header, timestamp uint64) (commontype.FeeConfig, *big.Int, error) {
+	feeConfig, lastChangedAt, err := chain.GetFeeConfigAt(parent)
+	if err != nil {
+		return commontype.EmptyFeeConfig, nil, err
+	}
+	scheduled, err := chain.GetScheduledFeeConfigAt(parent)
+	if err != nil {
+		return commontype.EmptyFeeConfig, nil, err
+	}
+	if scheduled != nil && timestamp >= scheduled.EffectiveTimestamp {
+		return scheduled.FeeConfig, new(big.Int).Add(parent.Number, common.Big1), nil
+	}
+	return feeConfig, lastChangedAt, nil
+}
@@ -0,0 +1,98 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package header
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/evm/commontype"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeConfig(t *testing.T) {
+	scheduled := &commontype.ScheduledFeeConfig{
+		FeeConfig:          testFeeConfigDouble,
+		EffectiveTimestamp: 10,
+	}
+
+	tests := []struct {
+		name      string
+		scheduled *commontype.ScheduledFeeConfig
+		timestamp uint64
+		want      commontype.FeeConfig
+	}{
+		{
+			name:      "nothing_scheduled",
+			timestamp: 10,
+			want:      testFeeConfig,
+		},
+		{
+			name:      "before_effective_timestamp",
+			scheduled: scheduled,
+			timestamp: 9,
+			want:      testFeeConfig,
+		},
+		{
+			name:      "at_effective_timestamp",
+			scheduled: scheduled,
+			timestamp: 10,
+			want:      testFeeConfigDouble,
+		},
+		{
+			name:      "after_effective_timestamp",
+			scheduled: scheduled,
+			timestamp: 11,
+			want:      testFeeConfigDouble,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, FeeConfig(testFeeConfig, test.scheduled, test.timestamp))
+		})
+	}
+}
+
+type testFeeConfigReader struct {
+	lastChangedAt *big.Int
+	scheduled     *commontype.ScheduledFeeConfig
+}
+
+func (r *testFeeConfigReader) GetFeeConfigAt(*types.Header) (commontype.FeeConfig, *big.Int, error) {
+	return testFeeConfig, r.lastChangedAt, nil
+}
+
+func (r *testFeeConfigReader) GetScheduledFeeConfigAt(*types.Header) (*commontype.ScheduledFeeConfig, error) {
+	return r.scheduled, nil
+}
+
+func TestFeeConfigAt(t *testing.T) {
+	require := require.New(t)
+
+	parent := &types.Header{Number: big.NewInt(7)}
+	reader := &testFeeConfigReader{
+		lastChangedAt: big.NewInt(3),
+		scheduled: &commontype.ScheduledFeeConfig{
+			FeeConfig:          testFeeConfigDouble,
+			EffectiveTimestamp: 10,
+		},
+	}
+	feeConfig, lastChangedAt, err := FeeConfigAt(reader, parent, 9)
+	require.NoError(err)
+	require.Equal(testFeeConfig, feeConfig)
+	require.Equal(big.NewInt(3), lastChangedAt)
+
+	// The child block stores the scheduled fee config
+	feeConfig, lastChangedAt, err = FeeConfigAt(reader, parent, 10)
+	require.NoError(err)
+	require.Equal(testFeeConfigDouble, feeConfig)
+	require.Equal(big.NewInt(8), lastChangedAt)
+
+	reader.scheduled = nil
+	feeConfig, lastChangedAt, err = FeeConfigAt(reader, parent, 10)
+	require.NoError(err)
+	require.Equal(testFeeConfig, feeConfig)
+	require.Equal(big.NewInt(3), lastChangedAt)
+}
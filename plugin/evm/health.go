@@ -3,12 +3,186 @@
 
 package evm
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
-// Health returns nil if this chain is healthy.
-// Also returns details, which should be one of:
-// string, []byte, map[string]string
+	"github.com/luxfi/node/consensus"
+)
+
+const (
+	bootstrappedCheck    = "bootstrapped"
+	lastAcceptedCheck    = "lastAcceptedBlock"
+	acceptorQueueCheck   = "acceptorQueue"
+	txPoolCheck          = "txPool"
+	connectedStakeCheck  = "connectedStake"
+	healthCheckPassedMsg = "healthy"
+)
+
+var errUnhealthy = errors.New("vm is unhealthy")
+
+// healthCheckResult is the outcome of a single health check.
+type healthCheckResult struct {
+	Healthy bool        `json:"healthy"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// healthReport is returned as the details of [VM.HealthCheck].
+type healthReport struct {
+	Healthy bool                          `json:"healthy"`
+	Checks  map[string]*healthCheckResult `json:"checks"`
+}
+
+// HealthCheck returns a report with the result of each individual check and
+// a non-nil error if any of them failed.
 func (vm *VM) HealthCheck(context.Context) (interface{}, error) {
-	// TODO perform actual health check
-	return nil, nil
+	report := &healthReport{
+		Healthy: true,
+		Checks: map[string]*healthCheckResult{
+			bootstrappedCheck: vm.checkBootstrapped(),
+		},
+	}
+	// The remaining checks are only meaningful once the chain is initialized
+	// and processing blocks.
+	if vm.blockChain != nil {
+		report.Checks[lastAcceptedCheck] = vm.checkLastAccepted()
+		report.Checks[acceptorQueueCheck] = vm.checkAcceptorQueue()
+	}
+	if vm.txPool != nil {
+		report.Checks[txPoolCheck] = vm.checkTxPool()
+	}
+	if vm.validatorsManager != nil {
+		report.Checks[connectedStakeCheck] = vm.checkConnectedStake()
+	}
+
+	var failed []string
+	for name, result := range report.Checks {
+		if !result.Healthy {
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) == 0 {
+		return report, nil
+	}
+	sort.Strings(failed)
+	report.Healthy = false
+	return report, fmt.Errorf("%w: failing checks: %s", errUnhealthy, strings.Join(failed, ", "))
+}
+
+// checkBootstrapped reports whether the VM has finished state sync and
+// bootstrapping and entered normal operations.
+func (vm *VM) checkBootstrapped() *healthCheckResult {
+	if vm.bootstrapped.Get() {
+		return &healthCheckResult{Healthy: true, Message: healthCheckPassedMsg}
+	}
+	message := "bootstrapping"
+	if vm.consensusState.Get() == consensus.StateSyncing {
+		message = "state syncing"
+	}
+	return &healthCheckResult{Message: message}
+}
+
+// checkLastAccepted reports how long ago the last accepted block was produced.
+func (vm *VM) checkLastAccepted() *healthCheckResult {
+	lastAccepted := vm.blockChain.LastConsensusAcceptedBlock()
+	blockTime := time.Unix(int64(lastAccepted.Time()), 0)
+	delay := vm.clock.Time().Sub(blockTime)
+	details := map[string]interface{}{
+		"height":    lastAccepted.NumberU64(),
+		"hash":      lastAccepted.Hash(),
+		"timestamp": blockTime.UTC(),
+		"delay":     delay.String(),
+	}
+
+	maxDelay := vm.config.HealthMaxBlockAcceptanceDelay.Duration
+	if maxDelay > 0 && delay > maxDelay {
+		return &healthCheckResult{
+			Message: fmt.Sprintf("last accepted block is %s old, exceeding %s", delay, maxDelay),
+			Details: details,
+		}
+	}
+	return &healthCheckResult{Healthy: true, Message: healthCheckPassedMsg, Details: details}
+}
+
+// checkAcceptorQueue reports the number of accepted blocks waiting to be
+// processed by the acceptor.
+func (vm *VM) checkAcceptorQueue() *healthCheckResult {
+	size := vm.blockChain.AcceptorQueueSize()
+	details := map[string]interface{}{
+		"size":  size,
+		"limit": vm.config.AcceptorQueueLimit,
+	}
+
+	maxSize := vm.config.MaxAcceptorQueueSize()
+	if maxSize > 0 && size > maxSize {
+		return &healthCheckResult{
+			Message: fmt.Sprintf("acceptor queue has %d blocks, exceeding %d", size, maxSize),
+			Details: details,
+		}
+	}
+	return &healthCheckResult{Healthy: true, Message: healthCheckPassedMsg, Details: details}
+}
+
+// checkTxPool reports the number of pending and queued transactions.
+func (vm *VM) checkTxPool() *healthCheckResult {
+	pending, queued := vm.txPool.Stats()
+	details := map[string]interface{}{
+		"pending": pending,
+		"queued":  queued,
+	}
+
+	maxSize := vm.config.HealthMaxTxPoolSize
+	if size := pending + queued; maxSize > 0 && size > maxSize {
+		return &healthCheckResult{
+			Message: fmt.Sprintf("tx pool has %d transactions, exceeding %d", size, maxSize),
+			Details: details,
+		}
+	}
+	return &healthCheckResult{Healthy: true, Message: healthCheckPassedMsg, Details: details}
+}
+
+// checkConnectedStake reports the fraction of the current validator stake this
+// node is connected to.
+func (vm *VM) checkConnectedStake() *healthCheckResult {
+	vm.vmLock.RLock()
+	defer vm.vmLock.RUnlock()
+
+	var totalWeight, connectedWeight uint64
+	for _, vID := range vm.validatorsManager.GetValidationIDs().List() {
+		validator, err := vm.validatorsManager.GetValidator(vID)
+		if err != nil {
+			return &healthCheckResult{Message: fmt.Sprintf("failed to get validator %s: %s", vID, err)}
+		}
+		if !validator.IsActive {
+			continue
+		}
+		totalWeight += validator.Weight
+		if vm.validatorsManager.IsConnected(validator.NodeID) {
+			connectedWeight += validator.Weight
+		}
+	}
+	// If no validators are tracked, there is no stake to be connected to.
+	connectedPercent := 1.0
+	if totalWeight > 0 {
+		connectedPercent = float64(connectedWeight) / float64(totalWeight)
+	}
+	details := map[string]interface{}{
+		"connectedWeight":  connectedWeight,
+		"totalWeight":      totalWeight,
+		"connectedPercent": connectedPercent,
+	}
+
+	minPercent := vm.config.HealthMinConnectedStakePercent
+	if connectedPercent < minPercent {
+		return &healthCheckResult{
+			Message: fmt.Sprintf("connected to %.2f%% of stake, below %.2f%%", connectedPercent*100, minPercent*100),
+			Details: details,
+		}
+	}
+	return &healthCheckResult{Healthy: true, Message: healthCheckPassedMsg, Details: details}
 }
@@ -0,0 +1,53 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheckBootstrapping(t *testing.T) {
+	_, vm, _, _ := GenesisVM(t, false, "", "", "")
+	defer func() {
+		require.NoError(t, vm.Shutdown(context.Background()))
+	}()
+
+	details, err := vm.HealthCheck(context.Background())
+	require.ErrorIs(t, err, errUnhealthy)
+	report := details.(*healthReport)
+	require.False(t, report.Healthy)
+	require.False(t, report.Checks[bootstrappedCheck].Healthy)
+	require.True(t, report.Checks[acceptorQueueCheck].Healthy)
+	require.True(t, report.Checks[txPoolCheck].Healthy)
+}
+
+func TestHealthCheckThresholds(t *testing.T) {
+	_, vm, _, _ := GenesisVM(t, true, "", `{"health-max-block-acceptance-delay": "1m"}`, "")
+	defer func() {
+		require.NoError(t, vm.Shutdown(context.Background()))
+	}()
+
+	// The genesis block is the last accepted block, so move the clock close
+	// to its timestamp to pass the block acceptance delay check.
+	genesisTime := time.Unix(int64(vm.blockChain.LastConsensusAcceptedBlock().Time()), 0)
+	vm.clock.Set(genesisTime.Add(time.Second))
+	details, err := vm.HealthCheck(context.Background())
+	require.NoError(t, err)
+	report := details.(*healthReport)
+	require.True(t, report.Healthy)
+	for name, result := range report.Checks {
+		require.True(t, result.Healthy, name)
+	}
+
+	vm.clock.Set(genesisTime.Add(2 * time.Minute))
+	details, err = vm.HealthCheck(context.Background())
+	require.ErrorIs(t, err, errUnhealthy)
+	report = details.(*healthReport)
+	require.False(t, report.Checks[lastAcceptedCheck].Healthy)
+	require.Contains(t, err.Error(), lastAcceptedCheck)
+}
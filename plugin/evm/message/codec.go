@@ -16,7 +16,8 @@ const (
 )
 
 var (
-	Codec codec.Manager
+	Codec           codec.Manager
+	CrossChainCodec codec.Manager
 )
 
 func init() {
@@ -50,4 +51,22 @@ func init() {
 	if errs.Errored() {
 		panic(errs.Err)
 	}
+
+	CrossChainCodec = codec.NewManager(maxMessageSize)
+	ccc := linearcodec.NewDefault()
+
+	errs = wrappers.Errs{}
+	errs.Add(
+		// CrossChainRequest Types
+		ccc.RegisterType(EthCallRequest{}),
+		ccc.RegisterType(EthCallResponse{}),
+		ccc.RegisterType(BlockHashRequest{}),
+		ccc.RegisterType(BlockHashResponse{}),
+
+		CrossChainCodec.RegisterCodec(Version, ccc),
+	)
+
+	if errs.Errored() {
+		panic(errs.Err)
+	}
 }
@@ -0,0 +1,100 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/luxfi/evm/internal/ethapi"
+	"github.com/luxfi/evm/rpc"
+	"github.com/luxfi/geth/log"
+	"github.com/luxfi/node/codec"
+	"github.com/luxfi/node/ids"
+)
+
+var _ CrossChainRequestHandler = &crossChainHandler{}
+
+// crossChainHandler implements the CrossChainRequestHandler interface by
+// serving read-only data from the last accepted state of the chain.
+type crossChainHandler struct {
+	backend         ethapi.Backend
+	crossChainCodec codec.Manager
+}
+
+// NewCrossChainHandler creates and returns a new instance of CrossChainRequestHandler
+func NewCrossChainHandler(b ethapi.Backend, codec codec.Manager) CrossChainRequestHandler {
+	return &crossChainHandler{
+		backend:         b,
+		crossChainCodec: codec,
+	}
+}
+
+// HandleEthCallRequest returns an encoded EthCallResponse to the given [ethCallRequest]
+// This function executes EVM Call against the state associated with [rpc.AcceptedBlockNumber] with the given
+// transaction call object [ethCallRequest].
+// This function does not return an error as errors are treated as FATAL to the node.
+func (c *crossChainHandler) HandleEthCallRequest(ctx context.Context, requestingChainID ids.ID, requestID uint32, ethCallRequest EthCallRequest) ([]byte, error) {
+	lastAcceptedBlockNumber := rpc.BlockNumber(c.backend.LastAcceptedBlock().NumberU64())
+	lastAcceptedBlockNumberOrHash := rpc.BlockNumberOrHash{BlockNumber: &lastAcceptedBlockNumber}
+
+	transactionArgs := ethapi.TransactionArgs{}
+	if err := json.Unmarshal(ethCallRequest.RequestArgs, &transactionArgs); err != nil {
+		log.Debug("error occurred with JSON unmarshalling ethCallRequest.RequestArgs", "err", err)
+		return nil, nil
+	}
+
+	result, err := ethapi.DoCall(ctx, c.backend, transactionArgs, lastAcceptedBlockNumberOrHash, nil, nil, c.backend.RPCEVMTimeout(), c.backend.RPCGasCap())
+	if err != nil {
+		log.Debug("error occurred with EthCall", "err", err, "transactionArgs", ethCallRequest.RequestArgs, "blockNumberOrHash", lastAcceptedBlockNumberOrHash)
+		return nil, nil
+	}
+
+	executionResult, err := json.Marshal(&result)
+	if err != nil {
+		log.Error("error occurred with JSON marshalling result", "err", err)
+		return nil, nil
+	}
+
+	response := EthCallResponse{
+		ExecutionResult: executionResult,
+	}
+
+	responseBytes, err := c.crossChainCodec.Marshal(Version, response)
+	if err != nil {
+		log.Error("error occurred with marshalling EthCallResponse", "err", err, "EthCallResponse", response)
+		return nil, nil
+	}
+
+	return responseBytes, nil
+}
+
+// HandleBlockHashRequest returns an encoded BlockHashResponse containing the hash of
+// the accepted block at [blockHashRequest.Height]. Heights above the last accepted
+// block are not served, since the block at that height may still change.
+// This function does not return an error as errors are treated as FATAL to the node.
+func (c *crossChainHandler) HandleBlockHashRequest(ctx context.Context, requestingChainID ids.ID, requestID uint32, blockHashRequest BlockHashRequest) ([]byte, error) {
+	if blockHashRequest.Height > c.backend.LastAcceptedBlock().NumberU64() {
+		log.Debug("block hash requested above last accepted height", "requestingChainID", requestingChainID, "requestID", requestID, "height", blockHashRequest.Height)
+		return nil, nil
+	}
+
+	header, err := c.backend.HeaderByNumber(ctx, rpc.BlockNumber(blockHashRequest.Height))
+	if err != nil || header == nil {
+		log.Debug("error occurred fetching header for BlockHashRequest", "err", err, "height", blockHashRequest.Height)
+		return nil, nil
+	}
+
+	response := BlockHashResponse{
+		Hash: header.Hash(),
+	}
+
+	responseBytes, err := c.crossChainCodec.Marshal(Version, response)
+	if err != nil {
+		log.Error("error occurred with marshalling BlockHashResponse", "err", err, "BlockHashResponse", response)
+		return nil, nil
+	}
+
+	return responseBytes, nil
+}
@@ -0,0 +1,72 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/node/codec"
+	"github.com/luxfi/node/ids"
+)
+
+var (
+	_ CrossChainRequest = EthCallRequest{}
+	_ CrossChainRequest = BlockHashRequest{}
+)
+
+// CrossChainRequest represents the interface a cross chain request should implement
+type CrossChainRequest interface {
+	// CrossChainRequest should implement String() for logging.
+	fmt.Stringer
+
+	// Handle allows [CrossChainRequest] to call respective methods on handler to handle
+	// this particular request type
+	Handle(ctx context.Context, requestingChainID ids.ID, requestID uint32, handler CrossChainRequestHandler) ([]byte, error)
+}
+
+// CrossChainRequestToBytes marshals the given cross chain request object into bytes
+func CrossChainRequestToBytes(codec codec.Manager, request CrossChainRequest) ([]byte, error) {
+	return codec.Marshal(Version, &request)
+}
+
+// EthCallRequest has the JSON Data necessary to execute a new EVM call on the blockchain
+type EthCallRequest struct {
+	RequestArgs []byte `serialize:"true"`
+}
+
+// EthCallResponse represents the JSON return value of the executed EVM call
+type EthCallResponse struct {
+	ExecutionResult []byte `serialize:"true"`
+}
+
+// String converts EthCallRequest to a string
+func (e EthCallRequest) String() string {
+	return fmt.Sprintf("%#v", e)
+}
+
+// Handle returns the encoded EthCallResponse by executing EVM call with the given EthCallRequest
+func (e EthCallRequest) Handle(ctx context.Context, requestingChainID ids.ID, requestID uint32, handler CrossChainRequestHandler) ([]byte, error) {
+	return handler.HandleEthCallRequest(ctx, requestingChainID, requestID, e)
+}
+
+// BlockHashRequest asks for the hash of the accepted block at [Height]
+type BlockHashRequest struct {
+	Height uint64 `serialize:"true"`
+}
+
+// BlockHashResponse contains the hash of the requested accepted block
+type BlockHashResponse struct {
+	Hash common.Hash `serialize:"true"`
+}
+
+func (b BlockHashRequest) String() string {
+	return fmt.Sprintf("BlockHashRequest(Height=%d)", b.Height)
+}
+
+// Handle returns the encoded BlockHashResponse for the requested height
+func (b BlockHashRequest) Handle(ctx context.Context, requestingChainID ids.ID, requestID uint32, handler CrossChainRequestHandler) ([]byte, error) {
+	return handler.HandleBlockHashRequest(ctx, requestingChainID, requestID, b)
+}
@@ -0,0 +1,52 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalCrossChainRequests(t *testing.T) {
+	tests := map[string]struct {
+		request     CrossChainRequest
+		response    interface{}
+		newResponse func() interface{}
+	}{
+		"eth call": {
+			request:     EthCallRequest{RequestArgs: []byte(`{"to":"0x0100000000000000000000000000000000000000"}`)},
+			response:    EthCallResponse{ExecutionResult: []byte(`{"UsedGas":21000}`)},
+			newResponse: func() interface{} { return &EthCallResponse{} },
+		},
+		"block hash": {
+			request:     BlockHashRequest{Height: 1337},
+			response:    BlockHashResponse{Hash: common.HexToHash("0x01")},
+			newResponse: func() interface{} { return &BlockHashResponse{} },
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			requestBytes, err := CrossChainRequestToBytes(CrossChainCodec, test.request)
+			require.NoError(err)
+
+			var request CrossChainRequest
+			_, err = CrossChainCodec.Unmarshal(requestBytes, &request)
+			require.NoError(err)
+			require.Equal(test.request, request)
+
+			responseBytes, err := CrossChainCodec.Marshal(Version, test.response)
+			require.NoError(err)
+
+			response := test.newResponse()
+			_, err = CrossChainCodec.Unmarshal(responseBytes, response)
+			require.NoError(err)
+			require.Equal(test.response, reflect.ValueOf(response).Elem().Interface())
+		})
+	}
+}
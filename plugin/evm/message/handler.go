@@ -9,7 +9,8 @@ import (
 )
 
 var (
-	_ RequestHandler = NoopRequestHandler{}
+	_ RequestHandler           = NoopRequestHandler{}
+	_ CrossChainRequestHandler = NoopCrossChainRequestHandler{}
 )
 
 // RequestHandler interface handles incoming requests from peers
@@ -34,9 +35,14 @@ type ResponseHandler interface {
 	OnFailure() error
 }
 
-// CrossChainRequestHandler interface handles incoming cross chain requests
+// CrossChainRequestHandler interface handles incoming requests from chains
+// in the same network.
+// Must have methods in format of handleType(context.Context, ids.ID, uint32, request Type) error
+// so that the CrossChainRequest object of relevant Type can invoke its respective
+// handle method on this struct.
 type CrossChainRequestHandler interface {
-	HandleCrossChainRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, request []byte) ([]byte, error)
+	HandleEthCallRequest(ctx context.Context, requestingChainID ids.ID, requestID uint32, ethCallRequest EthCallRequest) ([]byte, error)
+	HandleBlockHashRequest(ctx context.Context, requestingChainID ids.ID, requestID uint32, blockHashRequest BlockHashRequest) ([]byte, error)
 }
 
 // GossipHandler interface handles incoming gossip messages
@@ -71,3 +77,13 @@ func (NoopRequestHandler) HandleMessageSignatureRequest(ctx context.Context, nod
 func (NoopRequestHandler) HandleBlockSignatureRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, signatureRequest BlockSignatureRequest) ([]byte, error) {
 	return nil, nil
 }
+
+type NoopCrossChainRequestHandler struct{}
+
+func (NoopCrossChainRequestHandler) HandleEthCallRequest(ctx context.Context, requestingChainID ids.ID, requestID uint32, ethCallRequest EthCallRequest) ([]byte, error) {
+	return nil, nil
+}
+
+func (NoopCrossChainRequestHandler) HandleBlockHashRequest(ctx context.Context, requestingChainID ids.ID, requestID uint32, blockHashRequest BlockHashRequest) ([]byte, error) {
+	return nil, nil
+}
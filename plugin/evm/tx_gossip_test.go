@@ -250,3 +250,63 @@ func TestEthTxPushGossipInbound(t *testing.T) {
 
 	require.True(vm.txPool.Has(signedTx.Hash()))
 }
+
+// Tests that the txs submitted over the API are kept out of the push gossip when
+// private tx submission is enabled
+func TestEthTxPushGossipPrivateSubmission(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	snowCtx := utils.TestSnowContext()
+	sender := &enginetest.SenderStub{
+		SentAppGossip: make(chan []byte, 1),
+	}
+
+	vm := &VM{
+		ethTxPullGossiper: gossip.NoOpGossiper{},
+	}
+
+	require.NoError(vm.Initialize(
+		ctx,
+		snowCtx,
+		memdb.New(),
+		[]byte(genesisJSONLatest),
+		nil,
+		[]byte(`{"private-tx-submission": true}`),
+		make(chan common.Message),
+		nil,
+		sender,
+	))
+	require.NoError(vm.SetState(ctx, consensus.NormalOp))
+
+	defer func() {
+		require.NoError(vm.Shutdown(ctx))
+	}()
+
+	signer := types.NewEIP155Signer(vm.chainConfig.ChainID)
+	privateTx, err := types.SignTx(types.NewTransaction(0, testEthAddrs[0], big.NewInt(10), 21000, big.NewInt(testMinGasPrice), nil), signer, testKeys[0])
+	require.NoError(err)
+	publicTx, err := types.SignTx(types.NewTransaction(0, testEthAddrs[1], big.NewInt(10), 21000, big.NewInt(testMinGasPrice), nil), signer, testKeys[1])
+	require.NoError(err)
+
+	// issue a tx over the API, which is added to the mempool privately
+	require.NoError(vm.eth.APIBackend.SendTx(ctx, privateTx))
+	require.True(vm.txPool.Has(privateTx.Hash()))
+	require.True(vm.txPool.IsPrivate(privateTx.Hash()))
+	require.Nil(vm.eth.APIBackend.GetPoolTransaction(privateTx.Hash()))
+
+	// only the tx enqueued afterwards should be gossiped
+	require.NoError(vm.txPool.Add([]*types.Transaction{publicTx}, true, true)[0])
+	vm.ethTxPushGossiper.Get().Add(&GossipEthTx{publicTx})
+
+	sent := <-sender.SentAppGossip
+	got := &sdk.PushGossip{}
+	require.Equal(byte(p2p.TxGossipHandlerID), sent[0])
+	require.NoError(proto.Unmarshal(sent[1:], got))
+
+	marshaller := GossipEthTxMarshaller{}
+	require.Len(got.Gossip, 1)
+	gossipedTx, err := marshaller.UnmarshalGossip(got.Gossip[0])
+	require.NoError(err)
+	require.Equal(ids.ID(publicTx.Hash()), gossipedTx.GossipID())
+	require.Equal(publicTx.Hash(), vm.eth.APIBackend.GetPoolTransaction(publicTx.Hash()).Hash())
+}
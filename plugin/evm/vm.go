@@ -34,6 +34,8 @@ import (
 	"github.com/luxfi/evm/node"
 	"github.com/luxfi/evm/params"
 	"github.com/luxfi/evm/peer"
+	"github.com/luxfi/evm/plugin/evm/customrawdb"
+	customheader "github.com/luxfi/evm/plugin/evm/header"
 	"github.com/luxfi/evm/plugin/evm/message"
 	"github.com/luxfi/evm/rpc"
 	statesyncclient "github.com/luxfi/evm/sync/client"
@@ -124,6 +126,7 @@ var (
 	acceptedPrefix     = []byte("snowman_accepted")
 	metadataPrefix     = []byte("metadata")
 	warpPrefix         = []byte("warp")
+	warpRelayerPrefix  = []byte("warp_relayer")
 	ethDBPrefix        = []byte("ethdb")
 	validatorsDBPrefix = []byte("validators")
 )
@@ -138,6 +141,7 @@ var (
 	errNilBlockGasCostSubnetEVM      = errors.New("nil blockGasCost is invalid after subnetEVM")
 	errInvalidHeaderPredicateResults = errors.New("invalid header predicate results")
 	errInitializingLogger            = errors.New("failed to initialize logger")
+	errCrossChainUnsupported         = errors.New("cross chain requests are enabled but the node cannot send cross chain messages")
 )
 
 // legacyApiNames maps pre geth v1.10.20 api names to their updated counterparts.
@@ -204,6 +208,9 @@ type VM struct {
 	// [warpDB] is used to store warp message signatures
 	// set to a prefixDB with the prefix [warpPrefix]
 	warpDB database.Database
+	// [warpRelayerDB] is used to store the state of the warp relayer
+	// set to a prefixDB with the prefix [warpRelayerPrefix]
+	warpRelayerDB database.Database
 
 	validatorsDB database.Database
 
@@ -232,6 +239,8 @@ type VM struct {
 	sdkMetrics    *prometheus.Registry
 
 	bootstrapped luxUtils.Atomic[bool]
+	// consensusState is the most recent state set by the consensus engine.
+	consensusState luxUtils.Atomic[consensus.State]
 
 	logger SubnetEVMLogger
 	// State sync server and client
@@ -323,9 +332,11 @@ func (vm *VM) Initialize(
 	}
 
 	if vm.config.InspectDatabase {
-		if err := vm.inspectDatabases(); err != nil {
+		stats, err := vm.inspectDatabases()
+		if err != nil {
 			return err
 		}
+		customrawdb.WriteDatabaseStats(os.Stdout, stats)
 	}
 
 	g := new(core.Genesis)
@@ -415,10 +426,19 @@ func (vm *VM) Initialize(
 	vm.ethConfig.TxPool.AccountQueue = vm.config.TxPoolAccountQueue
 	vm.ethConfig.TxPool.GlobalQueue = vm.config.TxPoolGlobalQueue
 	vm.ethConfig.TxPool.Lifetime = vm.config.TxPoolLifetime.Duration
+	if journal := vm.config.TxPoolJournal; journal != "" {
+		if !filepath.IsAbs(journal) {
+			journal = filepath.Join(vm.ctx.ChainDataDir, journal)
+		}
+		vm.ethConfig.TxPool.Journal = journal
+		vm.ethConfig.TxPool.Rejournal = vm.config.TxPoolRejournal.Duration
+	}
 
 	vm.ethConfig.AllowUnfinalizedQueries = vm.config.AllowUnfinalizedQueries
 	vm.ethConfig.AllowUnprotectedTxs = vm.config.AllowUnprotectedTxs
 	vm.ethConfig.AllowUnprotectedTxHashes = vm.config.AllowUnprotectedTxHashes
+	vm.ethConfig.PrivateTxSubmission = vm.config.PrivateTxSubmission
+	vm.ethConfig.PrivateTxExpiry = vm.config.PrivateTxExpiryBlocks
 	vm.ethConfig.Preimages = vm.config.Preimages
 	vm.ethConfig.Pruning = vm.config.Pruning
 	vm.ethConfig.TrieCleanCache = vm.config.TrieCleanCache
@@ -434,6 +454,8 @@ func (vm *VM) Initialize(
 	vm.ethConfig.SnapshotWait = vm.config.SnapshotWait
 	vm.ethConfig.SnapshotVerify = vm.config.SnapshotVerify
 	vm.ethConfig.HistoricalProofQueryWindow = vm.config.HistoricalProofQueryWindow
+	vm.ethConfig.StateScheme = vm.config.StateScheme
+	vm.ethConfig.StateHistory = vm.config.StateHistory
 	vm.ethConfig.OfflinePruning = vm.config.OfflinePruning
 	vm.ethConfig.OfflinePruningBloomFilterSize = vm.config.OfflinePruningBloomFilterSize
 	vm.ethConfig.OfflinePruningDataDirectory = vm.config.OfflinePruningDataDirectory
@@ -460,6 +482,9 @@ func (vm *VM) Initialize(
 		log.Info("Config has not specified any coinbase address. Defaulting to the blackhole address.")
 		vm.ethConfig.Miner.Etherbase = constants.BlackholeAddr
 	}
+	vm.ethConfig.Miner.MaxBlockTxs = vm.config.BlockBuildingMaxTxs
+	vm.ethConfig.Miner.PriorityAddresses = vm.config.PriorityRegossipAddresses
+	vm.ethConfig.Miner.PriorityGasReservation = vm.config.BlockBuildingPriorityGasReservation
 
 	vm.chainConfig = g.Config
 	vm.networkID = vm.ethConfig.NetworkId
@@ -477,6 +502,9 @@ func (vm *VM) Initialize(
 	)
 
 	// initialize peer network
+	if _, ok := appSender.(peer.CrossChainAppSender); vm.config.CrossChainRequestsEnabled && !ok {
+		return fmt.Errorf("%w: %T", errCrossChainUnsupported, appSender)
+	}
 	if vm.p2pSender == nil {
 		vm.p2pSender = appSender
 	}
@@ -487,7 +515,7 @@ func (vm *VM) Initialize(
 	}
 	vm.p2pValidators = p2p.NewValidators(p2pNetwork.Peers, vm.ctx.Log, vm.ctx.SubnetID, vm.ctx.ValidatorState, maxValidatorSetStaleness)
 	vm.networkCodec = message.Codec
-	vm.Network = peer.NewNetwork(p2pNetwork, appSender, vm.networkCodec, chainCtx.NodeID, vm.config.MaxOutboundActiveRequests)
+	vm.Network = peer.NewNetwork(p2pNetwork, appSender, vm.networkCodec, message.CrossChainCodec, chainCtx.NodeID, vm.config.MaxOutboundActiveRequests, vm.config.MaxOutboundActiveCrossChainRequests)
 	vm.client = peer.NewNetworkClient(vm.Network)
 
 	vm.validatorsManager, err = validators.NewManager(vm.ctx, vm.validatorsDB, &vm.clock)
@@ -598,7 +626,7 @@ func (vm *VM) initializeChain(lastAcceptedHash common.Hash, ethConfig ethconfig.
 	vm.blockChain = vm.eth.BlockChain()
 	vm.miner = vm.eth.Miner()
 	lastAccepted := vm.blockChain.LastAcceptedBlock()
-	feeConfig, _, err := vm.blockChain.GetFeeConfigAt(lastAccepted.Header())
+	feeConfig, _, err := customheader.FeeConfigAt(vm.blockChain, lastAccepted.Header(), vm.clock.Unix())
 	if err != nil {
 		return err
 	}
@@ -693,6 +721,7 @@ func (vm *VM) initChainState(lastAcceptedBlock *types.Block) error {
 func (vm *VM) SetState(_ context.Context, state consensus.State) error {
 	vm.vmLock.Lock()
 	defer vm.vmLock.Unlock()
+	vm.consensusState.Set(state)
 	switch state {
 	case consensus.StateSyncing:
 		vm.bootstrapped.Set(false)
@@ -792,6 +821,14 @@ func (vm *VM) onNormalOperationsStarted() error {
 		}
 		vm.ethTxPushGossiper.Set(ethTxPushGossiper)
 	}
+	if vm.config.TxPoolJournal != "" {
+		vm.gossipLocalTxs()
+	}
+	vm.shutdownWg.Add(1)
+	go func() {
+		vm.gossipExpiredPrivateTxs(ctx)
+		vm.shutdownWg.Done()
+	}()
 
 	// NOTE: gossip network must be initialized first otherwise ETH tx gossip will not work.
 	vm.builder = vm.NewBlockBuilder(vm.toEngine)
@@ -842,11 +879,56 @@ func (vm *VM) onNormalOperationsStarted() error {
 		vm.shutdownWg.Done()
 	}()
 
+	if vm.config.WarpRelayerEnabled {
+		if err := vm.startWarpRelayer(ctx); err != nil {
+			return fmt.Errorf("failed to start warp relayer: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// gossipLocalTxs adds the pending local transactions, which includes the ones
+// reloaded from the transaction journal, to the push gossip.
+func (vm *VM) gossipLocalTxs() {
+	var (
+		gossiper = &EthPushGossiper{vm: vm}
+		count    int
+	)
+	for _, addr := range vm.txPool.Locals() {
+		pending, _ := vm.txPool.ContentFrom(addr)
+		for _, tx := range pending {
+			gossiper.Add(tx)
+		}
+		count += len(pending)
+	}
+	log.Info("Gossiping local transactions", "count", count)
+}
+
+// gossipExpiredPrivateTxs adds the private transactions to the push gossip once
+// they expire, until [ctx] is cancelled.
+func (vm *VM) gossipExpiredPrivateTxs(ctx context.Context) {
+	txsCh := make(chan core.NewTxsEvent)
+	sub := vm.txPool.SubscribeExpiredPrivateTxsEvent(txsCh)
+	defer sub.Unsubscribe()
+
+	gossiper := &EthPushGossiper{vm: vm}
+	for {
+		select {
+		case ev := <-txsCh:
+			for _, tx := range ev.Txs {
+				gossiper.Add(tx)
+			}
+		case <-sub.Err():
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // setAppRequestHandlers sets the request handlers for the VM to serve state sync
-// requests.
+// and cross chain requests.
 func (vm *VM) setAppRequestHandlers() {
 	// Create standalone EVM TrieDB (read only) for serving leafs requests.
 	// We create a standalone TrieDB here, so that it has a standalone cache from the one
@@ -862,6 +944,10 @@ func (vm *VM) setAppRequestHandlers() {
 
 	networkHandler := newNetworkHandler(vm.blockChain, vm.chaindb, evmTrieDB, vm.warpBackend, vm.networkCodec)
 	vm.Network.SetRequestHandler(networkHandler)
+
+	if vm.config.CrossChainRequestsEnabled {
+		vm.Network.SetCrossChainRequestHandler(message.NewCrossChainHandler(vm.eth.APIBackend, message.CrossChainCodec))
+	}
 }
 
 // Shutdown implements the snowman.ChainVM interface
@@ -916,6 +1002,7 @@ func (vm *VM) buildBlockWithContext(ctx context.Context, proposerVMBlockCtx *blo
 	predicateCtx := &precompileconfig.PredicateContext{
 		SnowCtx:            vm.ctx,
 		ProposerVMBlockCtx: proposerVMBlockCtx,
+		WarpValidatorSets:  warpValidators.NewCanonicalSetCache(),
 	}
 
 	block, err := vm.miner.GenerateBlock(predicateCtx)
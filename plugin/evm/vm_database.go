@@ -13,8 +13,8 @@ import (
 
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/log"
-	"github.com/luxfi/evm/interfaces/core/rawdb"
 	"github.com/luxfi/evm/plugin/evm/config"
+	"github.com/luxfi/evm/plugin/evm/customrawdb"
 	"github.com/luxfi/evm/plugin/evm/database"
 	"github.com/luxfi/node/api/metrics"
 	luxdatabase "github.com/luxfi/node/database"
@@ -29,6 +29,9 @@ import (
 const (
 	dbMetricsPrefix = "db"
 	meterDBGatherer = "meterdb"
+
+	// vmDatabaseLabel labels the VM's own databases in the inspection report.
+	vmDatabaseLabel = "VM"
 )
 
 type DatabaseConfig struct {
@@ -76,7 +79,7 @@ func (vm *VM) initializeDBs(avaDB luxdatabase.Database) error {
 	}
 	// Use NewNested rather than New so that the structure of the database
 	// remains the same regardless of the provided baseDB type.
-	vm.chaindb = rawdb.NewDatabase(database.WrapDatabase(prefixdb.NewNested(ethDBPrefix, db)))
+	vm.chaindb = database.NewChainDatabase(prefixdb.NewNested(ethDBPrefix, db))
 	vm.versiondb = versiondb.New(db)
 	vm.acceptedBlockDB = prefixdb.New(acceptedPrefix, vm.versiondb)
 	vm.metadataDB = prefixdb.New(metadataPrefix, vm.versiondb)
@@ -87,32 +90,42 @@ func (vm *VM) initializeDBs(avaDB luxdatabase.Database) error {
 	// [warpDB] is used to store warp message signatures
 	// set to a prefixDB with the prefix [warpPrefix]
 	vm.warpDB = prefixdb.New(warpPrefix, db)
+	// [warpRelayerDB] is used to store the state of the warp relayer
+	// set to a prefixDB with the prefix [warpRelayerPrefix]
+	vm.warpRelayerDB = prefixdb.New(warpRelayerPrefix, db)
 	// [validatorsDB] is used to store the current validator set and uptimes
 	// set to a prefixDB with the prefix [validatorsDBPrefix]
 	vm.validatorsDB = prefixdb.New(validatorsDBPrefix, db)
 	return nil
 }
 
-func (vm *VM) inspectDatabases() error {
+// inspectDatabases traverses the chain database and the VM's own databases and
+// returns the size and number of items of all different categories of data.
+func (vm *VM) inspectDatabases() ([]*customrawdb.DatabaseStat, error) {
 	start := time.Now()
 	log.Info("Starting database inspection")
-	if err := rawdb.InspectDatabase(vm.chaindb, nil, nil); err != nil {
-		return err
-	}
-	if err := inspectDB(vm.acceptedBlockDB, "acceptedBlockDB"); err != nil {
-		return err
-	}
-	if err := inspectDB(vm.metadataDB, "metadataDB"); err != nil {
-		return err
-	}
-	if err := inspectDB(vm.warpDB, "warpDB"); err != nil {
-		return err
+	stats, err := customrawdb.InspectDatabaseStats(vm.chaindb, nil, nil)
+	if err != nil {
+		return nil, err
 	}
-	if err := inspectDB(vm.validatorsDB, "validatorsDB"); err != nil {
-		return err
+	for _, db := range []struct {
+		db    luxdatabase.Database
+		label string
+	}{
+		{vm.acceptedBlockDB, "Accepted block"},
+		{vm.metadataDB, "Metadata"},
+		{vm.warpDB, "Warp messages"},
+		{vm.warpRelayerDB, "Warp relayer"},
+		{vm.validatorsDB, "Validator uptimes"},
+	} {
+		stat, err := inspectDB(db.db, db.label)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
 	}
 	log.Info("Completed database inspection", "elapsed", time.Since(start))
-	return nil
+	return stats, nil
 }
 
 // useStandaloneDatabase returns true if the chain can and should use a standalone database
@@ -167,34 +180,26 @@ func getDatabaseConfig(config config.Config, chainDataDir string) (DatabaseConfi
 	}, nil
 }
 
-func inspectDB(db luxdatabase.Database, label string) error {
+func inspectDB(db luxdatabase.Database, label string) (*customrawdb.DatabaseStat, error) {
 	it := db.NewIterator()
 	defer it.Release()
 
 	var (
-		count  int64
+		stat   = customrawdb.NewDatabaseStat(vmDatabaseLabel, label)
 		start  = time.Now()
 		logged = time.Now()
-
-		// Totals
-		total common.StorageSize
 	)
-	// Inspect key-value database first.
 	for it.Next() {
-		var (
-			key  = it.Key()
-			size = common.StorageSize(len(key) + len(it.Value()))
-		)
-		total += size
-		count++
-		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
-			log.Info("Inspecting database", "label", label, "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
+		stat.Add(uint64(len(it.Key()) + len(it.Value())))
+		if stat.Items%1000 == 0 && time.Since(logged) > 8*time.Second {
+			log.Info("Inspecting database", "label", label, "count", stat.Items, "elapsed", common.PrettyDuration(time.Since(start)))
 			logged = time.Now()
 		}
 	}
-	// Display the database statistic.
-	log.Info("Database statistics", "label", label, "total", total.String(), "count", count)
-	return nil
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to inspect %s database: %w", label, err)
+	}
+	return stat, nil
 }
 
 func newStandaloneDatabase(dbConfig DatabaseConfig, gatherer metrics.MultiGatherer, logger logging.Logger) (luxdatabase.Database, error) {
@@ -245,10 +245,12 @@ func GenesisVM(t *testing.T,
 func TestVMConfig(t *testing.T) {
 	txFeeCap := float64(11)
 	enabledEthAPIs := []string{"debug"}
-	configJSON := fmt.Sprintf(`{"rpc-tx-fee-cap": %g,"eth-apis": %s}`, txFeeCap, fmt.Sprintf("[%q]", enabledEthAPIs[0]))
+	apiMaxDuration := 3 * time.Second
+	configJSON := fmt.Sprintf(`{"rpc-tx-fee-cap": %g,"eth-apis": %s,"api-max-duration": %q}`, txFeeCap, fmt.Sprintf("[%q]", enabledEthAPIs[0]), apiMaxDuration)
 	_, vm, _, _ := GenesisVM(t, false, "", configJSON, "")
 	require.Equal(t, vm.config.RPCTxFeeCap, txFeeCap, "Tx Fee Cap should be set")
 	require.Equal(t, vm.config.EthAPIs(), enabledEthAPIs, "EnabledEthAPIs should be set")
+	require.Equal(t, apiMaxDuration, vm.eth.APIBackend.RPCEVMTimeout(), "The API max duration should bound eth_call and eth_simulateV1")
 	require.NoError(t, vm.Shutdown(context.Background()))
 }
 
@@ -276,6 +278,25 @@ func TestVMNilConfig(t *testing.T) {
 	require.NoError(t, vm.Shutdown(context.Background()))
 }
 
+func TestVMCrossChainRequestsUnsupported(t *testing.T) {
+	vm := &VM{}
+	ctx, dbManager, genesisBytes, issuer, _ := setupGenesis(t, "")
+	// Only the methods of the AppSender interface are exposed.
+	appSender := struct{ commonEng.AppSender }{&enginetest.Sender{T: t}}
+	err := vm.Initialize(
+		context.Background(),
+		ctx,
+		dbManager,
+		genesisBytes,
+		nil,
+		[]byte(`{"cross-chain-requests-enabled": true}`),
+		issuer,
+		[]*commonEng.Fx{},
+		appSender,
+	)
+	require.ErrorIs(t, err, errCrossChainUnsupported)
+}
+
 func TestVMContinuousProfiler(t *testing.T) {
 	profilerDir := t.TempDir()
 	profilerFrequency := 500 * time.Millisecond
@@ -8,4 +8,7 @@ import "errors"
 var (
 	ErrInvalidCoinbase             = errors.New("invalid coinbase")
 	ErrSenderAddressNotAllowListed = errors.New("cannot issue transaction from non-allow listed address")
+	ErrSenderAddressFrozen         = errors.New("cannot issue transaction from frozen address")
+	ErrRecipientAddressFrozen      = errors.New("cannot issue transaction to frozen address")
+	ErrDeployerNotAllowListed      = errors.New("cannot deploy contract from non-allow listed address")
 )
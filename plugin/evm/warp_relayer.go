@@ -0,0 +1,65 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/luxfi/evm/ethclient"
+	"github.com/luxfi/evm/warp"
+	"github.com/luxfi/evm/warp/relayer"
+	"github.com/luxfi/geth/crypto"
+	"github.com/luxfi/node/utils/set"
+)
+
+// startWarpRelayer starts relaying the warp messages accepted on this chain to
+// the configured destinations until [ctx] is cancelled.
+func (vm *VM) startWarpRelayer(ctx context.Context) error {
+	key, err := crypto.LoadECDSA(vm.config.WarpRelayerPrivateKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load private key: %w", err)
+	}
+
+	config := relayer.Config{
+		QuorumNum:     vm.config.WarpRelayerQuorumNum,
+		RetryInterval: vm.config.WarpRelayerRetryInterval.Duration,
+	}
+	var clients []ethclient.Client
+	closeClients := func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}
+	for _, dest := range vm.config.WarpRelayerDestinations {
+		client, err := ethclient.DialContext(ctx, dest.Endpoint)
+		if err != nil {
+			closeClients()
+			return fmt.Errorf("failed to dial %s: %w", dest.BlockchainID, err)
+		}
+		clients = append(clients, client)
+		config.Routes = append(config.Routes, relayer.Route{
+			DestinationChainID: dest.BlockchainID,
+			Client:             client,
+			Receiver:           dest.Receiver,
+			GasLimit:           dest.GasLimit,
+			AllowedSenders:     set.Of(dest.AllowedSenders...),
+		})
+	}
+
+	aggregator := warp.NewSignatureAggregator(vm.ctx.SubnetID, vm.ctx.ChainID, vm.ctx.ValidatorState, vm.client, vm.requirePrimaryNetworkSigners)
+	r, err := relayer.New(config, vm.blockChain, aggregator, key, vm.warpRelayerDB)
+	if err != nil {
+		closeClients()
+		return err
+	}
+
+	vm.shutdownWg.Add(1)
+	go func() {
+		defer vm.shutdownWg.Done()
+		defer closeClients()
+		r.Run(ctx)
+	}()
+	return nil
+}
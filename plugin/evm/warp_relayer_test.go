@@ -0,0 +1,191 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/luxfi/evm/core"
+	"github.com/luxfi/evm/ethclient"
+	"github.com/luxfi/evm/params"
+	"github.com/luxfi/evm/params/extras"
+	customheader "github.com/luxfi/evm/plugin/evm/header"
+	warpcontract "github.com/luxfi/evm/precompile/contracts/warp"
+	"github.com/luxfi/evm/predicate"
+	"github.com/luxfi/evm/rpc"
+	"github.com/luxfi/evm/utils"
+	"github.com/luxfi/evm/warp"
+	"github.com/luxfi/evm/warp/aggregator"
+	"github.com/luxfi/evm/warp/relayer"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/node/consensus/engine/chain/block"
+	"github.com/luxfi/node/consensus/validators"
+	"github.com/luxfi/node/consensus/validators/validatorstest"
+	"github.com/luxfi/node/database/memdb"
+	"github.com/luxfi/node/ids"
+	"github.com/luxfi/node/upgrade"
+	"github.com/luxfi/node/utils/crypto/bls"
+	"github.com/luxfi/node/utils/set"
+	"github.com/luxfi/node/vms/components/chain"
+	luxWarp "github.com/luxfi/node/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+)
+
+// backendSignatureGetter fetches the signatures of a local warp backend.
+type backendSignatureGetter struct {
+	backend warp.Backend
+}
+
+func (g *backendSignatureGetter) GetSignature(ctx context.Context, _ ids.NodeID, unsignedMessage *luxWarp.UnsignedMessage) (*bls.Signature, error) {
+	signatureBytes, err := g.backend.GetMessageSignature(ctx, unsignedMessage)
+	if err != nil {
+		return nil, err
+	}
+	return bls.SignatureFromBytes(signatureBytes)
+}
+
+// TestWarpRelayer relays a message sent on one VM to another, where it's
+// delivered to the warp precompile and verified.
+func TestWarpRelayer(t *testing.T) {
+	require := require.New(t)
+	genesis := &core.Genesis{}
+	require.NoError(genesis.UnmarshalJSON([]byte(genesisJSONDurango)))
+	params.GetExtra(genesis.Config).GenesisPrecompiles = extras.Precompiles{
+		warpcontract.ConfigKey: warpcontract.NewDefaultConfig(utils.TimeToNewUint64(upgrade.InitiallyActiveTime)),
+	}
+	genesisJSON, err := genesis.MarshalJSON()
+	require.NoError(err)
+
+	issuerA, vmA, _, _ := GenesisVM(t, true, string(genesisJSON), "", "")
+	defer func() {
+		require.NoError(vmA.Shutdown(context.Background()))
+	}()
+	issuerB, vmB, _, _ := GenesisVM(t, true, string(genesisJSON), "", "")
+	defer func() {
+		require.NoError(vmB.Shutdown(context.Background()))
+	}()
+
+	// The source chain is validated by the node of vmA alone.
+	nodeID := ids.GenerateTestNodeID()
+	pChainHeight := uint64(10)
+	vmB.ctx.ValidatorState = &validatorstest.State{
+		GetSubnetIDF: func(context.Context, ids.ID) (ids.ID, error) {
+			return vmB.ctx.SubnetID, nil
+		},
+		GetValidatorSetF: func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				nodeID: {NodeID: nodeID, PublicKey: vmA.ctx.PublicKey, Weight: 100},
+			}, nil
+		},
+	}
+	agg := aggregator.New(
+		&backendSignatureGetter{backend: vmA.warpBackend},
+		[]*luxWarp.Validator{{
+			PublicKey:      vmA.ctx.PublicKey,
+			PublicKeyBytes: bls.PublicKeyToCompressedBytes(vmA.ctx.PublicKey),
+			Weight:         100,
+			NodeIDs:        []ids.NodeID{nodeID},
+		}},
+		100,
+	)
+
+	// Deliver to vmB through its RPC API.
+	server := rpc.NewServer(0)
+	require.NoError(attachEthService(server, vmB.eth.APIs(), vmB.config.EthAPIs()))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	defer client.Close()
+
+	// The payload is used as the calldata of the delivery, which reads the
+	// delivered message back from the precompile.
+	getVerifiedWarpMessageInput, err := warpcontract.PackGetVerifiedWarpMessage(0)
+	require.NoError(err)
+	r, err := relayer.New(
+		relayer.Config{
+			RetryInterval: 10 * time.Millisecond,
+			Routes: []relayer.Route{{
+				DestinationChainID: ids.GenerateTestID(),
+				Client:             client,
+				Receiver:           warpcontract.ContractAddress,
+				AllowedSenders:     set.Of(testEthAddrs[0]),
+			}},
+		},
+		vmA.blockChain,
+		agg,
+		testKeys[1],
+		memdb.New(),
+	)
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Send the message on vmA.
+	sendWarpMessageInput, err := warpcontract.PackSendWarpMessage(getVerifiedWarpMessageInput)
+	require.NoError(err)
+	tx, err := types.SignTx(
+		types.NewTransaction(0, warpcontract.ContractAddress, common.Big0, 100_000, big.NewInt(testMinGasPrice), sendWarpMessageInput),
+		types.LatestSignerForChainID(vmA.chainConfig.ChainID),
+		testKeys[0],
+	)
+	require.NoError(err)
+	require.NoError(vmA.txPool.AddRemotesSync([]*types.Transaction{tx})[0])
+
+	<-issuerA
+	blkA, err := vmA.BuildBlock(context.Background())
+	require.NoError(err)
+	require.NoError(blkA.Verify(context.Background()))
+	require.NoError(vmA.SetPreference(context.Background(), blkA.ID()))
+	require.NoError(blkA.Accept(context.Background()))
+	vmA.blockChain.DrainAcceptorQueue()
+
+	// The relayer submits the delivery to vmB.
+	select {
+	case <-issuerB:
+	case <-time.After(5 * time.Second):
+		require.FailNow("delivery was not submitted")
+	}
+	blockCtx := &block.Context{PChainHeight: pChainHeight}
+	blkB, err := vmB.BuildBlockWithContext(context.Background(), blockCtx)
+	require.NoError(err)
+
+	ethBlock := blkB.(*chain.BlockWrapper).Block.(*Block).ethBlock
+	require.Len(ethBlock.Transactions(), 1)
+	delivery := ethBlock.Transactions()[0]
+	require.Equal(warpcontract.ContractAddress, *delivery.To())
+	require.Equal(getVerifiedWarpMessageInput, delivery.Data())
+
+	// The message was verified with the signature of the vmA validator.
+	results, err := predicate.ParseResults(customheader.PredicateBytesFromExtra(ethBlock.Extra()))
+	require.NoError(err)
+	require.Zero(set.BitsFromBytes(results.GetResults(delivery.Hash(), warpcontract.ContractAddress)).Len())
+
+	blkBWithCtx, ok := blkB.(block.WithVerifyContext)
+	require.True(ok)
+	require.NoError(blkBWithCtx.VerifyWithContext(context.Background(), blockCtx))
+	require.NoError(vmB.SetPreference(context.Background(), blkB.ID()))
+	require.NoError(blkB.Accept(context.Background()))
+	vmB.blockChain.DrainAcceptorQueue()
+
+	receipts := vmB.blockChain.GetReceiptsByHash(ethBlock.Hash())
+	require.Len(receipts, 1)
+	require.Equal(types.ReceiptStatusSuccessful, receipts[0].Status)
+
+	// The accepted delivery is not submitted again.
+	time.Sleep(100 * time.Millisecond)
+	pending, queued := vmB.txPool.Stats()
+	require.Zero(pending)
+	require.Zero(queued)
+}
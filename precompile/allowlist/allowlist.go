@@ -45,9 +45,14 @@ func GetAllowListStatus(state contract.StateReader, precompileAddr common.Addres
 }
 
 // SetAllowListRole sets the permissions of [address] to [role] for the precompile
-// at [precompileAddr].
+// at [precompileAddr], updating the role holder index if it is built and
+// removing any expiry of the previous role.
 // assumes [role] has already been verified as valid.
 func SetAllowListRole(stateDB contract.StateDB, precompileAddr, address common.Address, role Role) {
+	clearRoleExpiry(stateDB, precompileAddr, address)
+	if IsRoleIndexBuilt(stateDB, precompileAddr) {
+		updateRoleIndex(stateDB, precompileAddr, address, GetAllowListStatus(stateDB, precompileAddr, address), role)
+	}
 	// Generate the state key for [address]
 	addressKey := common.BytesToHash(address.Bytes())
 	// Assign [role] to the address
@@ -101,14 +106,20 @@ func createAllowListRoleSetter(precompileAddr common.Address, role Role) contrac
 		}
 
 		stateDB := evm.GetStateDB()
+		timestamp := evm.GetBlockContext().Timestamp()
 
 		// Verify that the caller is an admin with permission to modify the allow list
-		callerStatus := GetAllowListStatus(stateDB, precompileAddr, callerAddr)
+		callerStatus := GetActiveAllowListStatus(stateDB, precompileAddr, callerAddr, timestamp)
 		// Verify that the address we are trying to modify has a status that allows it to be modified
-		modifyStatus := GetAllowListStatus(stateDB, precompileAddr, modifyAddress)
+		modifyStatus := GetActiveAllowListStatus(stateDB, precompileAddr, modifyAddress, timestamp)
 		if !callerStatus.CanModify(modifyStatus, role) {
 			return nil, remainingGas, fmt.Errorf("%w: modify address: %s, from role: %s, to role: %s", ErrCannotModifyAllowList, callerAddr, modifyStatus, role)
 		}
+		if IsRoleIndexBuilt(stateDB, precompileAddr) {
+			if remainingGas, err = contract.DeductGas(remainingGas, ModifyAllowListIndexGasCost); err != nil {
+				return nil, 0, err
+			}
+		}
 		if contract.IsDurangoActivated(evm) {
 			if remainingGas, err = contract.DeductGas(remainingGas, AllowListEventGasCost); err != nil {
 				return nil, 0, err
@@ -152,7 +163,7 @@ func PackReadAllowListOutput(roleNumber *big.Int) ([]byte, error) {
 
 // createReadAllowList returns an execution function that reads the allow list for the given [precompileAddr].
 // The execution function parses the input into a single address and returns the 32 byte hash that specifies the
-// designated role of that address
+// designated role of that address. An expired role is reported as NoRole.
 func createReadAllowList(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
 	return func(evm contract.AccessibleState, callerAddr common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
 		if remainingGas, err = contract.DeductGas(suppliedGas, ReadAllowListGasCost); err != nil {
@@ -166,7 +177,7 @@ func createReadAllowList(precompileAddr common.Address) contract.RunStatefulPrec
 			return nil, remainingGas, err
 		}
 
-		role := GetAllowListStatus(evm.GetStateDB(), precompileAddr, readAddress)
+		role := GetActiveAllowListStatus(evm.GetStateDB(), precompileAddr, readAddress, evm.GetBlockContext().Timestamp())
 		packedOutput, err := PackReadAllowListOutput(role.Big())
 		if err != nil {
 			return nil, remainingGas, err
@@ -193,6 +204,14 @@ func CreateAllowListFunctions(precompileAddr common.Address) []*contract.Statefu
 		var fn *contract.StatefulPrecompileFunction
 		if name == "readAllowList" {
 			fn = contract.NewStatefulPrecompileFunction(method.ID, createReadAllowList(precompileAddr))
+		} else if name == "readAllowListWithExpiry" {
+			fn = contract.NewStatefulPrecompileFunctionWithActivator(method.ID, createReadAllowListWithExpiry(precompileAddr), contract.IsGraniteActivated)
+		} else if name == "setRoleWithExpiry" {
+			fn = contract.NewStatefulPrecompileFunctionWithActivator(method.ID, createSetRoleWithExpiry(precompileAddr), contract.IsGraniteActivated)
+		} else if name == "getRoleHolderCount" {
+			fn = contract.NewStatefulPrecompileFunctionWithActivator(method.ID, createGetRoleHolderCount(precompileAddr), isRoleIndexBuilt(precompileAddr))
+		} else if name == "getRoleHolders" {
+			fn = contract.NewStatefulPrecompileFunctionWithActivator(method.ID, createGetRoleHolders(precompileAddr), isRoleIndexBuilt(precompileAddr))
 		} else if adminFnName, _ := AdminRole.GetSetterFunctionName(); name == adminFnName {
 			fn = contract.NewStatefulPrecompileFunction(method.ID, createAllowListRoleSetter(precompileAddr, AdminRole))
 		} else if enabledFnName, _ := EnabledRole.GetSetterFunctionName(); name == enabledFnName {
@@ -20,6 +20,17 @@ type AllowListConfig struct {
 	EnabledAddresses []common.Address `json:"enabledAddresses,omitempty"` // initial enabled addresses
 }
 
+// AddressLister is implemented by the configs of the precompiles with an allow
+// list, which list the addresses they assign a role.
+type AddressLister interface {
+	Addresses() []common.Address
+}
+
+// Addresses returns the addresses assigned a role by the config.
+func (c *AllowListConfig) Addresses() []common.Address {
+	return slices.Concat(c.AdminAddresses, c.ManagerAddresses, c.EnabledAddresses)
+}
+
 // Configure initializes the address space of [precompileAddr] by initializing the role of each of
 // the addresses in [AllowListAdmins].
 func (c *AllowListConfig) Configure(chainConfig precompileconfig.ChainConfig, precompileAddr common.Address, state contract.StateDB, blockContext contract.ConfigurationBlockContext) error {
@@ -0,0 +1,236 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package allowlist
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/evm/precompile/contract"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/luxfi/geth/crypto"
+)
+
+// Roles granted with setRoleWithExpiry last until the block timestamp reaches
+// their expiry, after which they are treated as NoRole. The expiry of each
+// address is kept in a separate slot so that the role slot keeps its
+// original layout. An expiry of 0 means the role never expires.
+
+const (
+	SetRoleWithExpiryGasCost       = ModifyAllowListGasCost + contract.WriteGasCostPerSlot
+	ReadAllowListWithExpiryGasCost = ReadAllowListGasCost + contract.ReadGasCostPerSlot
+
+	// RoleSetWithExpiryEventGasCost is the gas cost of the RoleSetWithExpiry event.
+	// It is the base gas cost + the gas cost of the topics (signature, role, account, caller)
+	// and the gas cost of the non-indexed data (oldRole, expiry).
+	RoleSetWithExpiryEventGasCost = contract.LogGas + contract.LogTopicGas*4 + contract.LogDataGas*2*common.HashLength
+)
+
+var (
+	ErrInvalidRoleExpiry = errors.New("role expiry must be after the block timestamp")
+	ErrNoRoleWithExpiry  = errors.New("cannot grant NoRole with an expiry")
+
+	roleExpiryKeyPrefix = []byte("allowListRoleExpiry")
+)
+
+// SetRoleWithExpiryInput is the input of setRoleWithExpiry.
+type SetRoleWithExpiryInput struct {
+	Addr   common.Address
+	Role   *big.Int
+	Expiry *big.Int
+}
+
+// ReadAllowListWithExpiryOutput is the output of readAllowListWithExpiry.
+type ReadAllowListWithExpiryOutput struct {
+	Role   *big.Int
+	Expiry *big.Int
+}
+
+func roleExpiryKey(address common.Address) common.Hash {
+	return crypto.Keccak256Hash(roleExpiryKeyPrefix, address.Bytes())
+}
+
+// GetAllowListRoleExpiry returns the timestamp at which the role of [address]
+// for the precompile at [precompileAddr] expires, or 0 if it does not expire.
+func GetAllowListRoleExpiry(state contract.StateReader, precompileAddr common.Address, address common.Address) uint64 {
+	return new(big.Int).SetBytes(state.GetState(precompileAddr, roleExpiryKey(address)).Bytes()).Uint64()
+}
+
+// GetActiveAllowListStatus returns the allow list role of [address] for the
+// precompile at [precompileAddr] at [timestamp]. An expired role is NoRole.
+func GetActiveAllowListStatus(state contract.StateReader, precompileAddr common.Address, address common.Address, timestamp uint64) Role {
+	role := GetAllowListStatus(state, precompileAddr, address)
+	if expiry := GetAllowListRoleExpiry(state, precompileAddr, address); expiry != 0 && timestamp >= expiry {
+		return NoRole
+	}
+	return role
+}
+
+// SetAllowListRoleWithExpiry sets the permissions of [address] to [role] for the
+// precompile at [precompileAddr] until [expiry].
+// assumes [role] has already been verified as valid.
+func SetAllowListRoleWithExpiry(stateDB contract.StateDB, precompileAddr, address common.Address, role Role, expiry uint64) {
+	SetAllowListRole(stateDB, precompileAddr, address, role)
+	stateDB.SetState(precompileAddr, roleExpiryKey(address), common.BigToHash(new(big.Int).SetUint64(expiry)))
+}
+
+// clearRoleExpiry removes the expiry of [address] if it has one.
+func clearRoleExpiry(stateDB contract.StateDB, precompileAddr, address common.Address) {
+	key := roleExpiryKey(address)
+	if stateDB.GetState(precompileAddr, key) != (common.Hash{}) {
+		stateDB.SetState(precompileAddr, key, common.Hash{})
+	}
+}
+
+// PackSetRoleWithExpiry packs [address], [role] and [expiry] into the input data to setRoleWithExpiry.
+func PackSetRoleWithExpiry(address common.Address, role Role, expiry uint64) ([]byte, error) {
+	return AllowListABI.Pack("setRoleWithExpiry", address, role.Big(), new(big.Int).SetUint64(expiry))
+}
+
+// UnpackSetRoleWithExpiryInput attempts to unpack [input] into the arguments of setRoleWithExpiry.
+func UnpackSetRoleWithExpiryInput(input []byte) (SetRoleWithExpiryInput, error) {
+	inputStruct := SetRoleWithExpiryInput{}
+	err := AllowListABI.UnpackInputIntoInterface(&inputStruct, "setRoleWithExpiry", input, false)
+	return inputStruct, err
+}
+
+// PackReadAllowListWithExpiry packs [address] into the input data to readAllowListWithExpiry.
+func PackReadAllowListWithExpiry(address common.Address) ([]byte, error) {
+	return AllowListABI.Pack("readAllowListWithExpiry", address)
+}
+
+// UnpackReadAllowListWithExpiryInput attempts to unpack [input] into the address argument of readAllowListWithExpiry.
+func UnpackReadAllowListWithExpiryInput(input []byte) (common.Address, error) {
+	var readAddress common.Address
+	err := AllowListABI.UnpackInputIntoInterface(&readAddress, "readAllowListWithExpiry", input, false)
+	return readAddress, err
+}
+
+// PackReadAllowListWithExpiryOutput packs [role] and [expiry] into the output of readAllowListWithExpiry.
+func PackReadAllowListWithExpiryOutput(role Role, expiry uint64) ([]byte, error) {
+	return AllowListABI.PackOutput("readAllowListWithExpiry", role.Big(), new(big.Int).SetUint64(expiry))
+}
+
+// UnpackReadAllowListWithExpiryOutput attempts to unpack [output] into the role and expiry it contains.
+func UnpackReadAllowListWithExpiryOutput(output []byte) (ReadAllowListWithExpiryOutput, error) {
+	outputStruct := ReadAllowListWithExpiryOutput{}
+	err := AllowListABI.UnpackIntoInterface(&outputStruct, "readAllowListWithExpiry", output)
+	return outputStruct, err
+}
+
+// PackRoleSetWithExpiryEvent packs the event into the appropriate arguments for RoleSetWithExpiry.
+// It returns topic hashes and the encoded non-indexed data.
+func PackRoleSetWithExpiryEvent(role Role, account common.Address, caller common.Address, oldRole Role, expiry uint64) ([]common.Hash, []byte, error) {
+	return AllowListABI.PackEvent("RoleSetWithExpiry", role.Big(), account, caller, oldRole.Big(), new(big.Int).SetUint64(expiry))
+}
+
+// UnpackRoleSetWithExpiryEventData attempts to unpack non-indexed [dataBytes].
+func UnpackRoleSetWithExpiryEventData(dataBytes []byte) (Role, uint64, error) {
+	eventData := struct {
+		OldRole *big.Int
+		Expiry  *big.Int
+	}{}
+	err := AllowListABI.UnpackIntoInterface(&eventData, "RoleSetWithExpiry", dataBytes)
+	if err != nil {
+		return Role{}, 0, err
+	}
+	oldRole, err := FromBig(eventData.OldRole)
+	if err != nil {
+		return Role{}, 0, err
+	}
+	return oldRole, eventData.Expiry.Uint64(), nil
+}
+
+// createSetRoleWithExpiry returns an execution function that grants a role
+// which expires at the given timestamp for the given [precompileAddr].
+func createSetRoleWithExpiry(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, SetRoleWithExpiryGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		if readOnly {
+			return nil, remainingGas, vm.ErrWriteProtection
+		}
+
+		inputStruct, err := UnpackSetRoleWithExpiryInput(input)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		role, err := FromBig(inputStruct.Role)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if role.IsNoRole() {
+			return nil, remainingGas, ErrNoRoleWithExpiry
+		}
+		timestamp := evm.GetBlockContext().Timestamp()
+		if !inputStruct.Expiry.IsUint64() || inputStruct.Expiry.Uint64() <= timestamp {
+			return nil, remainingGas, fmt.Errorf("%w: expiry %s, block timestamp %d", ErrInvalidRoleExpiry, inputStruct.Expiry, timestamp)
+		}
+		expiry := inputStruct.Expiry.Uint64()
+
+		stateDB := evm.GetStateDB()
+
+		// Verify that the caller is allowed to grant [role] to an address with its current role
+		callerStatus := GetActiveAllowListStatus(stateDB, precompileAddr, callerAddr, timestamp)
+		modifyStatus := GetActiveAllowListStatus(stateDB, precompileAddr, inputStruct.Addr, timestamp)
+		if !callerStatus.CanModify(modifyStatus, role) {
+			return nil, remainingGas, fmt.Errorf("%w: modify address: %s, from role: %s, to role: %s", ErrCannotModifyAllowList, callerAddr, modifyStatus, role)
+		}
+		if IsRoleIndexBuilt(stateDB, precompileAddr) {
+			if remainingGas, err = contract.DeductGas(remainingGas, ModifyAllowListIndexGasCost); err != nil {
+				return nil, 0, err
+			}
+		}
+		if remainingGas, err = contract.DeductGas(remainingGas, RoleSetWithExpiryEventGasCost); err != nil {
+			return nil, 0, err
+		}
+		topics, data, err := PackRoleSetWithExpiryEvent(role, inputStruct.Addr, callerAddr, modifyStatus, expiry)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		stateDB.AddLog(&types.Log{
+			Address:     precompileAddr,
+			Topics:      topics,
+			Data:        data,
+			BlockNumber: evm.GetBlockContext().Number().Uint64(),
+		})
+
+		SetAllowListRoleWithExpiry(stateDB, precompileAddr, inputStruct.Addr, role, expiry)
+
+		return []byte{}, remainingGas, nil
+	}
+}
+
+// createReadAllowListWithExpiry returns an execution function that reads the
+// active role of an address and the timestamp at which it expires for the
+// given [precompileAddr]. An expired role is reported as NoRole with no expiry.
+func createReadAllowListWithExpiry(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, ReadAllowListWithExpiryGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		readAddress, err := UnpackReadAllowListWithExpiryInput(input)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		stateDB := evm.GetStateDB()
+		role := GetActiveAllowListStatus(stateDB, precompileAddr, readAddress, evm.GetBlockContext().Timestamp())
+		var expiry uint64
+		if !role.IsNoRole() {
+			expiry = GetAllowListRoleExpiry(stateDB, precompileAddr, readAddress)
+		}
+		packedOutput, err := PackReadAllowListWithExpiryOutput(role, expiry)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		return packedOutput, remainingGas, nil
+	}
+}
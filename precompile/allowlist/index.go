@@ -0,0 +1,326 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package allowlist
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/luxfi/evm/precompile/contract"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/crypto"
+)
+
+// The role holder index enumerates the addresses holding each role of the allow
+// list of a precompile. It is stored in the storage of the precompile under
+// hashed keys, which cannot collide with the address keys of the roles:
+//   - the number of holders of each role
+//   - the holder at each position of each role, positions being dense
+//   - the position (plus one) of each indexed holder in the list of its role
+//
+// The index is built by [BuildRoleIndex] when the state upgrade enabling it
+// activates, and maintained by [SetAllowListRole] from then on.
+// Holders whose role expired stay indexed until their role is set again, and
+// are left out when counting and listing the holders.
+
+const (
+	// ModifyAllowListIndexGasCost is the additional gas cost of modifying the
+	// role of an address once the role holder index is built. It covers
+	// removing the address from the list of its previous role, moving the last
+	// holder of that role into its position, and appending it to the list of
+	// its new role.
+	ModifyAllowListIndexGasCost = contract.ReadGasCostPerSlot*4 + contract.WriteGasCostPerSlot*8
+
+	// GetRoleHolderCountGasCost and GetRoleHoldersGasCost are the base gas
+	// costs of counting and listing role holders, in addition to
+	// [GetRoleHolderGasCost] per indexed holder counted or listed.
+	GetRoleHolderCountGasCost = contract.ReadGasCostPerSlot
+	GetRoleHoldersGasCost     = contract.ReadGasCostPerSlot
+	// GetRoleHolderGasCost covers reading a holder and the expiry of its role.
+	GetRoleHolderGasCost = contract.ReadGasCostPerSlot * 2
+)
+
+var (
+	roleIndexBuiltKey        = crypto.Keccak256Hash([]byte("allowListRoleIndexBuilt"))
+	roleHolderCountPrefix    = []byte("allowListRoleHolderCount")
+	roleHolderPrefix         = []byte("allowListRoleHolder")
+	roleHolderPositionPrefix = []byte("allowListRoleHolderPosition")
+
+	ErrNoRoleNotIndexed = errors.New("holders of NoRole are not indexed")
+)
+
+func roleHolderCountKey(role Role) common.Hash {
+	return crypto.Keccak256Hash(roleHolderCountPrefix, role.Bytes())
+}
+
+func roleHolderKey(role Role, position uint64) common.Hash {
+	return crypto.Keccak256Hash(roleHolderPrefix, role.Bytes(), common.BigToHash(new(big.Int).SetUint64(position)).Bytes())
+}
+
+func roleHolderPositionKey(address common.Address) common.Hash {
+	return crypto.Keccak256Hash(roleHolderPositionPrefix, address.Bytes())
+}
+
+// IsRoleIndexBuilt returns true if the role holder index of the precompile at
+// [precompileAddr] is built.
+func IsRoleIndexBuilt(state contract.StateReader, precompileAddr common.Address) bool {
+	return state.GetState(precompileAddr, roleIndexBuiltKey) != common.Hash{}
+}
+
+// indexedRoleHolderCount returns the number of addresses indexed as holding
+// [role] for the precompile at [precompileAddr], including the ones whose role
+// has expired.
+func indexedRoleHolderCount(state contract.StateReader, precompileAddr common.Address, role Role) uint64 {
+	return state.GetState(precompileAddr, roleHolderCountKey(role)).Big().Uint64()
+}
+
+// GetRoleHolderCount returns the number of addresses holding [role] for the
+// precompile at [precompileAddr] at [timestamp]. Holders whose role has expired
+// are not counted. Assumes the role holder index is built.
+func GetRoleHolderCount(state contract.StateReader, precompileAddr common.Address, role Role, timestamp uint64) uint64 {
+	return uint64(len(GetRoleHolders(state, precompileAddr, role, 0, math.MaxUint64, timestamp)))
+}
+
+// GetRoleHolders returns the addresses holding [role] for the precompile at
+// [precompileAddr] at [timestamp], among the [limit] indexed holders starting
+// from [offset]. Holders whose role has expired are left out, so a page holds
+// fewer than [limit] addresses if some of its roles expired.
+// Assumes the role holder index is built.
+func GetRoleHolders(state contract.StateReader, precompileAddr common.Address, role Role, offset uint64, limit uint64, timestamp uint64) []common.Address {
+	count := indexedRoleHolderCount(state, precompileAddr, role)
+	if offset >= count {
+		return []common.Address{}
+	}
+	limit = min(limit, count-offset)
+	holders := make([]common.Address, 0, limit)
+	for position := offset; position < offset+limit; position++ {
+		holder := common.BytesToAddress(state.GetState(precompileAddr, roleHolderKey(role, position)).Bytes())
+		if expiry := GetAllowListRoleExpiry(state, precompileAddr, holder); expiry != 0 && timestamp >= expiry {
+			continue
+		}
+		holders = append(holders, holder)
+	}
+	return holders
+}
+
+// BuildRoleIndex builds the role holder index of the precompile at
+// [precompileAddr] from [candidates], indexing each candidate holding a role.
+// Addresses already indexed are skipped, so that building an index again only
+// indexes the new candidates. It returns the candidates holding no role.
+//
+// WARNING: the roles are stored by address only, so the index cannot be checked
+// against them. The candidates must include every address holding a role, for
+// instance every address of a RoleSet event of the precompile and of its allow
+// list configs. A holder left out is never counted nor listed until its role is
+// set again.
+func BuildRoleIndex(stateDB contract.StateDB, precompileAddr common.Address, candidates []common.Address) []common.Address {
+	var noRole []common.Address
+	for _, candidate := range candidates {
+		role := GetAllowListStatus(stateDB, precompileAddr, candidate)
+		if role == NoRole {
+			noRole = append(noRole, candidate)
+			continue
+		}
+		if stateDB.GetState(precompileAddr, roleHolderPositionKey(candidate)) != (common.Hash{}) {
+			continue
+		}
+		addRoleHolder(stateDB, precompileAddr, candidate, role)
+	}
+	stateDB.SetState(precompileAddr, roleIndexBuiltKey, common.BigToHash(common.Big1))
+	return noRole
+}
+
+// updateRoleIndex moves [address] from the list of holders of [from] to the
+// list of holders of [to].
+func updateRoleIndex(stateDB contract.StateDB, precompileAddr common.Address, address common.Address, from Role, to Role) {
+	if from == to {
+		return
+	}
+	if from != NoRole {
+		removeRoleHolder(stateDB, precompileAddr, address, from)
+	}
+	if to != NoRole {
+		addRoleHolder(stateDB, precompileAddr, address, to)
+	}
+}
+
+func addRoleHolder(stateDB contract.StateDB, precompileAddr common.Address, address common.Address, role Role) {
+	count := indexedRoleHolderCount(stateDB, precompileAddr, role)
+	stateDB.SetState(precompileAddr, roleHolderKey(role, count), common.BytesToHash(address.Bytes()))
+	stateDB.SetState(precompileAddr, roleHolderPositionKey(address), common.BigToHash(new(big.Int).SetUint64(count+1)))
+	stateDB.SetState(precompileAddr, roleHolderCountKey(role), common.BigToHash(new(big.Int).SetUint64(count+1)))
+}
+
+func removeRoleHolder(stateDB contract.StateDB, precompileAddr common.Address, address common.Address, role Role) {
+	positionKey := roleHolderPositionKey(address)
+	position := stateDB.GetState(precompileAddr, positionKey).Big().Uint64()
+	if position == 0 {
+		// The address was not indexed, which only happens if it was missing
+		// from the candidates the index was built from.
+		return
+	}
+	position--
+
+	// Move the last holder into the position of the removed one to keep the
+	// positions dense.
+	last := indexedRoleHolderCount(stateDB, precompileAddr, role) - 1
+	if position != last {
+		lastHolder := stateDB.GetState(precompileAddr, roleHolderKey(role, last))
+		stateDB.SetState(precompileAddr, roleHolderKey(role, position), lastHolder)
+		stateDB.SetState(precompileAddr, roleHolderPositionKey(common.BytesToAddress(lastHolder.Bytes())), common.BigToHash(new(big.Int).SetUint64(position+1)))
+	}
+	stateDB.SetState(precompileAddr, roleHolderKey(role, last), common.Hash{})
+	stateDB.SetState(precompileAddr, positionKey, common.Hash{})
+	stateDB.SetState(precompileAddr, roleHolderCountKey(role), common.BigToHash(new(big.Int).SetUint64(last)))
+}
+
+// PackGetRoleHolderCount packs [role] into the input data to the getRoleHolderCount function
+func PackGetRoleHolderCount(role Role) ([]byte, error) {
+	return AllowListABI.Pack("getRoleHolderCount", role.Big())
+}
+
+// UnpackGetRoleHolderCountInput attempts to unpack [input] into the role argument
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackGetRoleHolderCountInput(input []byte) (Role, error) {
+	res, err := AllowListABI.UnpackInput("getRoleHolderCount", input, false)
+	if err != nil {
+		return Role{}, err
+	}
+	return indexedRoleFromBig(res[0].(*big.Int))
+}
+
+// PackGetRoleHolderCountOutput packs [count] into the output of getRoleHolderCount
+func PackGetRoleHolderCountOutput(count uint64) ([]byte, error) {
+	return AllowListABI.PackOutput("getRoleHolderCount", new(big.Int).SetUint64(count))
+}
+
+// GetRoleHoldersInput is the input of getRoleHolders
+type GetRoleHoldersInput struct {
+	Role   *big.Int
+	Offset *big.Int
+	Limit  *big.Int
+}
+
+// PackGetRoleHolders packs the arguments into the input data to the getRoleHolders function
+func PackGetRoleHolders(role Role, offset uint64, limit uint64) ([]byte, error) {
+	return AllowListABI.Pack("getRoleHolders", role.Big(), new(big.Int).SetUint64(offset), new(big.Int).SetUint64(limit))
+}
+
+// UnpackGetRoleHoldersInput attempts to unpack [input] into the arguments of getRoleHolders
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackGetRoleHoldersInput(input []byte) (Role, uint64, uint64, error) {
+	inputStruct := GetRoleHoldersInput{}
+	if err := AllowListABI.UnpackInputIntoInterface(&inputStruct, "getRoleHolders", input, false); err != nil {
+		return Role{}, 0, 0, err
+	}
+	role, err := indexedRoleFromBig(inputStruct.Role)
+	if err != nil {
+		return Role{}, 0, 0, err
+	}
+	// Out of range offsets and limits are capped, as no more holders could be
+	// indexed anyway.
+	offset, limit := uint64(math.MaxUint64), uint64(math.MaxUint64)
+	if inputStruct.Offset.IsUint64() {
+		offset = inputStruct.Offset.Uint64()
+	}
+	if inputStruct.Limit.IsUint64() {
+		limit = inputStruct.Limit.Uint64()
+	}
+	return role, offset, limit, nil
+}
+
+// PackGetRoleHoldersOutput packs [holders] into the output of getRoleHolders
+func PackGetRoleHoldersOutput(holders []common.Address) ([]byte, error) {
+	return AllowListABI.PackOutput("getRoleHolders", holders)
+}
+
+// UnpackGetRoleHoldersOutput attempts to unpack [output] into the holders returned by getRoleHolders
+func UnpackGetRoleHoldersOutput(output []byte) ([]common.Address, error) {
+	res, err := AllowListABI.Unpack("getRoleHolders", output)
+	if err != nil {
+		return nil, err
+	}
+	return res[0].([]common.Address), nil
+}
+
+// indexedRoleFromBig returns the role [b] if its holders are indexed.
+func indexedRoleFromBig(b *big.Int) (Role, error) {
+	role, err := FromBig(b)
+	if err != nil {
+		return Role{}, err
+	}
+	if role == NoRole {
+		return Role{}, ErrNoRoleNotIndexed
+	}
+	return role, nil
+}
+
+// createGetRoleHolderCount returns an execution function that returns the number of
+// holders of a role for the given [precompileAddr].
+func createGetRoleHolderCount(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, GetRoleHolderCountGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		role, err := UnpackGetRoleHolderCountInput(input)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		// Every indexed holder is read to leave out the expired ones.
+		stateDB := evm.GetStateDB()
+		count := indexedRoleHolderCount(stateDB, precompileAddr, role)
+		if remainingGas, err = contract.DeductGas(remainingGas, count*GetRoleHolderGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		packedOutput, err := PackGetRoleHolderCountOutput(GetRoleHolderCount(stateDB, precompileAddr, role, evm.GetBlockContext().Timestamp()))
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		return packedOutput, remainingGas, nil
+	}
+}
+
+// createGetRoleHolders returns an execution function that lists a page of the
+// holders of a role for the given [precompileAddr].
+func createGetRoleHolders(precompileAddr common.Address) contract.RunStatefulPrecompileFunc {
+	return func(evm contract.AccessibleState, callerAddr common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, GetRoleHoldersGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		role, offset, limit, err := UnpackGetRoleHoldersInput(input)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		stateDB := evm.GetStateDB()
+		count := indexedRoleHolderCount(stateDB, precompileAddr, role)
+		var listed uint64
+		if offset < count {
+			listed = min(limit, count-offset)
+		}
+		if remainingGas, err = contract.DeductGas(remainingGas, listed*GetRoleHolderGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		packedOutput, err := PackGetRoleHoldersOutput(GetRoleHolders(stateDB, precompileAddr, role, offset, listed, evm.GetBlockContext().Timestamp()))
+		if err != nil {
+			return nil, remainingGas, fmt.Errorf("failed to pack role holders: %w", err)
+		}
+		return packedOutput, remainingGas, nil
+	}
+}
+
+// isRoleIndexBuilt returns an activation function enabling the role holder
+// index functions of [precompileAddr] once its index is built.
+func isRoleIndexBuilt(precompileAddr common.Address) contract.ActivationFunc {
+	return func(evm contract.AccessibleState) bool {
+		return IsRoleIndexBuilt(evm.GetStateDB(), precompileAddr)
+	}
+}
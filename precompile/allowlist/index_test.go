@@ -0,0 +1,64 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package allowlist_test
+
+import (
+	"testing"
+
+	"github.com/luxfi/evm/core/extstate"
+	"github.com/luxfi/evm/precompile/allowlist"
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleIndex(t *testing.T) {
+	require := require.New(t)
+	state := extstate.NewTestStateDB(t)
+
+	var (
+		addrA      = common.Address{'a'}
+		addrB      = common.Address{'b'}
+		addrC      = common.Address{'c'}
+		unindexed  = common.Address{'u'}
+		noRoleAddr = common.Address{'n'}
+	)
+	for _, addr := range []common.Address{addrA, addrB, addrC, unindexed} {
+		allowlist.SetAllowListRole(state, dummyAddr, addr, allowlist.EnabledRole)
+	}
+
+	// Roles are not indexed until the index is built, and only candidates
+	// holding a role are indexed. The candidates holding no role are returned.
+	require.False(allowlist.IsRoleIndexBuilt(state, dummyAddr))
+	noRole := allowlist.BuildRoleIndex(state, dummyAddr, []common.Address{addrA, addrB, addrC, noRoleAddr})
+	require.Equal([]common.Address{noRoleAddr}, noRole)
+	require.True(allowlist.IsRoleIndexBuilt(state, dummyAddr))
+	require.Equal(uint64(3), allowlist.GetRoleHolderCount(state, dummyAddr, allowlist.EnabledRole, 0))
+	require.Equal([]common.Address{addrA, addrB, addrC}, allowlist.GetRoleHolders(state, dummyAddr, allowlist.EnabledRole, 0, 10, 0))
+	require.Equal([]common.Address{addrB}, allowlist.GetRoleHolders(state, dummyAddr, allowlist.EnabledRole, 1, 1, 0))
+
+	// The last holder takes the position of a removed one.
+	allowlist.SetAllowListRole(state, dummyAddr, addrA, allowlist.AdminRole)
+	require.Equal([]common.Address{addrC, addrB}, allowlist.GetRoleHolders(state, dummyAddr, allowlist.EnabledRole, 0, 10, 0))
+	require.Equal([]common.Address{addrA}, allowlist.GetRoleHolders(state, dummyAddr, allowlist.AdminRole, 0, 10, 0))
+
+	allowlist.SetAllowListRole(state, dummyAddr, addrB, allowlist.NoRole)
+	require.Equal([]common.Address{addrC}, allowlist.GetRoleHolders(state, dummyAddr, allowlist.EnabledRole, 0, 10, 0))
+
+	// Setting the same role again does not index the holder twice.
+	allowlist.SetAllowListRole(state, dummyAddr, addrC, allowlist.EnabledRole)
+	require.Equal(uint64(1), allowlist.GetRoleHolderCount(state, dummyAddr, allowlist.EnabledRole, 0))
+
+	// Building the index again indexes the missing holders only.
+	require.Empty(allowlist.BuildRoleIndex(state, dummyAddr, []common.Address{addrA, addrC, unindexed}))
+	require.Equal([]common.Address{addrC, unindexed}, allowlist.GetRoleHolders(state, dummyAddr, allowlist.EnabledRole, 0, 10, 0))
+	require.Equal([]common.Address{addrA}, allowlist.GetRoleHolders(state, dummyAddr, allowlist.AdminRole, 0, 10, 0))
+
+	// Holders are left out once their role expires.
+	allowlist.SetAllowListRoleWithExpiry(state, dummyAddr, addrC, allowlist.EnabledRole, 100)
+	require.Equal([]common.Address{addrC, unindexed}, allowlist.GetRoleHolders(state, dummyAddr, allowlist.EnabledRole, 0, 10, 99))
+	require.Equal([]common.Address{unindexed}, allowlist.GetRoleHolders(state, dummyAddr, allowlist.EnabledRole, 0, 10, 100))
+	require.Empty(allowlist.GetRoleHolders(state, dummyAddr, allowlist.EnabledRole, 0, 1, 100))
+	require.Equal(uint64(2), allowlist.GetRoleHolderCount(state, dummyAddr, allowlist.EnabledRole, 99))
+	require.Equal(uint64(1), allowlist.GetRoleHolderCount(state, dummyAddr, allowlist.EnabledRole, 100))
+}
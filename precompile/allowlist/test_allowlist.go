@@ -20,6 +20,8 @@ var (
 	TestEnabledAddr = common.HexToAddress("0x0000000000000000000000000000000000000022")
 	TestNoRoleAddr  = common.HexToAddress("0x0000000000000000000000000000000000000033")
 	TestManagerAddr = common.HexToAddress("0x0000000000000000000000000000000000000044")
+
+	testExpiryTimestamp = uint64(1_000)
 )
 
 func AllowListTests(t testing.TB, module modules.Module) map[string]testutils.PrecompileTest {
@@ -536,6 +538,384 @@ func AllowListTests(t testing.TB, module modules.Module) map[string]testutils.Pr
 			ReadOnly:    true,
 			ExpectedErr: vm.ErrOutOfGas.Error(),
 		},
+		"admin set enabled updates role index": {
+			Caller:     TestAdminAddr,
+			BeforeHook: SetDefaultRolesAndBuildIndex(contractAddress),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackModifyAllowList(TestNoRoleAddr, EnabledRole)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: ModifyAllowListGasCost + ModifyAllowListIndexGasCost + AllowListEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, []common.Address{TestEnabledAddr, TestNoRoleAddr}, GetRoleHolders(state, contractAddress, EnabledRole, 0, 10, 0))
+			},
+		},
+		"admin set no role updates role index": {
+			Caller:     TestAdminAddr,
+			BeforeHook: SetDefaultRolesAndBuildIndex(contractAddress),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackModifyAllowList(TestAdminAddr, NoRole)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: ModifyAllowListGasCost + ModifyAllowListIndexGasCost + AllowListEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Zero(t, GetRoleHolderCount(state, contractAddress, AdminRole, 0))
+				require.Empty(t, GetRoleHolders(state, contractAddress, AdminRole, 0, 10, 0))
+			},
+		},
+		"admin set enabled with role index insufficient gas": {
+			Caller:     TestAdminAddr,
+			BeforeHook: SetDefaultRolesAndBuildIndex(contractAddress),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackModifyAllowList(TestNoRoleAddr, EnabledRole)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: ModifyAllowListGasCost + ModifyAllowListIndexGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vm.ErrOutOfGas.Error(),
+		},
+		"no role get role holder count": {
+			Caller:     TestNoRoleAddr,
+			BeforeHook: SetDefaultRolesAndBuildIndex(contractAddress),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetRoleHolderCount(ManagerRole)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetRoleHolderCountGasCost + GetRoleHolderGasCost,
+			ReadOnly:    true,
+			ExpectedRes: common.BigToHash(common.Big1).Bytes(),
+		},
+		"no role get role holders": {
+			Caller:     TestNoRoleAddr,
+			BeforeHook: SetDefaultRolesAndBuildIndex(contractAddress),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetRoleHolders(AdminRole, 0, 10)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetRoleHoldersGasCost + GetRoleHolderGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetRoleHoldersOutput([]common.Address{TestAdminAddr})
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"get role holders leaves out expired roles": {
+			Caller: TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				SetDefaultRolesAndBuildIndex(contractAddress)(t, state)
+				SetAllowListRoleWithExpiry(state, contractAddress, TestNoRoleAddr, EnabledRole, testExpiryTimestamp)
+			},
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetRoleHolders(EnabledRole, 0, 10)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetRoleHoldersGasCost + 2*GetRoleHolderGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetRoleHoldersOutput([]common.Address{TestEnabledAddr})
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"get role holder count leaves out expired roles": {
+			Caller: TestNoRoleAddr,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				SetDefaultRolesAndBuildIndex(contractAddress)(t, state)
+				SetAllowListRoleWithExpiry(state, contractAddress, TestNoRoleAddr, EnabledRole, testExpiryTimestamp)
+			},
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetRoleHolderCount(EnabledRole)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetRoleHolderCountGasCost + 2*GetRoleHolderGasCost,
+			ReadOnly:    true,
+			ExpectedRes: common.BigToHash(common.Big1).Bytes(),
+		},
+		"get role holders past the last holder": {
+			Caller:     TestNoRoleAddr,
+			BeforeHook: SetDefaultRolesAndBuildIndex(contractAddress),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetRoleHolders(AdminRole, 1, 10)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetRoleHoldersGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetRoleHoldersOutput([]common.Address{})
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"get holders of no role fails": {
+			Caller:     TestNoRoleAddr,
+			BeforeHook: SetDefaultRolesAndBuildIndex(contractAddress),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetRoleHolders(NoRole, 0, 10)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetRoleHoldersGasCost,
+			ReadOnly:    true,
+			ExpectedErr: ErrNoRoleNotIndexed.Error(),
+		},
+		"get role holders before the role index is built fails": {
+			Caller:     TestNoRoleAddr,
+			BeforeHook: SetDefaultRoles(contractAddress),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetRoleHolders(AdminRole, 0, 10)
+				require.NoError(t, err)
+
+				return input
+			},
+			// Non-activated functions return the supplied gas
+			SuppliedGas: 0,
+			ReadOnly:    true,
+			ExpectedErr: "invalid non-activated function selector",
+		},
+		"admin set enabled with expiry": {
+			Caller:            TestAdminAddr,
+			BeforeHook:        SetDefaultRoles(contractAddress),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp - 100),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetRoleWithExpiry(TestNoRoleAddr, EnabledRole, testExpiryTimestamp)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetRoleWithExpiryGasCost + RoleSetWithExpiryEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, EnabledRole, GetAllowListStatus(state, contractAddress, TestNoRoleAddr))
+				require.Equal(t, testExpiryTimestamp, GetAllowListRoleExpiry(state, contractAddress, TestNoRoleAddr))
+				require.Equal(t, EnabledRole, GetActiveAllowListStatus(state, contractAddress, TestNoRoleAddr, testExpiryTimestamp-1))
+				require.Equal(t, NoRole, GetActiveAllowListStatus(state, contractAddress, TestNoRoleAddr, testExpiryTimestamp))
+				logsTopics, logsData := state.GetLogData()
+				assertSetRoleWithExpiryEvent(t, logsTopics, logsData, EnabledRole, TestNoRoleAddr, TestAdminAddr, NoRole, testExpiryTimestamp)
+			},
+		},
+		"admin set enabled with expiry updates role index": {
+			Caller:            TestAdminAddr,
+			BeforeHook:        SetDefaultRolesAndBuildIndex(contractAddress),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp - 100),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetRoleWithExpiry(TestNoRoleAddr, EnabledRole, testExpiryTimestamp)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetRoleWithExpiryGasCost + ModifyAllowListIndexGasCost + RoleSetWithExpiryEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, []common.Address{TestEnabledAddr, TestNoRoleAddr}, GetRoleHolders(state, contractAddress, EnabledRole, 0, 10, 0))
+			},
+		},
+		"admin set enabled with expiry insufficient gas": {
+			Caller:            TestAdminAddr,
+			BeforeHook:        SetDefaultRoles(contractAddress),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp - 100),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetRoleWithExpiry(TestNoRoleAddr, EnabledRole, testExpiryTimestamp)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetRoleWithExpiryGasCost + RoleSetWithExpiryEventGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vm.ErrOutOfGas.Error(),
+		},
+		"admin set enabled with expiry readOnly": {
+			Caller:            TestAdminAddr,
+			BeforeHook:        SetDefaultRoles(contractAddress),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp - 100),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetRoleWithExpiry(TestNoRoleAddr, EnabledRole, testExpiryTimestamp)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetRoleWithExpiryGasCost + RoleSetWithExpiryEventGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vm.ErrWriteProtection.Error(),
+		},
+		"admin set enabled with past expiry fails": {
+			Caller:            TestAdminAddr,
+			BeforeHook:        SetDefaultRoles(contractAddress),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetRoleWithExpiry(TestNoRoleAddr, EnabledRole, testExpiryTimestamp)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetRoleWithExpiryGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrInvalidRoleExpiry.Error(),
+		},
+		"admin set no role with expiry fails": {
+			Caller:            TestAdminAddr,
+			BeforeHook:        SetDefaultRoles(contractAddress),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp - 100),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetRoleWithExpiry(TestEnabledAddr, NoRole, testExpiryTimestamp)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetRoleWithExpiryGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrNoRoleWithExpiry.Error(),
+		},
+		"enabled set enabled with expiry fails": {
+			Caller:            TestEnabledAddr,
+			BeforeHook:        SetDefaultRoles(contractAddress),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp - 100),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetRoleWithExpiry(TestNoRoleAddr, EnabledRole, testExpiryTimestamp)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetRoleWithExpiryGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotModifyAllowList.Error(),
+		},
+		"expired admin set enabled fails": {
+			Caller:            TestAdminAddr,
+			BeforeHook:        setExpiringRole(contractAddress, TestAdminAddr, AdminRole),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackModifyAllowList(TestNoRoleAddr, EnabledRole)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: ModifyAllowListGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotModifyAllowList.Error(),
+		},
+		"admin set enabled removes expiry": {
+			Caller:            TestAdminAddr,
+			BeforeHook:        setExpiringRole(contractAddress, TestEnabledAddr, EnabledRole),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp - 100),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackModifyAllowList(TestEnabledAddr, EnabledRole)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: ModifyAllowListGasCost + AllowListEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Zero(t, GetAllowListRoleExpiry(state, contractAddress, TestEnabledAddr))
+				require.Equal(t, EnabledRole, GetActiveAllowListStatus(state, contractAddress, TestEnabledAddr, testExpiryTimestamp))
+			},
+		},
+		"read allow list with expiry": {
+			Caller:            TestNoRoleAddr,
+			BeforeHook:        setExpiringRole(contractAddress, TestEnabledAddr, EnabledRole),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp - 1),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackReadAllowListWithExpiry(TestEnabledAddr)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: ReadAllowListWithExpiryGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackReadAllowListWithExpiryOutput(EnabledRole, testExpiryTimestamp)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"read allow list with expiry of expired role": {
+			Caller:            TestNoRoleAddr,
+			BeforeHook:        setExpiringRole(contractAddress, TestEnabledAddr, EnabledRole),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackReadAllowListWithExpiry(TestEnabledAddr)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: ReadAllowListWithExpiryGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackReadAllowListWithExpiryOutput(NoRole, 0)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"read allow list of expired role": {
+			Caller:            TestNoRoleAddr,
+			BeforeHook:        setExpiringRole(contractAddress, TestEnabledAddr, EnabledRole),
+			SetupBlockContext: setupExpiryBlockContext(testExpiryTimestamp),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackReadAllowList(TestEnabledAddr)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: ReadAllowListGasCost,
+			ReadOnly:    true,
+			ExpectedRes: common.Hash(NoRole).Bytes(),
+		},
+		"admin set enabled with expiry pre-Granite": {
+			Caller:     TestAdminAddr,
+			BeforeHook: SetDefaultRoles(contractAddress),
+			ChainConfigFn: func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(ctrl)
+				config.EXPECT().IsDurango(gomock.Any()).Return(true).AnyTimes()
+				config.EXPECT().IsGranite(gomock.Any()).Return(false).AnyTimes()
+				return config
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetRoleWithExpiry(TestNoRoleAddr, EnabledRole, testExpiryTimestamp)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetRoleWithExpiryGasCost + RoleSetWithExpiryEventGasCost,
+			ReadOnly:    false,
+			ExpectedErr: "invalid non-activated function selector",
+		},
 		"initial config sets admins": {
 			Config: mkConfigWithAllowList(
 				module,
@@ -664,6 +1044,34 @@ func SetDefaultRoles(contractAddress common.Address) func(t testing.TB, state co
 	}
 }
 
+// setExpiringRole returns a BeforeHook that sets the roles set by [SetDefaultRoles]
+// and grants [role] to [address] until testExpiryTimestamp.
+func setExpiringRole(contractAddress common.Address, address common.Address, role Role) func(t testing.TB, state contract.StateDB) {
+	return func(t testing.TB, state contract.StateDB) {
+		SetDefaultRoles(contractAddress)(t, state)
+		SetAllowListRoleWithExpiry(state, contractAddress, address, role, testExpiryTimestamp)
+	}
+}
+
+// setupExpiryBlockContext returns a SetupBlockContext that sets the block
+// timestamp to [timestamp].
+func setupExpiryBlockContext(timestamp uint64) func(*contract.MockBlockContext) {
+	return func(mbc *contract.MockBlockContext) {
+		mbc.EXPECT().Number().Return(common.Big0).AnyTimes()
+		mbc.EXPECT().Timestamp().Return(timestamp).AnyTimes()
+	}
+}
+
+// SetDefaultRolesAndBuildIndex returns a BeforeHook that sets the roles set by
+// [SetDefaultRoles] and builds the role holder index from them.
+func SetDefaultRolesAndBuildIndex(contractAddress common.Address) func(t testing.TB, state contract.StateDB) {
+	return func(t testing.TB, state contract.StateDB) {
+		SetDefaultRoles(contractAddress)(t, state)
+		BuildRoleIndex(state, contractAddress, []common.Address{TestAdminAddr, TestManagerAddr, TestEnabledAddr, TestNoRoleAddr})
+		require.True(t, IsRoleIndexBuilt(state, contractAddress))
+	}
+}
+
 func RunPrecompileWithAllowListTests(t *testing.T, module modules.Module, newStateDB func(t testing.TB) contract.StateDB, contractTests map[string]testutils.PrecompileTest) {
 	t.Helper()
 	tests := AllowListTests(t, module)
@@ -709,3 +1117,18 @@ func assertSetRoleEvent(t testing.TB, logsTopics [][]common.Hash, logsData [][]b
 	data := logsData[0]
 	require.Equal(t, oldRole.Bytes(), data)
 }
+
+func assertSetRoleWithExpiryEvent(t testing.TB, logsTopics [][]common.Hash, logsData [][]byte, role Role, addr common.Address, caller common.Address, oldRole Role, expiry uint64) {
+	require.Len(t, logsTopics, 1)
+	require.Len(t, logsData, 1)
+	topics := logsTopics[0]
+	require.Len(t, topics, 4)
+	require.Equal(t, AllowListABI.Events["RoleSetWithExpiry"].ID, topics[0])
+	require.Equal(t, role.Hash(), topics[1])
+	require.Equal(t, common.BytesToHash(addr[:]), topics[2])
+	require.Equal(t, common.BytesToHash(caller[:]), topics[3])
+	gotOldRole, gotExpiry, err := UnpackRoleSetWithExpiryEventData(logsData[0])
+	require.NoError(t, err)
+	require.Equal(t, oldRole, gotOldRole)
+	require.Equal(t, expiry, gotExpiry)
+}
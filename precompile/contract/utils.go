@@ -61,3 +61,7 @@ func ParseABI(rawABI string) abi.ABI {
 func IsDurangoActivated(evm AccessibleState) bool {
 	return evm.GetChainConfig().IsDurango(evm.GetBlockContext().Timestamp())
 }
+
+func IsGraniteActivated(evm AccessibleState) bool {
+	return evm.GetChainConfig().IsGranite(evm.GetBlockContext().Timestamp())
+}
@@ -0,0 +1,81 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package denylist
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/luxfi/evm/precompile/allowlist"
+	"github.com/luxfi/evm/precompile/precompileconfig"
+
+	"github.com/luxfi/geth/common"
+)
+
+var _ precompileconfig.Config = &Config{}
+
+// Config implements the StatefulPrecompileConfig interface while adding in the
+// DenyList specific precompile config.
+type Config struct {
+	allowlist.AllowListConfig
+	precompileconfig.Upgrade
+	FrozenAddresses []common.Address `json:"frozenAddresses,omitempty"` // initial frozen addresses
+}
+
+// NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// DenyList with the given [admins], [enableds] and [managers] as members of the allowlist
+// and [frozen] as the initially frozen addresses.
+func NewConfig(blockTimestamp *uint64, admins []common.Address, enableds []common.Address, managers []common.Address, frozen []common.Address) *Config {
+	return &Config{
+		AllowListConfig: allowlist.AllowListConfig{
+			AdminAddresses:   admins,
+			EnabledAddresses: enableds,
+			ManagerAddresses: managers,
+		},
+		Upgrade:         precompileconfig.Upgrade{BlockTimestamp: blockTimestamp},
+		FrozenAddresses: frozen,
+	}
+}
+
+// NewDisableConfig returns config for a network upgrade at [blockTimestamp]
+// that disables DenyList.
+func NewDisableConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Key returns the key for the Contract precompileconfig.
+// This should be the same key as used in the precompile module.
+func (*Config) Key() string { return ConfigKey }
+
+// Verify tries to verify Config and returns an error accordingly.
+func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
+	frozen := make(map[common.Address]struct{}, len(c.FrozenAddresses))
+	for _, addr := range c.FrozenAddresses {
+		if _, ok := frozen[addr]; ok {
+			return fmt.Errorf("duplicate address in frozen list: %s", addr)
+		}
+		if !IsFreezable(addr) || slices.Contains(c.AdminAddresses, addr) || slices.Contains(c.ManagerAddresses, addr) {
+			return fmt.Errorf("%w: %s", ErrNotFreezable, addr)
+		}
+		frozen[addr] = struct{}{}
+	}
+	return c.AllowListConfig.Verify(chainConfig, c.Upgrade)
+}
+
+// Equal returns true if [cfg] is a [*Config] and it has been configured identical to [c].
+func (c *Config) Equal(cfg precompileconfig.Config) bool {
+	// typecast before comparison
+	other, ok := (cfg).(*Config)
+	if !ok {
+		return false
+	}
+	return c.Upgrade.Equal(&other.Upgrade) &&
+		c.AllowListConfig.Equal(&other.AllowListConfig) &&
+		slices.Equal(c.FrozenAddresses, other.FrozenAddresses)
+}
@@ -0,0 +1,72 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package denylist
+
+import (
+	"testing"
+
+	"github.com/luxfi/evm/precompile/allowlist"
+	"github.com/luxfi/evm/precompile/precompileconfig"
+	"github.com/luxfi/evm/precompile/testutils"
+	"github.com/luxfi/evm/utils"
+	"github.com/luxfi/geth/common"
+	"go.uber.org/mock/gomock"
+)
+
+var testFrozenAddr = common.HexToAddress("0x0000000000000000000000000000000000000055")
+
+func TestVerify(t *testing.T) {
+	admins := []common.Address{allowlist.TestAdminAddr}
+	tests := map[string]testutils.ConfigVerifyTest{
+		"valid frozen addresses": {
+			Config:        NewConfig(utils.NewUint64(3), admins, nil, nil, []common.Address{testFrozenAddr}),
+			ExpectedError: "",
+		},
+		"duplicate frozen address": {
+			Config:        NewConfig(utils.NewUint64(3), admins, nil, nil, []common.Address{testFrozenAddr, testFrozenAddr}),
+			ExpectedError: "duplicate address in frozen list",
+		},
+		"frozen admin": {
+			Config:        NewConfig(utils.NewUint64(3), []common.Address{testFrozenAddr}, nil, nil, []common.Address{testFrozenAddr}),
+			ExpectedError: ErrNotFreezable.Error(),
+		},
+		"frozen deny list": {
+			Config:        NewConfig(utils.NewUint64(3), admins, nil, nil, []common.Address{ContractAddress}),
+			ExpectedError: ErrNotFreezable.Error(),
+		},
+	}
+	allowlist.VerifyPrecompileWithAllowListTests(t, Module, tests)
+}
+
+func TestEqual(t *testing.T) {
+	admins := []common.Address{allowlist.TestAdminAddr}
+	tests := map[string]testutils.ConfigEqualTest{
+		"non-nil config and nil other": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, nil),
+			Other:    nil,
+			Expected: false,
+		},
+		"different type": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, nil),
+			Other:    precompileconfig.NewMockConfig(gomock.NewController(t)),
+			Expected: false,
+		},
+		"different timestamp": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, nil),
+			Other:    NewConfig(utils.NewUint64(4), admins, nil, nil, nil),
+			Expected: false,
+		},
+		"different frozen addresses": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, []common.Address{testFrozenAddr}),
+			Other:    NewConfig(utils.NewUint64(3), admins, nil, nil, nil),
+			Expected: false,
+		},
+		"same config": {
+			Config:   NewConfig(utils.NewUint64(3), admins, nil, nil, []common.Address{testFrozenAddr}),
+			Other:    NewConfig(utils.NewUint64(3), admins, nil, nil, []common.Address{testFrozenAddr}),
+			Expected: true,
+		},
+	}
+	allowlist.EqualPrecompileWithAllowListTests(t, Module, tests)
+}
@@ -0,0 +1,230 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package denylist
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/luxfi/evm/precompile/allowlist"
+	"github.com/luxfi/evm/precompile/contract"
+	"github.com/luxfi/evm/precompile/modules"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/luxfi/geth/crypto"
+	ethparams "github.com/luxfi/geth/params"
+)
+
+// DenyList keeps a list of frozen addresses in the storage trie. Frozen
+// addresses cannot issue transactions and cannot be the recipient of one.
+// The list is managed by the enabled addresses of the embedded allow list.
+
+const (
+	FreezeGasCost   uint64 = contract.WriteGasCostPerSlot + allowlist.ReadAllowListGasCost // write 1 slot + read allow list
+	UnfreezeGasCost uint64 = contract.WriteGasCostPerSlot + allowlist.ReadAllowListGasCost // write 1 slot + read allow list
+	IsFrozenGasCost uint64 = contract.ReadGasCostPerSlot
+)
+
+// Singleton StatefulPrecompiledContract and signatures.
+var (
+	ErrCannotFreeze   = errors.New("non-enabled cannot call freeze")
+	ErrCannotUnfreeze = errors.New("non-enabled cannot call unfreeze")
+	ErrNotFreezable   = errors.New("account cannot be frozen")
+
+	// DenyListRawABI contains the raw ABI of DenyList contract.
+	//go:embed contract.abi
+	DenyListRawABI string
+
+	DenyListABI        = contract.ParseABI(DenyListRawABI)
+	DenyListPrecompile = createDenyListPrecompile()
+
+	// frozenKeyPrefix prefixes the storage key of the frozen flag of an address,
+	// so that it does not collide with the allow list role of the address.
+	frozenKeyPrefix = []byte("frozen")
+	frozenValue     = common.BigToHash(common.Big1)
+)
+
+// GetDenyListAllowListStatus returns the role of [address] for the DenyList allow list.
+func GetDenyListAllowListStatus(stateDB contract.StateReader, address common.Address) allowlist.Role {
+	return allowlist.GetAllowListStatus(stateDB, ContractAddress, address)
+}
+
+// SetDenyListAllowListStatus sets the permissions of [address] to [role] for the
+// DenyList allow list. Assumes [role] has already been verified as valid.
+func SetDenyListAllowListStatus(stateDB contract.StateDB, address common.Address, role allowlist.Role) {
+	allowlist.SetAllowListRole(stateDB, ContractAddress, address, role)
+}
+
+func frozenKey(address common.Address) common.Hash {
+	return crypto.Keccak256Hash(frozenKeyPrefix, address.Bytes())
+}
+
+// IsFrozen returns true if [address] is frozen.
+func IsFrozen(stateDB contract.StateReader, address common.Address) bool {
+	return stateDB.GetState(ContractAddress, frozenKey(address)) == frozenValue
+}
+
+// IsFreezable returns false if [address] is the deny list itself, a precompile
+// or a system address. Freezing them would halt every transaction touching
+// them, which could not be undone if it locked out the deny list.
+func IsFreezable(address common.Address) bool {
+	return address != ContractAddress &&
+		address != ethparams.SystemAddress &&
+		!modules.ReservedAddress(address) &&
+		!slices.Contains(vm.PrecompiledAddressesPrague, address)
+}
+
+// SetFrozen freezes [address] if [frozen] is true and unfreezes it otherwise.
+func SetFrozen(stateDB contract.StateDB, address common.Address, frozen bool) {
+	value := common.Hash{}
+	if frozen {
+		value = frozenValue
+	}
+	stateDB.SetState(ContractAddress, frozenKey(address), value)
+}
+
+// PackFreeze packs [account] into the appropriate arguments for freeze.
+func PackFreeze(account common.Address) ([]byte, error) {
+	return DenyListABI.Pack("freeze", account)
+}
+
+// PackUnfreeze packs [account] into the appropriate arguments for unfreeze.
+func PackUnfreeze(account common.Address) ([]byte, error) {
+	return DenyListABI.Pack("unfreeze", account)
+}
+
+// PackIsFrozen packs [account] into the appropriate arguments for isFrozen.
+func PackIsFrozen(account common.Address) ([]byte, error) {
+	return DenyListABI.Pack("isFrozen", account)
+}
+
+// PackIsFrozenOutput attempts to pack given [frozen] of type bool
+// to conform the ABI outputs.
+func PackIsFrozenOutput(frozen bool) ([]byte, error) {
+	return DenyListABI.PackOutput("isFrozen", frozen)
+}
+
+// UnpackAccountInput attempts to unpack [input] into the account argument of [methodName].
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackAccountInput(methodName string, input []byte) (common.Address, error) {
+	var account common.Address
+	err := DenyListABI.UnpackInputIntoInterface(&account, methodName, input, false)
+	return account, err
+}
+
+func freeze(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	return setFrozen(accessibleState, caller, input, suppliedGas, readOnly, true)
+}
+
+func unfreeze(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	return setFrozen(accessibleState, caller, input, suppliedGas, readOnly, false)
+}
+
+// setFrozen freezes or unfreezes the account in [input] if [caller] is enabled.
+func setFrozen(accessibleState contract.AccessibleState, caller common.Address, input []byte, suppliedGas uint64, readOnly bool, frozen bool) (ret []byte, remainingGas uint64, err error) {
+	var (
+		methodName    = "freeze"
+		gasCost       = FreezeGasCost
+		eventGasCost  = AccountFrozenEventGasCost
+		errCannotCall = ErrCannotFreeze
+		packEvent     = PackAccountFrozenEvent
+	)
+	if !frozen {
+		methodName = "unfreeze"
+		gasCost = UnfreezeGasCost
+		eventGasCost = AccountUnfrozenEventGasCost
+		errCannotCall = ErrCannotUnfreeze
+		packEvent = PackAccountUnfrozenEvent
+	}
+
+	if remainingGas, err = contract.DeductGas(suppliedGas, gasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+	account, err := UnpackAccountInput(methodName, input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to call this function.
+	callerStatus := allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp())
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", errCannotCall, caller)
+	}
+
+	// Admins and managers are not frozen, so that the deny list always has a
+	// member able to unfreeze the other accounts.
+	if frozen {
+		accountStatus := allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, account, accessibleState.GetBlockContext().Timestamp())
+		if !IsFreezable(account) || accountStatus == allowlist.AdminRole || accountStatus == allowlist.ManagerRole {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrNotFreezable, account)
+		}
+	}
+
+	if remainingGas, err = contract.DeductGas(remainingGas, eventGasCost); err != nil {
+		return nil, 0, err
+	}
+	topics, data, err := packEvent(account, caller)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	stateDB.AddLog(&types.Log{
+		Address:     ContractAddress,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: accessibleState.GetBlockContext().Number().Uint64(),
+	})
+
+	SetFrozen(stateDB, account, frozen)
+	return []byte{}, remainingGas, nil
+}
+
+func isFrozen(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, IsFrozenGasCost); err != nil {
+		return nil, 0, err
+	}
+	account, err := UnpackAccountInput("isFrozen", input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	packedOutput, err := PackIsFrozenOutput(IsFrozen(accessibleState.GetStateDB(), account))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// createDenyListPrecompile returns a StatefulPrecompiledContract with getters and setters for the precompile.
+// Access to the setters is controlled by an allow list for [ContractAddress].
+func createDenyListPrecompile() contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+	functions = append(functions, allowlist.CreateAllowListFunctions(ContractAddress)...)
+	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"freeze":   freeze,
+		"isFrozen": isFrozen,
+		"unfreeze": unfreeze,
+	}
+
+	for name, function := range abiFunctionMap {
+		method, ok := DenyListABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+	}
+
+	// Construct the contract with no fallback function.
+	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
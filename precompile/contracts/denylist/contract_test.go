@@ -0,0 +1,261 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package denylist
+
+import (
+	"testing"
+
+	"github.com/luxfi/evm/core/extstate"
+	"github.com/luxfi/evm/precompile/allowlist"
+	"github.com/luxfi/evm/precompile/contract"
+	"github.com/luxfi/evm/precompile/testutils"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/vm"
+	ethparams "github.com/luxfi/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+var tests = map[string]testutils.PrecompileTest{
+	"freeze from no role fails": {
+		Caller:     allowlist.TestNoRoleAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackFreeze(testFrozenAddr)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: FreezeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrCannotFreeze.Error(),
+	},
+	"freeze from enabled succeeds": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackFreeze(testFrozenAddr)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: FreezeGasCost + AccountFrozenEventGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+			require.True(t, IsFrozen(stateDB, testFrozenAddr))
+
+			logsTopics, logsData := stateDB.GetLogData()
+			assertFreezeEvent(t, logsTopics, logsData, "AccountFrozen", testFrozenAddr, allowlist.TestEnabledAddr)
+		},
+	},
+	"freeze insufficient gas": {
+		Caller:     allowlist.TestAdminAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackFreeze(testFrozenAddr)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: FreezeGasCost + AccountFrozenEventGasCost - 1,
+		ReadOnly:    false,
+		ExpectedErr: vm.ErrOutOfGas.Error(),
+	},
+	"freeze readOnly fails": {
+		Caller:     allowlist.TestAdminAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackFreeze(testFrozenAddr)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: FreezeGasCost,
+		ReadOnly:    true,
+		ExpectedErr: vm.ErrWriteProtection.Error(),
+	},
+	"freeze admin fails": {
+		Caller: allowlist.TestEnabledAddr,
+		BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+			allowlist.SetDefaultRoles(Module.Address)(t, stateDB)
+			SetDenyListAllowListStatus(stateDB, testFrozenAddr, allowlist.AdminRole)
+		},
+		InputFn:     packFreezeFn(testFrozenAddr),
+		SuppliedGas: FreezeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrNotFreezable.Error(),
+	},
+	"freeze manager fails": {
+		Caller:      allowlist.TestEnabledAddr,
+		BeforeHook:  allowlist.SetDefaultRoles(Module.Address),
+		InputFn:     packFreezeFn(allowlist.TestManagerAddr),
+		SuppliedGas: FreezeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrNotFreezable.Error(),
+	},
+	"freeze deny list fails": {
+		Caller:      allowlist.TestEnabledAddr,
+		BeforeHook:  allowlist.SetDefaultRoles(Module.Address),
+		InputFn:     packFreezeFn(ContractAddress),
+		SuppliedGas: FreezeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrNotFreezable.Error(),
+	},
+	"freeze stateful precompile fails": {
+		Caller:      allowlist.TestEnabledAddr,
+		BeforeHook:  allowlist.SetDefaultRoles(Module.Address),
+		InputFn:     packFreezeFn(common.HexToAddress("0x0200000000000000000000000000000000000002")),
+		SuppliedGas: FreezeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrNotFreezable.Error(),
+	},
+	"freeze native precompile fails": {
+		Caller:      allowlist.TestEnabledAddr,
+		BeforeHook:  allowlist.SetDefaultRoles(Module.Address),
+		InputFn:     packFreezeFn(common.BytesToAddress([]byte{1})),
+		SuppliedGas: FreezeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrNotFreezable.Error(),
+	},
+	"freeze system address fails": {
+		Caller:      allowlist.TestEnabledAddr,
+		BeforeHook:  allowlist.SetDefaultRoles(Module.Address),
+		InputFn:     packFreezeFn(ethparams.SystemAddress),
+		SuppliedGas: FreezeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrNotFreezable.Error(),
+	},
+	"unfreeze from no role fails": {
+		Caller: allowlist.TestNoRoleAddr,
+		BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+			allowlist.SetDefaultRoles(Module.Address)(t, stateDB)
+			SetFrozen(stateDB, testFrozenAddr, true)
+		},
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackUnfreeze(testFrozenAddr)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: UnfreezeGasCost,
+		ReadOnly:    false,
+		ExpectedErr: ErrCannotUnfreeze.Error(),
+	},
+	"unfreeze from admin succeeds": {
+		Caller: allowlist.TestAdminAddr,
+		BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+			allowlist.SetDefaultRoles(Module.Address)(t, stateDB)
+			SetFrozen(stateDB, testFrozenAddr, true)
+		},
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackUnfreeze(testFrozenAddr)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: UnfreezeGasCost + AccountUnfrozenEventGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+			require.False(t, IsFrozen(stateDB, testFrozenAddr))
+
+			logsTopics, logsData := stateDB.GetLogData()
+			assertFreezeEvent(t, logsTopics, logsData, "AccountUnfrozen", testFrozenAddr, allowlist.TestAdminAddr)
+		},
+	},
+	"is frozen from no role": {
+		Caller: allowlist.TestNoRoleAddr,
+		BeforeHook: func(t testing.TB, stateDB contract.StateDB) {
+			SetFrozen(stateDB, testFrozenAddr, true)
+		},
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackIsFrozen(testFrozenAddr)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: IsFrozenGasCost,
+		ReadOnly:    true,
+		ExpectedRes: func() []byte {
+			res, err := PackIsFrozenOutput(true)
+			if err != nil {
+				panic(err)
+			}
+			return res
+		}(),
+	},
+	"is frozen of unfrozen address": {
+		Caller: allowlist.TestNoRoleAddr,
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackIsFrozen(testFrozenAddr)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: IsFrozenGasCost,
+		ReadOnly:    true,
+		ExpectedRes: func() []byte {
+			res, err := PackIsFrozenOutput(false)
+			if err != nil {
+				panic(err)
+			}
+			return res
+		}(),
+	},
+	"initial frozen addresses": {
+		Caller: allowlist.TestNoRoleAddr,
+		Config: &Config{
+			FrozenAddresses: []common.Address{testFrozenAddr},
+		},
+		AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+			require.True(t, IsFrozen(stateDB, testFrozenAddr))
+			require.False(t, IsFrozen(stateDB, allowlist.TestNoRoleAddr))
+		},
+	},
+	"frozen flag does not change allow list role": {
+		Caller:     allowlist.TestEnabledAddr,
+		BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+		InputFn: func(t testing.TB) []byte {
+			input, err := PackFreeze(allowlist.TestEnabledAddr)
+			require.NoError(t, err)
+
+			return input
+		},
+		SuppliedGas: FreezeGasCost + AccountFrozenEventGasCost,
+		ReadOnly:    false,
+		ExpectedRes: []byte{},
+		AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+			require.True(t, IsFrozen(stateDB, allowlist.TestEnabledAddr))
+			require.Equal(t, allowlist.EnabledRole, GetDenyListAllowListStatus(stateDB, allowlist.TestEnabledAddr))
+		},
+	},
+}
+
+func TestDenyListRun(t *testing.T) {
+	allowlist.RunPrecompileWithAllowListTests(t, Module, extstate.NewTestStateDB, tests)
+}
+
+func BenchmarkDenyList(b *testing.B) {
+	allowlist.BenchPrecompileWithAllowList(b, Module, extstate.NewTestStateDB, tests)
+}
+
+func packFreezeFn(account common.Address) func(t testing.TB) []byte {
+	return func(t testing.TB) []byte {
+		input, err := PackFreeze(account)
+		require.NoError(t, err)
+
+		return input
+	}
+}
+
+func assertFreezeEvent(t testing.TB, logsTopics [][]common.Hash, logsData [][]byte, eventName string, account common.Address, sender common.Address) {
+	require.Len(t, logsTopics, 1)
+	require.Len(t, logsData, 1)
+	topics := logsTopics[0]
+	require.Len(t, topics, 3)
+	require.Equal(t, DenyListABI.Events[eventName].ID, topics[0])
+	require.Equal(t, common.BytesToHash(account[:]), topics[1])
+	require.Equal(t, common.BytesToHash(sender[:]), topics[2])
+	require.Empty(t, logsData[0])
+}
@@ -0,0 +1,30 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package denylist
+
+import (
+	"github.com/luxfi/evm/precompile/contract"
+	"github.com/luxfi/geth/common"
+)
+
+const (
+	// AccountFrozenEventGasCost is the gas cost of the AccountFrozen event.
+	// It is calculated as the gas cost of the log operation + the gas cost of 3 topic hashes (signature + account + sender).
+	AccountFrozenEventGasCost = contract.LogGas + contract.LogTopicGas*3
+	// AccountUnfrozenEventGasCost is the gas cost of the AccountUnfrozen event.
+	// It is calculated as the gas cost of the log operation + the gas cost of 3 topic hashes (signature + account + sender).
+	AccountUnfrozenEventGasCost = contract.LogGas + contract.LogTopicGas*3
+)
+
+// PackAccountFrozenEvent packs the event into the appropriate arguments for AccountFrozen.
+// It returns topic hashes and the encoded non-indexed data.
+func PackAccountFrozenEvent(account common.Address, sender common.Address) ([]common.Hash, []byte, error) {
+	return DenyListABI.PackEvent("AccountFrozen", account, sender)
+}
+
+// PackAccountUnfrozenEvent packs the event into the appropriate arguments for AccountUnfrozen.
+// It returns topic hashes and the encoded non-indexed data.
+func PackAccountUnfrozenEvent(account common.Address, sender common.Address) ([]common.Hash, []byte, error) {
+	return DenyListABI.PackEvent("AccountUnfrozen", account, sender)
+}
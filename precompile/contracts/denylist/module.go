@@ -0,0 +1,60 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package denylist
+
+import (
+	"fmt"
+
+	"github.com/luxfi/evm/precompile/contract"
+	"github.com/luxfi/evm/precompile/modules"
+	"github.com/luxfi/evm/precompile/precompileconfig"
+
+	"github.com/luxfi/geth/common"
+)
+
+var _ contract.Configurator = &configurator{}
+
+// ConfigKey is the key used in json config files to specify this precompile config.
+// must be unique across all precompiles.
+const ConfigKey = "denyListConfig"
+
+var ContractAddress = common.HexToAddress("0x0200000000000000000000000000000000000006")
+
+// Module is the precompile module. It is used to register the precompile contract.
+var Module = modules.Module{
+	ConfigKey:    ConfigKey,
+	Address:      ContractAddress,
+	Contract:     DenyListPrecompile,
+	Configurator: &configurator{},
+}
+
+type configurator struct{}
+
+func init() {
+	// Register the precompile module.
+	// Each precompile contract registers itself through [RegisterModule] function.
+	if err := modules.RegisterModule(Module); err != nil {
+		panic(err)
+	}
+}
+
+// MakeConfig returns a new precompile config instance.
+// This is required for Marshal/Unmarshal the precompile config.
+func (*configurator) MakeConfig() precompileconfig.Config {
+	return new(Config)
+}
+
+// Configure configures [state] with the given [cfg] precompileconfig.
+// This function is called by the EVM once per precompile contract activation.
+// It freezes the initial frozen addresses of [cfg].
+func (*configurator) Configure(chainConfig precompileconfig.ChainConfig, cfg precompileconfig.Config, state contract.StateDB, blockContext contract.ConfigurationBlockContext) error {
+	config, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("expected config type %T, got %T: %v", &Config{}, cfg, cfg)
+	}
+	for _, addr := range config.FrozenAddresses {
+		SetFrozen(state, addr, true)
+	}
+	return config.AllowListConfig.Configure(chainConfig, ContractAddress, state, blockContext)
+}
@@ -18,6 +18,12 @@ func GetContractDeployerAllowListStatus(stateDB contract.StateReader, address co
 	return allowlist.GetAllowListStatus(stateDB, ContractAddress, address)
 }
 
+// GetActiveContractDeployerAllowListStatus returns the role of [address] for the
+// contract deployer allow list at [timestamp], treating an expired role as NoRole.
+func GetActiveContractDeployerAllowListStatus(stateDB contract.StateReader, address common.Address, timestamp uint64) allowlist.Role {
+	return allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, address, timestamp)
+}
+
 // SetContractDeployerAllowListStatus sets the permissions of [address] to [role] for the
 // contract deployer allow list.
 // assumes [role] has already been verified as valid.
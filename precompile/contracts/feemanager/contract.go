@@ -83,9 +83,23 @@ func SetFeeManagerStatus(stateDB contract.StateDB, address common.Address, role
 
 // GetStoredFeeConfig returns fee config from contract storage in given state
 func GetStoredFeeConfig(stateDB contract.StateReader) commontype.FeeConfig {
+	return readFeeConfig(stateDB, nil)
+}
+
+// feeConfigFieldKey returns the storage key of the fee config field [field] of
+// the fee config stored under [prefix].
+func feeConfigFieldKey(prefix []byte, field int) common.Hash {
+	var key common.Hash
+	copy(key[:], prefix)
+	key[len(prefix)] = byte(field)
+	return key
+}
+
+// readFeeConfig returns the fee config stored under [prefix] in given state
+func readFeeConfig(stateDB contract.StateReader, prefix []byte) commontype.FeeConfig {
 	feeConfig := commontype.FeeConfig{}
 	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
-		val := stateDB.GetState(ContractAddress, common.Hash{byte(i)})
+		val := stateDB.GetState(ContractAddress, feeConfigFieldKey(prefix, i))
 		switch i {
 		case gasLimitKey:
 			feeConfig.GasLimit = new(big.Int).Set(val.Big())
@@ -123,6 +137,19 @@ func StoreFeeConfig(stateDB contract.StateDB, feeConfig commontype.FeeConfig, bl
 		return fmt.Errorf("cannot verify fee config: %w", err)
 	}
 
+	writeFeeConfig(stateDB, nil, feeConfig)
+
+	blockNumber := blockContext.Number()
+	if blockNumber == nil {
+		return fmt.Errorf("blockNumber cannot be nil")
+	}
+	stateDB.SetState(ContractAddress, feeConfigLastChangedAtKey, common.BigToHash(blockNumber))
+	return nil
+}
+
+// writeFeeConfig stores [feeConfig] under [prefix] in the [stateDB].
+// Assumes [feeConfig] has already been verified.
+func writeFeeConfig(stateDB contract.StateDB, prefix []byte, feeConfig commontype.FeeConfig) {
 	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
 		var input common.Hash
 		switch i {
@@ -146,15 +173,8 @@ func StoreFeeConfig(stateDB contract.StateDB, feeConfig commontype.FeeConfig, bl
 			// This should never encounter an unknown fee config key
 			panic(fmt.Sprintf("unknown fee config key: %d", i))
 		}
-		stateDB.SetState(ContractAddress, common.Hash{byte(i)}, input)
+		stateDB.SetState(ContractAddress, feeConfigFieldKey(prefix, i), input)
 	}
-
-	blockNumber := blockContext.Number()
-	if blockNumber == nil {
-		return fmt.Errorf("blockNumber cannot be nil")
-	}
-	stateDB.SetState(ContractAddress, feeConfigLastChangedAtKey, common.BigToHash(blockNumber))
-	return nil
 }
 
 // PackSetFeeConfig packs [inputStruct] of type SetFeeConfigInput into the appropriate arguments for setFeeConfig.
@@ -223,7 +243,7 @@ func setFeeConfig(accessibleState contract.AccessibleState, caller common.Addres
 
 	stateDB := accessibleState.GetStateDB()
 	// Verify that the caller is in the allow list and therefore has the right to call this function.
-	callerStatus := GetFeeManagerStatus(stateDB, caller)
+	callerStatus := allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp())
 	if !callerStatus.IsEnabled() {
 		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotChangeFee, caller)
 	}
@@ -383,6 +403,12 @@ func createFeeManagerPrecompile() contract.StatefulPrecompiledContract {
 		"getFeeConfigLastChangedAt": getFeeConfigLastChangedAt,
 		"setFeeConfig":              setFeeConfig,
 	}
+	// Scheduling fee config changes is only available after Granite
+	graniteFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"scheduleFeeConfig":      scheduleFeeConfig,
+		"getPendingFeeConfig":    getPendingFeeConfig,
+		"cancelPendingFeeConfig": cancelPendingFeeConfig,
+	}
 
 	for name, function := range abiFunctionMap {
 		method, ok := FeeManagerABI.Methods[name]
@@ -391,6 +417,13 @@ func createFeeManagerPrecompile() contract.StatefulPrecompiledContract {
 		}
 		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
 	}
+	for name, function := range graniteFunctionMap {
+		method, ok := FeeManagerABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunctionWithActivator(method.ID, function, contract.IsGraniteActivated))
+	}
 	// Construct the contract with no fallback function.
 	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
 	if err != nil {
@@ -52,8 +52,21 @@ var (
 		MaxBlockGasCost:  new(big.Int),
 		BlockGasCostStep: new(big.Int),
 	}
-	testBlockNumber = big.NewInt(7)
-	tests           = map[string]testutils.PrecompileTest{
+	testBlockNumber        = big.NewInt(7)
+	testBlockTimestamp     = uint64(1_000)
+	testScheduledFeeConfig = commontype.ScheduledFeeConfig{
+		FeeConfig:          testFeeConfig,
+		EffectiveTimestamp: testBlockTimestamp + 60,
+	}
+	setupScheduleBlockContext = func(mbc *contract.MockBlockContext) {
+		mbc.EXPECT().Number().Return(testBlockNumber).AnyTimes()
+		mbc.EXPECT().Timestamp().Return(testBlockTimestamp).AnyTimes()
+	}
+	setDefaultRolesAndSchedule = func(t testing.TB, state contract.StateDB) {
+		allowlist.SetDefaultRoles(Module.Address)(t, state)
+		require.NoError(t, StoreScheduledFeeConfig(state, testScheduledFeeConfig))
+	}
+	tests = map[string]testutils.PrecompileTest{
 		"set config from no role fails": {
 			Caller:     allowlist.TestNoRoleAddr,
 			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
@@ -421,6 +434,225 @@ var (
 				require.Len(t, logsData, 0)
 			},
 		},
+		"schedule config from no role fails": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackScheduleFeeConfig(testScheduledFeeConfig)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas:       ScheduleFeeConfigGasCost,
+			ReadOnly:          false,
+			SetupBlockContext: setupScheduleBlockContext,
+			ExpectedErr:       ErrCannotChangeFee.Error(),
+		},
+		"schedule config pre-Granite fails": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackScheduleFeeConfig(testScheduledFeeConfig)
+				require.NoError(t, err)
+				return input
+			},
+			ChainConfigFn: func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(ctrl)
+				config.EXPECT().IsDurango(gomock.Any()).Return(true).AnyTimes()
+				config.EXPECT().IsGranite(gomock.Any()).Return(false).AnyTimes()
+				return config
+			},
+			// Non-activated functions return the supplied gas
+			SuppliedGas:       0,
+			ReadOnly:          false,
+			SetupBlockContext: setupScheduleBlockContext,
+			ExpectedErr:       "invalid non-activated function selector",
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Nil(t, GetScheduledFeeConfig(state))
+			},
+		},
+		"get pending fee config pre-Granite fails": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetPendingFeeConfig()
+				require.NoError(t, err)
+				return input
+			},
+			ChainConfigFn: func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(ctrl)
+				config.EXPECT().IsDurango(gomock.Any()).Return(true).AnyTimes()
+				config.EXPECT().IsGranite(gomock.Any()).Return(false).AnyTimes()
+				return config
+			},
+			SuppliedGas: 0,
+			ReadOnly:    true,
+			ExpectedErr: "invalid non-activated function selector",
+		},
+		"readOnly scheduleFeeConfig with enabled role fails": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackScheduleFeeConfig(testScheduledFeeConfig)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: ScheduleFeeConfigGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vm.ErrWriteProtection.Error(),
+		},
+		"schedule config from enabled address succeeds and emits logs": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackScheduleFeeConfig(testScheduledFeeConfig)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas:       ScheduleFeeConfigGasCost + FeeConfigScheduledEventGasCost,
+			ReadOnly:          false,
+			ExpectedRes:       []byte{},
+			SetupBlockContext: setupScheduleBlockContext,
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				// The fee config in effect is not changed until the effective timestamp.
+				require.Equal(t, zeroFeeConfig, GetStoredFeeConfig(state))
+				require.Equal(t, &testScheduledFeeConfig, GetScheduledFeeConfig(state))
+
+				logsTopics, logsData := state.GetLogData()
+				assertScheduledFeeEvent(t, logsTopics, logsData, "FeeConfigScheduled", allowlist.TestEnabledAddr, testScheduledFeeConfig)
+			},
+		},
+		"schedule config replaces the pending config": {
+			Caller:     allowlist.TestManagerAddr,
+			BeforeHook: setDefaultRolesAndSchedule,
+			InputFn: func(t testing.TB) []byte {
+				scheduled := testScheduledFeeConfig
+				scheduled.EffectiveTimestamp++
+				input, err := PackScheduleFeeConfig(scheduled)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas:       ScheduleFeeConfigGasCost + FeeConfigScheduledEventGasCost,
+			ReadOnly:          false,
+			ExpectedRes:       []byte{},
+			SetupBlockContext: setupScheduleBlockContext,
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				scheduled := GetScheduledFeeConfig(state)
+				require.NotNil(t, scheduled)
+				require.Equal(t, testScheduledFeeConfig.EffectiveTimestamp+1, scheduled.EffectiveTimestamp)
+			},
+		},
+		"schedule config at the current timestamp fails": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				scheduled := testScheduledFeeConfig
+				scheduled.EffectiveTimestamp = testBlockTimestamp
+				input, err := PackScheduleFeeConfig(scheduled)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas:       ScheduleFeeConfigGasCost + FeeConfigScheduledEventGasCost,
+			ReadOnly:          false,
+			SetupBlockContext: setupScheduleBlockContext,
+			ExpectedErr:       ErrInvalidEffectiveTimestamp.Error(),
+		},
+		"schedule invalid config fails": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				scheduled := testScheduledFeeConfig
+				scheduled.FeeConfig.MinBlockGasCost = new(big.Int).Mul(scheduled.FeeConfig.MaxBlockGasCost, common.Big2)
+				input, err := PackScheduleFeeConfig(scheduled)
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas:       ScheduleFeeConfigGasCost + FeeConfigScheduledEventGasCost,
+			ReadOnly:          false,
+			SetupBlockContext: setupScheduleBlockContext,
+			ExpectedErr:       "cannot be greater than maxBlockGasCost",
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Nil(t, GetScheduledFeeConfig(state))
+			},
+		},
+		"get pending fee config from non-enabled address": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: setDefaultRolesAndSchedule,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetPendingFeeConfig()
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: GetPendingFeeConfigGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetPendingFeeConfigOutput(&testScheduledFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"get pending fee config without pending config": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetPendingFeeConfig()
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: GetPendingFeeConfigGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetPendingFeeConfigOutput(nil)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"cancel pending fee config from no role fails": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: setDefaultRolesAndSchedule,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCancelPendingFeeConfig()
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: CancelPendingFeeConfigGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotChangeFee.Error(),
+		},
+		"cancel pending fee config from enabled address succeeds and emits logs": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: setDefaultRolesAndSchedule,
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCancelPendingFeeConfig()
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas:       CancelPendingFeeConfigGasCost + PendingFeeConfigCancelledEventGasCost,
+			ReadOnly:          false,
+			ExpectedRes:       []byte{},
+			SetupBlockContext: setupScheduleBlockContext,
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Nil(t, GetScheduledFeeConfig(state))
+
+				logsTopics, logsData := state.GetLogData()
+				assertScheduledFeeEvent(t, logsTopics, logsData, "PendingFeeConfigCancelled", allowlist.TestEnabledAddr, testScheduledFeeConfig)
+			},
+		},
+		"cancel without pending fee config fails": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackCancelPendingFeeConfig()
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: CancelPendingFeeConfigGasCost + PendingFeeConfigCancelledEventGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrNoPendingFeeConfig.Error(),
+		},
 	}
 )
 
@@ -454,3 +686,48 @@ func assertFeeEvent(
 	require.True(t, expectedOldFeeConfig.Equal(&oldFeeConfig), "expected %v, got %v", expectedOldFeeConfig, oldFeeConfig)
 	require.True(t, expectedNewFeeConfig.Equal(&resFeeConfig), "expected %v, got %v", expectedNewFeeConfig, resFeeConfig)
 }
+
+func assertScheduledFeeEvent(
+	t testing.TB,
+	logsTopics [][]common.Hash,
+	logsData [][]byte,
+	event string,
+	sender common.Address,
+	expected commontype.ScheduledFeeConfig,
+) {
+	require.Len(t, logsTopics, 1)
+	require.Len(t, logsData, 1)
+
+	topics := logsTopics[0]
+	require.Len(t, topics, 2)
+	require.Equal(t, FeeManagerABI.Events[event].ID, topics[0])
+	require.Equal(t, common.BytesToHash(sender[:]), topics[1])
+
+	scheduled, err := unpackScheduledFeeConfigEventData(event, logsData[0])
+	require.NoError(t, err)
+	require.Equal(t, expected.EffectiveTimestamp, scheduled.EffectiveTimestamp)
+	require.True(t, expected.FeeConfig.Equal(&scheduled.FeeConfig), "expected %v, got %v", expected.FeeConfig, scheduled.FeeConfig)
+}
+
+func TestApplyScheduledFeeConfig(t *testing.T) {
+	require := require.New(t)
+	state := extstate.NewTestStateDB(t)
+	require.NoError(StoreScheduledFeeConfig(state, testScheduledFeeConfig))
+
+	ctrl := gomock.NewController(t)
+	blockContext := contract.NewMockBlockContext(ctrl)
+	blockContext.EXPECT().Number().Return(testBlockNumber).AnyTimes()
+
+	// Nothing changes before the effective timestamp.
+	blockContext.EXPECT().Timestamp().Return(testScheduledFeeConfig.EffectiveTimestamp - 1).Times(1)
+	require.NoError(ApplyScheduledFeeConfig(state, blockContext))
+	require.Equal(zeroFeeConfig, GetStoredFeeConfig(state))
+	require.Equal(&testScheduledFeeConfig, GetScheduledFeeConfig(state))
+
+	// The scheduled config takes effect at the effective timestamp.
+	blockContext.EXPECT().Timestamp().Return(testScheduledFeeConfig.EffectiveTimestamp).Times(1)
+	require.NoError(ApplyScheduledFeeConfig(state, blockContext))
+	require.Equal(testFeeConfig, GetStoredFeeConfig(state))
+	require.EqualValues(testBlockNumber, GetFeeConfigLastChangedAt(state))
+	require.Nil(GetScheduledFeeConfig(state))
+}
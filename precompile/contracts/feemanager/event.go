@@ -77,3 +77,55 @@ func convertToCommonConfig(config changeFeeConfigEventData) commontype.FeeConfig
 		BlockGasCostStep:         config.BlockGasCostStep,
 	}
 }
+
+// FeeConfigScheduledEventGasCost is the gas cost of a FeeConfigScheduled event.
+// It is the base gas cost + the gas cost of the topics (signature, sender)
+// and the gas cost of the non-indexed data len(feeConfig) + len(effectiveTimestamp).
+const FeeConfigScheduledEventGasCost = contract.LogGas + contract.LogTopicGas*2 + (feeConfigInputLen+common.HashLength)*contract.LogDataGas
+
+// PendingFeeConfigCancelledEventGasCost is the gas cost of a PendingFeeConfigCancelled event.
+// It is the gas cost of reading the cancelled config + the base gas cost + the gas cost
+// of the topics (signature, sender) and the gas cost of the non-indexed data
+// len(feeConfig) + len(effectiveTimestamp).
+const PendingFeeConfigCancelledEventGasCost = GetPendingFeeConfigGasCost + contract.LogGas + contract.LogTopicGas*2 + (feeConfigInputLen+common.HashLength)*contract.LogDataGas
+
+// scheduledFeeConfigEventData represents the non-indexed data of the FeeConfigScheduled
+// and PendingFeeConfigCancelled events.
+type scheduledFeeConfigEventData struct {
+	FeeConfig          changeFeeConfigEventData
+	EffectiveTimestamp *big.Int
+}
+
+// PackFeeConfigScheduledEvent packs the event into the appropriate arguments for scheduleFeeConfig.
+// It returns topic hashes and the encoded non-indexed data.
+func PackFeeConfigScheduledEvent(sender common.Address, scheduled commontype.ScheduledFeeConfig) ([]common.Hash, []byte, error) {
+	return FeeManagerABI.PackEvent("FeeConfigScheduled", sender, convertFromCommonConfig(scheduled.FeeConfig), new(big.Int).SetUint64(scheduled.EffectiveTimestamp))
+}
+
+// UnpackFeeConfigScheduledEventData attempts to unpack non-indexed [dataBytes].
+func UnpackFeeConfigScheduledEventData(dataBytes []byte) (commontype.ScheduledFeeConfig, error) {
+	return unpackScheduledFeeConfigEventData("FeeConfigScheduled", dataBytes)
+}
+
+// PackPendingFeeConfigCancelledEvent packs the event into the appropriate arguments for cancelPendingFeeConfig.
+// It returns topic hashes and the encoded non-indexed data.
+func PackPendingFeeConfigCancelledEvent(sender common.Address, cancelled commontype.ScheduledFeeConfig) ([]common.Hash, []byte, error) {
+	return FeeManagerABI.PackEvent("PendingFeeConfigCancelled", sender, convertFromCommonConfig(cancelled.FeeConfig), new(big.Int).SetUint64(cancelled.EffectiveTimestamp))
+}
+
+// UnpackPendingFeeConfigCancelledEventData attempts to unpack non-indexed [dataBytes].
+func UnpackPendingFeeConfigCancelledEventData(dataBytes []byte) (commontype.ScheduledFeeConfig, error) {
+	return unpackScheduledFeeConfigEventData("PendingFeeConfigCancelled", dataBytes)
+}
+
+func unpackScheduledFeeConfigEventData(event string, dataBytes []byte) (commontype.ScheduledFeeConfig, error) {
+	eventData := scheduledFeeConfigEventData{}
+	err := FeeManagerABI.UnpackIntoInterface(&eventData, event, dataBytes)
+	if err != nil {
+		return commontype.ScheduledFeeConfig{}, err
+	}
+	return commontype.ScheduledFeeConfig{
+		FeeConfig:          convertToCommonConfig(eventData.FeeConfig),
+		EffectiveTimestamp: eventData.EffectiveTimestamp.Uint64(),
+	}, nil
+}
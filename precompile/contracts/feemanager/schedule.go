@@ -0,0 +1,323 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feemanager
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/evm/commontype"
+	"github.com/luxfi/evm/precompile/allowlist"
+	"github.com/luxfi/evm/precompile/contract"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+)
+
+const (
+	ScheduleFeeConfigGasCost      uint64 = contract.WriteGasCostPerSlot * (numFeeConfigField + 1) // plus one for setting the effective timestamp
+	GetPendingFeeConfigGasCost    uint64 = contract.ReadGasCostPerSlot * (numFeeConfigField + 1)  // plus one for reading the effective timestamp
+	CancelPendingFeeConfigGasCost uint64 = contract.WriteGasCostPerSlot * (numFeeConfigField + 1) // plus one for clearing the effective timestamp
+)
+
+var (
+	// pendingFeeConfigPrefix prefixes the storage keys of the fields of the
+	// scheduled fee config.
+	pendingFeeConfigPrefix = []byte{'p', 'f', 'c'}
+
+	// pendingFeeConfigEffectiveTimestampKey is the storage key of the effective
+	// timestamp of the scheduled fee config. Zero if no fee config is scheduled.
+	pendingFeeConfigEffectiveTimestampKey = common.Hash{'p', 'e', 't'}
+
+	ErrInvalidEffectiveTimestamp = errors.New("effective timestamp must be after the current block timestamp")
+	ErrNoPendingFeeConfig        = errors.New("no pending fee config")
+)
+
+// ScheduleFeeConfigInput is the ABI struct for the scheduleFeeConfig input.
+type ScheduleFeeConfigInput struct {
+	GasLimit                 *big.Int
+	TargetBlockRate          *big.Int
+	MinBaseFee               *big.Int
+	TargetGas                *big.Int
+	BaseFeeChangeDenominator *big.Int
+	MinBlockGasCost          *big.Int
+	MaxBlockGasCost          *big.Int
+	BlockGasCostStep         *big.Int
+	EffectiveTimestamp       *big.Int
+}
+
+// GetScheduledFeeConfig returns the fee config scheduled in given state, or nil
+// if no fee config is scheduled.
+func GetScheduledFeeConfig(stateDB contract.StateReader) *commontype.ScheduledFeeConfig {
+	effectiveTimestamp := stateDB.GetState(ContractAddress, pendingFeeConfigEffectiveTimestampKey).Big().Uint64()
+	if effectiveTimestamp == 0 {
+		return nil
+	}
+	return &commontype.ScheduledFeeConfig{
+		FeeConfig:          readFeeConfig(stateDB, pendingFeeConfigPrefix),
+		EffectiveTimestamp: effectiveTimestamp,
+	}
+}
+
+// StoreScheduledFeeConfig stores given [scheduled] fee config to the [stateDB],
+// replacing any fee config already scheduled.
+// A validation on the fee config is done before storing.
+func StoreScheduledFeeConfig(stateDB contract.StateDB, scheduled commontype.ScheduledFeeConfig) error {
+	if err := scheduled.FeeConfig.Verify(); err != nil {
+		return fmt.Errorf("cannot verify fee config: %w", err)
+	}
+	if scheduled.EffectiveTimestamp == 0 {
+		return ErrInvalidEffectiveTimestamp
+	}
+
+	writeFeeConfig(stateDB, pendingFeeConfigPrefix, scheduled.FeeConfig)
+	stateDB.SetState(ContractAddress, pendingFeeConfigEffectiveTimestampKey, common.BigToHash(new(big.Int).SetUint64(scheduled.EffectiveTimestamp)))
+	return nil
+}
+
+// clearScheduledFeeConfig removes the scheduled fee config from the [stateDB].
+func clearScheduledFeeConfig(stateDB contract.StateDB) {
+	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
+		stateDB.SetState(ContractAddress, feeConfigFieldKey(pendingFeeConfigPrefix, i), common.Hash{})
+	}
+	stateDB.SetState(ContractAddress, pendingFeeConfigEffectiveTimestampKey, common.Hash{})
+}
+
+// ApplyScheduledFeeConfig stores the scheduled fee config as the fee config if
+// it takes effect by the block in [blockContext], and removes it from the
+// scheduled fee config. This is called before the transactions of every block
+// while the precompile is enabled.
+func ApplyScheduledFeeConfig(stateDB contract.StateDB, blockContext contract.ConfigurationBlockContext) error {
+	scheduled := GetScheduledFeeConfig(stateDB)
+	if scheduled == nil || blockContext.Timestamp() < scheduled.EffectiveTimestamp {
+		return nil
+	}
+	clearScheduledFeeConfig(stateDB)
+	return StoreFeeConfig(stateDB, scheduled.FeeConfig, blockContext)
+}
+
+// PackScheduleFeeConfig packs [input] into the appropriate arguments for scheduleFeeConfig.
+func PackScheduleFeeConfig(input commontype.ScheduledFeeConfig) ([]byte, error) {
+	feeConfig := input.FeeConfig
+	return FeeManagerABI.Pack("scheduleFeeConfig",
+		feeConfig.GasLimit,
+		new(big.Int).SetUint64(feeConfig.TargetBlockRate),
+		feeConfig.MinBaseFee,
+		feeConfig.TargetGas,
+		feeConfig.BaseFeeChangeDenominator,
+		feeConfig.MinBlockGasCost,
+		feeConfig.MaxBlockGasCost,
+		feeConfig.BlockGasCostStep,
+		new(big.Int).SetUint64(input.EffectiveTimestamp),
+	)
+}
+
+// UnpackScheduleFeeConfigInput attempts to unpack [input] as ScheduleFeeConfigInput
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackScheduleFeeConfigInput(input []byte) (commontype.ScheduledFeeConfig, error) {
+	inputStruct := ScheduleFeeConfigInput{}
+	err := FeeManagerABI.UnpackInputIntoInterface(&inputStruct, "scheduleFeeConfig", input, false)
+	if err != nil {
+		return commontype.ScheduledFeeConfig{}, err
+	}
+	if !inputStruct.EffectiveTimestamp.IsUint64() {
+		return commontype.ScheduledFeeConfig{}, fmt.Errorf("%w: %s", ErrInvalidEffectiveTimestamp, inputStruct.EffectiveTimestamp)
+	}
+
+	return commontype.ScheduledFeeConfig{
+		FeeConfig: commontype.FeeConfig{
+			GasLimit:                 inputStruct.GasLimit,
+			TargetBlockRate:          inputStruct.TargetBlockRate.Uint64(),
+			MinBaseFee:               inputStruct.MinBaseFee,
+			TargetGas:                inputStruct.TargetGas,
+			BaseFeeChangeDenominator: inputStruct.BaseFeeChangeDenominator,
+			MinBlockGasCost:          inputStruct.MinBlockGasCost,
+			MaxBlockGasCost:          inputStruct.MaxBlockGasCost,
+			BlockGasCostStep:         inputStruct.BlockGasCostStep,
+		},
+		EffectiveTimestamp: inputStruct.EffectiveTimestamp.Uint64(),
+	}, nil
+}
+
+// scheduleFeeConfig checks if the caller has permissions to set the fee config.
+// The execution function parses [input] into a fee config and the timestamp it
+// takes effect at, and stores it as the scheduled fee config, replacing any fee
+// config already scheduled. The fee config in effect is not modified until the
+// first block with a timestamp of at least the effective timestamp.
+func scheduleFeeConfig(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, ScheduleFeeConfigGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	scheduled, err := UnpackScheduleFeeConfigInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to call this function.
+	callerStatus := allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp())
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotChangeFee, caller)
+	}
+
+	blockContext := accessibleState.GetBlockContext()
+	if scheduled.EffectiveTimestamp <= blockContext.Timestamp() {
+		return nil, remainingGas, fmt.Errorf("%w: %d <= %d", ErrInvalidEffectiveTimestamp, scheduled.EffectiveTimestamp, blockContext.Timestamp())
+	}
+
+	if remainingGas, err = contract.DeductGas(remainingGas, FeeConfigScheduledEventGasCost); err != nil {
+		return nil, 0, err
+	}
+	if err := StoreScheduledFeeConfig(stateDB, scheduled); err != nil {
+		return nil, remainingGas, err
+	}
+
+	topics, data, err := PackFeeConfigScheduledEvent(caller, scheduled)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	stateDB.AddLog(&types.Log{
+		Address:     ContractAddress,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: blockContext.Number().Uint64(),
+	})
+
+	// Return an empty output and the remaining gas
+	return []byte{}, remainingGas, nil
+}
+
+// PackGetPendingFeeConfig packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackGetPendingFeeConfig() ([]byte, error) {
+	return FeeManagerABI.Pack("getPendingFeeConfig")
+}
+
+// PackGetPendingFeeConfigOutput attempts to pack given [output] to conform the ABI outputs.
+// A nil [output] is packed as zero values.
+func PackGetPendingFeeConfigOutput(output *commontype.ScheduledFeeConfig) ([]byte, error) {
+	if output == nil {
+		output = &commontype.ScheduledFeeConfig{
+			FeeConfig: commontype.FeeConfig{
+				GasLimit:                 common.Big0,
+				MinBaseFee:               common.Big0,
+				TargetGas:                common.Big0,
+				BaseFeeChangeDenominator: common.Big0,
+				MinBlockGasCost:          common.Big0,
+				MaxBlockGasCost:          common.Big0,
+				BlockGasCostStep:         common.Big0,
+			},
+		}
+	}
+	feeConfig := output.FeeConfig
+	return FeeManagerABI.PackOutput("getPendingFeeConfig",
+		feeConfig.GasLimit,
+		new(big.Int).SetUint64(feeConfig.TargetBlockRate),
+		feeConfig.MinBaseFee,
+		feeConfig.TargetGas,
+		feeConfig.BaseFeeChangeDenominator,
+		feeConfig.MinBlockGasCost,
+		feeConfig.MaxBlockGasCost,
+		feeConfig.BlockGasCostStep,
+		new(big.Int).SetUint64(output.EffectiveTimestamp),
+	)
+}
+
+// UnpackGetPendingFeeConfigOutput attempts to unpack [output] as GetPendingFeeConfigOutput
+// assumes that [output] does not include selector (omits first 4 func signature bytes)
+// It returns nil if no fee config is pending.
+func UnpackGetPendingFeeConfigOutput(output []byte) (*commontype.ScheduledFeeConfig, error) {
+	outputStruct := ScheduleFeeConfigInput{}
+	err := FeeManagerABI.UnpackIntoInterface(&outputStruct, "getPendingFeeConfig", output)
+	if err != nil {
+		return nil, err
+	}
+	if outputStruct.EffectiveTimestamp.Sign() == 0 {
+		return nil, nil
+	}
+
+	return &commontype.ScheduledFeeConfig{
+		FeeConfig: commontype.FeeConfig{
+			GasLimit:                 outputStruct.GasLimit,
+			TargetBlockRate:          outputStruct.TargetBlockRate.Uint64(),
+			MinBaseFee:               outputStruct.MinBaseFee,
+			TargetGas:                outputStruct.TargetGas,
+			BaseFeeChangeDenominator: outputStruct.BaseFeeChangeDenominator,
+			MinBlockGasCost:          outputStruct.MinBlockGasCost,
+			MaxBlockGasCost:          outputStruct.MaxBlockGasCost,
+			BlockGasCostStep:         outputStruct.BlockGasCostStep,
+		},
+		EffectiveTimestamp: outputStruct.EffectiveTimestamp.Uint64(),
+	}, nil
+}
+
+// getPendingFeeConfig returns the scheduled fee config and its effective timestamp as an output.
+// Zero values are returned if no fee config is scheduled.
+func getPendingFeeConfig(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetPendingFeeConfigGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	scheduled := GetScheduledFeeConfig(accessibleState.GetStateDB())
+	output, err := PackGetPendingFeeConfigOutput(scheduled)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return output, remainingGas, nil
+}
+
+// PackCancelPendingFeeConfig packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackCancelPendingFeeConfig() ([]byte, error) {
+	return FeeManagerABI.Pack("cancelPendingFeeConfig")
+}
+
+// cancelPendingFeeConfig checks if the caller has permissions to set the fee config
+// and removes the scheduled fee config.
+func cancelPendingFeeConfig(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, CancelPendingFeeConfigGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to call this function.
+	callerStatus := allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp())
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotChangeFee, caller)
+	}
+
+	if remainingGas, err = contract.DeductGas(remainingGas, PendingFeeConfigCancelledEventGasCost); err != nil {
+		return nil, 0, err
+	}
+	cancelled := GetScheduledFeeConfig(stateDB)
+	if cancelled == nil {
+		return nil, remainingGas, ErrNoPendingFeeConfig
+	}
+	clearScheduledFeeConfig(stateDB)
+
+	topics, data, err := PackPendingFeeConfigCancelledEvent(caller, *cancelled)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	stateDB.AddLog(&types.Log{
+		Address:     ContractAddress,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: accessibleState.GetBlockContext().Number().Uint64(),
+	})
+
+	// Return an empty output and the remaining gas
+	return []byte{}, remainingGas, nil
+}
@@ -21,6 +21,16 @@ type Config struct {
 	allowlist.AllowListConfig
 	precompileconfig.Upgrade
 	InitialMint map[common.Address]*math.HexOrDecimal256 `json:"initialMint,omitempty"` // addresses to receive the initial mint mapped to the amount to mint
+
+	// MintCap caps the total amount minted after activation, including the
+	// initial mint. It does not account for the rest of the native supply.
+	MintCap *math.HexOrDecimal256 `json:"mintCap,omitempty"`
+	// MinterAllowances limits the amount the given minters can mint.
+	MinterAllowances map[common.Address]*math.HexOrDecimal256 `json:"minterAllowances,omitempty"`
+	// PeriodMintLimit limits the amount minted within every [PeriodSeconds]
+	// seconds, starting at activation.
+	PeriodMintLimit *math.HexOrDecimal256 `json:"periodMintLimit,omitempty"`
+	PeriodSeconds   uint64                `json:"periodSeconds,omitempty"`
 }
 
 // NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
@@ -65,12 +75,23 @@ func (c *Config) Equal(cfg precompileconfig.Config) bool {
 		return false
 	}
 
-	if len(c.InitialMint) != len(other.InitialMint) {
+	if !amountsEqual(c.InitialMint, other.InitialMint) || !amountsEqual(c.MinterAllowances, other.MinterAllowances) {
 		return false
 	}
 
-	for address, amount := range c.InitialMint {
-		val, ok := other.InitialMint[address]
+	return utils.BigNumEqual((*big.Int)(c.MintCap), (*big.Int)(other.MintCap)) &&
+		utils.BigNumEqual((*big.Int)(c.PeriodMintLimit), (*big.Int)(other.PeriodMintLimit)) &&
+		c.PeriodSeconds == other.PeriodSeconds
+}
+
+// amountsEqual returns true if [a] and [b] map the same addresses to equal amounts.
+func amountsEqual(a, b map[common.Address]*math.HexOrDecimal256) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for address, amount := range a {
+		val, ok := b[address]
 		if !ok {
 			return false
 		}
@@ -86,6 +107,7 @@ func (c *Config) Equal(cfg precompileconfig.Config) bool {
 
 func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
 	// ensure that all of the initial mint values in the map are non-nil positive values
+	initialMintTotal := new(big.Int)
 	for addr, amount := range c.InitialMint {
 		if amount == nil {
 			return fmt.Errorf("initial mint cannot contain nil amount for address %s", addr)
@@ -94,6 +116,43 @@ func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
 		if bigIntAmount.Sign() < 1 {
 			return fmt.Errorf("initial mint cannot contain invalid amount %v for address %s", bigIntAmount, addr)
 		}
+		initialMintTotal.Add(initialMintTotal, bigIntAmount)
+	}
+	if c.MintCap != nil {
+		mintCap := (*big.Int)(c.MintCap)
+		if mintCap.Sign() < 1 || mintCap.BitLen() > 256 {
+			return fmt.Errorf("invalid mint cap %v", mintCap)
+		}
+		if initialMintTotal.Cmp(mintCap) > 0 {
+			return fmt.Errorf("initial mint of %v exceeds mint cap %v", initialMintTotal, mintCap)
+		}
+	}
+	// ensure that all of the minter allowances are non-nil non-negative values
+	for addr, allowance := range c.MinterAllowances {
+		if allowance == nil {
+			return fmt.Errorf("minter allowances cannot contain nil allowance for address %s", addr)
+		}
+		bigIntAllowance := (*big.Int)(allowance)
+		if bigIntAllowance.Sign() < 0 || bigIntAllowance.BitLen() > 256 {
+			return fmt.Errorf("minter allowances cannot contain invalid allowance %v for address %s", bigIntAllowance, addr)
+		}
+	}
+	if c.PeriodMintLimit != nil {
+		periodMintLimit := (*big.Int)(c.PeriodMintLimit)
+		if periodMintLimit.Sign() < 1 || periodMintLimit.BitLen() > 256 {
+			return fmt.Errorf("invalid period mint limit %v", periodMintLimit)
+		}
+		if c.PeriodSeconds == 0 {
+			return fmt.Errorf("period mint limit requires a non-zero period")
+		}
+	} else if c.PeriodSeconds != 0 {
+		return fmt.Errorf("period of %d seconds set without a period mint limit", c.PeriodSeconds)
+	}
+	if (c.MintCap != nil || len(c.MinterAllowances) != 0 || c.PeriodMintLimit != nil) && c.Timestamp() != nil {
+		// Mint limits are only enforced after Granite
+		if !chainConfig.IsGranite(*c.Timestamp()) {
+			return fmt.Errorf("cannot set mint limits before Granite")
+		}
 	}
 	return c.AllowListConfig.Verify(chainConfig, c.Upgrade)
 }
@@ -56,6 +56,50 @@ func TestVerify(t *testing.T) {
 				}),
 			ExpectedError: "initial mint cannot contain invalid amount",
 		},
+		"valid mint limits": {
+			Config: withMintLimits(NewConfig(utils.NewUint64(3), admins, enableds, nil,
+				map[common.Address]*math.HexOrDecimal256{
+					common.HexToAddress("0x01"): math.NewHexOrDecimal256(100),
+				}), 100, map[common.Address]*math.HexOrDecimal256{
+				allowlist.TestEnabledAddr: math.NewHexOrDecimal256(0),
+			}, 10, 60),
+			ExpectedError: "",
+		},
+		"initial mint exceeds mint cap": {
+			Config: withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil,
+				map[common.Address]*math.HexOrDecimal256{
+					common.HexToAddress("0x01"): math.NewHexOrDecimal256(60),
+					common.HexToAddress("0x02"): math.NewHexOrDecimal256(50),
+				}), 100, nil, 0, 0),
+			ExpectedError: "exceeds mint cap",
+		},
+		"zero mint cap": {
+			Config:        withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), 0, nil, 0, 0),
+			ExpectedError: "invalid mint cap",
+		},
+		"negative minter allowance": {
+			Config: withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), -1, map[common.Address]*math.HexOrDecimal256{
+				allowlist.TestEnabledAddr: math.NewHexOrDecimal256(-1),
+			}, 0, 0),
+			ExpectedError: "minter allowances cannot contain invalid allowance",
+		},
+		"period mint limit without period": {
+			Config:        withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), -1, nil, 10, 0),
+			ExpectedError: "period mint limit requires a non-zero period",
+		},
+		"period without period mint limit": {
+			Config:        withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), -1, nil, -1, 60),
+			ExpectedError: "set without a period mint limit",
+		},
+		"mint limits before Granite": {
+			Config: withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), 100, nil, -1, 0),
+			ChainConfig: func() precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(gomock.NewController(t))
+				config.EXPECT().IsGranite(gomock.Any()).Return(false)
+				return config
+			}(),
+			ExpectedError: "cannot set mint limits before Granite",
+		},
 	}
 	allowlist.VerifyPrecompileWithAllowListTests(t, Module, tests)
 }
@@ -102,6 +146,32 @@ func TestEqual(t *testing.T) {
 				}),
 			Expected: false,
 		},
+		"different mint cap": {
+			Config:   withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), 100, nil, -1, 0),
+			Other:    withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), 101, nil, -1, 0),
+			Expected: false,
+		},
+		"different minter allowances": {
+			Config: withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), -1, map[common.Address]*math.HexOrDecimal256{
+				common.HexToAddress("0x01"): math.NewHexOrDecimal256(1),
+			}, -1, 0),
+			Other:    NewConfig(utils.NewUint64(3), admins, nil, nil, nil),
+			Expected: false,
+		},
+		"different period": {
+			Config:   withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), -1, nil, 10, 60),
+			Other:    withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), -1, nil, 10, 61),
+			Expected: false,
+		},
+		"same mint limits": {
+			Config: withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), 100, map[common.Address]*math.HexOrDecimal256{
+				common.HexToAddress("0x01"): math.NewHexOrDecimal256(1),
+			}, 10, 60),
+			Other: withMintLimits(NewConfig(utils.NewUint64(3), admins, nil, nil, nil), 100, map[common.Address]*math.HexOrDecimal256{
+				common.HexToAddress("0x01"): math.NewHexOrDecimal256(1),
+			}, 10, 60),
+			Expected: true,
+		},
 		"same config": {
 			Config: NewConfig(utils.NewUint64(3), admins, nil, nil,
 				map[common.Address]*math.HexOrDecimal256{
@@ -116,3 +186,17 @@ func TestEqual(t *testing.T) {
 	}
 	allowlist.EqualPrecompileWithAllowListTests(t, Module, tests)
 }
+
+// withMintLimits sets the mint limits of [config], where a negative [mintCap]
+// or [periodMintLimit] leaves the limit unset.
+func withMintLimits(config *Config, mintCap int64, minterAllowances map[common.Address]*math.HexOrDecimal256, periodMintLimit int64, periodSeconds uint64) *Config {
+	if mintCap >= 0 {
+		config.MintCap = math.NewHexOrDecimal256(mintCap)
+	}
+	config.MinterAllowances = minterAllowances
+	if periodMintLimit >= 0 {
+		config.PeriodMintLimit = math.NewHexOrDecimal256(periodMintLimit)
+	}
+	config.PeriodSeconds = periodSeconds
+	return config
+}
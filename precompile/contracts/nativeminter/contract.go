@@ -75,7 +75,8 @@ func UnpackMintNativeCoinInput(input []byte, useStrictMode bool) (common.Address
 	return inputStruct.Addr, inputStruct.Amount, err
 }
 
-// mintNativeCoin checks if the caller is permissioned for minting operation.
+// mintNativeCoin checks if the caller is permissioned for minting operation, and that the
+// mint is within the mint limits.
 // The execution function parses the [input] into native coin amount and receiver address.
 func mintNativeCoin(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
 	if remainingGas, err = contract.DeductGas(suppliedGas, MintGasCost); err != nil {
@@ -94,11 +95,18 @@ func mintNativeCoin(accessibleState contract.AccessibleState, caller common.Addr
 
 	stateDB := accessibleState.GetStateDB()
 	// Verify that the caller is in the allow list and therefore has the right to call this function.
-	callerStatus := allowlist.GetAllowListStatus(stateDB, ContractAddress, caller)
+	callerStatus := allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp())
 	if !callerStatus.IsEnabled() {
 		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotMint, caller)
 	}
 
+	// Mint limits are only enforced after Granite
+	if contract.IsGraniteActivated(accessibleState) {
+		if remainingGas, err = recordMint(stateDB, accessibleState.GetBlockContext().Timestamp(), caller, amount, remainingGas); err != nil {
+			return nil, remainingGas, err
+		}
+	}
+
 	if contract.IsDurangoActivated(accessibleState) {
 		if remainingGas, err = contract.DeductGas(remainingGas, NativeCoinMintedEventGasCost); err != nil {
 			return nil, 0, err
@@ -134,6 +142,16 @@ func createNativeMinterPrecompile() contract.StatefulPrecompiledContract {
 	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
 		"mintNativeCoin": mintNativeCoin,
 	}
+	// Mint limits are only available after Granite
+	graniteFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"setMintCap":            setMintCap,
+		"getMintCap":            getMintCap,
+		"setMinterAllowance":    setMinterAllowance,
+		"removeMinterAllowance": removeMinterAllowance,
+		"getMinterAllowance":    getMinterAllowance,
+		"setPeriodMintLimit":    setPeriodMintLimit,
+		"getPeriodMintLimit":    getPeriodMintLimit,
+	}
 
 	for name, function := range abiFunctionMap {
 		method, ok := NativeMinterABI.Methods[name]
@@ -142,6 +160,13 @@ func createNativeMinterPrecompile() contract.StatefulPrecompiledContract {
 		}
 		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
 	}
+	for name, function := range graniteFunctionMap {
+		method, ok := NativeMinterABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunctionWithActivator(method.ID, function, contract.IsGraniteActivated))
+	}
 	// Construct the contract with no fallback function.
 	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
 	if err != nil {
@@ -6,6 +6,7 @@ package nativeminter
 import (
 	"math/big"
 	"testing"
+	"time"
 	"github.com/luxfi/evm/core/state"
 	"github.com/luxfi/evm/precompile/allowlist"
 	"github.com/luxfi/evm/precompile/contract"
@@ -42,7 +43,7 @@ var (
 
 				return input
 			},
-			SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost,
+			SuppliedGas: MintGasCost + MintLimitsGasCost + NativeCoinMintedEventGasCost,
 			ReadOnly:    false,
 			ExpectedRes: []byte{},
 			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
@@ -75,7 +76,7 @@ var (
 
 				return input
 			},
-			SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost,
+			SuppliedGas: MintGasCost + MintLimitsGasCost + NativeCoinMintedEventGasCost,
 			ReadOnly:    false,
 			ExpectedRes: []byte{},
 			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
@@ -95,7 +96,7 @@ var (
 
 				return input
 			},
-			SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost,
+			SuppliedGas: MintGasCost + MintLimitsGasCost + NativeCoinMintedEventGasCost,
 			ReadOnly:    false,
 			ExpectedRes: []byte{},
 			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
@@ -115,7 +116,7 @@ var (
 
 				return input
 			},
-			SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost,
+			SuppliedGas: MintGasCost + MintLimitsGasCost + NativeCoinMintedEventGasCost,
 			ReadOnly:    false,
 			ExpectedRes: []byte{},
 			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
@@ -174,7 +175,7 @@ var (
 
 				return input
 			},
-			SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost - 1,
+			SuppliedGas: MintGasCost + MintLimitsGasCost + NativeCoinMintedEventGasCost - 1,
 			ReadOnly:    false,
 			ExpectedErr: vm.ErrOutOfGas.Error(),
 		},
@@ -184,6 +185,7 @@ var (
 			ChainConfigFn: func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
 				config := precompileconfig.NewMockChainConfig(ctrl)
 				config.EXPECT().IsDurango(gomock.Any()).Return(false).AnyTimes()
+				config.EXPECT().IsGranite(gomock.Any()).Return(false).AnyTimes()
 				return config
 			},
 			InputFn: func(t testing.TB) []byte {
@@ -207,6 +209,7 @@ var (
 			ChainConfigFn: func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
 				config := precompileconfig.NewMockChainConfig(ctrl)
 				config.EXPECT().IsDurango(gomock.Any()).Return(false).AnyTimes()
+				config.EXPECT().IsGranite(gomock.Any()).Return(false).AnyTimes()
 				return config
 			},
 			InputFn: func(t testing.TB) []byte {
@@ -228,6 +231,7 @@ var (
 			ChainConfigFn: func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
 				config := precompileconfig.NewMockChainConfig(ctrl)
 				config.EXPECT().IsDurango(gomock.Any()).Return(true).AnyTimes()
+				config.EXPECT().IsGranite(gomock.Any()).Return(false).AnyTimes()
 				return config
 			},
 			InputFn: func(t testing.TB) []byte {
@@ -250,6 +254,476 @@ var (
 				assertNativeCoinMintedEvent(t, logsTopics, logsData, allowlist.TestEnabledAddr, allowlist.TestEnabledAddr, common.Big1)
 			},
 		},
+		"mint within mint cap should succeed": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) { StoreMintCap(stateDB, common.Big2) }),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + MintLimitsGasCost + NativeCoinMintedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				expected := uint256.MustFromBig(common.Big2)
+				require.Equal(t, expected, stateDB.GetBalance(allowlist.TestEnabledAddr), "expected minted funds")
+
+				_, totalMinted := GetMintCap(stateDB)
+				require.Equal(t, common.Big2, totalMinted)
+			},
+		},
+		"mint exceeding mint cap should fail": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) { StoreMintCap(stateDB, common.Big1) }),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + MintLimitsGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrMintCapExceeded.Error(),
+		},
+		"mint within minter allowance should succeed": {
+			Caller: allowlist.TestEnabledAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				StoreMinterAllowance(stateDB, allowlist.TestEnabledAddr, true, common.Big3)
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + MintLimitsGasCost + MinterAllowanceMintGasCost + NativeCoinMintedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				limited, allowance := GetMinterAllowance(stateDB, allowlist.TestEnabledAddr)
+				require.True(t, limited)
+				require.Equal(t, common.Big1, allowance)
+			},
+		},
+		"mint exceeding minter allowance should fail": {
+			Caller: allowlist.TestEnabledAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				StoreMinterAllowance(stateDB, allowlist.TestEnabledAddr, true, common.Big1)
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + MintLimitsGasCost + MinterAllowanceMintGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrMinterAllowanceExceeded.Error(),
+		},
+		"minter allowance of another minter is not charged": {
+			Caller: allowlist.TestAdminAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				StoreMinterAllowance(stateDB, allowlist.TestEnabledAddr, true, common.Big0)
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + MintLimitsGasCost + NativeCoinMintedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+		},
+		"mint exceeding period mint limit should fail": {
+			Caller: allowlist.TestEnabledAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				StorePeriodMintLimit(stateDB, common.Big1, 60, uint64(time.Now().Unix()))
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + MintLimitsGasCost + PeriodMintLimitMintGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrPeriodMintLimitExceeded.Error(),
+		},
+		"mint after period mint limit resets should succeed": {
+			Caller: allowlist.TestEnabledAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				StorePeriodMintLimit(stateDB, common.Big2, 60, 0)
+				stateDB.SetState(ContractAddress, periodMintedKey, common.BigToHash(common.Big2))
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + MintLimitsGasCost + PeriodMintLimitMintGasCost + NativeCoinMintedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				period := GetPeriodMintLimit(stateDB)
+				require.NotZero(t, period.PeriodStart)
+				require.Equal(t, common.Big2, period.PeriodMinted)
+			},
+		},
+		"initial mint counts towards mint cap": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			Config: &Config{
+				InitialMint: map[common.Address]*math.HexOrDecimal256{
+					allowlist.TestEnabledAddr: math.NewHexOrDecimal256(2),
+				},
+				MintCap: math.NewHexOrDecimal256(3),
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + MintLimitsGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrMintCapExceeded.Error(),
+		},
+		"mints while the mint cap is removed count towards it": {
+			Caller: allowlist.TestEnabledAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				// Mint 2 under a cap of 3, and 1 more without a cap.
+				StoreMintCap(stateDB, common.Big3)
+				_, err := recordMint(stateDB, 0, allowlist.TestEnabledAddr, common.Big2, MintLimitsGasCost)
+				if err != nil {
+					panic(err)
+				}
+				StoreMintCap(stateDB, common.Big0)
+				_, err = recordMint(stateDB, 0, allowlist.TestEnabledAddr, common.Big1, MintLimitsGasCost)
+				if err != nil {
+					panic(err)
+				}
+				StoreMintCap(stateDB, big.NewInt(4))
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + MintLimitsGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrMintCapExceeded.Error(),
+		},
+		"set mint cap keeps the total minted": {
+			Caller: allowlist.TestAdminAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				stateDB.SetState(ContractAddress, totalMintedKey, common.BigToHash(common.Big2))
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetMintCap(common.Big3)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetMintCapGasCost + MintCapChangedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				mintCap, totalMinted := GetMintCap(stateDB)
+				require.Equal(t, common.Big3, mintCap)
+				require.Equal(t, common.Big2, totalMinted)
+			},
+		},
+		"mint is not recorded pre-Granite": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) { StoreMintCap(stateDB, common.Big1) }),
+			ChainConfigFn: func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(ctrl)
+				config.EXPECT().IsDurango(gomock.Any()).Return(true).AnyTimes()
+				config.EXPECT().IsGranite(gomock.Any()).Return(false).AnyTimes()
+				return config
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackMintNativeCoin(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: MintGasCost + NativeCoinMintedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				_, totalMinted := GetMintCap(stateDB)
+				require.Zero(t, totalMinted.Sign())
+			},
+		},
+		"set mint cap pre-Granite should fail": {
+			Caller:     allowlist.TestAdminAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			ChainConfigFn: func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(ctrl)
+				config.EXPECT().IsDurango(gomock.Any()).Return(true).AnyTimes()
+				config.EXPECT().IsGranite(gomock.Any()).Return(false).AnyTimes()
+				return config
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetMintCap(common.Big3)
+				require.NoError(t, err)
+
+				return input
+			},
+			// Non-activated functions return the supplied gas
+			SuppliedGas: 0,
+			ReadOnly:    false,
+			ExpectedErr: "invalid non-activated function selector",
+		},
+		"get period mint limit pre-Granite should fail": {
+			Caller:     allowlist.TestNoRoleAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			ChainConfigFn: func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
+				config := precompileconfig.NewMockChainConfig(ctrl)
+				config.EXPECT().IsDurango(gomock.Any()).Return(true).AnyTimes()
+				config.EXPECT().IsGranite(gomock.Any()).Return(false).AnyTimes()
+				return config
+			},
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetPeriodMintLimit()
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: 0,
+			ReadOnly:    true,
+			ExpectedErr: "invalid non-activated function selector",
+		},
+		"set mint cap from Admin should succeed": {
+			Caller:     allowlist.TestAdminAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetMintCap(common.Big3)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetMintCapGasCost + MintCapChangedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				mintCap, totalMinted := GetMintCap(stateDB)
+				require.Equal(t, common.Big3, mintCap)
+				require.Zero(t, totalMinted.Sign())
+
+				logsTopics, logsData := stateDB.GetLogData()
+				require.Len(t, logsTopics, 1)
+				require.Equal(t, NativeMinterABI.Events["MintCapChanged"].ID, logsTopics[0][0])
+				oldCap, newCap, err := UnpackMintCapChangedEventData(logsData[0])
+				require.NoError(t, err)
+				require.Zero(t, oldCap.Sign())
+				require.Equal(t, common.Big3, newCap)
+			},
+		},
+		"set mint cap from Enabled should fail": {
+			Caller:     allowlist.TestEnabledAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetMintCap(common.Big3)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetMintCapGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotSetMintLimits.Error(),
+		},
+		"readOnly set mint cap fails": {
+			Caller:     allowlist.TestAdminAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetMintCap(common.Big3)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetMintCapGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vm.ErrWriteProtection.Error(),
+		},
+		"get mint cap": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				StoreMintCap(stateDB, common.Big3)
+				stateDB.SetState(ContractAddress, totalMintedKey, common.BigToHash(common.Big1))
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetMintCap()
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetMintCapGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetMintCapOutput(common.Big3, common.Big1)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"set minter allowance from Admin should succeed": {
+			Caller:     allowlist.TestAdminAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetMinterAllowance(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetMinterAllowanceGasCost + MinterAllowanceChangedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				limited, allowance := GetMinterAllowance(stateDB, allowlist.TestEnabledAddr)
+				require.True(t, limited)
+				require.Equal(t, common.Big2, allowance)
+
+				logsTopics, logsData := stateDB.GetLogData()
+				require.Len(t, logsTopics, 1)
+				require.Equal(t, []common.Hash{
+					NativeMinterABI.Events["MinterAllowanceChanged"].ID,
+					common.BytesToHash(allowlist.TestAdminAddr[:]),
+					common.BytesToHash(allowlist.TestEnabledAddr[:]),
+				}, logsTopics[0])
+				limited, allowance, err := UnpackMinterAllowanceChangedEventData(logsData[0])
+				require.NoError(t, err)
+				require.True(t, limited)
+				require.Equal(t, common.Big2, allowance)
+			},
+		},
+		"remove minter allowance from Admin should succeed": {
+			Caller: allowlist.TestAdminAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				StoreMinterAllowance(stateDB, allowlist.TestEnabledAddr, true, common.Big2)
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackRemoveMinterAllowance(allowlist.TestEnabledAddr)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetMinterAllowanceGasCost + MinterAllowanceChangedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				limited, _ := GetMinterAllowance(stateDB, allowlist.TestEnabledAddr)
+				require.False(t, limited)
+			},
+		},
+		"set minter allowance from Manager should fail": {
+			Caller:     allowlist.TestManagerAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetMinterAllowance(allowlist.TestEnabledAddr, common.Big2)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetMinterAllowanceGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrCannotSetMintLimits.Error(),
+		},
+		"get minter allowance": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				StoreMinterAllowance(stateDB, allowlist.TestEnabledAddr, true, common.Big2)
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetMinterAllowance(allowlist.TestEnabledAddr)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetMinterAllowanceGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetMinterAllowanceOutput(true, common.Big2)
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"set period mint limit from Admin should succeed": {
+			Caller:     allowlist.TestAdminAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetPeriodMintLimit(common.Big2, 60)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetPeriodMintLimitGasCost + PeriodMintLimitChangedEventGasCost,
+			ReadOnly:    false,
+			ExpectedRes: []byte{},
+			AfterHook: func(t testing.TB, stateDB contract.StateDB) {
+				period := GetPeriodMintLimit(stateDB)
+				require.Equal(t, common.Big2, period.Limit)
+				require.Equal(t, uint64(60), period.PeriodSeconds)
+				require.NotZero(t, period.PeriodStart)
+				require.Zero(t, period.PeriodMinted.Sign())
+
+				logsTopics, logsData := stateDB.GetLogData()
+				require.Len(t, logsTopics, 1)
+				require.Equal(t, NativeMinterABI.Events["PeriodMintLimitChanged"].ID, logsTopics[0][0])
+				limit, periodSeconds, err := UnpackPeriodMintLimitChangedEventData(logsData[0])
+				require.NoError(t, err)
+				require.Equal(t, common.Big2, limit)
+				require.Equal(t, uint64(60), periodSeconds)
+			},
+		},
+		"set period mint limit with zero period should fail": {
+			Caller:     allowlist.TestAdminAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackSetPeriodMintLimit(common.Big2, 0)
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: SetPeriodMintLimitGasCost,
+			ReadOnly:    false,
+			ExpectedErr: ErrInvalidPeriodMintLimit.Error(),
+		},
+		"get period mint limit": {
+			Caller: allowlist.TestNoRoleAddr,
+			BeforeHook: withMintLimitsHook(func(stateDB contract.StateDB) {
+				StorePeriodMintLimit(stateDB, common.Big2, 60, 10)
+			}),
+			InputFn: func(t testing.TB) []byte {
+				input, err := PackGetPeriodMintLimit()
+				require.NoError(t, err)
+
+				return input
+			},
+			SuppliedGas: GetPeriodMintLimitGasCost,
+			ReadOnly:    true,
+			ExpectedRes: func() []byte {
+				res, err := PackGetPeriodMintLimitOutput(PeriodMintLimit{
+					Limit:         common.Big2,
+					PeriodSeconds: 60,
+					PeriodStart:   10,
+					PeriodMinted:  common.Big0,
+				})
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
 	}
 )
 
@@ -280,3 +754,12 @@ func assertNativeCoinMintedEvent(t testing.TB,
 	require.NoError(t, err)
 	require.True(t, expectedAmount.Cmp(amount) == 0, "expected", expectedAmount, "got", amount)
 }
+
+// withMintLimitsHook returns a BeforeHook setting the default roles and
+// storing mint limits with [storeLimits].
+func withMintLimitsHook(storeLimits func(stateDB contract.StateDB)) func(t testing.TB, stateDB contract.StateDB) {
+	return func(t testing.TB, stateDB contract.StateDB) {
+		allowlist.SetDefaultRoles(Module.Address)(t, stateDB)
+		storeLimits(stateDB)
+	}
+}
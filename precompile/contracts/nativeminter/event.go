@@ -31,3 +31,71 @@ func UnpackNativeCoinMintedEventData(dataBytes []byte) (*big.Int, error) {
 	err := NativeMinterABI.UnpackIntoInterface(&eventData, "NativeCoinMinted", dataBytes)
 	return eventData.Amount, err
 }
+
+const (
+	// MintCapChangedEventGasCost is the gas cost of the MintCapChanged event.
+	// It is the base gas cost + the gas cost of the topics (signature, sender)
+	// and the gas cost of the non-indexed data (32 bytes for each of oldCap and newCap).
+	MintCapChangedEventGasCost = contract.LogGas + contract.LogTopicGas*2 + contract.LogDataGas*2*common.HashLength
+
+	// MinterAllowanceChangedEventGasCost is the gas cost of the MinterAllowanceChanged event.
+	// It is the base gas cost + the gas cost of the topics (signature, sender, minter)
+	// and the gas cost of the non-indexed data (32 bytes for each of limited and allowance).
+	MinterAllowanceChangedEventGasCost = contract.LogGas + contract.LogTopicGas*3 + contract.LogDataGas*2*common.HashLength
+
+	// PeriodMintLimitChangedEventGasCost is the gas cost of the PeriodMintLimitChanged event.
+	// It is the base gas cost + the gas cost of the topics (signature, sender)
+	// and the gas cost of the non-indexed data (32 bytes for each of limit and periodSeconds).
+	PeriodMintLimitChangedEventGasCost = contract.LogGas + contract.LogTopicGas*2 + contract.LogDataGas*2*common.HashLength
+)
+
+// PackMintCapChangedEvent packs the event into the appropriate arguments for MintCapChanged.
+// It returns topic hashes and the encoded non-indexed data.
+func PackMintCapChangedEvent(sender common.Address, oldCap *big.Int, newCap *big.Int) ([]common.Hash, []byte, error) {
+	return NativeMinterABI.PackEvent("MintCapChanged", sender, oldCap, newCap)
+}
+
+// UnpackMintCapChangedEventData attempts to unpack non-indexed [dataBytes].
+func UnpackMintCapChangedEventData(dataBytes []byte) (*big.Int, *big.Int, error) {
+	var eventData = struct {
+		OldCap *big.Int
+		NewCap *big.Int
+	}{}
+	err := NativeMinterABI.UnpackIntoInterface(&eventData, "MintCapChanged", dataBytes)
+	return eventData.OldCap, eventData.NewCap, err
+}
+
+// PackMinterAllowanceChangedEvent packs the event into the appropriate arguments for MinterAllowanceChanged.
+// It returns topic hashes and the encoded non-indexed data.
+func PackMinterAllowanceChangedEvent(sender common.Address, minter common.Address, limited bool, allowance *big.Int) ([]common.Hash, []byte, error) {
+	return NativeMinterABI.PackEvent("MinterAllowanceChanged", sender, minter, limited, allowance)
+}
+
+// UnpackMinterAllowanceChangedEventData attempts to unpack non-indexed [dataBytes].
+func UnpackMinterAllowanceChangedEventData(dataBytes []byte) (bool, *big.Int, error) {
+	var eventData = struct {
+		Limited   bool
+		Allowance *big.Int
+	}{}
+	err := NativeMinterABI.UnpackIntoInterface(&eventData, "MinterAllowanceChanged", dataBytes)
+	return eventData.Limited, eventData.Allowance, err
+}
+
+// PackPeriodMintLimitChangedEvent packs the event into the appropriate arguments for PeriodMintLimitChanged.
+// It returns topic hashes and the encoded non-indexed data.
+func PackPeriodMintLimitChangedEvent(sender common.Address, limit *big.Int, periodSeconds uint64) ([]common.Hash, []byte, error) {
+	return NativeMinterABI.PackEvent("PeriodMintLimitChanged", sender, limit, new(big.Int).SetUint64(periodSeconds))
+}
+
+// UnpackPeriodMintLimitChangedEventData attempts to unpack non-indexed [dataBytes].
+func UnpackPeriodMintLimitChangedEventData(dataBytes []byte) (*big.Int, uint64, error) {
+	var eventData = struct {
+		Limit         *big.Int
+		PeriodSeconds *big.Int
+	}{}
+	err := NativeMinterABI.UnpackIntoInterface(&eventData, "PeriodMintLimitChanged", dataBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+	return eventData.Limit, eventData.PeriodSeconds.Uint64(), nil
+}
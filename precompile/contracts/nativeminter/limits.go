@@ -0,0 +1,535 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nativeminter
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/evm/accounts/abi"
+	"github.com/luxfi/evm/precompile/allowlist"
+	"github.com/luxfi/evm/precompile/contract"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/math"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/luxfi/geth/crypto"
+)
+
+const (
+	// Charged on top of MintGasCost after Granite, for reading the mint cap,
+	// the total minted, whether the minter is limited and the period mint limit,
+	// and recording the mint in the total minted.
+	MintLimitsGasCost uint64 = contract.ReadGasCostPerSlot*4 + contract.WriteGasCostPerSlot
+	// Charged on top of MintLimitsGasCost for each limit set, for reading the
+	// rest of the limit and recording the mint against it.
+	MinterAllowanceMintGasCost uint64 = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+	PeriodMintLimitMintGasCost uint64 = contract.ReadGasCostPerSlot*3 + contract.WriteGasCostPerSlot*2
+
+	SetMintCapGasCost         uint64 = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot // read the old cap, write the cap
+	GetMintCapGasCost         uint64 = contract.ReadGasCostPerSlot * 2
+	SetMinterAllowanceGasCost uint64 = contract.WriteGasCostPerSlot * 2
+	GetMinterAllowanceGasCost uint64 = contract.ReadGasCostPerSlot * 2
+	SetPeriodMintLimitGasCost uint64 = contract.WriteGasCostPerSlot * 4
+	GetPeriodMintLimitGasCost uint64 = contract.ReadGasCostPerSlot * 4
+)
+
+var (
+	// Storage keys of the mint limits. They start with a non-zero byte, so that
+	// they do not collide with the allow list keys, which are padded addresses.
+	mintCapKey       = common.Hash{'m', 'c'}
+	totalMintedKey   = common.Hash{'t', 'm'}
+	periodLimitKey   = common.Hash{'p', 'l'}
+	periodSecondsKey = common.Hash{'p', 's'}
+	periodStartKey   = common.Hash{'p', 'b'}
+	periodMintedKey  = common.Hash{'p', 'm'}
+
+	minterAllowanceKeyPrefix = []byte("minterAllowance")
+	minterLimitedKeyPrefix   = []byte("minterLimited")
+
+	ErrCannotSetMintLimits     = errors.New("non-admin cannot set mint limits")
+	ErrMintCapExceeded         = errors.New("mint exceeds mint cap")
+	ErrMinterAllowanceExceeded = errors.New("mint exceeds minter allowance")
+	ErrPeriodMintLimitExceeded = errors.New("mint exceeds period mint limit")
+	ErrInvalidPeriodMintLimit  = errors.New("invalid period mint limit")
+)
+
+// PeriodMintLimit is the limit on the amount minted within a period of
+// [PeriodSeconds] seconds, and the amount minted in the current period.
+type PeriodMintLimit struct {
+	Limit         *big.Int
+	PeriodSeconds uint64
+	PeriodStart   uint64
+	PeriodMinted  *big.Int
+}
+
+func minterAllowanceKey(minter common.Address) common.Hash {
+	return crypto.Keccak256Hash(minterAllowanceKeyPrefix, minter.Bytes())
+}
+
+func minterLimitedKey(minter common.Address) common.Hash {
+	return crypto.Keccak256Hash(minterLimitedKeyPrefix, minter.Bytes())
+}
+
+// GetMintCap returns the mint cap and the total amount minted since Granite,
+// including the initial mints of activations after Granite. The cap bounds the
+// amount minted by the precompile, not the native supply, which also includes
+// the genesis allocation and the amounts minted before Granite. A zero mint cap
+// means no cap is set.
+func GetMintCap(stateDB contract.StateReader) (*big.Int, *big.Int) {
+	mintCap := stateDB.GetState(ContractAddress, mintCapKey).Big()
+	totalMinted := stateDB.GetState(ContractAddress, totalMintedKey).Big()
+	return mintCap, totalMinted
+}
+
+// StoreMintCap sets the mint cap to [mintCap], where zero removes the cap.
+// The total amount minted is tracked whether a cap is set or not, so it is kept
+// when the cap is set or removed.
+func StoreMintCap(stateDB contract.StateDB, mintCap *big.Int) {
+	stateDB.SetState(ContractAddress, mintCapKey, common.BigToHash(mintCap))
+}
+
+// addTotalMinted adds [amount] to the total minted in [stateDB] and returns the
+// new total. The total saturates at the maximum uint256, which is above any cap.
+func addTotalMinted(stateDB contract.StateDB, amount *big.Int) *big.Int {
+	totalMinted := stateDB.GetState(ContractAddress, totalMintedKey).Big()
+	totalMinted.Add(totalMinted, amount)
+	if totalMinted.Cmp(math.MaxBig256) > 0 {
+		totalMinted.Set(math.MaxBig256)
+	}
+	stateDB.SetState(ContractAddress, totalMintedKey, common.BigToHash(totalMinted))
+	return totalMinted
+}
+
+// GetMinterAllowance returns whether the amount [minter] can mint is limited,
+// and the amount it can still mint if so.
+func GetMinterAllowance(stateDB contract.StateReader, minter common.Address) (bool, *big.Int) {
+	limited := stateDB.GetState(ContractAddress, minterLimitedKey(minter)) != common.Hash{}
+	allowance := stateDB.GetState(ContractAddress, minterAllowanceKey(minter)).Big()
+	return limited, allowance
+}
+
+// StoreMinterAllowance limits the amount [minter] can mint to [allowance] if
+// [limited], and removes its limit otherwise.
+func StoreMinterAllowance(stateDB contract.StateDB, minter common.Address, limited bool, allowance *big.Int) {
+	var limitedValue, allowanceValue common.Hash
+	if limited {
+		limitedValue = common.BigToHash(common.Big1)
+		allowanceValue = common.BigToHash(allowance)
+	}
+	stateDB.SetState(ContractAddress, minterLimitedKey(minter), limitedValue)
+	stateDB.SetState(ContractAddress, minterAllowanceKey(minter), allowanceValue)
+}
+
+// GetPeriodMintLimit returns the period mint limit. A zero limit means no limit
+// is set.
+func GetPeriodMintLimit(stateDB contract.StateReader) PeriodMintLimit {
+	return PeriodMintLimit{
+		Limit:         stateDB.GetState(ContractAddress, periodLimitKey).Big(),
+		PeriodSeconds: stateDB.GetState(ContractAddress, periodSecondsKey).Big().Uint64(),
+		PeriodStart:   stateDB.GetState(ContractAddress, periodStartKey).Big().Uint64(),
+		PeriodMinted:  stateDB.GetState(ContractAddress, periodMintedKey).Big(),
+	}
+}
+
+// StorePeriodMintLimit limits the amount minted within [periodSeconds] to
+// [limit], where a zero limit removes the limit. The first period starts at
+// [timestamp].
+func StorePeriodMintLimit(stateDB contract.StateDB, limit *big.Int, periodSeconds uint64, timestamp uint64) {
+	if limit.Sign() == 0 {
+		periodSeconds, timestamp = 0, 0
+	}
+	stateDB.SetState(ContractAddress, periodLimitKey, common.BigToHash(limit))
+	stateDB.SetState(ContractAddress, periodSecondsKey, common.BigToHash(new(big.Int).SetUint64(periodSeconds)))
+	stateDB.SetState(ContractAddress, periodStartKey, common.BigToHash(new(big.Int).SetUint64(timestamp)))
+	stateDB.SetState(ContractAddress, periodMintedKey, common.Hash{})
+}
+
+// recordMint checks that [minter] minting [amount] at [timestamp] is within the
+// mint limits, and records the mint against them.
+func recordMint(stateDB contract.StateDB, timestamp uint64, minter common.Address, amount *big.Int, suppliedGas uint64) (remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, MintLimitsGasCost); err != nil {
+		return 0, err
+	}
+
+	mintCap := stateDB.GetState(ContractAddress, mintCapKey).Big()
+	if totalMinted := addTotalMinted(stateDB, amount); mintCap.Sign() > 0 && totalMinted.Cmp(mintCap) > 0 {
+		return remainingGas, fmt.Errorf("%w: minting %s would bring the total minted to %s, above the cap of %s", ErrMintCapExceeded, amount, totalMinted, mintCap)
+	}
+
+	if limited, allowance := GetMinterAllowance(stateDB, minter); limited {
+		if remainingGas, err = contract.DeductGas(remainingGas, MinterAllowanceMintGasCost); err != nil {
+			return 0, err
+		}
+		if amount.Cmp(allowance) > 0 {
+			return remainingGas, fmt.Errorf("%w: %s cannot mint %s with a remaining allowance of %s", ErrMinterAllowanceExceeded, minter, amount, allowance)
+		}
+		allowance.Sub(allowance, amount)
+		stateDB.SetState(ContractAddress, minterAllowanceKey(minter), common.BigToHash(allowance))
+	}
+
+	if period := GetPeriodMintLimit(stateDB); period.Limit.Sign() > 0 {
+		if remainingGas, err = contract.DeductGas(remainingGas, PeriodMintLimitMintGasCost); err != nil {
+			return 0, err
+		}
+		if timestamp >= period.PeriodStart && timestamp-period.PeriodStart >= period.PeriodSeconds {
+			period.PeriodStart = timestamp
+			period.PeriodMinted = new(big.Int)
+		}
+		period.PeriodMinted.Add(period.PeriodMinted, amount)
+		if period.PeriodMinted.Cmp(period.Limit) > 0 {
+			return remainingGas, fmt.Errorf("%w: minting %s would bring the amount minted in the period starting at %d to %s, above the limit of %s", ErrPeriodMintLimitExceeded, amount, period.PeriodStart, period.PeriodMinted, period.Limit)
+		}
+		stateDB.SetState(ContractAddress, periodStartKey, common.BigToHash(new(big.Int).SetUint64(period.PeriodStart)))
+		stateDB.SetState(ContractAddress, periodMintedKey, common.BigToHash(period.PeriodMinted))
+	}
+
+	return remainingGas, nil
+}
+
+// addLog adds a log of the precompile with [topics] and [data] to the state.
+func addLog(accessibleState contract.AccessibleState, topics []common.Hash, data []byte) {
+	accessibleState.GetStateDB().AddLog(&types.Log{
+		Address:     ContractAddress,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: accessibleState.GetBlockContext().Number().Uint64(),
+	})
+}
+
+// PackSetMintCap packs [mintCap] into the appropriate arguments for setMintCap.
+func PackSetMintCap(mintCap *big.Int) ([]byte, error) {
+	return NativeMinterABI.Pack("setMintCap", mintCap)
+}
+
+// UnpackSetMintCapInput attempts to unpack [input] as the mint cap.
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackSetMintCapInput(input []byte) (*big.Int, error) {
+	res, err := NativeMinterABI.UnpackInput("setMintCap", input, false)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(res[0], new(*big.Int)).(**big.Int), nil
+}
+
+// setMintCap checks if the caller is an admin of the minter list, and sets the mint cap.
+func setMintCap(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, SetMintCapGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	mintCap, err := UnpackSetMintCapInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	if !allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp()).IsAdmin() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotSetMintLimits, caller)
+	}
+
+	if remainingGas, err = contract.DeductGas(remainingGas, MintCapChangedEventGasCost); err != nil {
+		return nil, 0, err
+	}
+	oldCap, _ := GetMintCap(stateDB)
+	topics, data, err := PackMintCapChangedEvent(caller, oldCap, mintCap)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	addLog(accessibleState, topics, data)
+
+	StoreMintCap(stateDB, mintCap)
+	return []byte{}, remainingGas, nil
+}
+
+// PackGetMintCap packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackGetMintCap() ([]byte, error) {
+	return NativeMinterABI.Pack("getMintCap")
+}
+
+// PackGetMintCapOutput attempts to pack [mintCap] and [totalMinted] to conform the ABI outputs.
+func PackGetMintCapOutput(mintCap *big.Int, totalMinted *big.Int) ([]byte, error) {
+	return NativeMinterABI.PackOutput("getMintCap", mintCap, totalMinted)
+}
+
+// UnpackGetMintCapOutput attempts to unpack [output] as the mint cap and total minted.
+// assumes that [output] does not include selector (omits first 4 func signature bytes)
+func UnpackGetMintCapOutput(output []byte) (*big.Int, *big.Int, error) {
+	outputStruct := struct {
+		Cap         *big.Int
+		TotalMinted *big.Int
+	}{}
+	err := NativeMinterABI.UnpackIntoInterface(&outputStruct, "getMintCap", output)
+	return outputStruct.Cap, outputStruct.TotalMinted, err
+}
+
+// getMintCap returns the mint cap and the total amount minted under it.
+func getMintCap(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetMintCapGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	mintCap, totalMinted := GetMintCap(accessibleState.GetStateDB())
+	output, err := PackGetMintCapOutput(mintCap, totalMinted)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return output, remainingGas, nil
+}
+
+// PackSetMinterAllowance packs [minter] and [allowance] into the appropriate arguments for setMinterAllowance.
+func PackSetMinterAllowance(minter common.Address, allowance *big.Int) ([]byte, error) {
+	return NativeMinterABI.Pack("setMinterAllowance", minter, allowance)
+}
+
+// UnpackSetMinterAllowanceInput attempts to unpack [input] as the minter and its allowance.
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackSetMinterAllowanceInput(input []byte) (common.Address, *big.Int, error) {
+	inputStruct := struct {
+		Minter    common.Address
+		Allowance *big.Int
+	}{}
+	err := NativeMinterABI.UnpackInputIntoInterface(&inputStruct, "setMinterAllowance", input, false)
+	return inputStruct.Minter, inputStruct.Allowance, err
+}
+
+// setMinterAllowance checks if the caller is an admin of the minter list, and limits
+// the amount the minter can mint to the given allowance.
+func setMinterAllowance(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, SetMinterAllowanceGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	minter, allowance, err := UnpackSetMinterAllowanceInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return storeMinterAllowance(accessibleState, caller, minter, true, allowance, remainingGas)
+}
+
+// PackRemoveMinterAllowance packs [minter] into the appropriate arguments for removeMinterAllowance.
+func PackRemoveMinterAllowance(minter common.Address) ([]byte, error) {
+	return NativeMinterABI.Pack("removeMinterAllowance", minter)
+}
+
+// UnpackRemoveMinterAllowanceInput attempts to unpack [input] as the minter.
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackRemoveMinterAllowanceInput(input []byte) (common.Address, error) {
+	res, err := NativeMinterABI.UnpackInput("removeMinterAllowance", input, false)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(res[0], new(common.Address)).(*common.Address), nil
+}
+
+// removeMinterAllowance checks if the caller is an admin of the minter list, and removes
+// the limit on the amount the minter can mint.
+func removeMinterAllowance(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, SetMinterAllowanceGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	minter, err := UnpackRemoveMinterAllowanceInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return storeMinterAllowance(accessibleState, caller, minter, false, new(big.Int), remainingGas)
+}
+
+func storeMinterAllowance(accessibleState contract.AccessibleState, caller common.Address, minter common.Address, limited bool, allowance *big.Int, suppliedGas uint64) (ret []byte, remainingGas uint64, err error) {
+	stateDB := accessibleState.GetStateDB()
+	if !allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp()).IsAdmin() {
+		return nil, suppliedGas, fmt.Errorf("%w: %s", ErrCannotSetMintLimits, caller)
+	}
+
+	if remainingGas, err = contract.DeductGas(suppliedGas, MinterAllowanceChangedEventGasCost); err != nil {
+		return nil, 0, err
+	}
+	topics, data, err := PackMinterAllowanceChangedEvent(caller, minter, limited, allowance)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	addLog(accessibleState, topics, data)
+
+	StoreMinterAllowance(stateDB, minter, limited, allowance)
+	return []byte{}, remainingGas, nil
+}
+
+// PackGetMinterAllowance packs [minter] into the appropriate arguments for getMinterAllowance.
+func PackGetMinterAllowance(minter common.Address) ([]byte, error) {
+	return NativeMinterABI.Pack("getMinterAllowance", minter)
+}
+
+// UnpackGetMinterAllowanceInput attempts to unpack [input] as the minter.
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackGetMinterAllowanceInput(input []byte) (common.Address, error) {
+	res, err := NativeMinterABI.UnpackInput("getMinterAllowance", input, false)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(res[0], new(common.Address)).(*common.Address), nil
+}
+
+// PackGetMinterAllowanceOutput attempts to pack [limited] and [allowance] to conform the ABI outputs.
+func PackGetMinterAllowanceOutput(limited bool, allowance *big.Int) ([]byte, error) {
+	return NativeMinterABI.PackOutput("getMinterAllowance", limited, allowance)
+}
+
+// UnpackGetMinterAllowanceOutput attempts to unpack [output] as whether the minter is limited
+// and its allowance.
+// assumes that [output] does not include selector (omits first 4 func signature bytes)
+func UnpackGetMinterAllowanceOutput(output []byte) (bool, *big.Int, error) {
+	outputStruct := struct {
+		Limited   bool
+		Allowance *big.Int
+	}{}
+	err := NativeMinterABI.UnpackIntoInterface(&outputStruct, "getMinterAllowance", output)
+	return outputStruct.Limited, outputStruct.Allowance, err
+}
+
+// getMinterAllowance returns whether the amount the minter can mint is limited, and the
+// amount it can still mint if so.
+func getMinterAllowance(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetMinterAllowanceGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	minter, err := UnpackGetMinterAllowanceInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	limited, allowance := GetMinterAllowance(accessibleState.GetStateDB(), minter)
+	output, err := PackGetMinterAllowanceOutput(limited, allowance)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return output, remainingGas, nil
+}
+
+// PackSetPeriodMintLimit packs [limit] and [periodSeconds] into the appropriate arguments for setPeriodMintLimit.
+func PackSetPeriodMintLimit(limit *big.Int, periodSeconds uint64) ([]byte, error) {
+	return NativeMinterABI.Pack("setPeriodMintLimit", limit, new(big.Int).SetUint64(periodSeconds))
+}
+
+// UnpackSetPeriodMintLimitInput attempts to unpack [input] as the period mint limit and
+// the length of the period in seconds.
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackSetPeriodMintLimitInput(input []byte) (*big.Int, uint64, error) {
+	inputStruct := struct {
+		Limit         *big.Int
+		PeriodSeconds *big.Int
+	}{}
+	if err := NativeMinterABI.UnpackInputIntoInterface(&inputStruct, "setPeriodMintLimit", input, false); err != nil {
+		return nil, 0, err
+	}
+	if !inputStruct.PeriodSeconds.IsUint64() {
+		return nil, 0, fmt.Errorf("%w: period of %s seconds is too long", ErrInvalidPeriodMintLimit, inputStruct.PeriodSeconds)
+	}
+	return inputStruct.Limit, inputStruct.PeriodSeconds.Uint64(), nil
+}
+
+// setPeriodMintLimit checks if the caller is an admin of the minter list, and limits the
+// amount minted within a period, starting a new period.
+func setPeriodMintLimit(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, SetPeriodMintLimitGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	limit, periodSeconds, err := UnpackSetPeriodMintLimitInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if limit.Sign() > 0 && periodSeconds == 0 {
+		return nil, remainingGas, fmt.Errorf("%w: period cannot be zero", ErrInvalidPeriodMintLimit)
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	if !allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, caller, accessibleState.GetBlockContext().Timestamp()).IsAdmin() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotSetMintLimits, caller)
+	}
+
+	if remainingGas, err = contract.DeductGas(remainingGas, PeriodMintLimitChangedEventGasCost); err != nil {
+		return nil, 0, err
+	}
+	topics, data, err := PackPeriodMintLimitChangedEvent(caller, limit, periodSeconds)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	addLog(accessibleState, topics, data)
+
+	StorePeriodMintLimit(stateDB, limit, periodSeconds, accessibleState.GetBlockContext().Timestamp())
+	return []byte{}, remainingGas, nil
+}
+
+// PackGetPeriodMintLimit packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackGetPeriodMintLimit() ([]byte, error) {
+	return NativeMinterABI.Pack("getPeriodMintLimit")
+}
+
+// PackGetPeriodMintLimitOutput attempts to pack [period] to conform the ABI outputs.
+func PackGetPeriodMintLimitOutput(period PeriodMintLimit) ([]byte, error) {
+	return NativeMinterABI.PackOutput("getPeriodMintLimit",
+		period.Limit,
+		new(big.Int).SetUint64(period.PeriodSeconds),
+		new(big.Int).SetUint64(period.PeriodStart),
+		period.PeriodMinted,
+	)
+}
+
+// UnpackGetPeriodMintLimitOutput attempts to unpack [output] as a PeriodMintLimit.
+// assumes that [output] does not include selector (omits first 4 func signature bytes)
+func UnpackGetPeriodMintLimitOutput(output []byte) (PeriodMintLimit, error) {
+	outputStruct := struct {
+		Limit         *big.Int
+		PeriodSeconds *big.Int
+		PeriodStart   *big.Int
+		PeriodMinted  *big.Int
+	}{}
+	if err := NativeMinterABI.UnpackIntoInterface(&outputStruct, "getPeriodMintLimit", output); err != nil {
+		return PeriodMintLimit{}, err
+	}
+	return PeriodMintLimit{
+		Limit:         outputStruct.Limit,
+		PeriodSeconds: outputStruct.PeriodSeconds.Uint64(),
+		PeriodStart:   outputStruct.PeriodStart.Uint64(),
+		PeriodMinted:  outputStruct.PeriodMinted,
+	}, nil
+}
+
+// getPeriodMintLimit returns the period mint limit and the amount minted in the period of
+// the last mint. The period ends [PeriodSeconds] after its start, even if no mint started
+// a new period since.
+func getPeriodMintLimit(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetPeriodMintLimitGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	output, err := PackGetPeriodMintLimitOutput(GetPeriodMintLimit(accessibleState.GetStateDB()))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return output, remainingGas, nil
+}
@@ -51,13 +51,32 @@ func (*configurator) Configure(chainConfig precompileconfig.ChainConfig, cfg pre
 	if !ok {
 		return fmt.Errorf("expected config type %T, got %T: %v", &Config{}, cfg, cfg)
 	}
+	initialMintTotal := new(big.Int)
 	for to, amount := range config.InitialMint {
 		if amount != nil {
 			amountBig := (*big.Int)(amount)
 			amountU256, _ := uint256.FromBig(amountBig)
 			state.AddBalance(to, amountU256)
+			initialMintTotal.Add(initialMintTotal, amountBig)
 		}
 	}
 
+	// After Granite, the initial mint counts towards the total minted and so
+	// towards the mint cap.
+	if chainConfig.IsGranite(blockContext.Timestamp()) && initialMintTotal.Sign() > 0 {
+		addTotalMinted(state, initialMintTotal)
+	}
+	if config.MintCap != nil {
+		StoreMintCap(state, (*big.Int)(config.MintCap))
+	}
+	for minter, allowance := range config.MinterAllowances {
+		if allowance != nil {
+			StoreMinterAllowance(state, minter, true, (*big.Int)(allowance))
+		}
+	}
+	if config.PeriodMintLimit != nil {
+		StorePeriodMintLimit(state, (*big.Int)(config.PeriodMintLimit), config.PeriodSeconds, blockContext.Timestamp())
+	}
+
 	return config.AllowListConfig.Configure(chainConfig, ContractAddress, state, blockContext)
 }
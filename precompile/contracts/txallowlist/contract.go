@@ -17,6 +17,12 @@ func GetTxAllowListStatus(stateDB contract.StateReader, address common.Address)
 	return allowlist.GetAllowListStatus(stateDB, ContractAddress, address)
 }
 
+// GetActiveTxAllowListStatus returns the role of [address] for the tx allow list
+// at [timestamp], treating an expired role as NoRole.
+func GetActiveTxAllowListStatus(stateDB contract.StateReader, address common.Address, timestamp uint64) allowlist.Role {
+	return allowlist.GetActiveAllowListStatus(stateDB, ContractAddress, address, timestamp)
+}
+
 // SetTxAllowListStatus sets the permissions of [address] to [role] for the
 // tx allow list.
 // assumes [role] has already been verified as valid.
@@ -22,6 +22,10 @@ const (
 	WarpDefaultQuorumNumerator uint64 = 67
 	WarpQuorumNumeratorMinimum uint64 = 33
 	WarpQuorumDenominator      uint64 = 100
+
+	// WarpDefaultMaxSourceValidators is the number of source validators the
+	// validator set lookup is charged for from Granite, unless configured.
+	WarpDefaultMaxSourceValidators uint64 = 1_024
 )
 
 var (
@@ -32,6 +36,7 @@ var (
 
 var (
 	errOverflowSignersGasCost     = errors.New("overflow calculating warp signers gas cost")
+	errOverflowValidatorsGasCost  = errors.New("overflow calculating warp validator set gas cost")
 	errInvalidPredicateBytes      = errors.New("cannot unpack predicate bytes")
 	errInvalidWarpMsg             = errors.New("cannot unpack warp message")
 	errCannotParseWarpMsg         = errors.New("cannot parse warp message")
@@ -42,6 +47,7 @@ var (
 	errWarpCannotBeActivated      = errors.New("warp cannot be activated before Durango")
 	errFailedVerification         = errors.New("cannot verify warp signature")
 	errCannotRetrieveValidatorSet = errors.New("cannot retrieve validator set")
+	errTooManySourceValidators    = errors.New("too many validators in the source validator set")
 )
 
 // Config implements the precompileconfig.Config interface and
@@ -50,6 +56,10 @@ type Config struct {
 	precompileconfig.Upgrade
 	QuorumNumerator              uint64 `json:"quorumNumerator"`
 	RequirePrimaryNetworkSigners bool   `json:"requirePrimaryNetworkSigners"`
+	// MaxSourceValidators is the size of the largest source validator set
+	// messages are accepted from once Granite is activated, which the validator
+	// set lookup is charged for. 0 denotes using the default.
+	MaxSourceValidators uint64 `json:"maxSourceValidators,omitempty"`
 }
 
 // NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
@@ -111,7 +121,16 @@ func (c *Config) Equal(s precompileconfig.Config) bool {
 		return false
 	}
 	equals := c.Upgrade.Equal(&other.Upgrade)
-	return equals && c.QuorumNumerator == other.QuorumNumerator
+	return equals && c.QuorumNumerator == other.QuorumNumerator && c.MaxSourceValidators == other.MaxSourceValidators
+}
+
+// maxSourceValidators returns the size of the largest source validator set
+// messages are accepted from once Granite is activated.
+func (c *Config) maxSourceValidators() uint64 {
+	if c.MaxSourceValidators != 0 {
+		return c.MaxSourceValidators
+	}
+	return WarpDefaultMaxSourceValidators
 }
 
 func (c *Config) Accept(acceptCtx *precompileconfig.AcceptContext, blockHash common.Hash, blockNumber uint64, txHash common.Hash, logIndex int, topics []common.Hash, logData []byte) error {
@@ -139,10 +158,18 @@ func (c *Config) Accept(acceptCtx *precompileconfig.AcceptContext, blockHash com
 // 1. Base cost of the message
 // 2. Size of the message
 // 3. Number of signers
-// 4. TODO: Lookup of the validator set
+// 4. Lookup of the validator set (from Granite)
+//
+// The lookup is charged per message and for every validator of the largest
+// source validator set messages are accepted from, as neither the source subnet
+// nor the size of its validator set can be known without state access. The
+// signer bitset only bounds the size of the set from below, so it can't be
+// charged for instead. Messages from larger validator sets fail verification.
+// Validator sets are cached within a block, so repeated lookups of the same set
+// are overcharged rather than undercharged.
 //
 // If the payload of the warp message fails parsing, return a non-nil error invalidating the transaction.
-func (c *Config) PredicateGas(predicateBytes []byte) (uint64, error) {
+func (c *Config) PredicateGas(predicateBytes []byte, rules precompileconfig.Rules) (uint64, error) {
 	totalGas := GasCostPerSignatureVerification
 	bytesGasCost, overflow := math.SafeMul(GasCostPerWarpMessageBytes, uint64(len(predicateBytes)))
 	if overflow {
@@ -179,11 +206,26 @@ func (c *Config) PredicateGas(predicateBytes []byte) (uint64, error) {
 		return 0, fmt.Errorf("overflow adding signer gas (PrevTotal: %d, VerificationGas: %d)", totalGas, signerGas)
 	}
 
+	if rules.IsGraniteActivated() {
+		validatorGas, overflow := math.SafeMul(c.maxSourceValidators(), GasCostPerWarpValidator)
+		if overflow {
+			return 0, errOverflowValidatorsGasCost
+		}
+		validatorGas, overflow = math.SafeAdd(validatorGas, GasCostPerValidatorSetLookup)
+		if overflow {
+			return 0, errOverflowValidatorsGasCost
+		}
+		totalGas, overflow = math.SafeAdd(totalGas, validatorGas)
+		if overflow {
+			return 0, fmt.Errorf("overflow adding validator set gas (PrevTotal: %d, ValidatorSetGas: %d)", totalGas, validatorGas)
+		}
+	}
+
 	return totalGas, nil
 }
 
 // VerifyPredicate returns whether the predicate described by [predicateBytes] passes verification.
-func (c *Config) VerifyPredicate(predicateContext *precompileconfig.PredicateContext, predicateBytes []byte) error {
+func (c *Config) VerifyPredicate(predicateContext *precompileconfig.PredicateContext, predicateBytes []byte, rules precompileconfig.Rules) error {
 	unpackedPredicateBytes, err := predicate.UnpackPredicate(predicateBytes)
 	if err != nil {
 		return fmt.Errorf("%w: %w", errInvalidPredicateBytes, err)
@@ -210,16 +252,33 @@ func (c *Config) VerifyPredicate(predicateContext *precompileconfig.PredicateCon
 		c.RequirePrimaryNetworkSigners,
 	)
 
-	validatorSet, err := warp.GetCanonicalValidatorSetFromChainID(
-		context.Background(),
-		state,
-		predicateContext.ProposerVMBlockCtx.PChainHeight,
-		warpMsg.UnsignedMessage.SourceChainID,
-	)
+	var validatorSet warp.CanonicalValidatorSet
+	if predicateContext.WarpValidatorSets != nil {
+		validatorSet, err = predicateContext.WarpValidatorSets.GetCanonicalValidatorSetFromChainID(
+			context.Background(),
+			state,
+			predicateContext.ProposerVMBlockCtx.PChainHeight,
+			warpMsg.UnsignedMessage.SourceChainID,
+		)
+	} else {
+		validatorSet, err = warp.GetCanonicalValidatorSetFromChainID(
+			context.Background(),
+			state,
+			predicateContext.ProposerVMBlockCtx.PChainHeight,
+			warpMsg.UnsignedMessage.SourceChainID,
+		)
+	}
 	if err != nil {
 		log.Debug("failed to retrieve canonical validator set", "msgID", warpMsg.ID(), "err", err)
 		return fmt.Errorf("%w: %w", errCannotRetrieveValidatorSet, err)
 	}
+	// From Granite, PredicateGas only charges for up to the maximum number of
+	// source validators.
+	if rules.IsGraniteActivated() {
+		if numValidators, maxValidators := uint64(len(validatorSet.Validators)), c.maxSourceValidators(); numValidators > maxValidators {
+			return fmt.Errorf("%w: %d > %d", errTooManySourceValidators, numValidators, maxValidators)
+		}
+	}
 
 	err = warpMsg.Signature.Verify(
 		&warpMsg.UnsignedMessage,
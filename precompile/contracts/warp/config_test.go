@@ -72,6 +72,12 @@ func TestEqualWarpConfig(t *testing.T) {
 			Expected: false,
 		},
 
+		"different max source validators": {
+			Config:   NewDefaultConfig(utils.NewUint64(3)),
+			Other:    &Config{Upgrade: precompileconfig.Upgrade{BlockTimestamp: utils.NewUint64(3)}, MaxSourceValidators: 100},
+			Expected: false,
+		},
+
 		"same default config": {
 			Config:   NewDefaultConfig(utils.NewUint64(3)),
 			Other:    NewDefaultConfig(utils.NewUint64(3)),
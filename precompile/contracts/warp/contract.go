@@ -32,6 +32,11 @@ const (
 	GasCostPerWarpSigner            uint64 = 500
 	GasCostPerWarpMessageBytes      uint64 = 100
 	GasCostPerSignatureVerification uint64 = 200_000
+
+	// Charged from Granite for retrieving the validator set of the source subnet
+	// from the P-Chain, and for every validator the source validator set may have.
+	GasCostPerValidatorSetLookup uint64 = 20_000
+	GasCostPerWarpValidator      uint64 = 100
 )
 
 var (
@@ -23,6 +23,7 @@ import (
 	"github.com/luxfi/evm/precompile/testutils"
 	"github.com/luxfi/evm/predicate"
 	"github.com/luxfi/evm/utils"
+	warpValidators "github.com/luxfi/evm/warp/validators"
 	"github.com/luxfi/node/consensus/validators/validatorstest"
 	"github.com/luxfi/node/utils/crypto/bls/signer/localsigner"
 	luxWarp "github.com/luxfi/node/vms/platformvm/warp"
@@ -699,6 +700,129 @@ func initWarpPredicateTests() {
 	}
 }
 
+func TestWarpPredicateGasGranite(t *testing.T) {
+	snowCtx := createSnowCtx([]validatorRange{
+		{
+			start:     0,
+			end:       100,
+			weight:    20,
+			publicKey: true,
+		},
+	})
+
+	tests := make(map[string]testutils.PredicateTest)
+	for _, numSigners := range []int{1, 10, 100} {
+		predicateBytes := createPredicate(numSigners)
+		test := createValidPredicateTest(snowCtx, uint64(numSigners), predicateBytes)
+		test.Rules = &params.Rules{IsGranite: true}
+		// The lookup is charged for the largest source validator set, regardless
+		// of the number of signers.
+		test.Gas += GasCostPerValidatorSetLookup + WarpDefaultMaxSourceValidators*GasCostPerWarpValidator
+		tests[fmt.Sprintf("granite %d signer(s)", numSigners)] = test
+	}
+	testutils.RunPredicateTests(t, tests)
+}
+
+func TestWarpPredicateGasSparseSigners(t *testing.T) {
+	require := require.New(t)
+
+	// The first validator holds enough weight to sign along with any other.
+	snowCtx := createSnowCtx([]validatorRange{
+		{
+			start:     0,
+			end:       1,
+			weight:    1_000,
+			publicKey: true,
+		},
+		{
+			start:     1,
+			end:       100,
+			weight:    1,
+			publicKey: true,
+		},
+	})
+
+	// Sign by the first validator and another in the middle of the set, so the
+	// signer bitset is about half as long as the validator set.
+	aggregateSignature, err := bls.AggregateSignatures([]*bls.Signature{blsSignatures[0], blsSignatures[50]})
+	require.NoError(err)
+	warpSignature := &luxWarp.BitSetSignature{
+		Signers: set.NewBits(0, 50).Bytes(),
+	}
+	copy(warpSignature.Signature[:], bls.SignatureToBytes(aggregateSignature))
+	warpMsg, err := luxWarp.NewMessage(unsignedMsg, warpSignature)
+	require.NoError(err)
+	predicateBytes := predicate.PackPredicate(warpMsg.Bytes())
+
+	baseGas := GasCostPerSignatureVerification + uint64(len(predicateBytes))*GasCostPerWarpMessageBytes + 2*GasCostPerWarpSigner
+	tests := map[string]testutils.PredicateTest{
+		"pre-granite": createValidPredicateTest(snowCtx, 2, predicateBytes),
+	}
+
+	test := createValidPredicateTest(snowCtx, 2, predicateBytes)
+	test.Rules = &params.Rules{IsGranite: true}
+	test.Gas = baseGas + GasCostPerValidatorSetLookup + WarpDefaultMaxSourceValidators*GasCostPerWarpValidator
+	tests["granite"] = test
+
+	// The gas is charged for every validator the source validator set may have,
+	// not for the length of the signer bitset.
+	test = createValidPredicateTest(snowCtx, 2, predicateBytes)
+	test.Config = &Config{
+		Upgrade:             precompileconfig.Upgrade{BlockTimestamp: utils.NewUint64(0)},
+		MaxSourceValidators: 100,
+	}
+	test.Rules = &params.Rules{IsGranite: true}
+	test.Gas = baseGas + GasCostPerValidatorSetLookup + 100*GasCostPerWarpValidator
+	tests["granite max source validators"] = test
+
+	// Messages from larger validator sets than charged for fail verification.
+	test = createValidPredicateTest(snowCtx, 2, predicateBytes)
+	test.Config = &Config{
+		Upgrade:             precompileconfig.Upgrade{BlockTimestamp: utils.NewUint64(0)},
+		MaxSourceValidators: 99,
+	}
+	test.Rules = &params.Rules{IsGranite: true}
+	test.Gas = baseGas + GasCostPerValidatorSetLookup + 99*GasCostPerWarpValidator
+	test.ExpectedErr = errTooManySourceValidators
+	tests["granite too many source validators"] = test
+
+	testutils.RunPredicateTests(t, tests)
+}
+
+func TestWarpPredicateValidatorSetCache(t *testing.T) {
+	require := require.New(t)
+
+	snowCtx := createSnowCtx([]validatorRange{
+		{
+			start:     0,
+			end:       100,
+			weight:    20,
+			publicKey: true,
+		},
+	})
+	innerState := snowCtx.ValidatorState.(*validatorstest.State)
+	getValidatorSetF := innerState.GetValidatorSetF
+	var lookups int
+	innerState.GetValidatorSetF = func(ctx context.Context, height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		lookups++
+		return getValidatorSetF(ctx, height, subnetID)
+	}
+
+	// Predicates verified with the same context retrieve the validator set once.
+	predicateContext := &precompileconfig.PredicateContext{
+		SnowCtx: snowCtx,
+		ProposerVMBlockCtx: &block.Context{
+			PChainHeight: 1,
+		},
+		WarpValidatorSets: warpValidators.NewCanonicalSetCache(),
+	}
+	config := NewDefaultConfig(utils.NewUint64(0))
+	require.NoError(config.VerifyPredicate(predicateContext, createPredicate(100), &params.Rules{}))
+	require.ErrorIs(config.VerifyPredicate(predicateContext, createPredicate(1), &params.Rules{}), errFailedVerification)
+	require.NoError(config.VerifyPredicate(predicateContext, createPredicate(100), &params.Rules{}))
+	require.Equal(1, lookups)
+}
+
 func TestWarpPredicate(t *testing.T) {
 	testutils.RunPredicateTests(t, predicateTests)
 }
@@ -5,8 +5,12 @@
 package precompileconfig
 
 import (
+	"context"
+
 	"github.com/luxfi/node/consensus"
 	"github.com/luxfi/node/consensus/engine/chain/block"
+	"github.com/luxfi/node/consensus/validators"
+	"github.com/luxfi/node/ids"
 	"github.com/luxfi/node/vms/platformvm/warp"
 	"github.com/luxfi/evm/commontype"
 	"github.com/luxfi/geth/common"
@@ -36,6 +40,24 @@ type PredicateContext struct {
 	SnowCtx *consensus.Context
 	// ProposerVMBlockCtx defines the ProposerVM context the predicate is verified within
 	ProposerVMBlockCtx *block.Context
+	// WarpValidatorSets caches the validator sets retrieved while verifying the
+	// predicates of a block. If nil, the validator sets are not cached.
+	WarpValidatorSets WarpValidatorSetCache
+}
+
+// WarpValidatorSetCache caches the canonical validator sets retrieved to verify
+// the warp messages of a block.
+type WarpValidatorSetCache interface {
+	// GetCanonicalValidatorSetFromChainID returns the canonical validator set of
+	// the subnet validating [chainID] at [height], retrieving it from [state] if
+	// not cached yet.
+	GetCanonicalValidatorSetFromChainID(ctx context.Context, state validators.State, height uint64, chainID ids.ID) (warp.CanonicalValidatorSet, error)
+}
+
+// Rules defines the interface that provides information about the rules of the
+// chain a predicate is charged for.
+type Rules interface {
+	IsGraniteActivated() bool
 }
 
 // Predicater is an optional interface for StatefulPrecompileContracts to implement.
@@ -46,8 +68,8 @@ type PredicateContext struct {
 // will not maintain backwards compatibility of this interface and your code should not
 // rely on this. Designed for use only by precompiles that ship with evm.
 type Predicater interface {
-	PredicateGas(predicateBytes []byte) (uint64, error)
-	VerifyPredicate(predicateContext *PredicateContext, predicateBytes []byte) error
+	PredicateGas(predicateBytes []byte, rules Rules) (uint64, error)
+	VerifyPredicate(predicateContext *PredicateContext, predicateBytes []byte, rules Rules) error
 }
 
 type WarpMessageWriter interface {
@@ -79,4 +101,6 @@ type ChainConfig interface {
 	AllowedFeeRecipients() bool
 	// IsDurango returns true if the time is after Durango.
 	IsDurango(time uint64) bool
+	// IsGranite returns true if the time is after Granite.
+	IsGranite(time uint64) bool
 }
@@ -36,32 +36,32 @@ func (m *MockPredicater) EXPECT() *MockPredicaterMockRecorder {
 }
 
 // PredicateGas mocks base method.
-func (m *MockPredicater) PredicateGas(predicateBytes []byte) (uint64, error) {
+func (m *MockPredicater) PredicateGas(predicateBytes []byte, rules Rules) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PredicateGas", predicateBytes)
+	ret := m.ctrl.Call(m, "PredicateGas", predicateBytes, rules)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // PredicateGas indicates an expected call of PredicateGas.
-func (mr *MockPredicaterMockRecorder) PredicateGas(predicateBytes any) *gomock.Call {
+func (mr *MockPredicaterMockRecorder) PredicateGas(predicateBytes, rules any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PredicateGas", reflect.TypeOf((*MockPredicater)(nil).PredicateGas), predicateBytes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PredicateGas", reflect.TypeOf((*MockPredicater)(nil).PredicateGas), predicateBytes, rules)
 }
 
 // VerifyPredicate mocks base method.
-func (m *MockPredicater) VerifyPredicate(predicateContext *PredicateContext, predicateBytes []byte) error {
+func (m *MockPredicater) VerifyPredicate(predicateContext *PredicateContext, predicateBytes []byte, rules Rules) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VerifyPredicate", predicateContext, predicateBytes)
+	ret := m.ctrl.Call(m, "VerifyPredicate", predicateContext, predicateBytes, rules)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // VerifyPredicate indicates an expected call of VerifyPredicate.
-func (mr *MockPredicaterMockRecorder) VerifyPredicate(predicateContext, predicateBytes any) *gomock.Call {
+func (mr *MockPredicaterMockRecorder) VerifyPredicate(predicateContext, predicateBytes, rules any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPredicate", reflect.TypeOf((*MockPredicater)(nil).VerifyPredicate), predicateContext, predicateBytes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPredicate", reflect.TypeOf((*MockPredicater)(nil).VerifyPredicate), predicateContext, predicateBytes, rules)
 }
 
 // MockConfig is a mock of Config interface.
@@ -224,6 +224,20 @@ func (mr *MockChainConfigMockRecorder) IsDurango(time any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDurango", reflect.TypeOf((*MockChainConfig)(nil).IsDurango), time)
 }
 
+// IsGranite mocks base method.
+func (m *MockChainConfig) IsGranite(time uint64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsGranite", time)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsGranite indicates an expected call of IsGranite.
+func (mr *MockChainConfigMockRecorder) IsGranite(time any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsGranite", reflect.TypeOf((*MockChainConfig)(nil).IsGranite), time)
+}
+
 // MockAccepter is a mock of Accepter interface.
 type MockAccepter struct {
 	ctrl     *gomock.Controller
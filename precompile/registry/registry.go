@@ -7,6 +7,7 @@ package registry
 // Force imports of each precompile to ensure each precompile's init function runs and registers itself
 // with the registry.
 import (
+	_ "github.com/luxfi/evm/precompile/contracts/denylist"
 	_ "github.com/luxfi/evm/precompile/contracts/deployerallowlist"
 	_ "github.com/luxfi/evm/precompile/contracts/nativeminter"
 	_ "github.com/luxfi/evm/precompile/contracts/txallowlist"
@@ -35,5 +36,6 @@ import (
 // FeeManagerAddress                = common.HexToAddress("0x0200000000000000000000000000000000000003")
 // RewardManagerAddress             = common.HexToAddress("0x0200000000000000000000000000000000000004")
 // WarpAddress                      = common.HexToAddress("0x0200000000000000000000000000000000000005")
+// DenyListAddress                  = common.HexToAddress("0x0200000000000000000000000000000000000006")
 // ADD YOUR PRECOMPILE HERE
 // {YourPrecompile}Address          = common.HexToAddress("0x03000000000000000000000000000000000000??")
@@ -95,6 +95,7 @@ func (test PrecompileTest) setup(t testing.TB, module modules.Module, state cont
 			mockChainConfig.EXPECT().GetFeeConfig().AnyTimes().Return(commontype.ValidTestFeeConfig)
 			mockChainConfig.EXPECT().AllowedFeeRecipients().AnyTimes().Return(false)
 			mockChainConfig.EXPECT().IsDurango(gomock.Any()).AnyTimes().Return(true)
+			mockChainConfig.EXPECT().IsGranite(gomock.Any()).AnyTimes().Return(true)
 			return mockChainConfig
 		}
 	}
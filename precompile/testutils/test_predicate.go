@@ -16,6 +16,9 @@ type PredicateTest struct {
 	Config precompileconfig.Config
 
 	PredicateContext *precompileconfig.PredicateContext
+	// Rules are the rules the predicate is charged and verified under. If nil,
+	// no network upgrade affecting predicates is activated.
+	Rules precompileconfig.Rules
 
 	PredicateBytes []byte
 	Gas            uint64
@@ -23,12 +26,21 @@ type PredicateTest struct {
 	ExpectedErr    error
 }
 
+// preGraniteRules are the default rules of a PredicateTest.
+type preGraniteRules struct{}
+
+func (preGraniteRules) IsGraniteActivated() bool { return false }
+
 func (test PredicateTest) Run(t testing.TB) {
 	t.Helper()
 	require := require.New(t)
 	predicate := test.Config.(precompileconfig.Predicater)
 
-	predicateGas, predicateGasErr := predicate.PredicateGas(test.PredicateBytes)
+	rules := test.Rules
+	if rules == nil {
+		rules = preGraniteRules{}
+	}
+	predicateGas, predicateGasErr := predicate.PredicateGas(test.PredicateBytes, rules)
 	require.ErrorIs(predicateGasErr, test.GasErr)
 	if test.GasErr != nil {
 		return
@@ -36,7 +48,7 @@ func (test PredicateTest) Run(t testing.TB) {
 
 	require.Equal(test.Gas, predicateGas)
 
-	predicateRes := predicate.VerifyPredicate(test.PredicateContext, test.PredicateBytes)
+	predicateRes := predicate.VerifyPredicate(test.PredicateContext, test.PredicateBytes, rules)
 	require.ErrorIs(predicateRes, test.ExpectedErr)
 }
 
@@ -321,7 +321,7 @@ func (t *StateTest) RunNoVerify(subtest StateSubtest, vmconfig vm.Config, snapsh
 	snapshot := state.StateDB.Snapshot()
 	gaspool := new(core.GasPool)
 	gaspool.AddGas(block.GasLimit())
-	_, err = core.ApplyMessage(evm, msg, gaspool)
+	_, err = core.ApplyMessage(config, evm, msg, gaspool)
 	if err != nil {
 		state.StateDB.RevertToSnapshot(snapshot)
 	}
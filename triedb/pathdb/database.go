@@ -27,13 +27,16 @@
 package pathdb
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/core/rawdb"
 	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/crypto"
 	"github.com/luxfi/geth/ethdb"
 	"github.com/luxfi/geth/log"
 	"github.com/luxfi/geth/trie/trienode"
@@ -41,35 +44,14 @@ import (
 	"github.com/luxfi/evm/params"
 )
 
-// StateSetWithOrigin wraps the state set with origin meta
-type StateSetWithOrigin struct {
-	AccountOrigin map[common.Address][]byte
-	StorageOrigin map[common.Address]map[common.Hash][]byte
-	AccountData   map[common.Address][]byte
-	StorageData   map[common.Address]map[common.Hash][]byte
-}
-
-// triestate compatibility wrapper
-type triestateCompat struct{}
-
-type Set = StateSetWithOrigin
-
-var triestate = triestateCompat{}
-
-// NewStateSetWithOrigin creates a new StateSetWithOrigin 
-func NewStateSetWithOrigin(accounts map[common.Address][]byte, storages map[common.Address]map[common.Hash][]byte, accountOrigin map[common.Address][]byte, storageOrigin map[common.Address]map[common.Hash][]byte, incomplete bool) *StateSetWithOrigin {
-	// Create the state set
-	s := &StateSetWithOrigin{}
-	// Note: We can't directly set the fields because they might be unexported
-	// This is a compatibility shim - the actual implementation would need to use
-	// the proper constructor from ethereum/go-ethereum/triedb/pathdb
-	return s
-}
-
 const (
 	// maxDiffLayers is the maximum diff layers allowed in the layer tree.
 	maxDiffLayers = 128
 
+	// defaultHistoricLayers is the default number of layers which can be
+	// rebuilt below the disk layer for historic reads.
+	defaultHistoricLayers = maxDiffLayers
+
 	// defaultCleanSize is the default memory allowance of clean cache.
 	defaultCleanSize = 16 * 1024 * 1024
 
@@ -123,6 +105,7 @@ type Config struct {
 	CleanCacheSize int    // Maximum memory allowance (in bytes) for caching clean nodes
 	DirtyCacheSize int    // Maximum memory allowance (in bytes) for caching dirty nodes
 	ReadOnly       bool   // Flag whether the database is opened in read only mode.
+	HistoricLayers uint64 // Maximum number of layers rebuilt below the disk layer for historic reads
 }
 
 // BackendConstructor returns a new pathdb backend
@@ -138,6 +121,9 @@ func (c *Config) sanitize() *Config {
 		log.Warn("Sanitizing invalid node buffer size", "provided", common.StorageSize(conf.DirtyCacheSize), "updated", common.StorageSize(maxBufferSize))
 		conf.DirtyCacheSize = maxBufferSize
 	}
+	if conf.HistoricLayers == 0 {
+		conf.HistoricLayers = defaultHistoricLayers
+	}
 	return &conf
 }
 
@@ -146,6 +132,7 @@ var Defaults = &Config{
 	StateHistory:   params.FullImmutabilityThreshold,
 	CleanCacheSize: defaultCleanSize,
 	DirtyCacheSize: DefaultBufferSize,
+	HistoricLayers: defaultHistoricLayers,
 }
 
 // ReadOnly is the config in order to open database in read only mode.
@@ -173,9 +160,7 @@ type Database struct {
 	diskdb     ethdb.Database // Persistent storage for matured trie nodes
 	tree       *layerTree     // The group for all known layers
 	lock       sync.RWMutex   // Lock to prevent mutations from happening at the same time
-
-	// NOTE(freezer): This is disabled since we do not have a freezer.
-	// freezer    *rawdb.ResettableFreezer // Freezer for storing trie histories, nil possible in tests
+	historic   historicLayers // Layers rebuilt from state histories for historic reads
 }
 
 // New attempts to load an already existing layer from a persistent key-value
@@ -197,53 +182,32 @@ func New(diskdb ethdb.Database, config *Config) *Database {
 	// and in-memory layer journal.
 	db.tree = newLayerTree(db.loadLayers())
 
-	// NOTE(freezer): This is disabled since we do not have a freezer.
-	// Open the freezer for state history if the passed database contains an
-	// ancient store. Otherwise, all the relevant functionalities are disabled.
-	//
-	// Because the freezer can only be opened once at the same time, this
-	// mechanism also ensures that at most one **non-readOnly** database
-	// is opened at the same time to prevent accidental mutation.
-	// if ancient, err := diskdb.AncientDatadir(); err == nil && ancient != "" && !db.readOnly {
-	// 	freezer, err := rawdb.NewStateFreezer(ancient, false)
-	// 	if err != nil {
-	// 		log.Crit("Failed to open state history freezer", "err", err)
-	// 	}
-	// 	db.freezer = freezer
-	//
-	// 	diskLayerID := db.tree.bottom().stateID()
-	// 	if diskLayerID == 0 {
-	// 		// Reset the entire state histories in case the trie database is
-	// 		// not initialized yet, as these state histories are not expected.
-	// 		frozen, err := db.freezer.Ancients()
-	// 		if err != nil {
-	// 			log.Crit("Failed to retrieve head of state history", "err", err)
-	// 		}
-	// 		if frozen != 0 {
-	// 			err := db.freezer.Reset()
-	// 			if err != nil {
-	// 				log.Crit("Failed to reset state histories", "err", err)
-	// 			}
-	// 			log.Info("Truncated extraneous state history")
-	// 		}
-	// 	} else {
-	// 		// Truncate the extra state histories above in freezer in case
-	// 		// it's not aligned with the disk layer.
-	// 		pruned, err := truncateFromHead(db.diskdb, freezer, diskLayerID)
-	// 		if err != nil {
-	// 			log.Crit("Failed to truncate extra state histories", "err", err)
-	// 		}
-	// 		if pruned != 0 {
-	// 			log.Warn("Truncated extra state histories", "number", pruned)
-	// 		}
-	// 	}
-	// }
-	// // Disable database in case node is still in the initial state sync stage.
-	// if rawdb.ReadSnapSyncStatusFlag(diskdb) == rawdb.StateSyncRunning && !db.readOnly {
-	// 	if err := db.Disable(); err != nil {
-	// 		log.Crit("Failed to disable database", "err", err) // impossible to happen
-	// 	}
-	// }
+	// State histories are stored in the key-value store alongside the trie
+	// nodes, see schema.go. Truncate the ones which are not aligned with the
+	// disk layer, e.g. written right before a crash without the matching
+	// nodes being flushed.
+	if !db.readOnly {
+		diskLayerID := db.tree.bottom().stateID()
+		if diskLayerID == 0 {
+			// Reset the entire state histories in case the trie database is
+			// not initialized yet, as these state histories are not expected.
+			if readStateHistoryHead(diskdb) != 0 {
+				if err := resetHistories(diskdb); err != nil {
+					log.Crit("Failed to reset state histories", "err", err)
+				}
+				log.Info("Truncated extraneous state history")
+			}
+		} else {
+			// Truncate the extra state histories above the disk layer.
+			pruned, err := truncateFromHead(diskdb, diskLayerID)
+			if err != nil {
+				log.Crit("Failed to truncate extra state histories", "err", err)
+			}
+			if pruned != 0 {
+				log.Warn("Truncated extra state histories", "number", pruned)
+			}
+		}
+	}
 	log.Warn("Path-based state scheme is an experimental feature")
 	return db
 }
@@ -251,6 +215,9 @@ func New(diskdb ethdb.Database, config *Config) *Database {
 // Reader retrieves a layer belonging to the given state root.
 func (db *Database) Reader(root common.Hash) (database.NodeReader, error) {
 	l := db.tree.get(root)
+	if l == nil {
+		l = db.historic.get(db.tree.bottom(), root)
+	}
 	if l == nil {
 		return nil, fmt.Errorf("state %#x is not available", root)
 	}
@@ -337,11 +304,10 @@ func (db *Database) Enable(root common.Hash) error {
 		return errDatabaseReadOnly
 	}
 	// Ensure the provided state root matches the stored one.
-	// FIXME: We can't verify the root hash match without the proper API
-	// stored := rawdb.ReadAccountTrieNode(db.diskdb, nil)
-	// if stored != root {
-	// 	return fmt.Errorf("state root mismatch: stored %x, synced %x", stored, root)
-	// }
+	root = types.TrieRootHash(root)
+	if stored := diskRoot(db.diskdb); stored != root {
+		return fmt.Errorf("state root mismatch: stored %x, synced %x", stored, root)
+	}
 	// Drop the stale state journal in persistent database and
 	// reset the persistent state id back to zero.
 	batch := db.diskdb.NewBatch()
@@ -350,16 +316,12 @@ func (db *Database) Enable(root common.Hash) error {
 	if err := batch.Write(); err != nil {
 		return err
 	}
-	// NOTE(freezer): This is disabled since we do not have a freezer.
-	// Clean up all state histories in freezer. Theoretically
-	// all root->id mappings should be removed as well. Since
-	// mappings can be huge and might take a while to clear
-	// them, just leave them in disk and wait for overwriting.
-	// if db.freezer != nil {
-	// 	if err := db.freezer.Reset(); err != nil {
-	// 		return err
-	// 	}
-	// }
+	// Clean up all state histories. Theoretically all root->id
+	// mappings should be removed as well, which is done by the
+	// history deletion for the ones still covered by histories.
+	if err := resetHistories(db.diskdb); err != nil {
+		return err
+	}
 	// Re-construct a new disk layer backed by persistent state
 	// with **empty clean cache and node buffer**.
 	db.tree.reset(newDiskLayer(root, 0, db, nil, newNodeBuffer(db.bufferSize, nil, 0)))
@@ -374,14 +336,57 @@ func (db *Database) Enable(root common.Hash) error {
 // Recover rollbacks the database to a specified historical point.
 // The state is supported as the rollback destination only if it's
 // canonical state and the corresponding trie histories are existent.
-// FIXME: TrieLoader type doesn't exist in ethereum v1.16.1
-// func (db *Database) Recover(root common.Hash, loader TrieLoader) error {
-// 	// NOTE(freezer): This is disabled since we do not have a freezer.
-// 	return errors.New("state rollback is non-supported")
-// }
+func (db *Database) Recover(root common.Hash, loader TrieLoader) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	// Short circuit if rollback operation is not supported.
+	if err := db.modifyAllowed(); err != nil {
+		return err
+	}
+	root = types.TrieRootHash(root)
+	if !db.recoverable(root) {
+		return errStateUnrecoverable
+	}
+	// Apply the state histories upon the disk layer in order.
+	var (
+		start = time.Now()
+		dl    = db.tree.bottom()
+	)
+	for dl.rootHash() != root {
+		h, err := readHistory(db.diskdb, dl.stateID())
+		if err != nil {
+			return err
+		}
+		dl, err = dl.revert(h, loader)
+		if err != nil {
+			return err
+		}
+		// reset layer with newly created disk layer. It must be
+		// done after each revert operation, otherwise the new
+		// disk layer won't be accessible from outside.
+		db.tree.reset(dl)
+	}
+	rawdb.DeleteTrieJournal(db.diskdb)
+	_, err := truncateFromHead(db.diskdb, dl.stateID())
+	if err != nil {
+		return err
+	}
+	log.Debug("Recovered state", "root", root, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
 
 // Recoverable returns the indicator if the specified state is recoverable.
 func (db *Database) Recoverable(root common.Hash) bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.recoverable(types.TrieRootHash(root))
+}
+
+// recoverable is the lock-free version of Recoverable, the caller must hold
+// the database lock.
+func (db *Database) recoverable(root common.Hash) bool {
 	// Ensure the requested state is a known state.
 	id := rawdb.ReadStateID(db.diskdb, root)
 	if id == nil {
@@ -394,24 +399,22 @@ func (db *Database) Recoverable(root common.Hash) bool {
 	if *id >= dl.stateID() {
 		return false
 	}
-	return false
-	// NOTE(freezer): This is disabled since we do not have a freezer.
 	// Ensure the requested state is a canonical state and all state
 	// histories in range [id+1, disklayer.ID] are present and complete.
-	// parent := root
-	// return checkHistories(db.freezer, *id+1, dl.stateID()-*id, func(m *meta) error {
-	// 	if m.parent != parent {
-	// 		return errors.New("unexpected state history")
-	// 	}
-	// 	if len(m.incomplete) > 0 {
-	// 		return errors.New("incomplete state history")
-	// 	}
-	// 	parent = m.root
-	// 	return nil
-	// }) == nil
+	parent := root
+	return checkHistories(db.diskdb, *id+1, dl.stateID()-*id, func(m *meta) error {
+		if m.parent != parent {
+			return errors.New("unexpected state history")
+		}
+		if len(m.incomplete) > 0 {
+			return errors.New("incomplete state history")
+		}
+		parent = m.root
+		return nil
+	}) == nil
 }
 
-// Close closes the trie database and the held freezer.
+// Close closes the trie database.
 func (db *Database) Close() error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -422,13 +425,6 @@ func (db *Database) Close() error {
 
 	// Release the memory held by clean cache.
 	db.tree.bottom().resetCache()
-
-	// NOTE(freezer): This is disabled since we do not have a freezer.
-	// Close the attached state history freezer.
-	//if db.freezer == nil {
-	//	return nil
-	//}
-	//return db.freezer.Close()
 	return nil
 }
 
@@ -491,3 +487,13 @@ func (db *Database) modifyAllowed() error {
 	}
 	return nil
 }
+
+// diskRoot returns the root of the persistent state, the empty root hash is
+// returned if the persistent state is empty.
+func diskRoot(db ethdb.KeyValueReader) common.Hash {
+	blob, _ := rawdb.ReadAccountTrieNode(db, nil)
+	if len(blob) == 0 {
+		return types.EmptyRootHash
+	}
+	return crypto.Keccak256Hash(blob)
+}
@@ -34,7 +34,7 @@ import (
 	"testing"
 
 	"github.com/luxfi/geth/common"
-	"github.com/luxfi/evm/interfaces/core/rawdb"
+	"github.com/luxfi/geth/core/rawdb"
 	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/crypto"
 	"github.com/luxfi/geth/rlp"
@@ -108,8 +108,6 @@ type tester struct {
 
 func newTester(t *testing.T, historyLimit uint64) *tester {
 	var (
-		// NOTE(freezer): This is disabled since we do not have a freezer.
-		// disk, _ = rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
 		disk = rawdb.NewMemoryDatabase()
 		db   = New(disk, &Config{
 			StateHistory:   historyLimit,
@@ -315,7 +313,7 @@ func (t *tester) generate(parent common.Hash) (common.Hash, *trienode.MergedNode
 			}
 		}
 	}
-	return root, ctx.nodes, triestate.New(ctx.accountOrigin, ctx.storageOrigin, nil)
+	return root, ctx.nodes, NewStateSetWithOrigin(ctx.accountOrigin, ctx.storageOrigin, nil)
 }
 
 // lastHash returns the latest root hash, or empty if nothing is cached.
@@ -352,6 +350,39 @@ func (t *tester) verifyState(root common.Hash) error {
 	return nil
 }
 
+// verifyHistory checks that the state histories of all layers flattened into
+// disk are stored, and that no history exists for the layers above.
+func (t *tester) verifyHistory() error {
+	bottom := t.bottomIndex()
+	for i, root := range t.roots {
+		// The state history related to the state above disk layer should not exist.
+		if i > bottom {
+			_, err := readHistory(t.db.diskdb, uint64(i+1))
+			if err == nil {
+				return errors.New("unexpected state history")
+			}
+			continue
+		}
+		// The state history related to the state below or equal to the disk layer
+		// should exist.
+		obj, err := readHistory(t.db.diskdb, uint64(i+1))
+		if err != nil {
+			return err
+		}
+		parent := types.EmptyRootHash
+		if i != 0 {
+			parent = t.roots[i-1]
+		}
+		if obj.meta.parent != parent {
+			return fmt.Errorf("unexpected parent, want: %x, got: %x", parent, obj.meta.parent)
+		}
+		if obj.meta.root != root {
+			return fmt.Errorf("unexpected root, want: %x, got: %x", root, obj.meta.root)
+		}
+	}
+	return nil
+}
+
 // bottomIndex returns the index of current disk layer.
 func (t *tester) bottomIndex() int {
 	bottom := t.db.tree.bottom()
@@ -368,10 +399,9 @@ func TestDatabaseRollback(t *testing.T) {
 	tester := newTester(t, 0)
 	defer tester.release()
 
-	// NOTE(freezer): This is disabled since we do not have a freezer.
-	// if err := tester.verifyHistory(); err != nil {
-	// 	t.Fatalf("Invalid state history, err: %v", err)
-	// }
+	if err := tester.verifyHistory(); err != nil {
+		t.Fatalf("Invalid state history, err: %v", err)
+	}
 	// Revert database from top to bottom
 	for i := tester.bottomIndex(); i >= 0; i-- {
 		root := tester.roots[i]
@@ -380,17 +410,14 @@ func TestDatabaseRollback(t *testing.T) {
 			parent = tester.roots[i-1]
 		}
 		loader := newHashLoader(tester.snapAccounts[root], tester.snapStorages[root])
-		// NOTE(freezer): This is disabled since we do not have a freezer.
-		// if err := tester.db.Recover(parent, loader); err != nil {
-		// 	t.Fatalf("Failed to revert db, err: %v", err)
-		// }
-		require.ErrorContains(t, tester.db.Recover(parent, loader), "state rollback is non-supported")
+		if err := tester.db.Recover(parent, loader); err != nil {
+			t.Fatalf("Failed to revert db, err: %v", err)
+		}
 		tester.verifyState(parent)
 	}
-	// NOTE(freezer): This is disabled since we do not have a freezer.
-	// if tester.db.tree.len() != 1 {
-	// 	t.Fatal("Only disk layer is expected")
-	// }
+	if tester.db.tree.len() != 1 {
+		t.Fatal("Only disk layer is expected")
+	}
 }
 
 func TestDatabaseRecoverable(t *testing.T) {
@@ -426,9 +453,7 @@ func TestDatabaseRecoverable(t *testing.T) {
 	}
 	for i, c := range cases {
 		result := tester.db.Recoverable(c.root)
-		// NOTE(freezer): This is disabled since we do not have a freezer.
-		// originally was `result != c.expect`
-		if result != false {
+		if result != c.expect {
 			t.Fatalf("case: %d, unexpected result, want %t, got %t", i, c.expect, result)
 		}
 	}
@@ -438,7 +463,7 @@ func TestDisable(t *testing.T) {
 	tester := newTester(t, 0)
 	defer tester.release()
 
-	_, stored := rawdb.ReadAccountTrieNode(tester.db.diskdb, nil)
+	stored := diskRoot(tester.db.diskdb)
 	if err := tester.db.Disable(); err != nil {
 		t.Fatal("Failed to deactivate database")
 	}
@@ -453,15 +478,10 @@ func TestDisable(t *testing.T) {
 	if blob := rawdb.ReadTrieJournal(tester.db.diskdb); len(blob) != 0 {
 		t.Fatal("Failed to clean journal")
 	}
-	// NOTE(freezer): This is disabled since we do not have a freezer.
 	// Ensure all trie histories are removed
-	// n, err := tester.db.freezer.Ancients()
-	// if err != nil {
-	// 	t.Fatal("Failed to clean state history")
-	// }
-	// if n != 0 {
-	// 	t.Fatal("Failed to clean state history")
-	// }
+	if n := readStateHistoryHead(tester.db.diskdb); n != 0 {
+		t.Fatal("Failed to clean state history")
+	}
 	// Verify layer tree structure, single disk layer is expected
 	if tester.db.tree.len() != 1 {
 		t.Fatalf("Extra layer kept %d", tester.db.tree.len())
@@ -489,11 +509,10 @@ func TestCommit(t *testing.T) {
 	if err := tester.verifyState(tester.lastHash()); err != nil {
 		t.Fatalf("State is invalid, err: %v", err)
 	}
-	// NOTE(freezer): This is disabled since we do not have a freezer.
 	// Verify state histories
-	// if err := tester.verifyHistory(); err != nil {
-	// 	t.Fatalf("State history is invalid, err: %v", err)
-	// }
+	if err := tester.verifyHistory(); err != nil {
+		t.Fatalf("State history is invalid, err: %v", err)
+	}
 }
 
 func TestJournal(t *testing.T) {
@@ -528,7 +547,7 @@ func TestCorruptedJournal(t *testing.T) {
 		t.Errorf("Failed to journal, err: %v", err)
 	}
 	tester.db.Close()
-	_, root := rawdb.ReadAccountTrieNode(tester.db.diskdb, nil)
+	root := diskRoot(tester.db.diskdb)
 
 	// Mutate the journal in disk, it should be regarded as invalid
 	blob := rawdb.ReadTrieJournal(tester.db.diskdb)
@@ -562,22 +581,93 @@ func TestCorruptedJournal(t *testing.T) {
 // In this scenario, it is mandatory to update the persistent state before
 // truncating the tail histories. This ensures that the ID of the persistent state
 // always falls within the range of [oldest-history-id, latest-history-id].
-// func TestTailTruncateHistory(t *testing.T) {
-// 	tester := newTester(t, 10)
-// 	defer tester.release()
-//
-// 	tester.db.Close()
-// 	tester.db = New(tester.db.diskdb, &Config{StateHistory: 10})
-//
-// 	head, err := tester.db.freezer.Ancients()
-// 	if err != nil {
-// 		t.Fatalf("Failed to obtain freezer head")
-// 	}
-// 	stored := rawdb.ReadPersistentStateID(tester.db.diskdb)
-// 	if head != stored {
-// 		t.Fatalf("Failed to truncate excess history object above, stored: %d, head: %d", stored, head)
-// 	}
-// }
+func TestTailTruncateHistory(t *testing.T) {
+	tester := newTester(t, 10)
+	defer tester.release()
+
+	tester.db.Close()
+	tester.db = New(tester.db.diskdb, &Config{StateHistory: 10})
+
+	head := readStateHistoryHead(tester.db.diskdb)
+	stored := rawdb.ReadPersistentStateID(tester.db.diskdb)
+	if head != stored {
+		t.Fatalf("Failed to truncate excess history object above, stored: %d, head: %d", stored, head)
+	}
+	if tail := readStateHistoryTail(tester.db.diskdb); tail > stored {
+		t.Fatalf("Persistent state is below the oldest history, stored: %d, tail: %d", stored, tail)
+	}
+}
+
+// TestDatabaseRestart simulates the crash and restart scenarios covered by
+// core/blockchain_repair_test.go at the trie database level, checking which
+// states are available after the database is reopened and that all states
+// below the persistent one can still be recovered from the state histories.
+func TestDatabaseRestart(t *testing.T) {
+	var cases = []struct {
+		name    string
+		journal bool // Whether the layers were journaled before the restart
+		crash   int  // Number of extra histories left above the disk layer by a crash
+	}{
+		{name: "crash without journal"},
+		{name: "clean shutdown", journal: true},
+		{name: "crash after history write", crash: 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tester := newTester(t, 0)
+			defer tester.release()
+
+			diskdb := tester.db.diskdb
+			if c.journal {
+				require.NoError(t, tester.db.Journal(tester.lastHash()))
+			}
+			tester.db.Close()
+
+			// Simulate histories written for the layers above the disk layer
+			// without the corresponding trie nodes being flushed.
+			head := readStateHistoryHead(diskdb)
+			for i := 0; i < c.crash; i++ {
+				h := makeHistory()
+				accountData, storageData, accountIndex, storageIndex := h.encode()
+				writeStateHistory(diskdb, head+uint64(i)+1, h.meta.encode(), accountIndex, storageIndex, accountData, storageData)
+			}
+			writeStateHistoryHead(diskdb, head+uint64(c.crash))
+
+			// Without a journal the database restarts from the persistent
+			// state, and all histories above it must be truncated.
+			tester.db = New(diskdb, nil)
+			require.Equal(t, tester.db.tree.bottom().stateID(), readStateHistoryHead(diskdb))
+			require.NoError(t, tester.verifyHistory())
+
+			// The layers above the disk layer are only available if journaled.
+			bottom := tester.bottomIndex()
+			for i := bottom + 1; i < len(tester.roots); i++ {
+				err := tester.verifyState(tester.roots[i])
+				if c.journal {
+					require.NoError(t, err, "state %d", i)
+				} else {
+					require.Error(t, err, "state %d", i)
+				}
+			}
+			// Roll the database back to the initial state.
+			for i := bottom; i >= 0; i-- {
+				root := tester.roots[i]
+				parent := types.EmptyRootHash
+				if i > 0 {
+					parent = tester.roots[i-1]
+				}
+				require.True(t, tester.db.Recoverable(parent), "state %d", i-1)
+				loader := newHashLoader(tester.snapAccounts[root], tester.snapStorages[root])
+				require.NoError(t, tester.db.Recover(parent, loader))
+				if i > 0 {
+					require.NoError(t, tester.verifyState(parent))
+				}
+			}
+			require.Equal(t, 1, tester.db.tree.len())
+			require.Zero(t, readStateHistoryHead(diskdb))
+		})
+	}
+}
 
 // copyAccounts returns a deep-copied account set of the provided one.
 func copyAccounts(set map[common.Hash][]byte) map[common.Hash][]byte {
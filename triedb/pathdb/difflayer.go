@@ -46,7 +46,7 @@ type diffLayer struct {
 	id     uint64                                    // Corresponding state id
 	block  uint64                                    // Associated block number
 	nodes  map[common.Hash]map[string]*trienode.Node // Cached trie nodes indexed by owner and path
-	states *Set                            // Associated state change set for building history
+	states *Set                                      // Associated state change set for building history
 	memory uint64                                    // Approximate guess as to how much memory we use
 
 	parent layer        // Parent layer modified by this one, never nil, **can be changed**
@@ -75,8 +75,7 @@ func newDiffLayer(parent layer, root common.Hash, id uint64, block uint64, nodes
 		count += len(subset)
 	}
 	if states != nil {
-		// FIXME: Can't access unexported stateSet field
-		// dl.memory += states.stateSet.size
+		dl.memory += uint64(states.Size())
 	}
 	dirtyWriteMeter.Mark(size)
 	diffLayerNodesMeter.Mark(int64(count))
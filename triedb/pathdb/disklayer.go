@@ -191,24 +191,16 @@ func (dl *diskLayer) commit(bottom *diffLayer, force bool) (*diskLayer, error) {
 		overflow bool
 		oldest   uint64
 	)
-	// NOTE(freezer): This is disabled since we do not have a freezer.
-	// if dl.db.freezer != nil {
-	// 	err := writeHistory(dl.db.freezer, bottom)
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	// 	// Determine if the persisted history object has exceeded the configured
-	// 	// limitation, set the overflow as true if so.
-	// 	tail, err := dl.db.freezer.Tail()
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	// 	limit := dl.db.config.StateHistory
-	// 	if limit != 0 && bottom.stateID()-tail > limit {
-	// 		overflow = true
-	// 		oldest = bottom.stateID() - limit + 1 // track the id of history **after truncation**
-	// 	}
-	// }
+	if err := writeHistory(dl.db.diskdb, bottom); err != nil {
+		return nil, err
+	}
+	// Determine if the persisted history object has exceeded the configured
+	// limitation, set the overflow as true if so.
+	limit := dl.db.config.StateHistory
+	if limit != 0 && bottom.stateID()-readStateHistoryTail(dl.db.diskdb) > limit {
+		overflow = true
+		oldest = bottom.stateID() - limit + 1 // track the id of history **after truncation**
+	}
 	// Mark the diskLayer as stale before applying any mutations on top.
 	dl.stale = true
 
@@ -236,19 +228,17 @@ func (dl *diskLayer) commit(bottom *diffLayer, force bool) (*diskLayer, error) {
 		return nil, err
 	}
 	// To remove outdated history objects from the end, we set the 'tail' parameter
-	// to 'oldest-1' due to the offset between the freezer index and the history ID.
+	// to 'oldest-1' as the stored histories are in the range of (tail, head].
 	if overflow {
-		// NOTE(freezer): This is disabled since we do not have a freezer.
-		// 	pruned, err := truncateFromTail(ndl.db.diskdb, ndl.db.freezer, oldest-1)
-		// 	if err != nil {
-		// 		return nil, err
-		// 	}
-		// 	log.Debug("Pruned state history", "items", pruned, "tailid", oldest)
+		pruned, err := truncateFromTail(ndl.db.diskdb, oldest-1)
+		if err != nil {
+			return nil, err
+		}
+		log.Debug("Pruned state history", "items", pruned, "tailid", oldest)
 	}
 	return ndl, nil
 }
 
-// nolint: unused
 // revert applies the given state history and return a reverted disk layer.
 func (dl *diskLayer) revert(h *history, loader TrieLoader) (*diskLayer, error) {
 	if h.meta.root != dl.rootHash() {
@@ -266,12 +256,10 @@ func (dl *diskLayer) revert(h *history, loader TrieLoader) (*diskLayer, error) {
 	// Apply the reverse state changes upon the current state. This must
 	// be done before holding the lock in order to access state in "this"
 	// layer.
-	// FIXME: triestate.Apply doesn't exist in ethereum v1.16.1
-	// nodes, err := triestate.Apply(h.meta.parent, h.meta.root, h.accounts, h.storages, loader)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// var nodes *trienode.MergedNodeSet
+	nodes, err := apply(h.meta.parent, h.meta.root, h.accounts, h.storages, loader)
+	if err != nil {
+		return nil, err
+	}
 	// Mark the diskLayer as stale before applying any mutations on top.
 	dl.lock.Lock()
 	defer dl.lock.Unlock()
@@ -284,15 +272,13 @@ func (dl *diskLayer) revert(h *history, loader TrieLoader) (*diskLayer, error) {
 	// needs to be reverted is not yet flushed and cached in node
 	// buffer, otherwise, manipulate persistent state directly.
 	if !dl.buffer.empty() {
-		// FIXME: Can't pass MergedNodeSet to revert which expects map type
-		err := errors.New("revert not implemented for MergedNodeSet")
+		err := dl.buffer.revert(dl.db.diskdb, nodes)
 		if err != nil {
 			return nil, err
 		}
 	} else {
 		batch := dl.db.diskdb.NewBatch()
-		// FIXME: Can't pass MergedNodeSet to writeNodes which expects map type
-		// writeNodes(batch, nodes, dl.cleans)
+		writeNodes(batch, nodes, dl.cleans)
 		rawdb.WritePersistentStateID(batch, dl.id-1)
 		if err := batch.Write(); err != nil {
 			log.Crit("Failed to write states", "err", err)
@@ -48,16 +48,18 @@ var (
 	// to not maintain the layer's original state.
 	errSnapshotStale = errors.New("layer stale")
 
-	// nolint: unused
 	// errUnexpectedHistory is returned if an unmatched state history is applied
 	// to the database for state rollback.
 	errUnexpectedHistory = errors.New("unexpected state history")
 
-	// nolint: unused
 	// errStateUnrecoverable is returned if state is required to be reverted to
 	// a destination without associated state history available.
 	errStateUnrecoverable = errors.New("state is unrecoverable")
 
+	// errStateTooDeep is returned if a historic state is requested further
+	// below the disk layer than the layers allowed to be rebuilt.
+	errStateTooDeep = errors.New("state is too deep below the disk layer")
+
 	// errUnexpectedNode is returned if the requested node with specified path is
 	// not hash matched with expectation.
 	errUnexpectedNode = errors.New("unexpected node")
@@ -31,8 +31,12 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/geth/log"
 	"slices"
 )
 
@@ -46,8 +50,8 @@ import (
 // no state changes whatsoever, no state is created for it. Each state history
 // will have a sequentially increasing number acting as its unique identifier.
 //
-// The state history is written to disk (ancient store) when the corresponding
-// diff layer is merged into the disk layer. At the same time, system can prune
+// The state history is written to disk (key-value store, see schema.go) when the
+// corresponding diff layer is merged into the disk layer. At the same time, system can prune
 // the oldest histories according to config.
 //
 //                                                        Disk State
@@ -264,25 +268,22 @@ func newHistory(root common.Hash, parent common.Hash, block uint64, states *Set)
 		storageList = make(map[common.Address][]common.Hash)
 		incomplete  []common.Address
 	)
-	// FIXME: Can't access unexported stateSet field
-	// for addr := range states.stateSet.accountData {
-	// 	accountList = append(accountList, common.BytesToAddress(addr.Bytes()))
-	// }
+	for addr := range states.Accounts {
+		accountList = append(accountList, addr)
+	}
 	slices.SortFunc(accountList, common.Address.Cmp)
 
-	// FIXME: Can't access unexported stateSet field
-	// for addr, slots := range states.stateSet.storageData {
-	// 	slist := make([]common.Hash, 0, len(slots))
-	// 	for slotHash := range slots {
-	// 		slist = append(slist, slotHash)
-	// 	}
-	// 	slices.SortFunc(slist, common.Hash.Cmp)
-	// 	storageList[common.BytesToAddress(addr.Bytes())] = slist
-	// }
-	// FIXME: Incomplete doesn't exist in StateSetWithOrigin
-	// for addr := range states.Incomplete {
-	// 	incomplete = append(incomplete, addr)
-	// }
+	for addr, slots := range states.Storages {
+		slist := make([]common.Hash, 0, len(slots))
+		for slotHash := range slots {
+			slist = append(slist, slotHash)
+		}
+		slices.SortFunc(slist, common.Hash.Cmp)
+		storageList[addr] = slist
+	}
+	for addr := range states.Incomplete {
+		incomplete = append(incomplete, addr)
+	}
 	slices.SortFunc(incomplete, common.Address.Cmp)
 
 	return &history{
@@ -293,9 +294,9 @@ func newHistory(root common.Hash, parent common.Hash, block uint64, states *Set)
 			block:      block,
 			incomplete: incomplete,
 		},
-		accounts:    make(map[common.Address][]byte), // FIXME: Convert from states.stateSet.accountData
+		accounts:    states.Accounts,
 		accountList: accountList,
-		storages:    make(map[common.Address]map[common.Hash][]byte), // FIXME: Convert from states.stateSet.storageData
+		storages:    states.Storages,
 		storageList: storageList,
 	}
 }
@@ -496,3 +497,145 @@ func (h *history) decode(accountData, storageData, accountIndexes, storageIndexe
 	h.storageList = storageList
 	return nil
 }
+
+// readHistory reads and decodes the state history object by the given id.
+func readHistory(db ethdb.KeyValueReader, id uint64) (*history, error) {
+	blob, accountIndexes, storageIndexes, accountData, storageData := readStateHistory(db, id)
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("state history not found %d", id)
+	}
+	var m meta
+	if err := m.decode(blob); err != nil {
+		return nil, err
+	}
+	dec := history{meta: &m}
+	if err := dec.decode(accountData, storageData, accountIndexes, storageIndexes); err != nil {
+		return nil, err
+	}
+	return &dec, nil
+}
+
+// writeHistory writes the state history with provided state set. The histories
+// must be written in order, the id of the given layer must follow the id of the
+// most recently stored history.
+func writeHistory(db ethdb.KeyValueStore, dl *diffLayer) error {
+	// Short circuit if state set is not available.
+	if dl.states == nil {
+		return errors.New("state change set is not available")
+	}
+	if head := readStateHistoryHead(db); head+1 != dl.stateID() {
+		return fmt.Errorf("out of order state history, head: %d, id: %d", head, dl.stateID())
+	}
+	var (
+		start   = time.Now()
+		history = newHistory(dl.rootHash(), dl.parentLayer().rootHash(), dl.block, dl.states)
+	)
+	accountData, storageData, accountIndex, storageIndex := history.encode()
+	dataSize := common.StorageSize(len(accountData) + len(storageData))
+	indexSize := common.StorageSize(len(accountIndex) + len(storageIndex))
+
+	// Write history data and move the head atomically, so a crash can not
+	// leave a partially written history behind.
+	batch := db.NewBatch()
+	writeStateHistory(batch, dl.stateID(), history.meta.encode(), accountIndex, storageIndex, accountData, storageData)
+	writeStateHistoryHead(batch, dl.stateID())
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	historyDataBytesMeter.Mark(int64(dataSize))
+	historyIndexBytesMeter.Mark(int64(indexSize))
+	historyBuildTimeMeter.UpdateSince(start)
+	log.Debug("Stored state history", "id", dl.stateID(), "block", dl.block, "data", dataSize, "index", indexSize, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
+// checkHistories retrieves a batch of meta objects with the specified range
+// and performs the callback on each item.
+func checkHistories(db ethdb.KeyValueReader, start, count uint64, check func(*meta) error) error {
+	for id := start; id < start+count; id++ {
+		blob := readStateHistoryMeta(db, id)
+		if len(blob) == 0 {
+			return fmt.Errorf("state history not found %d", id)
+		}
+		var dec meta
+		if err := dec.decode(blob); err != nil {
+			return err
+		}
+		if err := check(&dec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncateFromHead removes the extra state histories from the head with the given
+// parameters. It returns the number of items removed from the head.
+func truncateFromHead(db ethdb.KeyValueStore, nhead uint64) (int, error) {
+	ohead, otail := readStateHistoryHead(db), readStateHistoryTail(db)
+	if ohead <= nhead {
+		return 0, nil
+	}
+	if nhead < otail {
+		return 0, fmt.Errorf("out of range, tail: %d, head: %d, target: %d", otail, ohead, nhead)
+	}
+	if err := deleteHistories(db, nhead+1, ohead); err != nil {
+		return 0, err
+	}
+	writeStateHistoryHead(db, nhead)
+	return int(ohead - nhead), nil
+}
+
+// truncateFromTail removes the extra state histories from the tail with the given
+// parameters. It returns the number of items removed from the tail.
+func truncateFromTail(db ethdb.KeyValueStore, ntail uint64) (int, error) {
+	ohead, otail := readStateHistoryHead(db), readStateHistoryTail(db)
+	if otail >= ntail {
+		return 0, nil
+	}
+	if ntail > ohead {
+		return 0, fmt.Errorf("out of range, tail: %d, head: %d, target: %d", otail, ohead, ntail)
+	}
+	if err := deleteHistories(db, otail+1, ntail); err != nil {
+		return 0, err
+	}
+	writeStateHistoryTail(db, ntail)
+	return int(ntail - otail), nil
+}
+
+// resetHistories removes all the stored state histories along with the
+// associated root->id lookups, and resets both head and tail to zero.
+func resetHistories(db ethdb.KeyValueStore) error {
+	head, tail := readStateHistoryHead(db), readStateHistoryTail(db)
+	if head > tail {
+		if err := deleteHistories(db, tail+1, head); err != nil {
+			return err
+		}
+	}
+	batch := db.NewBatch()
+	writeStateHistoryHead(batch, 0)
+	writeStateHistoryTail(batch, 0)
+	return batch.Write()
+}
+
+// deleteHistories removes the state histories in the range [from, to] along
+// with the root->id lookups of the states they produced.
+func deleteHistories(db ethdb.KeyValueStore, from, to uint64) error {
+	batch := db.NewBatch()
+	for id := from; id <= to; id++ {
+		var m meta
+		if err := m.decode(readStateHistoryMeta(db, id)); err != nil {
+			return err
+		}
+		rawdb.DeleteStateID(batch, m.root)
+		deleteStateHistory(batch, id)
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	return batch.Write()
+}
@@ -0,0 +1,135 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pathdb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/log"
+	"github.com/luxfi/geth/triedb/database"
+)
+
+// historicLayers holds the layers rebuilt from the state histories below the
+// disk layer. Unlike [Database.Recover], rebuilding a historic state leaves the
+// persistent state untouched: each rebuilt layer only carries the trie nodes
+// reverting its child, and resolves everything else through it.
+//
+// The rebuilt layers form a single chain on top of the disk layer they were
+// derived from, and are discarded once that disk layer is replaced. As every
+// layer references the ones above it, the chain can only be released as a
+// whole, hence its length is capped by [Config.HistoricLayers].
+type historicLayers struct {
+	build sync.Mutex // Lock to serialize rebuilding, held while applying histories
+
+	lock   sync.RWMutex
+	base   *diskLayer            // Disk layer the rebuilt layers are derived from
+	bottom layer                 // Oldest rebuilt layer, the base if nothing is rebuilt yet
+	layers map[common.Hash]layer // Rebuilt layers indexed by state root
+}
+
+// get returns the rebuilt layer with the given state root, or nil if it's not
+// rebuilt on top of the given disk layer.
+func (h *historicLayers) get(base *diskLayer, root common.Hash) layer {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	if h.base != base {
+		return nil
+	}
+	return h.layers[root]
+}
+
+// add links a newly rebuilt layer below the current bottom.
+func (h *historicLayers) add(l layer) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.layers[l.rootHash()] = l
+	h.bottom = l
+}
+
+// reset drops all the rebuilt layers if they are not derived from the given
+// disk layer, and returns the layer to continue rebuilding from.
+func (h *historicLayers) reset(base *diskLayer) layer {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.base != base {
+		h.base = base
+		h.bottom = base
+		h.layers = make(map[common.Hash]layer)
+	}
+	return h.bottom
+}
+
+// HistoricReader rebuilds the state with the given root by applying the state
+// histories upon the disk layer, and returns a reader of it. The state must be
+// canonical, below the disk layer and within the retained history window (see
+// [Config.StateHistory]). The loader is used to open the tries of the states
+// being rebuilt, which are readable through [Database.Reader] meanwhile.
+//
+// The rebuilt states are cached until the disk layer is replaced, so reading
+// states in descending order only applies every history once. At most
+// [Config.HistoricLayers] states below the disk layer can be rebuilt, so that
+// the memory held by the cached layers is bounded.
+func (db *Database) HistoricReader(root common.Hash, loader TrieLoader) (database.NodeReader, error) {
+	root = types.TrieRootHash(root)
+
+	db.lock.RLock()
+	if l := db.tree.get(root); l != nil {
+		db.lock.RUnlock()
+		return l, nil
+	}
+	if db.waitSync || !db.recoverable(root) {
+		db.lock.RUnlock()
+		return nil, fmt.Errorf("%w: %#x", errStateUnrecoverable, root)
+	}
+	var (
+		base  = db.tree.bottom()
+		depth = base.stateID() - *rawdb.ReadStateID(db.diskdb, root)
+	)
+	db.lock.RUnlock()
+
+	if depth > db.config.HistoricLayers {
+		return nil, fmt.Errorf("%w: %#x is %d states below, limit %d", errStateTooDeep, root, depth, db.config.HistoricLayers)
+	}
+
+	db.historic.build.Lock()
+	defer db.historic.build.Unlock()
+
+	if l := db.historic.get(base, root); l != nil {
+		return l, nil
+	}
+	var (
+		start = time.Now()
+		count int
+		dl    = db.historic.reset(base)
+	)
+	// The requested state is canonical and not yet rebuilt, hence it must be
+	// below the oldest rebuilt one.
+	for dl.rootHash() != root {
+		h, err := readHistory(db.diskdb, dl.stateID())
+		if err != nil {
+			return nil, err
+		}
+		if h.meta.root != dl.rootHash() {
+			return nil, errUnexpectedHistory
+		}
+		nodes, err := apply(h.meta.parent, h.meta.root, h.accounts, h.storages, loader)
+		if err != nil {
+			return nil, err
+		}
+		dl = newDiffLayer(dl, h.meta.parent, dl.stateID()-1, h.meta.block-1, nodes, nil)
+		db.historic.add(dl)
+		count++
+	}
+	historicRebuildTimer.UpdateSince(start)
+	log.Debug("Rebuilt historic state", "root", root, "id", dl.stateID(), "histories", count, "elapsed", common.PrettyDuration(time.Since(start)))
+	return dl, nil
+}
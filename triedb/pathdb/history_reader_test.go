@@ -0,0 +1,123 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pathdb
+
+import (
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// snapLoader opens the tries of any state captured by the tester.
+type snapLoader struct {
+	t *tester
+}
+
+func (l *snapLoader) OpenTrie(root common.Hash) (Trie, error) {
+	return newHashLoader(l.t.snapAccounts[root], l.t.snapStorages[root]).OpenTrie(root)
+}
+
+func (l *snapLoader) OpenStorageTrie(stateRoot common.Hash, addrHash, root common.Hash) (Trie, error) {
+	return newHashLoader(l.t.snapAccounts[stateRoot], l.t.snapStorages[stateRoot]).OpenStorageTrie(stateRoot, addrHash, root)
+}
+
+func TestHistoricReader(t *testing.T) {
+	tester := newTester(t, 0)
+	defer tester.release()
+
+	var (
+		loader = &snapLoader{t: tester}
+		bottom = tester.bottomIndex()
+		diskID = tester.db.tree.bottom().stateID()
+	)
+	require.Positive(t, bottom)
+
+	// Historic states are not available through the plain reader.
+	_, err := tester.db.Reader(tester.roots[bottom-1])
+	require.Error(t, err)
+
+	// Rebuild the states in descending order, every state must be readable
+	// right after it's rebuilt, and stays readable afterwards.
+	for i := bottom - 1; i >= 0; i-- {
+		reader, err := tester.db.HistoricReader(tester.roots[i], loader)
+		require.NoError(t, err, "state %d", i)
+		require.NotNil(t, reader)
+		require.NoError(t, tester.verifyState(tester.roots[i]), "state %d", i)
+	}
+	for i := bottom - 1; i >= 0; i-- {
+		require.NoError(t, tester.verifyState(tester.roots[i]), "state %d", i)
+	}
+	// The live states are served as they are.
+	_, err = tester.db.HistoricReader(tester.lastHash(), loader)
+	require.NoError(t, err)
+
+	// Rebuilding must not touch the persistent state or the histories.
+	require.Equal(t, diskID, tester.db.tree.bottom().stateID())
+	require.Equal(t, diskID, readStateHistoryHead(tester.db.diskdb))
+	require.NoError(t, tester.verifyHistory())
+
+	// Unknown states can't be rebuilt.
+	_, err = tester.db.HistoricReader(common.Hash{0x1}, loader)
+	require.ErrorIs(t, err, errStateUnrecoverable)
+
+	// The states are rebuilt on a fresh cache once the disk layer moves.
+	require.NoError(t, tester.db.Commit(tester.lastHash(), false))
+	_, err = tester.db.Reader(tester.roots[0])
+	require.Error(t, err)
+	_, err = tester.db.HistoricReader(tester.roots[bottom], loader)
+	require.NoError(t, err)
+	require.NoError(t, tester.verifyState(tester.roots[bottom]))
+}
+
+func TestHistoricReaderLimit(t *testing.T) {
+	tester := newTester(t, 0)
+	defer tester.release()
+
+	const limit = 16
+	tester.db.config.HistoricLayers = limit
+
+	var (
+		loader = &snapLoader{t: tester}
+		bottom = tester.bottomIndex()
+	)
+	require.Greater(t, bottom, limit)
+
+	// The deepest state allowed, produced by the history [limit] states below
+	// the disk layer, can be rebuilt along with the ones above it.
+	_, err := tester.db.HistoricReader(tester.roots[bottom-limit], loader)
+	require.NoError(t, err)
+	for i := bottom - 1; i >= bottom-limit; i-- {
+		require.NoError(t, tester.verifyState(tester.roots[i]), "state %d", i)
+	}
+	require.Len(t, tester.db.historic.layers, limit)
+
+	// The states below it can't, and nothing more is rebuilt.
+	_, err = tester.db.HistoricReader(tester.roots[bottom-limit-1], loader)
+	require.ErrorIs(t, err, errStateTooDeep)
+	require.Error(t, tester.verifyState(tester.roots[bottom-limit-1]))
+	require.Len(t, tester.db.historic.layers, limit)
+}
+
+func TestHistoricReaderWindow(t *testing.T) {
+	tester := newTester(t, 10)
+	defer tester.release()
+
+	var (
+		loader = &snapLoader{t: tester}
+		tail   = readStateHistoryTail(tester.db.diskdb)
+	)
+	require.Positive(t, tail)
+
+	// The oldest state within the retained history window, produced by the
+	// first history after the tail, can be rebuilt. Note state n is the one
+	// of roots[n-1].
+	_, err := tester.db.HistoricReader(tester.roots[tail], loader)
+	require.NoError(t, err)
+	require.NoError(t, tester.verifyState(tester.roots[tail]))
+
+	// States below the retained history window can't.
+	_, err = tester.db.HistoricReader(tester.roots[tail-1], loader)
+	require.ErrorIs(t, err, errStateUnrecoverable)
+}
@@ -32,7 +32,9 @@ import (
 	"testing"
 
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/rawdb"
 	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethdb"
 	"github.com/luxfi/geth/rlp"
 	"github.com/luxfi/evm/interfaces/trie/testutil"
 )
@@ -53,14 +55,13 @@ func randomStateSet(n int) *Set {
 		account := generateAccount(types.EmptyRootHash)
 		accounts[addr] = types.SlimAccountRLP(account)
 	}
-	return triestate.New(accounts, storages, nil)
+	return NewStateSetWithOrigin(accounts, storages, nil)
 }
 
 func makeHistory() *history {
 	return newHistory(testutil.RandomHash(), types.EmptyRootHash, 0, randomStateSet(3))
 }
 
-// nolint: unused
 func makeHistories(n int) []*history {
 	var (
 		parent = types.EmptyRootHash
@@ -109,6 +110,138 @@ func TestEncodeDecodeHistory(t *testing.T) {
 	}
 }
 
+// storeHistories writes the given histories in order on top of the stored
+// ones, along with the root->id lookups of the states they produced.
+func storeHistories(db ethdb.KeyValueStore, hs []*history) {
+	head := readStateHistoryHead(db)
+	for i, h := range hs {
+		id := head + uint64(i) + 1
+		accountData, storageData, accountIndex, storageIndex := h.encode()
+		writeStateHistory(db, id, h.meta.encode(), accountIndex, storageIndex, accountData, storageData)
+		rawdb.WriteStateID(db, h.meta.root, id)
+	}
+	writeStateHistoryHead(db, head+uint64(len(hs)))
+}
+
+func checkHistory(t *testing.T, db ethdb.KeyValueReader, id uint64, root common.Hash, exist bool) {
+	blob := readStateHistoryMeta(db, id)
+	if exist && len(blob) == 0 {
+		t.Fatalf("Failed to load account history, id: %d", id)
+	}
+	if !exist && len(blob) != 0 {
+		t.Fatalf("Unexpected account history, id: %d", id)
+	}
+	if exist && rawdb.ReadStateID(db, root) == nil {
+		t.Fatalf("Root->ID mapping is not found, %d", id)
+	}
+	if !exist && rawdb.ReadStateID(db, root) != nil {
+		t.Fatalf("Unexpected root->ID mapping, %d", id)
+	}
+}
+
+func checkHistoriesInRange(t *testing.T, db ethdb.KeyValueReader, from, to uint64, roots []common.Hash, exist bool) {
+	for i, j := from, 0; i <= to; i, j = i+1, j+1 {
+		checkHistory(t, db, i, roots[j], exist)
+	}
+}
+
+func TestReadWriteHistory(t *testing.T) {
+	var (
+		db = rawdb.NewMemoryDatabase()
+		hs = makeHistories(10)
+	)
+	storeHistories(db, hs)
+	for i, h := range hs {
+		dec, err := readHistory(db, uint64(i+1))
+		if err != nil {
+			t.Fatalf("Failed to read history %d, err: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(dec.meta, h.meta) {
+			t.Fatalf("meta is mismatched, id: %d", i+1)
+		}
+		if !compareSet(dec.accounts, h.accounts) {
+			t.Fatalf("account data is mismatched, id: %d", i+1)
+		}
+		if !compareStorages(dec.storages, h.storages) {
+			t.Fatalf("storage data is mismatched, id: %d", i+1)
+		}
+	}
+	if _, err := readHistory(db, uint64(len(hs)+1)); err == nil {
+		t.Fatal("Unexpected history above the head")
+	}
+}
+
+func TestTruncateHeadHistory(t *testing.T) {
+	var (
+		roots []common.Hash
+		db    = rawdb.NewMemoryDatabase()
+		hs    = makeHistories(10)
+	)
+	storeHistories(db, hs)
+	for _, h := range hs {
+		roots = append(roots, h.meta.root)
+	}
+	for size := len(hs); size > 0; size-- {
+		pruned, err := truncateFromHead(db, uint64(size-1))
+		if err != nil {
+			t.Fatalf("Failed to truncate from head %v", err)
+		}
+		if pruned != 1 {
+			t.Error("Unexpected pruned items", "want", 1, "got", pruned)
+		}
+		checkHistoriesInRange(t, db, uint64(size), uint64(10), roots[size-1:], false)
+		checkHistoriesInRange(t, db, uint64(1), uint64(size-1), roots[:size-1], true)
+	}
+	if head := readStateHistoryHead(db); head != 0 {
+		t.Fatalf("Unexpected history head, want: 0, got: %d", head)
+	}
+}
+
+func TestTruncateTailHistory(t *testing.T) {
+	var (
+		roots []common.Hash
+		db    = rawdb.NewMemoryDatabase()
+		hs    = makeHistories(10)
+	)
+	storeHistories(db, hs)
+	for _, h := range hs {
+		roots = append(roots, h.meta.root)
+	}
+	pruned, _ := truncateFromTail(db, uint64(len(hs)-1))
+	if pruned != len(hs)-1 {
+		t.Error("Unexpected pruned items", "want", len(hs)-1, "got", pruned)
+	}
+	checkHistoriesInRange(t, db, uint64(1), uint64(len(hs)-1), roots[:len(hs)-1], false)
+	checkHistory(t, db, uint64(len(hs)), roots[len(hs)-1], true)
+
+	// Truncation out of the stored range must be rejected.
+	if _, err := truncateFromTail(db, uint64(len(hs)+1)); err == nil {
+		t.Fatal("Unexpected tail truncation above the head")
+	}
+	if _, err := truncateFromHead(db, uint64(len(hs)-2)); err == nil {
+		t.Fatal("Unexpected head truncation below the tail")
+	}
+}
+
+func TestResetHistories(t *testing.T) {
+	var (
+		roots []common.Hash
+		db    = rawdb.NewMemoryDatabase()
+		hs    = makeHistories(10)
+	)
+	storeHistories(db, hs)
+	for _, h := range hs {
+		roots = append(roots, h.meta.root)
+	}
+	if err := resetHistories(db); err != nil {
+		t.Fatalf("Failed to reset histories %v", err)
+	}
+	checkHistoriesInRange(t, db, uint64(1), uint64(len(hs)), roots, false)
+	if head, tail := readStateHistoryHead(db), readStateHistoryTail(db); head != 0 || tail != 0 {
+		t.Fatalf("Unexpected history range, head: %d, tail: %d", head, tail)
+	}
+}
+
 func compareSet[k comparable](a, b map[k][]byte) bool {
 	if len(a) != len(b) {
 		return false
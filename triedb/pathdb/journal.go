@@ -124,11 +124,7 @@ func (db *Database) loadJournal(diskRoot common.Hash) (layer, error) {
 // loadLayers loads a pre-existing state layer backed by a key-value store.
 func (db *Database) loadLayers() layer {
 	// Retrieve the root node of persistent state.
-	rootData, _ := rawdb.ReadAccountTrieNode(db.diskdb, nil)
-	var root common.Hash
-	if len(rootData) > 0 {
-		root = crypto.Keccak256Hash(rootData)
-	}
+	root := diskRoot(db.diskdb)
 
 	// Load the layers by resolving the journal
 	head, err := db.loadJournal(root)
@@ -250,8 +246,7 @@ func (db *Database) loadDiffLayer(parent layer, r *rlp.Stream) (layer, error) {
 		}
 		storages[entry.Account] = set
 	}
-	// FIXME: triestate.New doesn't exist, use NewStateSetWithOrigin instead
-	states := NewStateSetWithOrigin(accounts, storages, nil, nil, false)
+	states := NewStateSetWithOrigin(accounts, storages, incomplete)
 	return db.loadDiffLayer(newDiffLayer(parent, root, parent.stateID()+1, block, nodes, states), r)
 }
 
@@ -319,15 +314,31 @@ func (dl *diffLayer) journal(w io.Writer) error {
 		return err
 	}
 	// Write the accumulated state changes into buffer
-	var jacct journalAccounts
-	// FIXME: Can't access fields of StateSetWithOrigin as they are unexported
-	// The journal encoding would need to be reimplemented using the proper API
-	// For now, return empty data structures
+	var (
+		jacct   journalAccounts
+		storage []journalStorage
+	)
+	if dl.states != nil {
+		for addr, account := range dl.states.Accounts {
+			jacct.Addresses = append(jacct.Addresses, addr)
+			jacct.Accounts = append(jacct.Accounts, account)
+		}
+		storage = make([]journalStorage, 0, len(dl.states.Storages))
+		for addr, slots := range dl.states.Storages {
+			entry := journalStorage{Account: addr}
+			if _, ok := dl.states.Incomplete[addr]; ok {
+				entry.Incomplete = true
+			}
+			for slotHash, slot := range slots {
+				entry.Hashes = append(entry.Hashes, slotHash)
+				entry.Slots = append(entry.Slots, slot)
+			}
+			storage = append(storage, entry)
+		}
+	}
 	if err := rlp.Encode(w, jacct); err != nil {
 		return err
 	}
-	storage := make([]journalStorage, 0)
-	// FIXME: Need to access states data through proper API
 	if err := rlp.Encode(w, storage); err != nil {
 		return err
 	}
@@ -368,11 +379,7 @@ func (db *Database) Journal(root common.Hash) error {
 	}
 	// The stored state in disk might be empty, convert the
 	// root to emptyRoot in this case.
-	rootData, _ := rawdb.ReadAccountTrieNode(db.diskdb, nil)
-	var diskroot common.Hash
-	if len(rootData) > 0 {
-		diskroot = crypto.Keccak256Hash(rootData)
-	}
+	diskroot := diskRoot(db.diskdb)
 
 	// Secondly write out the state root in disk, ensure all layers
 	// on top are continuous with disk.
@@ -70,4 +70,5 @@ var (
 	historyBuildTimeMeter  = metrics.GetOrRegisterTimer("pathdb/history/time", nil)
 	historyDataBytesMeter  = metrics.GetOrRegisterMeter("pathdb/history/bytes/data", nil)
 	historyIndexBytesMeter = metrics.GetOrRegisterMeter("pathdb/history/bytes/index", nil)
+	historicRebuildTimer   = metrics.GetOrRegisterTimer("pathdb/history/rebuild", nil)
 )
@@ -0,0 +1,132 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pathdb
+
+import (
+	"encoding/binary"
+
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/geth/log"
+)
+
+// State histories are kept in the key-value store rather than in an ancient
+// store, since the VM does not run with a freezer. Each history is split into
+// the same five sections the freezer tables would hold, keyed by history id.
+var (
+	// stateHistoryPrefix + id (uint64 big endian) + section -> history section
+	stateHistoryPrefix = []byte("pathdb-h")
+
+	// stateHistoryHeadKey tracks the id of the most recently stored history.
+	stateHistoryHeadKey = []byte("pathdb-history-head")
+
+	// stateHistoryTailKey tracks the id of the most recently pruned history.
+	// Stored histories are those in the range (tail, head].
+	stateHistoryTailKey = []byte("pathdb-history-tail")
+)
+
+const (
+	stateHistoryMeta         = byte('m')
+	stateHistoryAccountIndex = byte('a')
+	stateHistoryStorageIndex = byte('s')
+	stateHistoryAccountData  = byte('A')
+	stateHistoryStorageData  = byte('S')
+)
+
+var stateHistorySections = []byte{
+	stateHistoryMeta,
+	stateHistoryAccountIndex,
+	stateHistoryStorageIndex,
+	stateHistoryAccountData,
+	stateHistoryStorageData,
+}
+
+// stateHistoryKey = stateHistoryPrefix + id (uint64 big endian) + section
+func stateHistoryKey(id uint64, section byte) []byte {
+	key := make([]byte, len(stateHistoryPrefix)+9)
+	copy(key, stateHistoryPrefix)
+	binary.BigEndian.PutUint64(key[len(stateHistoryPrefix):], id)
+	key[len(key)-1] = section
+	return key
+}
+
+// readStateHistoryHead retrieves the id of the latest stored state history.
+func readStateHistoryHead(db ethdb.KeyValueReader) uint64 {
+	return readUint64(db, stateHistoryHeadKey)
+}
+
+// writeStateHistoryHead stores the id of the latest stored state history.
+func writeStateHistoryHead(db ethdb.KeyValueWriter, id uint64) {
+	writeUint64(db, stateHistoryHeadKey, id)
+}
+
+// readStateHistoryTail retrieves the id of the latest pruned state history.
+func readStateHistoryTail(db ethdb.KeyValueReader) uint64 {
+	return readUint64(db, stateHistoryTailKey)
+}
+
+// writeStateHistoryTail stores the id of the latest pruned state history.
+func writeStateHistoryTail(db ethdb.KeyValueWriter, id uint64) {
+	writeUint64(db, stateHistoryTailKey, id)
+}
+
+// readStateHistoryMeta retrieves the metadata of the state history with the
+// given id.
+func readStateHistoryMeta(db ethdb.KeyValueReader, id uint64) []byte {
+	blob, err := db.Get(stateHistoryKey(id, stateHistoryMeta))
+	if err != nil {
+		return nil
+	}
+	return blob
+}
+
+// readStateHistory retrieves all sections of the state history with the given
+// id. Missing sections are returned as nil.
+func readStateHistory(db ethdb.KeyValueReader, id uint64) (meta, accountIndex, storageIndex, accounts, storages []byte) {
+	read := func(section byte) []byte {
+		blob, err := db.Get(stateHistoryKey(id, section))
+		if err != nil {
+			return nil
+		}
+		return blob
+	}
+	return read(stateHistoryMeta), read(stateHistoryAccountIndex), read(stateHistoryStorageIndex), read(stateHistoryAccountData), read(stateHistoryStorageData)
+}
+
+// writeStateHistory writes all sections of the state history with the given id.
+func writeStateHistory(db ethdb.KeyValueWriter, id uint64, meta, accountIndex, storageIndex, accounts, storages []byte) {
+	for section, blob := range map[byte][]byte{
+		stateHistoryMeta:         meta,
+		stateHistoryAccountIndex: accountIndex,
+		stateHistoryStorageIndex: storageIndex,
+		stateHistoryAccountData:  accounts,
+		stateHistoryStorageData:  storages,
+	} {
+		if err := db.Put(stateHistoryKey(id, section), blob); err != nil {
+			log.Crit("Failed to store state history", "id", id, "err", err)
+		}
+	}
+}
+
+// deleteStateHistory removes all sections of the state history with the given id.
+func deleteStateHistory(db ethdb.KeyValueWriter, id uint64) {
+	for _, section := range stateHistorySections {
+		if err := db.Delete(stateHistoryKey(id, section)); err != nil {
+			log.Crit("Failed to delete state history", "id", id, "err", err)
+		}
+	}
+}
+
+func readUint64(db ethdb.KeyValueReader, key []byte) uint64 {
+	blob, err := db.Get(key)
+	if err != nil || len(blob) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(blob)
+}
+
+func writeUint64(db ethdb.KeyValueWriter, key []byte, number uint64) {
+	if err := db.Put(key, binary.BigEndian.AppendUint64(nil, number)); err != nil {
+		log.Crit("Failed to store state history marker", "key", string(key), "err", err)
+	}
+}
@@ -37,21 +37,6 @@ import (
 	"slices"
 )
 
-// Trie interface for test utility
-type Trie interface {
-	Get(key []byte) ([]byte, error)
-	Update(key, value []byte) error
-	Delete(key []byte) error
-	Commit(collectLeaf bool) (common.Hash, *trienode.NodeSet, error)
-}
-
-// TrieLoader interface for test utility
-type TrieLoader interface {
-	OpenTrie(root common.Hash) (Trie, error)
-	OpenStorageTrie(stateRoot common.Hash, addrHash, root common.Hash) (Trie, error)
-}
-
-
 // testHasher is a test utility for computing root hash of a batch of state
 // elements. The hash algorithm is to sort all the elements in lexicographical
 // order, concat the key and value in turn, and perform hash calculation on
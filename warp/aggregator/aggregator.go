@@ -5,27 +5,75 @@ package aggregator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
+
 	"github.com/luxfi/evm/params"
 	"github.com/luxfi/geth/log"
+	"github.com/luxfi/node/ids"
 	"github.com/luxfi/node/utils/crypto/bls"
 	"github.com/luxfi/node/utils/set"
 	luxWarp "github.com/luxfi/node/vms/platformvm/warp"
 )
 
+// DefaultNodeTimeout is the time given to each node of a validator to provide
+// its signature before the next one is asked.
+const DefaultNodeTimeout = 10 * time.Second
+
+var (
+	ErrNodeTimeout      = errors.New("timed out fetching signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
 type AggregateSignatureResult struct {
 	// Weight of validators included in the aggregate signature.
 	SignatureWeight uint64
 	// Total weight of all validators in the subnet.
 	TotalWeight uint64
-	// The message with the aggregate signature.
+	// Indices of the validators included in the aggregate signature.
+	Signers set.Bits
+	// Validators no signature could be fetched from, ordered by index.
+	Failures []*ValidatorFailure
+	// The message with the aggregate signature, nil if the signature weight
+	// doesn't meet the quorum.
 	Message *luxWarp.Message
 }
 
+// ValidatorFailure describes why no signature could be fetched from a validator.
+type ValidatorFailure struct {
+	// Index of the validator in the canonical validator set.
+	Index  int
+	Weight uint64
+	// Failure of each node of the validator asked, in the order they were asked.
+	Nodes []*NodeFailure
+}
+
+// NodeFailure is the error a node failed to provide its signature with.
+type NodeFailure struct {
+	NodeID ids.NodeID
+	Err    error
+}
+
+// InsufficientWeightError is returned if the signatures fetched don't meet the
+// quorum. It carries the partial result describing the failed validators.
+type InsufficientWeightError struct {
+	Result *AggregateSignatureResult
+}
+
+func (e *InsufficientWeightError) Error() string {
+	return fmt.Sprintf("%s: signature weight %d of %d, %d validators failed",
+		luxWarp.ErrInsufficientWeight, e.Result.SignatureWeight, e.Result.TotalWeight, len(e.Result.Failures))
+}
+
+func (e *InsufficientWeightError) Unwrap() error { return luxWarp.ErrInsufficientWeight }
+
 type signatureFetchResult struct {
-	sig    *bls.Signature
-	index  int
-	weight uint64
+	sig      *bls.Signature
+	index    int
+	weight   uint64
+	failures []*NodeFailure
 }
 
 // Aggregator requests signatures from validators and
@@ -34,104 +82,79 @@ type Aggregator struct {
 	validators  []*luxWarp.Validator
 	totalWeight uint64
 	client      SignatureGetter
+	nodeTimeout time.Duration
 }
 
 // New returns a signature aggregator that will attempt to aggregate signatures from [validators].
 func New(client SignatureGetter, validators []*luxWarp.Validator, totalWeight uint64) *Aggregator {
+	return NewWithNodeTimeout(client, validators, totalWeight, DefaultNodeTimeout)
+}
+
+// NewWithNodeTimeout returns a signature aggregator that gives each node of
+// [validators] [nodeTimeout] to provide its signature before moving on to the
+// next node of the same validator.
+func NewWithNodeTimeout(client SignatureGetter, validators []*luxWarp.Validator, totalWeight uint64, nodeTimeout time.Duration) *Aggregator {
 	return &Aggregator{
 		client:      client,
 		validators:  validators,
 		totalWeight: totalWeight,
+		nodeTimeout: nodeTimeout,
 	}
 }
 
 // Returns an aggregate signature over [unsignedMessage].
 // The returned signature's weight exceeds the threshold given by [quorumNum].
+// Otherwise, an [*InsufficientWeightError] is returned.
+//
+// The nodes of each validator are asked in order, falling back to the next one
+// if a node fails or doesn't provide its signature within the node timeout.
 func (a *Aggregator) AggregateSignatures(ctx context.Context, unsignedMessage *luxWarp.UnsignedMessage, quorumNum uint64) (*AggregateSignatureResult, error) {
 	// Create a child context to cancel signature fetching if we reach signature threshold.
 	signatureFetchCtx, signatureFetchCancel := context.WithCancel(ctx)
 	defer signatureFetchCancel()
 
-	// Fetch signatures from validators concurrently.
-	signatureFetchResultChan := make(chan *signatureFetchResult)
+	// Fetch signatures from validators concurrently. The channel is buffered so
+	// the fetches still in flight once the threshold is reached can complete.
+	signatureFetchResultChan := make(chan *signatureFetchResult, len(a.validators))
 	for i, validator := range a.validators {
-		var (
-			i         = i
-			validator = validator
-			// TODO: update from a single nodeID to the original slice and use extra nodeIDs as backup.
-			nodeID = validator.NodeIDs[0]
-		)
+		i, validator := i, validator
 		go func() {
-			log.Debug("Fetching warp signature",
-				"nodeID", nodeID,
-				"index", i,
-				"msgID", unsignedMessage.ID(),
-			)
-
-			signature, err := a.client.GetSignature(signatureFetchCtx, nodeID, unsignedMessage)
-			if err != nil {
-				log.Debug("Failed to fetch warp signature",
-					"nodeID", nodeID,
-					"index", i,
-					"err", err,
-					"msgID", unsignedMessage.ID(),
-				)
-				signatureFetchResultChan <- nil
-				return
-			}
-
-			log.Debug("Retrieved warp signature",
-				"nodeID", nodeID,
-				"msgID", unsignedMessage.ID(),
-				"index", i,
-			)
-
-			if !bls.Verify(validator.PublicKey, signature, unsignedMessage.Bytes()) {
-				log.Debug("Failed to verify warp signature",
-					"nodeID", nodeID,
-					"index", i,
-					"msgID", unsignedMessage.ID(),
-				)
-				signatureFetchResultChan <- nil
-				return
-			}
-
-			signatureFetchResultChan <- &signatureFetchResult{
-				sig:    signature,
-				index:  i,
-				weight: validator.Weight,
-			}
+			signatureFetchResultChan <- a.fetchSignature(signatureFetchCtx, i, validator, unsignedMessage)
 		}()
 	}
 
 	var (
 		signatures                = make([]*bls.Signature, 0, len(a.validators))
-		signersBitset             = set.NewBits()
-		signaturesWeight          = uint64(0)
+		result                    = &AggregateSignatureResult{TotalWeight: a.totalWeight, Signers: set.NewBits()}
 		signaturesPassedThreshold = false
 	)
 
 	for i := 0; i < len(a.validators); i++ {
 		signatureFetchResult := <-signatureFetchResultChan
-		if signatureFetchResult == nil {
+		if signatureFetchResult.sig == nil {
+			result.Failures = append(result.Failures, &ValidatorFailure{
+				Index:  signatureFetchResult.index,
+				Weight: signatureFetchResult.weight,
+				Nodes:  signatureFetchResult.failures,
+			})
 			continue
 		}
 
 		signatures = append(signatures, signatureFetchResult.sig)
-		signersBitset.Add(signatureFetchResult.index)
-		signaturesWeight += signatureFetchResult.weight
+		result.Signers.Add(signatureFetchResult.index)
+		result.SignatureWeight += signatureFetchResult.weight
 		log.Debug("Updated weight",
-			"totalWeight", signaturesWeight,
+			"totalWeight", result.SignatureWeight,
 			"addedWeight", signatureFetchResult.weight,
 			"msgID", unsignedMessage.ID(),
 		)
 
 		// If the signature weight meets the requested threshold, cancel signature fetching
-		if err := luxWarp.VerifyWeight(signaturesWeight, a.totalWeight, quorumNum, params.WarpQuorumDenominator); err == nil {
+		if err := luxWarp.VerifyWeight(result.SignatureWeight, a.totalWeight, quorumNum, params.WarpQuorumDenominator); err == nil {
 			log.Debug("Verify weight passed, exiting aggregation early",
 				"quorumNum", quorumNum,
 				"totalWeight", a.totalWeight,
-				"signatureWeight", signaturesWeight,
+				"signatureWeight", result.SignatureWeight,
 				"msgID", unsignedMessage.ID(),
 			)
 			signatureFetchCancel()
@@ -139,10 +162,11 @@ func (a *Aggregator) AggregateSignatures(ctx context.Context, unsignedMessage *l
 			break
 		}
 	}
+	sort.Slice(result.Failures, func(i, j int) bool { return result.Failures[i].Index < result.Failures[j].Index })
 
 	// If I failed to fetch sufficient signature stake, return an error
 	if !signaturesPassedThreshold {
-		return nil, luxWarp.ErrInsufficientWeight
+		return nil, &InsufficientWeightError{Result: result}
 	}
 
 	// Otherwise, return the aggregate signature
@@ -152,18 +176,72 @@ func (a *Aggregator) AggregateSignatures(ctx context.Context, unsignedMessage *l
 	}
 
 	warpSignature := &luxWarp.BitSetSignature{
-		Signers: signersBitset.Bytes(),
+		Signers: result.Signers.Bytes(),
 	}
 	copy(warpSignature.Signature[:], bls.SignatureToBytes(aggregateSignature))
 
-	msg, err := luxWarp.NewMessage(unsignedMessage, warpSignature)
+	result.Message, err = luxWarp.NewMessage(unsignedMessage, warpSignature)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct warp message: %w", err)
 	}
+	return result, nil
+}
+
+// fetchSignature fetches the signature of [validator] from each of its nodes in
+// turn, until one of them provides it or [ctx] is cancelled.
+func (a *Aggregator) fetchSignature(ctx context.Context, index int, validator *luxWarp.Validator, unsignedMessage *luxWarp.UnsignedMessage) *signatureFetchResult {
+	result := &signatureFetchResult{
+		index:  index,
+		weight: validator.Weight,
+	}
+	for _, nodeID := range validator.NodeIDs {
+		log.Debug("Fetching warp signature",
+			"nodeID", nodeID,
+			"index", index,
+			"msgID", unsignedMessage.ID(),
+		)
+
+		signature, err := a.fetchNodeSignature(ctx, nodeID, validator, unsignedMessage)
+		if err == nil {
+			log.Debug("Retrieved warp signature",
+				"nodeID", nodeID,
+				"msgID", unsignedMessage.ID(),
+				"index", index,
+			)
+			result.sig = signature
+			return result
+		}
+
+		log.Debug("Failed to fetch warp signature",
+			"nodeID", nodeID,
+			"index", index,
+			"err", err,
+			"msgID", unsignedMessage.ID(),
+		)
+		result.failures = append(result.failures, &NodeFailure{NodeID: nodeID, Err: err})
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return result
+}
+
+// fetchNodeSignature fetches and verifies the signature of [validator] from
+// [nodeID], giving up once the node timeout elapses.
+func (a *Aggregator) fetchNodeSignature(ctx context.Context, nodeID ids.NodeID, validator *luxWarp.Validator, unsignedMessage *luxWarp.UnsignedMessage) (*bls.Signature, error) {
+	nodeCtx, cancel := context.WithTimeout(ctx, a.nodeTimeout)
+	defer cancel()
 
-	return &AggregateSignatureResult{
-		Message:         msg,
-		SignatureWeight: signaturesWeight,
-		TotalWeight:     a.totalWeight,
-	}, nil
+	signature, err := a.client.GetSignature(nodeCtx, nodeID, unsignedMessage)
+	if err != nil {
+		// Tell the node timing out apart from the aggregation being cancelled.
+		if ctx.Err() == nil && nodeCtx.Err() != nil {
+			return nil, fmt.Errorf("%w after %s: %w", ErrNodeTimeout, a.nodeTimeout, err)
+		}
+		return nil, err
+	}
+	if !bls.Verify(validator.PublicKey, signature, unsignedMessage.Bytes()) {
+		return nil, ErrInvalidSignature
+	}
+	return signature, nil
 }
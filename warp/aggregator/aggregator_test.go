@@ -7,10 +7,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
+	"github.com/luxfi/evm/params"
+	warpcontract "github.com/luxfi/evm/precompile/contracts/warp"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"github.com/luxfi/node/ids"
 	"github.com/luxfi/node/utils/crypto/bls"
+	"github.com/luxfi/node/utils/set"
 	luxWarp "github.com/luxfi/node/vms/platformvm/warp"
 )
 
@@ -48,9 +52,7 @@ func TestAggregateSignatures(t *testing.T) {
 		vdr2: sig2,
 		vdr3: sig3,
 	}
-	nonVdrSk, err := localsigner.New()
-	require.NoError(t, err)
-	nonVdrSig, err := nonVdrSk.Sign(unsignedMsg.Bytes())
+	nonVdrSk, err := bls.NewSecretKey()
 	require.NoError(t, err)
 	nonVdrSig := bls.Sign(nonVdrSk, unsignedMsg.Bytes())
 	vdrs := []*luxWarp.Validator{
@@ -381,3 +383,163 @@ func TestAggregateSignatures(t *testing.T) {
 		})
 	}
 }
+
+// TestAggregateSignaturesPrecompileQuorum checks the aggregator reaches a
+// quorum exactly when the warp precompile would accept the aggregated message.
+func TestAggregateSignaturesPrecompileQuorum(t *testing.T) {
+	require.Equal(t, warpcontract.WarpQuorumDenominator, uint64(params.WarpQuorumDenominator))
+
+	unsignedMsg := &luxWarp.UnsignedMessage{
+		NetworkID:     1338,
+		SourceChainID: ids.ID{'y', 'e', 'e', 't'},
+		Payload:       []byte("hello world"),
+	}
+	require.NoError(t, unsignedMsg.Initialize())
+
+	const numVdrs = 3
+	var (
+		vdrs = make([]*luxWarp.Validator, numVdrs)
+		sigs = make([]*bls.Signature, numVdrs)
+	)
+	for i := range vdrs {
+		var sk *bls.SecretKey
+		sk, vdrs[i] = newValidator(t, 1)
+		sigs[i] = bls.Sign(sk, unsignedMsg.Bytes())
+	}
+
+	for signers := 1; signers <= numVdrs; signers++ {
+		ctrl := gomock.NewController(t)
+		client := NewMockSignatureGetter(ctrl)
+		for i, vdr := range vdrs {
+			if i < signers {
+				client.EXPECT().GetSignature(gomock.Any(), vdr.NodeIDs[0], gomock.Any()).Return(sigs[i], nil).AnyTimes()
+			} else {
+				client.EXPECT().GetSignature(gomock.Any(), vdr.NodeIDs[0], gomock.Any()).Return(nil, errors.New("no signature")).AnyTimes()
+			}
+		}
+		a := New(client, vdrs, numVdrs)
+
+		_, err := a.AggregateSignatures(context.Background(), unsignedMsg, warpcontract.WarpDefaultQuorumNumerator)
+		verifyErr := luxWarp.VerifyWeight(uint64(signers), numVdrs, warpcontract.WarpDefaultQuorumNumerator, warpcontract.WarpQuorumDenominator)
+		if verifyErr != nil {
+			require.ErrorIs(t, err, luxWarp.ErrInsufficientWeight, "signers %d", signers)
+		} else {
+			require.NoError(t, err, "signers %d", signers)
+		}
+	}
+	// Two thirds of the weight fall short of the default quorum of the
+	// precompile, so only the last round succeeds.
+	require.Error(t, luxWarp.VerifyWeight(2, numVdrs, warpcontract.WarpDefaultQuorumNumerator, warpcontract.WarpQuorumDenominator))
+}
+
+func TestAggregateSignaturesBackupNodes(t *testing.T) {
+	errTest := errors.New("test error")
+	unsignedMsg := &luxWarp.UnsignedMessage{
+		NetworkID:     1338,
+		SourceChainID: ids.ID{'y', 'e', 'e', 't'},
+		Payload:       []byte("hello world"),
+	}
+	require.NoError(t, unsignedMsg.Initialize())
+
+	vdr1sk, vdr1 := newValidator(t, 10)
+	vdr2sk, vdr2 := newValidator(t, 10)
+	sig1 := bls.Sign(vdr1sk, unsignedMsg.Bytes())
+	sig2 := bls.Sign(vdr2sk, unsignedMsg.Bytes())
+	nonVdrSk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	nonVdrSig := bls.Sign(nonVdrSk, unsignedMsg.Bytes())
+
+	node1a, node1b := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	node2a, node2b := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	vdr1.NodeIDs = []ids.NodeID{node1a, node1b}
+	vdr2.NodeIDs = []ids.NodeID{node2a, node2b}
+	vdrs := []*luxWarp.Validator{vdr1, vdr2}
+
+	// stall blocks until the node timeout elapses.
+	stall := func(ctx context.Context, _ ids.NodeID, _ *luxWarp.UnsignedMessage) (*bls.Signature, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	tests := []struct {
+		name             string
+		setup            func(*MockSignatureGetter)
+		quorumNum        uint64
+		expectedSigners  []int
+		expectedFailures map[int][]error
+		expectedErr      error
+	}{
+		{
+			name: "backup nodes used after errors",
+			setup: func(client *MockSignatureGetter) {
+				client.EXPECT().GetSignature(gomock.Any(), node1a, gomock.Any()).Return(nil, errTest).Times(1)
+				client.EXPECT().GetSignature(gomock.Any(), node1b, gomock.Any()).Return(sig1, nil).Times(1)
+				client.EXPECT().GetSignature(gomock.Any(), node2a, gomock.Any()).Return(nonVdrSig, nil).Times(1)
+				client.EXPECT().GetSignature(gomock.Any(), node2b, gomock.Any()).Return(sig2, nil).Times(1)
+			},
+			quorumNum:       100,
+			expectedSigners: []int{0, 1},
+		},
+		{
+			name: "backup node used after timeout",
+			setup: func(client *MockSignatureGetter) {
+				client.EXPECT().GetSignature(gomock.Any(), node1a, gomock.Any()).DoAndReturn(stall).Times(1)
+				client.EXPECT().GetSignature(gomock.Any(), node1b, gomock.Any()).Return(sig1, nil).Times(1)
+				client.EXPECT().GetSignature(gomock.Any(), node2a, gomock.Any()).Return(sig2, nil).Times(1)
+			},
+			quorumNum:       100,
+			expectedSigners: []int{0, 1},
+		},
+		{
+			name: "all nodes of a validator fail",
+			setup: func(client *MockSignatureGetter) {
+				client.EXPECT().GetSignature(gomock.Any(), node1a, gomock.Any()).Return(sig1, nil).Times(1)
+				client.EXPECT().GetSignature(gomock.Any(), node2a, gomock.Any()).DoAndReturn(stall).Times(1)
+				client.EXPECT().GetSignature(gomock.Any(), node2b, gomock.Any()).Return(nonVdrSig, nil).Times(1)
+			},
+			quorumNum:        100,
+			expectedSigners:  []int{0},
+			expectedFailures: map[int][]error{1: {ErrNodeTimeout, ErrInvalidSignature}},
+			expectedErr:      luxWarp.ErrInsufficientWeight,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			client := NewMockSignatureGetter(gomock.NewController(t))
+			tt.setup(client)
+			a := NewWithNodeTimeout(client, vdrs, vdr1.Weight+vdr2.Weight, 50*time.Millisecond)
+
+			res, err := a.AggregateSignatures(context.Background(), unsignedMsg, tt.quorumNum)
+			require.ErrorIs(err, tt.expectedErr)
+			if err != nil {
+				var weightErr *InsufficientWeightError
+				require.ErrorAs(err, &weightErr)
+				res = weightErr.Result
+				require.Nil(res.Message)
+			} else {
+				require.NotNil(res.Message)
+			}
+
+			require.Equal(set.NewBits(tt.expectedSigners...).Bytes(), res.Signers.Bytes())
+			expectedWeight := uint64(0)
+			for _, i := range tt.expectedSigners {
+				expectedWeight += vdrs[i].Weight
+			}
+			require.Equal(expectedWeight, res.SignatureWeight)
+			require.Equal(vdr1.Weight+vdr2.Weight, res.TotalWeight)
+
+			require.Len(res.Failures, len(tt.expectedFailures))
+			for _, failure := range res.Failures {
+				expectedErrs := tt.expectedFailures[failure.Index]
+				require.Equal(vdrs[failure.Index].Weight, failure.Weight)
+				require.Len(failure.Nodes, len(expectedErrs))
+				for i, node := range failure.Nodes {
+					require.Equal(vdrs[failure.Index].NodeIDs[i], node.NodeID)
+					require.ErrorIs(node.Err, expectedErrs[i])
+				}
+			}
+		})
+	}
+}
@@ -12,10 +12,10 @@ import (
 	"github.com/luxfi/node/ids"
 	"github.com/luxfi/node/network/p2p/acp118"
 	"github.com/luxfi/node/consensus/chain"
-	"github.com/luxfi/node/consensus/validators"
 	luxWarp "github.com/luxfi/node/vms/platformvm/warp"
 	"github.com/luxfi/node/vms/platformvm/warp/payload"
 	"github.com/luxfi/geth/log"
+	"github.com/luxfi/evm/plugin/evm/validators/interfaces"
 )
 
 var (
@@ -60,7 +60,7 @@ type backend struct {
 	messageCache              cache.Cacher[ids.ID, *luxWarp.UnsignedMessage]
 	offchainAddressedCallMsgs map[ids.ID]*luxWarp.UnsignedMessage
 	stats                     *verifierStats
-	validatorReader           validators.State
+	validatorReader           interfaces.ValidatorReader
 }
 
 // NewBackend creates a new Backend, and initializes the signature cache and message tracking database.
@@ -69,7 +69,7 @@ func NewBackend(
 	sourceChainID ids.ID,
 	warpSigner luxWarp.Signer,
 	blockClient BlockClient,
-	validatorReader validators.State,
+	validatorReader interfaces.ValidatorReader,
 	db database.Database,
 	signatureCache cache.Cacher[ids.ID, []byte],
 	offchainMessages [][]byte,
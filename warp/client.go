@@ -17,8 +17,10 @@ type Client interface {
 	GetMessage(ctx context.Context, messageID ids.ID) ([]byte, error)
 	GetMessageSignature(ctx context.Context, messageID ids.ID) ([]byte, error)
 	GetMessageAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetIDStr string) ([]byte, error)
+	GetMessageAggregateSignatureDetails(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetIDStr string) (*AggregateSignatureDetails, error)
 	GetBlockSignature(ctx context.Context, blockID ids.ID) ([]byte, error)
 	GetBlockAggregateSignature(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetIDStr string) ([]byte, error)
+	GetBlockAggregateSignatureDetails(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetIDStr string) (*AggregateSignatureDetails, error)
 }
 
 // client implementation for interacting with EVM [chain]
@@ -61,6 +63,14 @@ func (c *client) GetMessageAggregateSignature(ctx context.Context, messageID ids
 	return res, nil
 }
 
+func (c *client) GetMessageAggregateSignatureDetails(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetIDStr string) (*AggregateSignatureDetails, error) {
+	var res AggregateSignatureDetails
+	if err := c.client.CallContext(ctx, &res, "warp_getMessageAggregateSignatureDetails", messageID, quorumNum, subnetIDStr); err != nil {
+		return nil, fmt.Errorf("call to warp_getMessageAggregateSignatureDetails failed. err: %w", err)
+	}
+	return &res, nil
+}
+
 func (c *client) GetBlockSignature(ctx context.Context, blockID ids.ID) ([]byte, error) {
 	var res hexutil.Bytes
 	if err := c.client.CallContext(ctx, &res, "warp_getBlockSignature", blockID); err != nil {
@@ -76,3 +86,11 @@ func (c *client) GetBlockAggregateSignature(ctx context.Context, blockID ids.ID,
 	}
 	return res, nil
 }
+
+func (c *client) GetBlockAggregateSignatureDetails(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetIDStr string) (*AggregateSignatureDetails, error) {
+	var res AggregateSignatureDetails
+	if err := c.client.CallContext(ctx, &res, "warp_getBlockAggregateSignatureDetails", blockID, quorumNum, subnetIDStr); err != nil {
+		return nil, fmt.Errorf("call to warp_getBlockAggregateSignatureDetails failed. err: %w", err)
+	}
+	return &res, nil
+}
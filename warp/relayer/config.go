@@ -0,0 +1,100 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/node/ids"
+	"github.com/luxfi/node/utils/set"
+)
+
+const (
+	DefaultQuorumNum          = 67
+	DefaultGasLimit           = 1_000_000
+	DefaultRetryInterval      = 5 * time.Second
+	DefaultAggregationTimeout = 30 * time.Second
+)
+
+var (
+	errNoRoutes           = errors.New("no destinations to relay to")
+	errDuplicateRoute     = errors.New("duplicate destination")
+	errNoDestinationChain = errors.New("missing destination blockchainID")
+	errNoClient           = errors.New("missing destination client")
+)
+
+// Config configures the relayer.
+type Config struct {
+	// QuorumNum is the quorum numerator the aggregate signatures must meet.
+	QuorumNum uint64
+	// RetryInterval is the interval to retry failed deliveries and to check
+	// the submitted ones were accepted at.
+	RetryInterval time.Duration
+	// AggregationTimeout bounds the time spent aggregating the signatures
+	// over a single message.
+	AggregationTimeout time.Duration
+	// Routes are the destinations messages are relayed to.
+	Routes []Route
+}
+
+// Route relays the messages of the allowed senders to a destination chain.
+//
+// Warp messages are not addressed to a chain: every message of an allowed
+// sender is delivered to every route. The payload of the message is used as the
+// calldata of the delivery transaction to [Receiver], which can authenticate it
+// through the warp precompile.
+type Route struct {
+	DestinationChainID ids.ID
+	Client             DestinationClient
+	Receiver           common.Address
+	GasLimit           uint64
+	// AllowedSenders are the source addresses whose messages are relayed. All
+	// messages are relayed if empty.
+	AllowedSenders set.Set[common.Address]
+}
+
+// allows returns true if the messages of [sender] are relayed through the route.
+func (r *Route) allows(sender common.Address) bool {
+	return r.AllowedSenders.Len() == 0 || r.AllowedSenders.Contains(sender)
+}
+
+// setDefaults sets the defaults of the options left unset.
+func (c *Config) setDefaults() {
+	if c.QuorumNum == 0 {
+		c.QuorumNum = DefaultQuorumNum
+	}
+	if c.RetryInterval == 0 {
+		c.RetryInterval = DefaultRetryInterval
+	}
+	if c.AggregationTimeout == 0 {
+		c.AggregationTimeout = DefaultAggregationTimeout
+	}
+	for i := range c.Routes {
+		if c.Routes[i].GasLimit == 0 {
+			c.Routes[i].GasLimit = DefaultGasLimit
+		}
+	}
+}
+
+func (c *Config) verify() error {
+	if len(c.Routes) == 0 {
+		return errNoRoutes
+	}
+	destinations := set.NewSet[ids.ID](len(c.Routes))
+	for _, route := range c.Routes {
+		switch {
+		case route.DestinationChainID == ids.Empty:
+			return errNoDestinationChain
+		case route.Client == nil:
+			return fmt.Errorf("%w for %s", errNoClient, route.DestinationChainID)
+		case destinations.Contains(route.DestinationChainID):
+			return fmt.Errorf("%w: %s", errDuplicateRoute, route.DestinationChainID)
+		}
+		destinations.Add(route.DestinationChainID)
+	}
+	return nil
+}
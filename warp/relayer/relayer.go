@@ -0,0 +1,512 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package relayer delivers the warp messages sent on this chain to other
+// chains, as an in-process alternative to running an external relayer.
+package relayer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/luxfi/evm/core"
+	"github.com/luxfi/evm/core/txpool"
+	"github.com/luxfi/evm/ethclient"
+	warpcontract "github.com/luxfi/evm/precompile/contracts/warp"
+	"github.com/luxfi/evm/predicate"
+	"github.com/luxfi/evm/warp/aggregator"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/crypto"
+	"github.com/luxfi/geth/event"
+	"github.com/luxfi/geth/log"
+	"github.com/luxfi/node/database"
+	"github.com/luxfi/node/ids"
+	luxWarp "github.com/luxfi/node/vms/platformvm/warp"
+	"github.com/luxfi/node/vms/platformvm/warp/payload"
+)
+
+// feeBumpPercent is the minimum increase of the fees of a delivery replacing
+// another with the same nonce, which the mempool requires to replace it.
+const feeBumpPercent = 10
+
+// Source provides the blocks accepted on the chain messages are relayed from.
+type Source interface {
+	SubscribeAcceptedLogsEvent(ch chan<- []*types.Log) event.Subscription
+	LastAcceptedBlock() *types.Block
+	GetBlockByNumber(number uint64) *types.Block
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+}
+
+// Aggregator aggregates the signatures of the source chain validators over the
+// messages to relay.
+type Aggregator interface {
+	AggregateSignatures(ctx context.Context, unsignedMessage *luxWarp.UnsignedMessage, quorumNum uint64) (*aggregator.AggregateSignatureResult, error)
+}
+
+// DestinationClient submits the delivery transactions to a destination chain.
+type DestinationClient interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateBaseFee(ctx context.Context) (*big.Int, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// destination is the state of relaying to a route, initialized lazily as the
+// destination may not be reachable when the relayer starts.
+type destination struct {
+	Route
+
+	initialized bool
+	signer      types.Signer
+	nonce       uint64
+}
+
+// Relayer delivers the warp messages sent in the accepted blocks of the source
+// chain to the destinations they are routed to.
+//
+// The blocks are processed in order. The messages of a block are all submitted
+// before moving to the next one, so a failure to aggregate the signatures or to
+// reach a destination holds back the delivery of the following messages until
+// it succeeds on retry.
+type Relayer struct {
+	config     Config
+	source     Source
+	aggregator Aggregator
+	key        *ecdsa.PrivateKey
+	address    common.Address
+	store      *store
+
+	destinations []*destination
+	// notify is signalled when new logs are accepted.
+	notify chan struct{}
+}
+
+// New returns a relayer of the messages accepted on [source], which submits the
+// delivery transactions signed by [key]. The state of the deliveries is
+// persisted in [db]; when starting afresh, only the messages accepted from now
+// on are relayed.
+func New(config Config, source Source, aggregator Aggregator, key *ecdsa.PrivateKey, db database.Database) (*Relayer, error) {
+	config.setDefaults()
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	r := &Relayer{
+		config:     config,
+		source:     source,
+		aggregator: aggregator,
+		key:        key,
+		address:    crypto.PubkeyToAddress(key.PublicKey),
+		store:      &store{db: db},
+		notify:     make(chan struct{}, 1),
+	}
+	for _, route := range config.Routes {
+		r.destinations = append(r.destinations, &destination{Route: route})
+	}
+
+	// Only relay the messages accepted from now on when starting afresh.
+	if _, ok, err := r.store.lastProcessed(); err != nil {
+		return nil, fmt.Errorf("failed to read relayer state: %w", err)
+	} else if !ok {
+		if err := r.store.setLastProcessed(source.LastAcceptedBlock().NumberU64()); err != nil {
+			return nil, fmt.Errorf("failed to initialize relayer state: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// Run relays the messages until [ctx] is cancelled.
+func (r *Relayer) Run(ctx context.Context) {
+	logsCh := make(chan []*types.Log, 1)
+	sub := r.source.SubscribeAcceptedLogsEvent(logsCh)
+	defer sub.Unsubscribe()
+
+	log.Info("Starting warp relayer", "address", r.address, "destinations", len(r.destinations))
+
+	// The subscription is only used to be notified of new messages, which are
+	// then read from the accepted blocks. This keeps the acceptor from being
+	// blocked by slow deliveries, and relays the messages missed while the
+	// relayer was not running the same way.
+	go func() {
+		for {
+			select {
+			case logs := <-logsCh:
+				if !containsWarpMessage(logs) {
+					continue
+				}
+				select {
+				case r.notify <- struct{}{}:
+				default:
+				}
+			case <-sub.Err():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(r.config.RetryInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.processAccepted(ctx); err != nil && ctx.Err() == nil {
+			log.Warn("Failed to relay warp messages", "err", err)
+		}
+		if err := r.confirmDeliveries(ctx); err != nil && ctx.Err() == nil {
+			log.Warn("Failed to confirm warp message deliveries", "err", err)
+		}
+		select {
+		case <-r.notify:
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Info("Stopped warp relayer")
+			return
+		}
+	}
+}
+
+// containsWarpMessage returns true if any of [logs] was emitted by sending a
+// warp message.
+func containsWarpMessage(logs []*types.Log) bool {
+	for _, l := range logs {
+		if isWarpMessage(l) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWarpMessage(l *types.Log) bool {
+	return l.Address == warpcontract.ContractAddress && len(l.Topics) > 0 && l.Topics[0] == warpcontract.WarpABI.Events["SendWarpMessage"].ID
+}
+
+// processAccepted relays the messages of the blocks accepted since the last
+// processed one.
+func (r *Relayer) processAccepted(ctx context.Context) error {
+	lastProcessed, _, err := r.store.lastProcessed()
+	if err != nil {
+		return err
+	}
+	lastAccepted := r.source.LastAcceptedBlock().NumberU64()
+	for height := lastProcessed + 1; height <= lastAccepted; height++ {
+		block := r.source.GetBlockByNumber(height)
+		if block == nil {
+			return fmt.Errorf("accepted block %d not found", height)
+		}
+		if err := r.processBlock(ctx, block); err != nil {
+			return fmt.Errorf("failed to relay messages of block %d: %w", height, err)
+		}
+		if err := r.store.setLastProcessed(height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Relayer) processBlock(ctx context.Context, block *types.Block) error {
+	for _, receipt := range r.source.GetReceiptsByHash(block.Hash()) {
+		for _, l := range receipt.Logs {
+			if !isWarpMessage(l) {
+				continue
+			}
+			unsignedMessage, err := warpcontract.UnpackSendWarpEventDataToMessage(l.Data)
+			if err != nil {
+				// The precompile emitted the message, so this can't happen.
+				log.Error("Skipping invalid warp message", "block", block.NumberU64(), "tx", l.TxHash, "err", err)
+				continue
+			}
+			if err := r.relay(ctx, unsignedMessage); err != nil {
+				return fmt.Errorf("message %s: %w", unsignedMessage.ID(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// relay submits [unsignedMessage] to the destinations it's routed to, if not
+// submitted yet.
+func (r *Relayer) relay(ctx context.Context, unsignedMessage *luxWarp.UnsignedMessage) error {
+	addressedCall, err := payload.ParseAddressedCall(unsignedMessage.Payload)
+	if err != nil {
+		return err
+	}
+	sender := common.BytesToAddress(addressedCall.SourceAddress)
+
+	var signedMessage *luxWarp.Message
+	for _, dest := range r.destinations {
+		if !dest.allows(sender) {
+			continue
+		}
+		submitted, err := r.store.hasDelivery(dest.DestinationChainID, unsignedMessage.ID())
+		if err != nil {
+			return err
+		}
+		if submitted {
+			continue
+		}
+		// The signatures are only aggregated once for all destinations.
+		if signedMessage == nil {
+			signedMessage, err = r.aggregate(ctx, unsignedMessage)
+			if err != nil {
+				return err
+			}
+		}
+		if err := r.submit(ctx, dest, signedMessage, addressedCall.Payload); err != nil {
+			return fmt.Errorf("failed to submit to %s: %w", dest.DestinationChainID, err)
+		}
+	}
+	return nil
+}
+
+func (r *Relayer) aggregate(ctx context.Context, unsignedMessage *luxWarp.UnsignedMessage) (*luxWarp.Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.config.AggregationTimeout)
+	defer cancel()
+
+	result, err := r.aggregator.AggregateSignatures(ctx, unsignedMessage, r.config.QuorumNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures: %w", err)
+	}
+	log.Debug("Aggregated warp message signatures", "msgID", unsignedMessage.ID(), "signatureWeight", result.SignatureWeight, "totalWeight", result.TotalWeight)
+	return result.Message, nil
+}
+
+// submit signs the transaction delivering [signedMessage] to [dest], persists it
+// and submits it.
+func (r *Relayer) submit(ctx context.Context, dest *destination, signedMessage *luxWarp.Message, calldata []byte) error {
+	if err := r.initDestination(ctx, dest); err != nil {
+		return err
+	}
+	gasTipCap, gasFeeCap, err := suggestFees(ctx, dest.Client)
+	if err != nil {
+		return err
+	}
+	tx, err := types.SignTx(
+		predicate.NewPredicateTx(
+			dest.signer.ChainID(),
+			dest.nonce,
+			&dest.Receiver,
+			dest.GasLimit,
+			gasFeeCap,
+			gasTipCap,
+			common.Big0,
+			calldata,
+			types.AccessList{},
+			warpcontract.ContractAddress,
+			signedMessage.Bytes(),
+		),
+		dest.signer,
+		r.key,
+	)
+	if err != nil {
+		return err
+	}
+	d := &delivery{
+		destinationChainID: dest.DestinationChainID,
+		messageID:          signedMessage.ID(),
+		status:             statusSubmitted,
+		tx:                 tx,
+	}
+	if err := r.store.putDelivery(d); err != nil {
+		return err
+	}
+	dest.nonce++
+
+	r.send(ctx, dest.Client, d)
+	return nil
+}
+
+// suggestFees returns the gas tip and fee caps of a delivery to [client],
+// leaving room for the base fee to double before the delivery is accepted.
+func suggestFees(ctx context.Context, client DestinationClient) (*big.Int, *big.Int, error) {
+	gasTipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	baseFee, err := client.EstimateBaseFee(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	gasFeeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, common.Big2), gasTipCap)
+	return gasTipCap, gasFeeCap, nil
+}
+
+// bumpFee returns [fee] raised by [feeBumpPercent], rounded up, or [suggested]
+// if higher.
+func bumpFee(fee *big.Int, suggested *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+feeBumpPercent))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(suggested) < 0 {
+		return new(big.Int).Set(suggested)
+	}
+	return bumped
+}
+
+// send submits the transaction of [d], which was persisted beforehand.
+//
+// Failing to submit it is retried by [confirmDeliveries], submitting another
+// transaction would deliver twice.
+func (r *Relayer) send(ctx context.Context, client DestinationClient, d *delivery) {
+	err := client.SendTransaction(ctx, d.tx)
+	switch {
+	case err == nil:
+		log.Info("Submitted warp message delivery", "msgID", d.messageID, "destination", d.destinationChainID, "tx", d.tx.Hash(), "nonce", d.tx.Nonce())
+	case strings.Contains(err.Error(), txpool.ErrAlreadyKnown.Error()):
+	default:
+		log.Warn("Failed to submit warp message delivery", "msgID", d.messageID, "destination", d.destinationChainID, "tx", d.tx.Hash(), "err", err)
+	}
+}
+
+// replace signs the transaction of [d] again with [nonce] and the given fees,
+// and persists it before it's submitted. The hash of the replaced transaction is
+// kept, as it may still be accepted instead of the new one.
+func (r *Relayer) replace(dest *destination, d *delivery, nonce uint64, gasTipCap *big.Int, gasFeeCap *big.Int) error {
+	tx, err := types.SignNewTx(r.key, dest.signer, &types.DynamicFeeTx{
+		ChainID:    dest.signer.ChainID(),
+		Nonce:      nonce,
+		To:         d.tx.To(),
+		Gas:        d.tx.Gas(),
+		GasFeeCap:  gasFeeCap,
+		GasTipCap:  gasTipCap,
+		Value:      d.tx.Value(),
+		Data:       d.tx.Data(),
+		AccessList: d.tx.AccessList(),
+	})
+	if err != nil {
+		return err
+	}
+	d.replaced = append(d.replaced, d.tx.Hash())
+	d.tx = tx
+	return r.store.putDelivery(d)
+}
+
+// initDestination fetches the chain ID and the next nonce of the relayer on
+// [dest], unless already done.
+func (r *Relayer) initDestination(ctx context.Context, dest *destination) error {
+	if dest.initialized {
+		return nil
+	}
+	chainID, err := dest.Client.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	nonce, err := dest.Client.NonceAt(ctx, r.address, nil)
+	if err != nil {
+		return err
+	}
+	// Deliveries submitted before a restart may not be accepted yet.
+	deliveries, err := r.store.submittedDeliveries()
+	if err != nil {
+		return err
+	}
+	for _, d := range deliveries {
+		if d.destinationChainID == dest.DestinationChainID && d.tx.Nonce() >= nonce {
+			nonce = d.tx.Nonce() + 1
+		}
+	}
+	dest.signer = types.LatestSignerForChainID(chainID)
+	dest.nonce = nonce
+	dest.initialized = true
+	return nil
+}
+
+// confirmDeliveries checks whether the submitted deliveries were accepted by
+// their destination, and resubmits those that were not, replacing them if their
+// fees are too low or their nonce was used.
+func (r *Relayer) confirmDeliveries(ctx context.Context) error {
+	deliveries, err := r.store.submittedDeliveries()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, d := range deliveries {
+		if err := r.confirmDelivery(ctx, d); err != nil {
+			errs = append(errs, fmt.Errorf("delivery of %s to %s: %w", d.messageID, d.destinationChainID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Relayer) confirmDelivery(ctx context.Context, d *delivery) error {
+	var dest *destination
+	for _, candidate := range r.destinations {
+		if candidate.DestinationChainID == d.destinationChainID {
+			dest = candidate
+		}
+	}
+	// The destination is no longer configured.
+	if dest == nil {
+		return nil
+	}
+
+	if accepted, err := r.checkAccepted(ctx, dest.Client, d); err != nil || accepted {
+		return err
+	}
+	if err := r.initDestination(ctx, dest); err != nil {
+		return err
+	}
+	gasTipCap, gasFeeCap, err := suggestFees(ctx, dest.Client)
+	if err != nil {
+		return err
+	}
+	// The fees of the transaction may be too low to ever be accepted, as the
+	// base fee rose. Replace it with one paying the current fees, bumped enough
+	// to replace it in the mempool.
+	if d.tx.GasTipCap().Cmp(gasTipCap) < 0 || d.tx.GasFeeCap().Cmp(gasFeeCap) < 0 {
+		gasTipCap, gasFeeCap = bumpFee(d.tx.GasTipCap(), gasTipCap), bumpFee(d.tx.GasFeeCap(), gasFeeCap)
+		if err := r.replace(dest, d, d.tx.Nonce(), gasTipCap, gasFeeCap); err != nil {
+			return err
+		}
+		log.Info("Bumped warp message delivery fees", "msgID", d.messageID, "destination", d.destinationChainID, "tx", d.tx.Hash(), "gasFeeCap", gasFeeCap)
+	}
+
+	err = dest.Client.SendTransaction(ctx, d.tx)
+	switch {
+	case err == nil || strings.Contains(err.Error(), txpool.ErrAlreadyKnown.Error()):
+		return nil
+	case strings.Contains(err.Error(), core.ErrNonceTooLow.Error()):
+		// The delivery may have been accepted since it was checked. Otherwise
+		// the nonce was used by another transaction, so the delivery is
+		// queued again with a fresh nonce.
+		if accepted, err := r.checkAccepted(ctx, dest.Client, d); err != nil || accepted {
+			return err
+		}
+		log.Warn("Requeuing warp message delivery with used nonce", "msgID", d.messageID, "destination", d.destinationChainID, "tx", d.tx.Hash(), "nonce", d.tx.Nonce(), "newNonce", dest.nonce)
+		if err := r.replace(dest, d, dest.nonce, gasTipCap, gasFeeCap); err != nil {
+			return err
+		}
+		dest.nonce++
+
+		r.send(ctx, dest.Client, d)
+		return nil
+	default:
+		return err
+	}
+}
+
+// checkAccepted returns true if the transaction of [d], or one it replaced, was
+// accepted by the destination, in which case [d] is marked as accepted.
+func (r *Relayer) checkAccepted(ctx context.Context, client DestinationClient, d *delivery) (bool, error) {
+	for _, hash := range append([]common.Hash{d.tx.Hash()}, d.replaced...) {
+		receipt, err := client.TransactionReceipt(ctx, hash)
+		switch {
+		case errors.Is(err, ethclient.NotFound):
+			continue
+		case err != nil:
+			return false, err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			log.Warn("Warp message delivery reverted", "msgID", d.messageID, "destination", d.destinationChainID, "tx", hash)
+		}
+		d.status = statusAccepted
+		return true, r.store.putDelivery(d)
+	}
+	return false, nil
+}
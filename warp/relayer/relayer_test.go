@@ -0,0 +1,501 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxfi/evm/core"
+	"github.com/luxfi/evm/core/txpool"
+	"github.com/luxfi/evm/ethclient"
+	warpcontract "github.com/luxfi/evm/precompile/contracts/warp"
+	"github.com/luxfi/evm/warp/aggregator"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/crypto"
+	"github.com/luxfi/geth/event"
+	"github.com/luxfi/node/database"
+	"github.com/luxfi/node/database/memdb"
+	"github.com/luxfi/node/ids"
+	"github.com/luxfi/node/utils/set"
+	luxWarp "github.com/luxfi/node/vms/platformvm/warp"
+	"github.com/luxfi/node/vms/platformvm/warp/payload"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testSourceChainID = ids.GenerateTestID()
+	testSenderA       = common.Address{0xa}
+	testSenderB       = common.Address{0xb}
+	errTest           = errors.New("test error")
+)
+
+type testSource struct {
+	lock     sync.Mutex
+	blocks   []*types.Block
+	receipts map[common.Hash]types.Receipts
+	logsFeed event.Feed
+}
+
+func newTestSource() *testSource {
+	s := &testSource{receipts: make(map[common.Hash]types.Receipts)}
+	s.addBlock()
+	return s
+}
+
+// addBlock accepts a block with a receipt per log.
+func (s *testSource) addBlock(logs ...*types.Log) {
+	s.lock.Lock()
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(len(s.blocks)))})
+	s.blocks = append(s.blocks, block)
+	var receipts types.Receipts
+	for _, l := range logs {
+		receipts = append(receipts, &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{l}})
+	}
+	s.receipts[block.Hash()] = receipts
+	s.lock.Unlock()
+
+	s.logsFeed.Send(logs)
+}
+
+func (s *testSource) SubscribeAcceptedLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return s.logsFeed.Subscribe(ch)
+}
+
+func (s *testSource) LastAcceptedBlock() *types.Block {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.blocks[len(s.blocks)-1]
+}
+
+func (s *testSource) GetBlockByNumber(number uint64) *types.Block {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if number >= uint64(len(s.blocks)) {
+		return nil
+	}
+	return s.blocks[number]
+}
+
+func (s *testSource) GetReceiptsByHash(hash common.Hash) types.Receipts {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.receipts[hash]
+}
+
+type testAggregator struct {
+	lock  sync.Mutex
+	calls int
+	err   error
+}
+
+func (a *testAggregator) AggregateSignatures(_ context.Context, unsignedMessage *luxWarp.UnsignedMessage, _ uint64) (*aggregator.AggregateSignatureResult, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.calls++
+	if a.err != nil {
+		return nil, a.err
+	}
+	msg, err := luxWarp.NewMessage(unsignedMessage, &luxWarp.BitSetSignature{})
+	if err != nil {
+		return nil, err
+	}
+	return &aggregator.AggregateSignatureResult{Message: msg}, nil
+}
+
+type testClient struct {
+	lock     sync.Mutex
+	chainID  *big.Int
+	nonce    uint64
+	baseFee  *big.Int
+	sent     []*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	sendErr  error
+}
+
+func newTestClient() *testClient {
+	return &testClient{
+		chainID:  big.NewInt(99999),
+		baseFee:  big.NewInt(25),
+		receipts: make(map[common.Hash]*types.Receipt),
+	}
+}
+
+func (c *testClient) ChainID(context.Context) (*big.Int, error) { return c.chainID, nil }
+
+func (c *testClient) NonceAt(context.Context, common.Address, *big.Int) (uint64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.nonce, nil
+}
+
+func (c *testClient) SuggestGasTipCap(context.Context) (*big.Int, error) { return big.NewInt(1), nil }
+
+func (c *testClient) EstimateBaseFee(context.Context) (*big.Int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.baseFee, nil
+}
+
+func (c *testClient) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.sendErr != nil {
+		return c.sendErr
+	}
+	c.sent = append(c.sent, tx)
+	return nil
+}
+
+func (c *testClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	receipt, ok := c.receipts[txHash]
+	if !ok {
+		return nil, ethclient.NotFound
+	}
+	return receipt, nil
+}
+
+func (c *testClient) sentTxs() []*types.Transaction {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]*types.Transaction(nil), c.sent...)
+}
+
+// newWarpLog returns the log emitted by [sender] sending a message with
+// [payloadBytes], along with the message.
+func newWarpLog(t *testing.T, sender common.Address, payloadBytes []byte) (*types.Log, *luxWarp.UnsignedMessage) {
+	addressedCall, err := payload.NewAddressedCall(sender.Bytes(), payloadBytes)
+	require.NoError(t, err)
+	unsignedMessage, err := luxWarp.NewUnsignedMessage(1, testSourceChainID, addressedCall.Bytes())
+	require.NoError(t, err)
+	topics, data, err := warpcontract.PackSendWarpMessageEvent(sender, common.Hash(unsignedMessage.ID()), unsignedMessage.Bytes())
+	require.NoError(t, err)
+	return &types.Log{Address: warpcontract.ContractAddress, Topics: topics, Data: data}, unsignedMessage
+}
+
+func newTestRelayer(t *testing.T, source Source, agg Aggregator, db database.Database, routes ...Route) *Relayer {
+	key, err := crypto.HexToECDSA("56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027")
+	require.NoError(t, err)
+	r, err := New(Config{Routes: routes}, source, agg, key, db)
+	require.NoError(t, err)
+	return r
+}
+
+func TestRelayerDeliversRoutedMessages(t *testing.T) {
+	require := require.New(t)
+
+	source := newTestSource()
+	logA, msgA := newWarpLog(t, testSenderA, []byte("a"))
+	logB, msgB := newWarpLog(t, testSenderB, []byte("b"))
+	otherLog := &types.Log{Address: common.Address{0x1}, Topics: []common.Hash{{0x1}}}
+	source.addBlock(logA, otherLog)
+	source.addBlock(logB)
+
+	agg := &testAggregator{}
+	restricted, open := newTestClient(), newTestClient()
+	r := newTestRelayer(t, source, agg, memdb.New(),
+		Route{
+			DestinationChainID: ids.GenerateTestID(),
+			Client:             restricted,
+			Receiver:           common.Address{0x11},
+			AllowedSenders:     set.Of(testSenderA),
+		},
+		Route{
+			DestinationChainID: ids.GenerateTestID(),
+			Client:             open,
+			Receiver:           common.Address{0x22},
+			GasLimit:           200_000,
+		},
+	)
+	require.NoError(r.store.setLastProcessed(0))
+	require.NoError(r.processAccepted(context.Background()))
+
+	lastProcessed, _, err := r.store.lastProcessed()
+	require.NoError(err)
+	require.Equal(uint64(2), lastProcessed)
+	// The signatures are aggregated once per message.
+	require.Equal(2, agg.calls)
+
+	sent := restricted.sentTxs()
+	require.Len(sent, 1)
+	require.Equal(common.Address{0x11}, *sent[0].To())
+	require.Equal([]byte("a"), sent[0].Data())
+	require.Equal(uint64(DefaultGasLimit), sent[0].Gas())
+
+	sent = open.sentTxs()
+	require.Len(sent, 2)
+	for i, msg := range []*luxWarp.UnsignedMessage{msgA, msgB} {
+		tx := sent[i]
+		require.Equal(uint64(i), tx.Nonce())
+		require.Equal(uint64(200_000), tx.Gas())
+		require.Equal(big.NewInt(51), tx.GasFeeCap())
+		require.Len(tx.AccessList(), 1)
+		require.Equal(warpcontract.ContractAddress, tx.AccessList()[0].Address)
+
+		has, err := r.store.hasDelivery(r.destinations[1].DestinationChainID, msg.ID())
+		require.NoError(err)
+		require.True(has)
+	}
+}
+
+func TestRelayerRetriesFailedBlock(t *testing.T) {
+	require := require.New(t)
+
+	source := newTestSource()
+	warpLog, _ := newWarpLog(t, testSenderA, []byte("a"))
+	source.addBlock(warpLog)
+
+	agg := &testAggregator{err: errTest}
+	client := newTestClient()
+	r := newTestRelayer(t, source, agg, memdb.New(), Route{DestinationChainID: ids.GenerateTestID(), Client: client})
+	require.NoError(r.store.setLastProcessed(0))
+
+	require.ErrorIs(r.processAccepted(context.Background()), errTest)
+	lastProcessed, _, err := r.store.lastProcessed()
+	require.NoError(err)
+	require.Zero(lastProcessed)
+	require.Empty(client.sentTxs())
+
+	agg.err = nil
+	require.NoError(r.processAccepted(context.Background()))
+	lastProcessed, _, err = r.store.lastProcessed()
+	require.NoError(err)
+	require.Equal(uint64(1), lastProcessed)
+	require.Len(client.sentTxs(), 1)
+}
+
+func TestRelayerNoDuplicateDeliveryAfterRestart(t *testing.T) {
+	require := require.New(t)
+
+	source := newTestSource()
+	warpLog, _ := newWarpLog(t, testSenderA, []byte("a"))
+	source.addBlock(warpLog)
+
+	db := memdb.New()
+	client := newTestClient()
+	route := Route{DestinationChainID: ids.GenerateTestID(), Client: client}
+	r := newTestRelayer(t, source, &testAggregator{}, db, route)
+	require.NoError(r.store.setLastProcessed(0))
+	require.NoError(r.processAccepted(context.Background()))
+	sent := client.sentTxs()
+	require.Len(sent, 1)
+
+	// Restart as if the block was not marked as processed, before the
+	// delivery was accepted.
+	agg := &testAggregator{}
+	r = newTestRelayer(t, source, agg, db, route)
+	require.NoError(r.store.setLastProcessed(0))
+	require.NoError(r.processAccepted(context.Background()))
+	require.Zero(agg.calls)
+	require.Len(client.sentTxs(), 1)
+
+	// The persisted delivery is resubmitted as is.
+	require.NoError(r.confirmDeliveries(context.Background()))
+	resent := client.sentTxs()
+	require.Len(resent, 2)
+	require.Equal(sent[0].Hash(), resent[1].Hash())
+
+	// Later deliveries don't reuse its nonce.
+	warpLog, _ = newWarpLog(t, testSenderA, []byte("b"))
+	source.addBlock(warpLog)
+	require.NoError(r.processAccepted(context.Background()))
+	resent = client.sentTxs()
+	require.Len(resent, 3)
+	require.Equal(uint64(1), resent[2].Nonce())
+}
+
+func TestRelayerConfirmDeliveries(t *testing.T) {
+	require := require.New(t)
+
+	source := newTestSource()
+	logA, msgA := newWarpLog(t, testSenderA, []byte("a"))
+	logB, msgB := newWarpLog(t, testSenderA, []byte("b"))
+	source.addBlock(logA, logB)
+
+	client := newTestClient()
+	r := newTestRelayer(t, source, &testAggregator{}, memdb.New(), Route{DestinationChainID: ids.GenerateTestID(), Client: client})
+	require.NoError(r.store.setLastProcessed(0))
+	require.NoError(r.processAccepted(context.Background()))
+
+	// Only accept the first delivery.
+	sent := client.sentTxs()
+	require.Len(sent, 2)
+	client.receipts[sent[0].Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	require.NoError(r.confirmDeliveries(context.Background()))
+
+	submitted, err := r.store.submittedDeliveries()
+	require.NoError(err)
+	require.Len(submitted, 1)
+	require.Equal(msgB.ID(), submitted[0].messageID)
+	require.Len(client.sentTxs(), 3)
+
+	// Already known transactions are left pending.
+	client.sendErr = txpool.ErrAlreadyKnown
+	require.NoError(r.confirmDeliveries(context.Background()))
+	submitted, err = r.store.submittedDeliveries()
+	require.NoError(err)
+	require.Len(submitted, 1)
+
+	// Deliveries whose nonce was used by another transaction are queued again
+	// with a fresh nonce.
+	client.sendErr = core.ErrNonceTooLow
+	require.NoError(r.confirmDeliveries(context.Background()))
+	submitted, err = r.store.submittedDeliveries()
+	require.NoError(err)
+	require.Len(submitted, 1)
+	requeued := submitted[0].tx
+	require.Equal(uint64(2), requeued.Nonce())
+	require.Equal(sent[1].Data(), requeued.Data())
+	require.Equal(sent[1].AccessList(), requeued.AccessList())
+	require.Equal([]common.Hash{sent[1].Hash()}, submitted[0].replaced)
+
+	client.sendErr = nil
+	require.NoError(r.confirmDeliveries(context.Background()))
+	sent = client.sentTxs()
+	require.Equal(requeued.Hash(), sent[len(sent)-1].Hash())
+
+	client.receipts[requeued.Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	require.NoError(r.confirmDeliveries(context.Background()))
+	submitted, err = r.store.submittedDeliveries()
+	require.NoError(err)
+	require.Empty(submitted)
+
+	// Neither message is delivered again.
+	for _, msg := range []*luxWarp.UnsignedMessage{msgA, msgB} {
+		has, err := r.store.hasDelivery(r.destinations[0].DestinationChainID, msg.ID())
+		require.NoError(err)
+		require.True(has)
+	}
+}
+
+func TestRelayerBumpsFees(t *testing.T) {
+	require := require.New(t)
+
+	source := newTestSource()
+	warpLog, _ := newWarpLog(t, testSenderA, []byte("a"))
+	source.addBlock(warpLog)
+
+	client := newTestClient()
+	r := newTestRelayer(t, source, &testAggregator{}, memdb.New(), Route{DestinationChainID: ids.GenerateTestID(), Client: client})
+	require.NoError(r.store.setLastProcessed(0))
+	require.NoError(r.processAccepted(context.Background()))
+	sent := client.sentTxs()
+	require.Len(sent, 1)
+	require.Equal(big.NewInt(51), sent[0].GasFeeCap())
+
+	// The base fee rose above the fee cap, so the delivery is replaced at the
+	// same nonce with the current fees.
+	client.lock.Lock()
+	client.baseFee = big.NewInt(100)
+	client.lock.Unlock()
+	require.NoError(r.confirmDeliveries(context.Background()))
+	sent = client.sentTxs()
+	require.Len(sent, 2)
+	require.Equal(sent[0].Nonce(), sent[1].Nonce())
+	require.Equal(sent[0].Data(), sent[1].Data())
+	require.Equal(big.NewInt(201), sent[1].GasFeeCap())
+	// The tip is bumped for the replacement to be accepted by the mempool.
+	require.Equal(big.NewInt(2), sent[1].GasTipCap())
+
+	// Sufficient fees are not bumped again.
+	require.NoError(r.confirmDeliveries(context.Background()))
+	sent = client.sentTxs()
+	require.Len(sent, 3)
+	require.Equal(sent[1].Hash(), sent[2].Hash())
+
+	// The replaced transaction being accepted completes the delivery.
+	client.receipts[sent[0].Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	require.NoError(r.confirmDeliveries(context.Background()))
+	submitted, err := r.store.submittedDeliveries()
+	require.NoError(err)
+	require.Empty(submitted)
+	require.Len(client.sentTxs(), 3)
+}
+
+func TestBumpFee(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(big.NewInt(2), bumpFee(big.NewInt(1), big.NewInt(1)))
+	require.Equal(big.NewInt(110), bumpFee(big.NewInt(100), big.NewInt(50)))
+	require.Equal(big.NewInt(112), bumpFee(big.NewInt(101), big.NewInt(50)))
+	require.Equal(big.NewInt(500), bumpFee(big.NewInt(100), big.NewInt(500)))
+}
+
+func TestRelayerRun(t *testing.T) {
+	require := require.New(t)
+
+	// Messages accepted before the relayer first started are not relayed.
+	source := newTestSource()
+	warpLog, _ := newWarpLog(t, testSenderA, []byte("before"))
+	source.addBlock(warpLog)
+
+	client := newTestClient()
+	r := newTestRelayer(t, source, &testAggregator{}, memdb.New(), Route{DestinationChainID: ids.GenerateTestID(), Client: client})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	warpLog, _ = newWarpLog(t, testSenderA, []byte("after"))
+	source.addBlock(warpLog)
+	require.Eventually(func() bool { return len(client.sentTxs()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal([]byte("after"), client.sentTxs()[0].Data())
+}
+
+func TestConfigVerify(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	tests := []struct {
+		name        string
+		routes      []Route
+		expectedErr error
+	}{
+		{
+			name:        "no routes",
+			expectedErr: errNoRoutes,
+		},
+		{
+			name:        "missing destination",
+			routes:      []Route{{Client: newTestClient()}},
+			expectedErr: errNoDestinationChain,
+		},
+		{
+			name:        "missing client",
+			routes:      []Route{{DestinationChainID: chainID}},
+			expectedErr: errNoClient,
+		},
+		{
+			name: "duplicate destination",
+			routes: []Route{
+				{DestinationChainID: chainID, Client: newTestClient()},
+				{DestinationChainID: chainID, Client: newTestClient()},
+			},
+			expectedErr: errDuplicateRoute,
+		},
+		{
+			name:   "valid",
+			routes: []Route{{DestinationChainID: chainID, Client: newTestClient()}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := Config{Routes: test.routes}
+			config.setDefaults()
+			require.ErrorIs(t, config.verify(), test.expectedErr)
+		})
+	}
+}
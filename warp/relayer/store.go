@@ -0,0 +1,140 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rlp"
+	"github.com/luxfi/node/database"
+	"github.com/luxfi/node/ids"
+)
+
+var (
+	lastProcessedKey = []byte("last_processed")
+	deliveryPrefix   = []byte("delivery")
+
+	errInvalidDelivery = errors.New("invalid delivery record")
+)
+
+type deliveryStatus byte
+
+const (
+	// statusSubmitted deliveries were signed and submitted to the destination,
+	// but not yet accepted there.
+	statusSubmitted deliveryStatus = iota + 1
+	// statusAccepted deliveries were accepted by the destination.
+	statusAccepted
+)
+
+func (s deliveryStatus) String() string {
+	switch s {
+	case statusSubmitted:
+		return "submitted"
+	case statusAccepted:
+		return "accepted"
+	default:
+		return "unknown"
+	}
+}
+
+// delivery is the persisted state of delivering a message to a destination.
+//
+// The signed transaction is persisted before it's submitted, so that it's only
+// ever resubmitted or replaced after a restart, rather than delivered again.
+type delivery struct {
+	destinationChainID ids.ID
+	messageID          ids.ID
+	status             deliveryStatus
+	tx                 *types.Transaction
+	// replaced are the hashes of the transactions [tx] replaced, any of which
+	// may be accepted instead of it.
+	replaced []common.Hash
+}
+
+// deliveryRecord is the encoding of a delivery in the store, after its status.
+type deliveryRecord struct {
+	Tx       *types.Transaction
+	Replaced []common.Hash
+}
+
+// store persists the state of the relayer.
+type store struct {
+	db database.Database
+}
+
+func deliveryKey(destinationChainID ids.ID, messageID ids.ID) []byte {
+	key := make([]byte, 0, len(deliveryPrefix)+2*ids.IDLen)
+	key = append(key, deliveryPrefix...)
+	key = append(key, destinationChainID[:]...)
+	return append(key, messageID[:]...)
+}
+
+// lastProcessed returns the height of the last source block whose messages were
+// all submitted, and false if no block was processed yet.
+func (s *store) lastProcessed() (uint64, bool, error) {
+	b, err := s.db.Get(lastProcessedKey)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, err
+	case len(b) != 8:
+		return 0, false, fmt.Errorf("invalid last processed height length %d", len(b))
+	}
+	return binary.BigEndian.Uint64(b), true, nil
+}
+
+func (s *store) setLastProcessed(height uint64) error {
+	return s.db.Put(lastProcessedKey, binary.BigEndian.AppendUint64(nil, height))
+}
+
+// hasDelivery returns true if the message was already submitted to the
+// destination.
+func (s *store) hasDelivery(destinationChainID ids.ID, messageID ids.ID) (bool, error) {
+	return s.db.Has(deliveryKey(destinationChainID, messageID))
+}
+
+func (s *store) putDelivery(d *delivery) error {
+	recordBytes, err := rlp.EncodeToBytes(&deliveryRecord{Tx: d.tx, Replaced: d.replaced})
+	if err != nil {
+		return err
+	}
+	return s.db.Put(deliveryKey(d.destinationChainID, d.messageID), append([]byte{byte(d.status)}, recordBytes...))
+}
+
+// submittedDeliveries returns the deliveries not yet accepted by their
+// destination.
+func (s *store) submittedDeliveries() ([]*delivery, error) {
+	it := s.db.NewIteratorWithPrefix(deliveryPrefix)
+	defer it.Release()
+
+	var deliveries []*delivery
+	for it.Next() {
+		key, value := it.Key(), it.Value()
+		if len(key) != len(deliveryPrefix)+2*ids.IDLen || len(value) == 0 {
+			return nil, fmt.Errorf("%w: %x", errInvalidDelivery, key)
+		}
+		if deliveryStatus(value[0]) != statusSubmitted {
+			continue
+		}
+		var record deliveryRecord
+		if err := rlp.DecodeBytes(value[1:], &record); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidDelivery, err)
+		}
+		d := &delivery{
+			status:   statusSubmitted,
+			tx:       record.Tx,
+			replaced: record.Replaced,
+		}
+		copy(d.destinationChainID[:], key[len(deliveryPrefix):])
+		copy(d.messageID[:], key[len(deliveryPrefix)+ids.IDLen:])
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, it.Error()
+}
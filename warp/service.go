@@ -14,30 +14,23 @@ import (
 	"github.com/luxfi/evm/warp/aggregator"
 	"github.com/luxfi/evm/warp/validators"
 	"github.com/luxfi/geth/common/hexutil"
-	"github.com/luxfi/geth/log"
 )
 
-var errNoValidators = errors.New("cannot aggregate signatures from subnet with no validators")
-
 // API introduces snowman specific functionality to the evm
 type API struct {
 	networkID                     uint32
 	sourceSubnetID, sourceChainID ids.ID
 	backend                       Backend
-	state                         validators.State
-	client                        peer.NetworkClient
-	requirePrimaryNetworkSigners  func() bool
+	aggregator                    *SignatureAggregator
 }
 
 func NewAPI(networkID uint32, sourceSubnetID ids.ID, sourceChainID ids.ID, state validators.State, backend Backend, client peer.NetworkClient, requirePrimaryNetworkSigners func() bool) *API {
 	return &API{
-		networkID:                    networkID,
-		sourceSubnetID:               sourceSubnetID,
-		sourceChainID:                sourceChainID,
-		backend:                      backend,
-		state:                        state,
-		client:                       client,
-		requirePrimaryNetworkSigners: requirePrimaryNetworkSigners,
+		networkID:      networkID,
+		sourceSubnetID: sourceSubnetID,
+		sourceChainID:  sourceChainID,
+		backend:        backend,
+		aggregator:     NewSignatureAggregator(sourceSubnetID, sourceChainID, state, client, requirePrimaryNetworkSigners),
 	}
 }
 
@@ -72,6 +65,36 @@ func (a *API) GetBlockSignature(ctx context.Context, blockID ids.ID) (hexutil.By
 	return signature[:], nil
 }
 
+// AggregateSignatureDetails describes the outcome of aggregating the signatures
+// over a warp message.
+type AggregateSignatureDetails struct {
+	// SignedMessage is the message with the aggregate signature, omitted if the
+	// signature weight doesn't meet the quorum.
+	SignedMessage   hexutil.Bytes `json:"signedMessage,omitempty"`
+	SignatureWeight uint64        `json:"signatureWeight"`
+	TotalWeight     uint64        `json:"totalWeight"`
+	// Signers is the bitset of the indices of the validators included in the
+	// aggregate signature, in the canonical validator set.
+	Signers  hexutil.Bytes      `json:"signers"`
+	Failures []ValidatorFailure `json:"failures"`
+}
+
+// ValidatorFailure describes why no signature could be fetched from the
+// validator at [Index] of the canonical validator set.
+type ValidatorFailure struct {
+	Index     int           `json:"index"`
+	PublicKey hexutil.Bytes `json:"publicKey"`
+	Weight    uint64        `json:"weight"`
+	Nodes     []NodeFailure `json:"nodes"`
+}
+
+// NodeFailure is the reason a node of a validator failed to provide its signature.
+type NodeFailure struct {
+	NodeID   ids.NodeID `json:"nodeID"`
+	Reason   string     `json:"reason"`
+	TimedOut bool       `json:"timedOut"`
+}
+
 // GetMessageAggregateSignature fetches the aggregate signature for the requested [messageID]
 func (a *API) GetMessageAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetIDStr string) (signedMessageBytes hexutil.Bytes, err error) {
 	unsignedMessage, err := a.backend.GetMessage(messageID)
@@ -81,57 +104,102 @@ func (a *API) GetMessageAggregateSignature(ctx context.Context, messageID ids.ID
 	return a.aggregateSignatures(ctx, unsignedMessage, quorumNum, subnetIDStr)
 }
 
-// GetBlockAggregateSignature fetches the aggregate signature for the requested [blockID]
-func (a *API) GetBlockAggregateSignature(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetIDStr string) (signedMessageBytes hexutil.Bytes, err error) {
-	blockHashPayload, err := payload.NewHash(blockID)
+// GetMessageAggregateSignatureDetails fetches the aggregate signature for the
+// requested [messageID], along with the details of the aggregation. The details
+// are returned even if the signature weight doesn't meet the quorum.
+func (a *API) GetMessageAggregateSignatureDetails(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetIDStr string) (*AggregateSignatureDetails, error) {
+	unsignedMessage, err := a.backend.GetMessage(messageID)
 	if err != nil {
 		return nil, err
 	}
-	unsignedMessage, err := warp.NewUnsignedMessage(a.networkID, a.sourceChainID, blockHashPayload.Bytes())
+	return a.aggregateSignatureDetails(ctx, unsignedMessage, quorumNum, subnetIDStr)
+}
+
+// GetBlockAggregateSignature fetches the aggregate signature for the requested [blockID]
+func (a *API) GetBlockAggregateSignature(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetIDStr string) (signedMessageBytes hexutil.Bytes, err error) {
+	unsignedMessage, err := a.blockMessage(blockID)
 	if err != nil {
 		return nil, err
 	}
-
 	return a.aggregateSignatures(ctx, unsignedMessage, quorumNum, subnetIDStr)
 }
 
-func (a *API) aggregateSignatures(ctx context.Context, unsignedMessage *warp.UnsignedMessage, quorumNum uint64, subnetIDStr string) (hexutil.Bytes, error) {
-	subnetID := a.sourceSubnetID
-	if len(subnetIDStr) > 0 {
-		sid, err := ids.FromString(subnetIDStr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse subnetID: %q", subnetIDStr)
-		}
-		subnetID = sid
-	}
-	pChainHeight, err := a.state.GetCurrentHeight(ctx)
+// GetBlockAggregateSignatureDetails fetches the aggregate signature for the
+// requested [blockID], along with the details of the aggregation. The details
+// are returned even if the signature weight doesn't meet the quorum.
+func (a *API) GetBlockAggregateSignatureDetails(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetIDStr string) (*AggregateSignatureDetails, error) {
+	unsignedMessage, err := a.blockMessage(blockID)
 	if err != nil {
 		return nil, err
 	}
+	return a.aggregateSignatureDetails(ctx, unsignedMessage, quorumNum, subnetIDStr)
+}
 
-	state := validators.NewState(&a.state, a.sourceSubnetID, a.sourceChainID, a.requirePrimaryNetworkSigners())
-	validatorSet, err := warp.GetCanonicalValidatorSetFromSubnetID(ctx, state, pChainHeight, subnetID)
+// blockMessage returns the unsigned warp message over [blockID].
+func (a *API) blockMessage(blockID ids.ID) (*warp.UnsignedMessage, error) {
+	blockHashPayload, err := payload.NewHash(blockID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get validator set: %w", err)
-	}
-	if len(validatorSet.Validators) == 0 {
-		return nil, fmt.Errorf("%w (SubnetID: %s, Height: %d)", errNoValidators, subnetID, pChainHeight)
+		return nil, err
 	}
+	return warp.NewUnsignedMessage(a.networkID, a.sourceChainID, blockHashPayload.Bytes())
+}
 
-	log.Debug("Fetching signature",
-		"sourceSubnetID", subnetID,
-		"height", pChainHeight,
-		"numValidators", len(validatorSet.Validators),
-		"totalWeight", validatorSet.TotalWeight,
-	)
-
-	agg := aggregator.New(aggregator.NewSignatureGetter(a.client), validatorSet.Validators, validatorSet.TotalWeight)
-	signatureResult, err := agg.AggregateSignatures(ctx, unsignedMessage, quorumNum)
+func (a *API) aggregateSignatures(ctx context.Context, unsignedMessage *warp.UnsignedMessage, quorumNum uint64, subnetIDStr string) (hexutil.Bytes, error) {
+	signatureResult, _, err := a.aggregate(ctx, unsignedMessage, quorumNum, subnetIDStr)
 	if err != nil {
 		return nil, err
 	}
-	// TODO: return the signature and total weight as well to the caller for more complete details
-	// Need to decide on the best UI for this and write up documentation with the potential
-	// gotchas that could impact signed messages becoming invalid.
 	return hexutil.Bytes(signatureResult.Message.Bytes()), nil
 }
+
+func (a *API) aggregateSignatureDetails(ctx context.Context, unsignedMessage *warp.UnsignedMessage, quorumNum uint64, subnetIDStr string) (*AggregateSignatureDetails, error) {
+	signatureResult, validatorSet, err := a.aggregate(ctx, unsignedMessage, quorumNum, subnetIDStr)
+	var weightErr *aggregator.InsufficientWeightError
+	switch {
+	case errors.As(err, &weightErr):
+		signatureResult = weightErr.Result
+	case err != nil:
+		return nil, err
+	}
+
+	details := &AggregateSignatureDetails{
+		SignatureWeight: signatureResult.SignatureWeight,
+		TotalWeight:     signatureResult.TotalWeight,
+		Signers:         signatureResult.Signers.Bytes(),
+		Failures:        make([]ValidatorFailure, 0, len(signatureResult.Failures)),
+	}
+	if signatureResult.Message != nil {
+		details.SignedMessage = signatureResult.Message.Bytes()
+	}
+	for _, failure := range signatureResult.Failures {
+		validatorFailure := ValidatorFailure{
+			Index:     failure.Index,
+			PublicKey: validatorSet.Validators[failure.Index].PublicKeyBytes,
+			Weight:    failure.Weight,
+			Nodes:     make([]NodeFailure, 0, len(failure.Nodes)),
+		}
+		for _, node := range failure.Nodes {
+			validatorFailure.Nodes = append(validatorFailure.Nodes, NodeFailure{
+				NodeID:   node.NodeID,
+				Reason:   node.Err.Error(),
+				TimedOut: errors.Is(node.Err, aggregator.ErrNodeTimeout) || errors.Is(node.Err, context.DeadlineExceeded),
+			})
+		}
+		details.Failures = append(details.Failures, validatorFailure)
+	}
+	return details, nil
+}
+
+// aggregate aggregates the signatures over [unsignedMessage] from the
+// validators of the requested subnet, returning the validator set used.
+func (a *API) aggregate(ctx context.Context, unsignedMessage *warp.UnsignedMessage, quorumNum uint64, subnetIDStr string) (*aggregator.AggregateSignatureResult, warp.CanonicalValidatorSet, error) {
+	subnetID := a.sourceSubnetID
+	if len(subnetIDStr) > 0 {
+		sid, err := ids.FromString(subnetIDStr)
+		if err != nil {
+			return nil, warp.CanonicalValidatorSet{}, fmt.Errorf("failed to parse subnetID: %q", subnetIDStr)
+		}
+		subnetID = sid
+	}
+	return a.aggregator.aggregate(ctx, unsignedMessage, quorumNum, subnetID)
+}
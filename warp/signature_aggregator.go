@@ -0,0 +1,74 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/evm/peer"
+	"github.com/luxfi/evm/warp/aggregator"
+	"github.com/luxfi/evm/warp/validators"
+	"github.com/luxfi/geth/log"
+	"github.com/luxfi/node/ids"
+	"github.com/luxfi/node/vms/platformvm/warp"
+)
+
+var errNoValidators = errors.New("cannot aggregate signatures from subnet with no validators")
+
+// SignatureAggregator aggregates the signatures of the current validators of a
+// subnet over warp messages, fetching them from the validators over the network.
+type SignatureAggregator struct {
+	sourceSubnetID, sourceChainID ids.ID
+	state                         validators.State
+	client                        peer.NetworkClient
+	requirePrimaryNetworkSigners  func() bool
+}
+
+func NewSignatureAggregator(sourceSubnetID ids.ID, sourceChainID ids.ID, state validators.State, client peer.NetworkClient, requirePrimaryNetworkSigners func() bool) *SignatureAggregator {
+	return &SignatureAggregator{
+		sourceSubnetID:               sourceSubnetID,
+		sourceChainID:                sourceChainID,
+		state:                        state,
+		client:                       client,
+		requirePrimaryNetworkSigners: requirePrimaryNetworkSigners,
+	}
+}
+
+// AggregateSignatures aggregates the signatures over [unsignedMessage] from the
+// current validators of the source subnet.
+func (s *SignatureAggregator) AggregateSignatures(ctx context.Context, unsignedMessage *warp.UnsignedMessage, quorumNum uint64) (*aggregator.AggregateSignatureResult, error) {
+	result, _, err := s.aggregate(ctx, unsignedMessage, quorumNum, s.sourceSubnetID)
+	return result, err
+}
+
+// aggregate aggregates the signatures over [unsignedMessage] from the current
+// validators of [subnetID], returning the validator set used.
+func (s *SignatureAggregator) aggregate(ctx context.Context, unsignedMessage *warp.UnsignedMessage, quorumNum uint64, subnetID ids.ID) (*aggregator.AggregateSignatureResult, warp.CanonicalValidatorSet, error) {
+	pChainHeight, err := s.state.GetCurrentHeight(ctx)
+	if err != nil {
+		return nil, warp.CanonicalValidatorSet{}, err
+	}
+
+	state := validators.NewState(&s.state, s.sourceSubnetID, s.sourceChainID, s.requirePrimaryNetworkSigners())
+	validatorSet, err := warp.GetCanonicalValidatorSetFromSubnetID(ctx, state, pChainHeight, subnetID)
+	if err != nil {
+		return nil, warp.CanonicalValidatorSet{}, fmt.Errorf("failed to get validator set: %w", err)
+	}
+	if len(validatorSet.Validators) == 0 {
+		return nil, warp.CanonicalValidatorSet{}, fmt.Errorf("%w (SubnetID: %s, Height: %d)", errNoValidators, subnetID, pChainHeight)
+	}
+
+	log.Debug("Fetching signature",
+		"sourceSubnetID", subnetID,
+		"height", pChainHeight,
+		"numValidators", len(validatorSet.Validators),
+		"totalWeight", validatorSet.TotalWeight,
+	)
+
+	agg := aggregator.New(aggregator.NewSignatureGetter(s.client), validatorSet.Validators, validatorSet.TotalWeight)
+	signatureResult, err := agg.AggregateSignatures(ctx, unsignedMessage, quorumNum)
+	return signatureResult, validatorSet, err
+}
@@ -0,0 +1,86 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"sync"
+
+	"github.com/luxfi/evm/precompile/precompileconfig"
+	"github.com/luxfi/node/consensus/validators"
+	"github.com/luxfi/node/ids"
+	"github.com/luxfi/node/vms/platformvm/warp"
+)
+
+var _ precompileconfig.WarpValidatorSetCache = (*CanonicalSetCache)(nil)
+
+type canonicalSetKey struct {
+	height   uint64
+	subnetID ids.ID
+}
+
+type canonicalSetResult struct {
+	validatorSet warp.CanonicalValidatorSet
+	err          error
+}
+
+type subnetIDResult struct {
+	subnetID ids.ID
+	err      error
+}
+
+// CanonicalSetCache caches the canonical validator sets retrieved to verify the
+// warp messages of a block, so that the validator set of a subnet is retrieved
+// from the P-Chain at most once per block regardless of the number of messages
+// it signed.
+//
+// Failed lookups are cached as well, as they are retried with the same
+// arguments within a block.
+type CanonicalSetCache struct {
+	lock          sync.Mutex
+	subnetIDs     map[ids.ID]subnetIDResult
+	validatorSets map[canonicalSetKey]canonicalSetResult
+}
+
+func NewCanonicalSetCache() *CanonicalSetCache {
+	return &CanonicalSetCache{
+		subnetIDs:     make(map[ids.ID]subnetIDResult),
+		validatorSets: make(map[canonicalSetKey]canonicalSetResult),
+	}
+}
+
+// GetCanonicalValidatorSetFromChainID returns the canonical validator set of the
+// subnet validating [chainID] at [height] as
+// warp.GetCanonicalValidatorSetFromChainID does, retrieving it from [state] if
+// not cached yet.
+func (c *CanonicalSetCache) GetCanonicalValidatorSetFromChainID(ctx context.Context, state validators.State, height uint64, chainID ids.ID) (warp.CanonicalValidatorSet, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	subnetResult, ok := c.subnetIDs[chainID]
+	if !ok {
+		subnetResult.subnetID, subnetResult.err = state.GetSubnetID(ctx, chainID)
+		c.subnetIDs[chainID] = subnetResult
+	}
+	if subnetResult.err != nil {
+		return warp.CanonicalValidatorSet{}, subnetResult.err
+	}
+
+	// Key by the subnet whose validators are retrieved, which is shared by the
+	// Primary Network and the receiving subnet unless Primary Network signers
+	// are required.
+	key := canonicalSetKey{
+		height:   height,
+		subnetID: subnetResult.subnetID,
+	}
+	if warpState, ok := state.(*State); ok {
+		key.subnetID = warpState.validatorSubnetID(subnetResult.subnetID)
+	}
+	setResult, ok := c.validatorSets[key]
+	if !ok {
+		setResult.validatorSet, setResult.err = warp.GetCanonicalValidatorSetFromSubnetID(ctx, state, height, subnetResult.subnetID)
+		c.validatorSets[key] = setResult
+	}
+	return setResult.validatorSet, setResult.err
+}
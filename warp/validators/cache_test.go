@@ -0,0 +1,121 @@
+// (c) 2025, Hanzo Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luxfi/node/consensus/validators"
+	"github.com/luxfi/node/consensus/validators/validatorstest"
+	"github.com/luxfi/node/ids"
+	"github.com/luxfi/node/utils/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalSetCache(t *testing.T) {
+	require := require.New(t)
+
+	mySubnetID := ids.GenerateTestID()
+	otherSubnetID := ids.GenerateTestID()
+	otherChainID := ids.GenerateTestID()
+	primaryChainID := ids.GenerateTestID()
+	subnetIDs := map[ids.ID]ids.ID{
+		otherChainID:   otherSubnetID,
+		primaryChainID: constants.PrimaryNetworkID,
+	}
+
+	var (
+		subnetIDLookups     int
+		validatorSetLookups = make(map[ids.ID]int)
+	)
+	innerState := &validatorstest.State{
+		GetSubnetIDF: func(_ context.Context, chainID ids.ID) (ids.ID, error) {
+			subnetIDLookups++
+			return subnetIDs[chainID], nil
+		},
+		GetValidatorSetF: func(_ context.Context, _ uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			validatorSetLookups[subnetID]++
+			return map[ids.NodeID]*validators.GetValidatorOutput{}, nil
+		},
+	}
+
+	cache := NewCanonicalSetCache()
+	for i := 0; i < 3; i++ {
+		_, err := cache.GetCanonicalValidatorSetFromChainID(context.Background(), NewState(innerState, mySubnetID, otherChainID, false), 10, otherChainID)
+		require.NoError(err)
+	}
+	require.Equal(1, subnetIDLookups)
+	require.Equal(1, validatorSetLookups[otherSubnetID])
+
+	// The validator set is retrieved again at another height.
+	_, err := cache.GetCanonicalValidatorSetFromChainID(context.Background(), NewState(innerState, mySubnetID, otherChainID, false), 11, otherChainID)
+	require.NoError(err)
+	require.Equal(2, validatorSetLookups[otherSubnetID])
+
+	// Messages from the Primary Network are verified against the validator set
+	// of the receiving subnet unless Primary Network signers are required.
+	_, err = cache.GetCanonicalValidatorSetFromChainID(context.Background(), NewState(innerState, mySubnetID, primaryChainID, false), 10, primaryChainID)
+	require.NoError(err)
+	require.Equal(1, validatorSetLookups[mySubnetID])
+	require.Zero(validatorSetLookups[constants.PrimaryNetworkID])
+
+	_, err = cache.GetCanonicalValidatorSetFromChainID(context.Background(), NewState(innerState, mySubnetID, primaryChainID, true), 10, primaryChainID)
+	require.NoError(err)
+	require.Equal(1, validatorSetLookups[mySubnetID])
+	require.Equal(1, validatorSetLookups[constants.PrimaryNetworkID])
+	require.Equal(2, subnetIDLookups)
+}
+
+func TestCanonicalSetCacheError(t *testing.T) {
+	require := require.New(t)
+
+	errTest := errors.New("non-nil error")
+	var lookups int
+	innerState := &validatorstest.State{
+		GetSubnetIDF: func(context.Context, ids.ID) (ids.ID, error) {
+			return ids.GenerateTestID(), nil
+		},
+		GetValidatorSetF: func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			lookups++
+			return nil, errTest
+		},
+	}
+
+	chainID := ids.GenerateTestID()
+	state := NewState(innerState, ids.GenerateTestID(), chainID, false)
+	cache := NewCanonicalSetCache()
+	for i := 0; i < 2; i++ {
+		_, err := cache.GetCanonicalValidatorSetFromChainID(context.Background(), state, 10, chainID)
+		require.ErrorIs(err, errTest)
+	}
+	require.Equal(1, lookups)
+}
+
+func TestCanonicalSetCacheUnwrappedState(t *testing.T) {
+	require := require.New(t)
+
+	subnetID := ids.GenerateTestID()
+	var lookups int
+	innerState := &validatorstest.State{
+		GetSubnetIDF: func(context.Context, ids.ID) (ids.ID, error) {
+			return subnetID, nil
+		},
+		GetValidatorSetF: func(_ context.Context, _ uint64, gotSubnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			require.Equal(subnetID, gotSubnetID)
+			lookups++
+			return map[ids.NodeID]*validators.GetValidatorOutput{}, nil
+		},
+	}
+
+	// A state not wrapped in a State is keyed by the subnet of the chain.
+	chainID := ids.GenerateTestID()
+	cache := NewCanonicalSetCache()
+	for i := 0; i < 2; i++ {
+		_, err := cache.GetCanonicalValidatorSetFromChainID(context.Background(), innerState, 10, chainID)
+		require.NoError(err)
+	}
+	require.Equal(1, lookups)
+}
@@ -41,14 +41,20 @@ func (s *State) GetValidatorSet(
 	height uint64,
 	subnetID ids.ID,
 ) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	return s.State.GetValidatorSet(ctx, height, s.validatorSubnetID(subnetID))
+}
+
+// validatorSubnetID returns the subnet whose validator set is used for the
+// messages of [subnetID].
+func (s *State) validatorSubnetID(subnetID ids.ID) ids.ID {
 	// If the subnetID is anything other than the Primary Network, or Primary
 	// Network signers are required (except P-Chain), this is a direct passthrough.
 	usePrimary := s.requirePrimaryNetworkSigners && s.sourceChainID != constants.PlatformChainID
 	if usePrimary || subnetID != constants.PrimaryNetworkID {
-		return s.State.GetValidatorSet(ctx, height, subnetID)
+		return subnetID
 	}
 
 	// If the requested subnet is the primary network, then we return the validator
 	// set for the Subnet that is receiving the message instead.
-	return s.State.GetValidatorSet(ctx, height, s.mySubnetID)
+	return s.mySubnetID
 }
@@ -109,16 +109,25 @@ func (b *backend) verifyOffchainAddressedCall(addressedCall *payload.AddressedCa
 	return nil
 }
 
+// verifyUptimeMessage returns nil if the validator referenced by [uptimeMsg] is
+// known and its locally observed uptime is at least the requested TotalUptime.
 func (b *backend) verifyUptimeMessage(uptimeMsg *messages.ValidatorUptime) *engine.AppError {
-	// FIXME: GetValidatorAndUptime method doesn't exist in validators.State interface
-	// vdr, currentUptime, _, err := b.validatorReader.GetValidatorAndUptime(uptimeMsg.ValidationID)
-	var err error
-	if err == nil {
-		err = fmt.Errorf("GetValidatorAndUptime not implemented")
+	vdr, currentUptime, _, err := b.validatorReader.GetValidatorAndUptime(uptimeMsg.ValidationID)
+	if err != nil {
+		return &engine.AppError{
+			Code:    VerifyErrCode,
+			Message: fmt.Sprintf("failed to get uptime for validationID %s: %s", uptimeMsg.ValidationID, err.Error()),
+		}
 	}
-	b.stats.IncUptimeValidationFail()
-	return &engine.AppError{
-		Code:    VerifyErrCode,
-		Message: fmt.Sprintf("uptime verification not implemented: %s", err.Error()),
+
+	currentUptimeSeconds := uint64(currentUptime.Seconds())
+	// verify the current uptime against the total uptime in the message
+	if currentUptimeSeconds < uptimeMsg.TotalUptime {
+		return &engine.AppError{
+			Code:    VerifyErrCode,
+			Message: fmt.Sprintf("current uptime %d is less than queried uptime %d for nodeID %s", currentUptimeSeconds, uptimeMsg.TotalUptime, vdr.NodeID),
+		}
 	}
+
+	return nil
 }